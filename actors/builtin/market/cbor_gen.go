@@ -7,13 +7,15 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/go-state-types/abi"
+	big "github.com/filecoin-project/go-state-types/big"
+	market "github.com/filecoin-project/specs-actors/v3/actors/builtin/market"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{139}
+var lengthBufState = []byte{142}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -93,6 +95,25 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 	if err := t.TotalClientStorageFee.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.ProviderDeals (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.ProviderDeals); err != nil {
+		return xerrors.Errorf("failed to write cid field t.ProviderDeals: %w", err)
+	}
+
+	// t.ClientDeals (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.ClientDeals); err != nil {
+		return xerrors.Errorf("failed to write cid field t.ClientDeals: %w", err)
+	}
+
+	// t.DealPayments (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.DealPayments); err != nil {
+		return xerrors.Errorf("failed to write cid field t.DealPayments: %w", err)
+	}
+
 	return nil
 }
 
@@ -110,7 +131,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 11 {
+	if extra != 14 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -251,6 +272,42 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 			return xerrors.Errorf("unmarshaling t.TotalClientStorageFee: %w", err)
 		}
 
+	}
+	// t.ProviderDeals (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.ProviderDeals: %w", err)
+		}
+
+		t.ProviderDeals = c
+
+	}
+	// t.ClientDeals (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.ClientDeals: %w", err)
+		}
+
+		t.ClientDeals = c
+
+	}
+	// t.DealPayments (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.DealPayments: %w", err)
+		}
+
+		t.DealPayments = c
+
 	}
 	return nil
 }
@@ -399,7 +456,7 @@ func (t *DealState) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
-var lengthBufPublishStorageDealsParams = []byte{129}
+var lengthBufPublishStorageDealsParams = []byte{130}
 
 func (t *PublishStorageDealsParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -425,6 +482,20 @@ func (t *PublishStorageDealsParams) MarshalCBOR(w io.Writer) error {
 			return err
 		}
 	}
+
+	// t.PaymentVouchers ([]market.DealPaymentVoucher) (slice)
+	if len(t.PaymentVouchers) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.PaymentVouchers was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.PaymentVouchers))); err != nil {
+		return err
+	}
+	for _, v := range t.PaymentVouchers {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -442,7 +513,7 @@ func (t *PublishStorageDealsParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 1 {
+	if extra != 2 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -475,95 +546,152 @@ func (t *PublishStorageDealsParams) UnmarshalCBOR(r io.Reader) error {
 		t.Deals[i] = v
 	}
 
+	// t.PaymentVouchers ([]market.DealPaymentVoucher) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.PaymentVouchers: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.PaymentVouchers = make([]DealPaymentVoucher, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v DealPaymentVoucher
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.PaymentVouchers[i] = v
+	}
+
 	return nil
 }
 
-var lengthBufDealProposal = []byte{139}
+var lengthBufGetActiveDealsParams = []byte{129}
 
-func (t *DealProposal) MarshalCBOR(w io.Writer) error {
+func (t *GetActiveDealsParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufDealProposal); err != nil {
+	if _, err := w.Write(lengthBufGetActiveDealsParams); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.PieceCID (cid.Cid) (struct)
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
 
-	if err := cbg.WriteCidBuf(scratch, w, t.PieceCID); err != nil {
-		return xerrors.Errorf("failed to write cid field t.PieceCID: %w", err)
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+func (t *GetActiveDealsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = GetActiveDealsParams{}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.PieceSize)); err != nil {
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
 		return err
 	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
 
-	// t.VerifiedDeal (bool) (bool)
-	if err := cbg.WriteBool(w, t.VerifiedDeal); err != nil {
-		return err
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Client (address.Address) (struct)
-	if err := t.Client.MarshalCBOR(w); err != nil {
+	// t.DealIDs ([]abi.DealID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
 		return err
 	}
 
-	// t.Provider (address.Address) (struct)
-	if err := t.Provider.MarshalCBOR(w); err != nil {
-		return err
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
 	}
 
-	// t.Label (market.DealLabel) (struct)
-	if err := t.Label.MarshalCBOR(w); err != nil {
-		return err
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
 	}
 
-	// t.StartEpoch (abi.ChainEpoch) (int64)
-	if t.StartEpoch >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.StartEpoch)); err != nil {
-			return err
-		}
-	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.StartEpoch-1)); err != nil {
-			return err
-		}
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
 	}
 
-	// t.EndEpoch (abi.ChainEpoch) (int64)
-	if t.EndEpoch >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.EndEpoch)); err != nil {
-			return err
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
 		}
-	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.EndEpoch-1)); err != nil {
-			return err
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
 		}
+
+		t.DealIDs[i] = abi.DealID(val)
 	}
 
-	// t.StoragePricePerEpoch (big.Int) (struct)
-	if err := t.StoragePricePerEpoch.MarshalCBOR(w); err != nil {
+	return nil
+}
+
+var lengthBufGetActiveDealsReturn = []byte{129}
+
+func (t *GetActiveDealsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-
-	// t.ProviderCollateral (big.Int) (struct)
-	if err := t.ProviderCollateral.MarshalCBOR(w); err != nil {
+	if _, err := w.Write(lengthBufGetActiveDealsReturn); err != nil {
 		return err
 	}
 
-	// t.ClientCollateral (big.Int) (struct)
-	if err := t.ClientCollateral.MarshalCBOR(w); err != nil {
+	scratch := make([]byte, 9)
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
 		return err
 	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
-	*t = DealProposal{}
+func (t *GetActiveDealsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetActiveDealsReturn{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -576,83 +704,1386 @@ func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 11 {
+	if extra != 1 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.PieceCID (cid.Cid) (struct)
+	// t.DealIDs ([]abi.DealID) (slice)
 
-	{
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
 
-		c, err := cbg.ReadCid(br)
-		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.PieceCID: %w", err)
-		}
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+	}
 
-		t.PieceCID = c
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
 
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
 	}
-	// t.PieceSize (abi.PaddedPieceSize) (uint64)
 
-	{
+	for i := 0; i < int(extra); i++ {
 
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
 		if err != nil {
-			return err
+			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
 		}
+
 		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
 		}
-		t.PieceSize = abi.PaddedPieceSize(extra)
 
+		t.DealIDs[i] = abi.DealID(val)
 	}
-	// t.VerifiedDeal (bool) (bool)
 
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
+	return nil
+}
+
+var lengthBufActivateDealsBatchParams = []byte{129}
+
+func (t *ActivateDealsBatchParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if maj != cbg.MajOther {
-		return fmt.Errorf("booleans must be major type 7")
-	}
-	switch extra {
-	case 20:
-		t.VerifiedDeal = false
-	case 21:
-		t.VerifiedDeal = true
-	default:
-		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	if _, err := w.Write(lengthBufActivateDealsBatchParams); err != nil {
+		return err
 	}
-	// t.Client (address.Address) (struct)
 
-	{
+	scratch := make([]byte, 9)
 
-		if err := t.Client.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Client: %w", err)
+	// t.Sectors ([]market.SectorDeals) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+func (t *ActivateDealsBatchParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ActivateDealsBatchParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
 
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
 	}
-	// t.Provider (address.Address) (struct)
 
-	{
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
 
-		if err := t.Provider.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Provider: %w", err)
-		}
+	// t.Sectors ([]market.SectorDeals) (slice)
 
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
 	}
-	// t.Label (market.DealLabel) (struct)
 
-	{
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Sectors: array too large (%d)", extra)
+	}
 
-		if err := t.Label.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Label: %w", err)
-		}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
 
+	if extra > 0 {
+		t.Sectors = make([]market.SectorDeals, extra)
 	}
-	// t.StartEpoch (abi.ChainEpoch) (int64)
-	{
-		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+
+	for i := 0; i < int(extra); i++ {
+
+		var v market.SectorDeals
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Sectors[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufDealActivationResult = []byte{130}
+
+func (t *DealActivationResult) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealActivationResult); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	// t.Outcome (market.DealActivationOutcome) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Outcome)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *DealActivationResult) UnmarshalCBOR(r io.Reader) error {
+	*t = DealActivationResult{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	// t.Outcome (market.DealActivationOutcome) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Outcome = DealActivationOutcome(extra)
+
+	}
+	return nil
+}
+
+var lengthBufSectorDealActivation = []byte{133}
+
+func (t *SectorDealActivation) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorDealActivation); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Activated (bool) (bool)
+	if err := cbg.WriteBool(w, t.Activated); err != nil {
+		return err
+	}
+
+	// t.DealSpace (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealSpace)); err != nil {
+		return err
+	}
+
+	// t.DealWeight (big.Int) (struct)
+	if err := t.DealWeight.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.VerifiedDealWeight (big.Int) (struct)
+	if err := t.VerifiedDealWeight.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Deals ([]market.DealActivationResult) (slice)
+	if len(t.Deals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+		return err
+	}
+	for _, v := range t.Deals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SectorDealActivation) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorDealActivation{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Activated (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Activated = false
+	case 21:
+		t.Activated = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.DealSpace (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealSpace = uint64(extra)
+
+	}
+	// t.DealWeight (big.Int) (struct)
+
+	{
+
+		if err := t.DealWeight.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.DealWeight: %w", err)
+		}
+
+	}
+	// t.VerifiedDealWeight (big.Int) (struct)
+
+	{
+
+		if err := t.VerifiedDealWeight.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.VerifiedDealWeight: %w", err)
+		}
+
+	}
+	// t.Deals ([]market.DealActivationResult) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Deals: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Deals = make([]DealActivationResult, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v DealActivationResult
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Deals[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufActivateDealsBatchReturn = []byte{129}
+
+func (t *ActivateDealsBatchReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufActivateDealsBatchReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Sectors ([]market.SectorDealActivation) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ActivateDealsBatchReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = ActivateDealsBatchReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sectors ([]market.SectorDealActivation) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Sectors: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Sectors = make([]SectorDealActivation, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v SectorDealActivation
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Sectors[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufCancelPendingDealParams = []byte{129}
+
+func (t *CancelPendingDealParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCancelPendingDealParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *CancelPendingDealParams) UnmarshalCBOR(r io.Reader) error {
+	*t = CancelPendingDealParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	return nil
+}
+
+var lengthBufExtendDealsParams = []byte{129}
+
+func (t *ExtendDealsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExtendDealsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Extensions ([]market.ClientDealExtension) (slice)
+	if len(t.Extensions) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Extensions was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Extensions))); err != nil {
+		return err
+	}
+	for _, v := range t.Extensions {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ExtendDealsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ExtendDealsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Extensions ([]market.ClientDealExtension) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Extensions: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Extensions = make([]ClientDealExtension, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ClientDealExtension
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Extensions[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufGetBalanceReturn = []byte{130}
+
+func (t *GetBalanceReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetBalanceReturn); err != nil {
+		return err
+	}
+
+	// t.Balance (big.Int) (struct)
+	if err := t.Balance.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Locked (big.Int) (struct)
+	if err := t.Locked.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *GetBalanceReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetBalanceReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Balance (big.Int) (struct)
+
+	{
+
+		if err := t.Balance.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Balance: %w", err)
+		}
+
+	}
+	// t.Locked (big.Int) (struct)
+
+	{
+
+		if err := t.Locked.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Locked: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDealQueryParams = []byte{129}
+
+func (t *DealQueryParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealQueryParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *DealQueryParams) UnmarshalCBOR(r io.Reader) error {
+	*t = DealQueryParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	return nil
+}
+
+var lengthBufGetDealActivationReturn = []byte{130}
+
+func (t *GetDealActivationReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDealActivationReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Activated (abi.ChainEpoch) (int64)
+	if t.Activated >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Activated)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Activated-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Terminated (abi.ChainEpoch) (int64)
+	if t.Terminated >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Terminated)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Terminated-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *GetDealActivationReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDealActivationReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Activated (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Activated = abi.ChainEpoch(extraI)
+	}
+	// t.Terminated (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Terminated = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufGetDealsByProviderParams = []byte{129}
+
+func (t *GetDealsByProviderParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDealsByProviderParams); err != nil {
+		return err
+	}
+
+	// t.Provider (address.Address) (struct)
+	if err := t.Provider.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *GetDealsByProviderParams) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDealsByProviderParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Provider (address.Address) (struct)
+
+	{
+
+		if err := t.Provider.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Provider: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufGetDealsByProviderReturn = []byte{129}
+
+func (t *GetDealsByProviderReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDealsByProviderReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *GetDealsByProviderReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDealsByProviderReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealIDs ([]abi.DealID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
+		}
+
+		t.DealIDs[i] = abi.DealID(val)
+	}
+
+	return nil
+}
+
+var lengthBufSettleDealPaymentsParams = []byte{129}
+
+func (t *SettleDealPaymentsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSettleDealPaymentsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SettleDealPaymentsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SettleDealPaymentsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealIDs ([]abi.DealID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
+		}
+
+		t.DealIDs[i] = abi.DealID(val)
+	}
+
+	return nil
+}
+
+var lengthBufSettleDealPaymentsReturn = []byte{129}
+
+func (t *SettleDealPaymentsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSettleDealPaymentsReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Payments ([]big.Int) (slice)
+	if len(t.Payments) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Payments was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Payments))); err != nil {
+		return err
+	}
+	for _, v := range t.Payments {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SettleDealPaymentsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = SettleDealPaymentsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Payments ([]big.Int) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Payments: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Payments = make([]big.Int, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v big.Int
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Payments[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufDealPaymentVoucher = []byte{130}
+
+func (t *DealPaymentVoucher) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealPaymentVoucher); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealIndex (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealIndex)); err != nil {
+		return err
+	}
+
+	// t.Voucher (paych.SignedVoucher) (struct)
+	if err := t.Voucher.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealPaymentVoucher) UnmarshalCBOR(r io.Reader) error {
+	*t = DealPaymentVoucher{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealIndex (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealIndex = uint64(extra)
+
+	}
+	// t.Voucher (paych.SignedVoucher) (struct)
+
+	{
+
+		if err := t.Voucher.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Voucher: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufVerifyDealPaymentParams = []byte{129}
+
+func (t *VerifyDealPaymentParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufVerifyDealPaymentParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Proposal (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.Proposal); err != nil {
+		return xerrors.Errorf("failed to write cid field t.Proposal: %w", err)
+	}
+
+	return nil
+}
+
+func (t *VerifyDealPaymentParams) UnmarshalCBOR(r io.Reader) error {
+	*t = VerifyDealPaymentParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Proposal (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.Proposal: %w", err)
+		}
+
+		t.Proposal = c
+
+	}
+	return nil
+}
+
+var lengthBufDealProposal = []byte{139}
+
+func (t *DealProposal) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealProposal); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.PieceCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.PieceCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.PieceCID: %w", err)
+	}
+
+	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.PieceSize)); err != nil {
+		return err
+	}
+
+	// t.VerifiedDeal (bool) (bool)
+	if err := cbg.WriteBool(w, t.VerifiedDeal); err != nil {
+		return err
+	}
+
+	// t.Client (address.Address) (struct)
+	if err := t.Client.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Provider (address.Address) (struct)
+	if err := t.Provider.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Label (market.DealLabel) (struct)
+	if err := t.Label.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.StartEpoch (abi.ChainEpoch) (int64)
+	if t.StartEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.StartEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.StartEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.EndEpoch (abi.ChainEpoch) (int64)
+	if t.EndEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.EndEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.EndEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.StoragePricePerEpoch (big.Int) (struct)
+	if err := t.StoragePricePerEpoch.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ProviderCollateral (big.Int) (struct)
+	if err := t.ProviderCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ClientCollateral (big.Int) (struct)
+	if err := t.ClientCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
+	*t = DealProposal{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 11 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.PieceCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.PieceCID: %w", err)
+		}
+
+		t.PieceCID = c
+
+	}
+	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.PieceSize = abi.PaddedPieceSize(extra)
+
+	}
+	// t.VerifiedDeal (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.VerifiedDeal = false
+	case 21:
+		t.VerifiedDeal = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.Client (address.Address) (struct)
+
+	{
+
+		if err := t.Client.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Client: %w", err)
+		}
+
+	}
+	// t.Provider (address.Address) (struct)
+
+	{
+
+		if err := t.Provider.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Provider: %w", err)
+		}
+
+	}
+	// t.Label (market.DealLabel) (struct)
+
+	{
+
+		if err := t.Label.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Label: %w", err)
+		}
+
+	}
+	// t.StartEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
 		if err != nil {
 			return err
@@ -791,3 +2222,303 @@ func (t *ClientDealProposal) UnmarshalCBOR(r io.Reader) error {
 	}
 	return nil
 }
+
+var lengthBufDealExtension = []byte{134}
+
+func (t *DealExtension) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealExtension); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	// t.NewEndEpoch (abi.ChainEpoch) (int64)
+	if t.NewEndEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NewEndEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.NewEndEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.NewStoragePricePerEpoch (big.Int) (struct)
+	if err := t.NewStoragePricePerEpoch.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewProviderCollateral (big.Int) (struct)
+	if err := t.NewProviderCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewClientCollateral (big.Int) (struct)
+	if err := t.NewClientCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealExtension) UnmarshalCBOR(r io.Reader) error {
+	*t = DealExtension{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 6 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	// t.NewEndEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.NewEndEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.NewStoragePricePerEpoch (big.Int) (struct)
+
+	{
+
+		if err := t.NewStoragePricePerEpoch.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewStoragePricePerEpoch: %w", err)
+		}
+
+	}
+	// t.NewProviderCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.NewProviderCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewProviderCollateral: %w", err)
+		}
+
+	}
+	// t.NewClientCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.NewClientCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewClientCollateral: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufClientDealExtension = []byte{130}
+
+func (t *ClientDealExtension) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufClientDealExtension); err != nil {
+		return err
+	}
+
+	// t.Extension (market.DealExtension) (struct)
+	if err := t.Extension.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ClientSignature (crypto.Signature) (struct)
+	if err := t.ClientSignature.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ClientDealExtension) UnmarshalCBOR(r io.Reader) error {
+	*t = ClientDealExtension{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Extension (market.DealExtension) (struct)
+
+	{
+
+		if err := t.Extension.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Extension: %w", err)
+		}
+
+	}
+	// t.ClientSignature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.ClientSignature.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ClientSignature: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDealPayment = []byte{131}
+
+func (t *DealPayment) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealPayment); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	// t.Channel (address.Address) (struct)
+	if err := t.Channel.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Voucher (paych.SignedVoucher) (struct)
+	if err := t.Voucher.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealPayment) UnmarshalCBOR(r io.Reader) error {
+	*t = DealPayment{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	// t.Channel (address.Address) (struct)
+
+	{
+
+		if err := t.Channel.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Channel: %w", err)
+		}
+
+	}
+	// t.Voucher (paych.SignedVoucher) (struct)
+
+	{
+
+		if err := t.Voucher.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Voucher: %w", err)
+		}
+
+	}
+	return nil
+}
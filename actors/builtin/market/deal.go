@@ -15,6 +15,8 @@ import (
 	"github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/paych"
 )
 
 //var PieceCIDPrefix = cid.Prefix{
@@ -213,6 +215,33 @@ type ClientDealProposal struct {
 	ClientSignature acrypto.Signature
 }
 
+// DealPayment records a deal whose storage fee is paid through a payment channel, instead of from the client's escrow.
+type DealPayment struct {
+	DealID abi.DealID
+	// ID address of the channel from the client to the provider.
+	Channel addr.Address
+	// Voucher signed by the client, which the channel redeems only while the deal is active.
+	Voucher paych.SignedVoucher
+}
+
+// DealExtension proposes new terms for an active deal, applying from the epoch at which the extension is made.
+type DealExtension struct {
+	DealID abi.DealID
+	// The sector holding the deal, whose expiration bounds the new end epoch.
+	SectorNumber            abi.SectorNumber
+	NewEndEpoch             abi.ChainEpoch
+	NewStoragePricePerEpoch abi.TokenAmount
+	// Total collateral for the extended deal, which may not be less than the deal's current collateral.
+	NewProviderCollateral abi.TokenAmount
+	NewClientCollateral   abi.TokenAmount
+}
+
+// ClientDealExtension is a DealExtension signed by the deal's client
+type ClientDealExtension struct {
+	Extension       DealExtension
+	ClientSignature acrypto.Signature
+}
+
 func (p *DealProposal) Duration() abi.ChainEpoch {
 	return p.EndEpoch - p.StartEpoch
 }
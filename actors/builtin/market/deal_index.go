@@ -0,0 +1,74 @@
+package market
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// An index of deal IDs by the address of a deal party.
+// This is a SetMultimap keyed by address rather than epoch. An address with no deals has no entry.
+type DealIndex struct {
+	mm *SetMultimap
+}
+
+// Interprets a store as a deal index with root `r`.
+func AsDealIndex(s adt.Store, r cid.Cid, outerBitwidth, innerBitwidth int) (*DealIndex, error) {
+	mm, err := AsSetMultimap(s, r, outerBitwidth, innerBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &DealIndex{mm}, nil
+}
+
+// Creates a new index backed by an empty HAMT and flushes it to the store.
+func MakeEmptyDealIndex(s adt.Store, bitwidth int) (*DealIndex, error) {
+	mm, err := MakeEmptySetMultimap(s, bitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &DealIndex{mm}, nil
+}
+
+// Returns the root cid of the underlying HAMT.
+func (di *DealIndex) Root() (cid.Cid, error) {
+	return di.mm.Root()
+}
+
+// Adds a deal to the set indexed by an address.
+func (di *DealIndex) Put(a addr.Address, dealID abi.DealID) error {
+	return di.mm.putMany(abi.AddrKey(a), []abi.DealID{dealID})
+}
+
+// Adds deals to the set indexed by an address, writing the set once.
+func (di *DealIndex) PutMany(a addr.Address, dealIDs []abi.DealID) error {
+	return di.mm.putMany(abi.AddrKey(a), dealIDs)
+}
+
+// Removes a deal from the set indexed by an address, expecting it to be present.
+func (di *DealIndex) Remove(a addr.Address, dealID abi.DealID) error {
+	return di.mm.remove(abi.AddrKey(a), dealID, true)
+}
+
+// Iterates the deals indexed by an address, iteration halts if the function returns an error.
+func (di *DealIndex) ForEach(a addr.Address, fn func(dealID abi.DealID) error) error {
+	return di.mm.forEach(abi.AddrKey(a), fn)
+}
+
+// Iterates every indexed address and deal, iteration halts if the function returns an error.
+func (di *DealIndex) ForAll(fn func(a addr.Address, dealID abi.DealID) error) error {
+	var setRoot cbg.CborCid
+	return di.mm.mp.ForEach(&setRoot, func(key string) error {
+		a, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return xerrors.Errorf("deal index has key that is not an address: %x: %w", key, err)
+		}
+		return di.mm.forEach(abi.AddrKey(a), func(dealID abi.DealID) error {
+			return fn(a, dealID)
+		})
+	})
+}
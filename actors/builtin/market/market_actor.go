@@ -1,6 +1,7 @@
 package market
 
 import (
+	"bytes"
 	"sort"
 
 	addr "github.com/filecoin-project/go-address"
@@ -19,6 +20,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/paych"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/reward"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
@@ -41,6 +43,17 @@ func (a Actor) Exports() []interface{} {
 		7:                         a.OnMinerSectorsTerminate,
 		8:                         a.ComputeDataCommitment,
 		9:                         a.CronTick,
+		10:                        a.GetActiveDeals,
+		11:                        a.ActivateDealsBatch,
+		12:                        a.CancelPendingDeal,
+		13:                        a.ExtendDeals,
+		14:                        a.GetBalance,
+		15:                        a.GetDealProposal,
+		16:                        a.GetDealState,
+		17:                        a.GetDealActivation,
+		18:                        a.GetDealsByProvider,
+		19:                        a.SettleDealPayments,
+		20:                        a.VerifyDealPayment,
 	}
 }
 
@@ -142,6 +155,15 @@ func (a Actor) AddBalance(rt Runtime, providerOrClientAddress *addr.Address) *ab
 
 type PublishStorageDealsParams struct {
 	Deals []ClientDealProposal
+	// Vouchers through which the storage fees of some of the deals are paid, instead of from the clients' escrow.
+	PaymentVouchers []DealPaymentVoucher
+}
+
+// DealPaymentVoucher pays the storage fee of one of a batch of published deals through a payment channel.
+type DealPaymentVoucher struct {
+	// Index of the deal in the batch.
+	DealIndex uint64
+	Voucher   paych.SignedVoucher
 }
 
 //type PublishStorageDealsReturn struct {
@@ -185,6 +207,14 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		rt.Abortf(exitcode.ErrForbidden, "caller %v is not worker or control address of provider %v", caller, provider)
 	}
 	resolvedAddrs := make(map[addr.Address]addr.Address, len(params.Deals))
+	paymentVouchers := make(map[int]*paych.SignedVoucher, len(params.PaymentVouchers))
+	for i := range params.PaymentVouchers {
+		pv := &params.PaymentVouchers[i]
+		builtin.RequireParam(rt, pv.DealIndex < uint64(len(params.Deals)), "payment voucher for deal index %d out of range", pv.DealIndex)
+		_, duplicate := paymentVouchers[int(pv.DealIndex)]
+		builtin.RequireParam(rt, !duplicate, "more than one payment voucher for deal index %d", pv.DealIndex)
+		paymentVouchers[int(pv.DealIndex)] = &pv.Voucher
+	}
 	baselinePower := requestCurrentBaselinePower(rt)
 	networkRawPower, networkQAPower := requestCurrentNetworkPower(rt)
 
@@ -193,6 +223,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	proposalCidLookup := make(map[cid.Cid]struct{})
 	validProposalCids := make([]cid.Cid, 0)
 	validDeals := make([]ClientDealProposal, 0, len(params.Deals))
+	validPayments := make([]*DealPayment, 0, len(params.Deals))
 	totalClientLockup := make(map[addr.Address]abi.TokenAmount)
 	totalProviderLockup := abi.NewTokenAmount(0)
 
@@ -225,7 +256,13 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		if _, ok := totalClientLockup[client]; !ok {
 			totalClientLockup[client] = abi.NewTokenAmount(0)
 		}
-		totalClientLockup[client] = big.Sum(totalClientLockup[client], deal.Proposal.ClientBalanceRequirement())
+		// The storage fee of a deal paid through a payment channel is not drawn from escrow.
+		voucher, paidByChannel := paymentVouchers[di]
+		clientLockup := deal.Proposal.ClientBalanceRequirement()
+		if paidByChannel {
+			clientLockup = deal.Proposal.ClientCollateral
+		}
+		totalClientLockup[client] = big.Sum(totalClientLockup[client], clientLockup)
 		clientBalanceOk, err := msm.balanceCovered(client, totalClientLockup[client])
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check client balance coverage")
 		if !clientBalanceOk {
@@ -262,6 +299,18 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 			continue
 		}
 
+		/*
+			drop deals paid through a payment channel with an invalid payment voucher
+		*/
+		var payment *DealPayment
+		if paidByChannel {
+			payment, err = validateDealPayment(rt, &deal.Proposal, pcid, voucher)
+			if err != nil {
+				rt.Log(rtt.INFO, "invalid deal %d: %s", di, err)
+				continue
+			}
+		}
+
 		/*
 			check VerifiedClient allowed cap and deduct PieceSize from cap
 			drop deals with a DealSize that cannot be fully covered by VerifiedClient's available DataCap
@@ -287,6 +336,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		proposalCidLookup[pcid] = struct{}{}
 		validProposalCids = append(validProposalCids, pcid)
 		validDeals = append(validDeals, deal)
+		validPayments = append(validPayments, payment)
 		validInputBf.Set(uint64(di))
 	}
 
@@ -302,13 +352,18 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	rt.StateTransaction(&st, func() {
 		msm, err := st.mutator(adt.AsStore(rt)).withPendingProposals(WritePermission).
 			withDealProposals(WritePermission).withDealsByEpoch(WritePermission).withEscrowTable(WritePermission).
-			withLockedTable(WritePermission).build()
+			withLockedTable(WritePermission).withDealIndexes(WritePermission).withDealPayments(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
 		// All storage dealProposals will be added in an atomic transaction; this operation will be unrolled if any of them fails.
 		// This should only fail on programmer error because all expected invalid conditions should be filtered in the first set of checks.
 		for vdi, validDeal := range validDeals {
-			err := msm.lockClientAndProviderBalances(&validDeal.Proposal)
+			payment := validPayments[vdi]
+			storageFee := validDeal.Proposal.TotalStorageFee()
+			if payment != nil {
+				storageFee = big.Zero()
+			}
+			err := msm.lockClientAndProviderBalances(&validDeal.Proposal, storageFee)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to lock balance")
 
 			id := msm.generateStorageDealID()
@@ -319,6 +374,13 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 
 			err = msm.dealProposals.Set(id, &validDeal.Proposal)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal")
+			err = msm.indexDeal(id, &validDeal.Proposal)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to index deal")
+			if payment != nil {
+				payment.DealID = id
+				err = msm.dealPayments.Put(abi.CidKey(pcid), payment)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set payment for deal %d", id)
+			}
 
 			// We randomize the first epoch for when the deal will be processed so an attacker isn't able to
 			// schedule too many deals for the same tick.
@@ -418,6 +480,9 @@ func (a Actor) ActivateDeals(rt Runtime, params *ActivateDealsParams) *abi.Empty
 	minerAddr := rt.Caller()
 	currEpoch := rt.CurrEpoch()
 
+	// Payment channels are queried before the state transaction.
+	paymentFailures := checkDealPaymentsForActivation(rt, params.DealIDs)
+
 	var st State
 	store := adt.AsStore(rt)
 
@@ -452,6 +517,10 @@ func (a Actor) ActivateDeals(rt Runtime, params *ActivateDealsParams) *abi.Empty
 				rt.Abortf(exitcode.ErrIllegalState, "tried to activate deal that was not in the pending set (%s)", propc)
 			}
 
+			if err, failed := paymentFailures[dealID]; failed {
+				rt.Abortf(exitcode.ErrForbidden, "cannot activate deal %d: %s", dealID, err)
+			}
+
 			err = msm.dealStates.Set(dealID, &DealState{
 				SectorStartEpoch: currEpoch,
 				LastUpdatedEpoch: EpochUndefined,
@@ -467,6 +536,83 @@ func (a Actor) ActivateDeals(rt Runtime, params *ActivateDealsParams) *abi.Empty
 	return nil
 }
 
+type ActivateDealsBatchParams struct {
+	Sectors []SectorDeals
+}
+
+// DealActivationOutcome describes whether a deal was activated, or why not.
+type DealActivationOutcome uint64
+
+const (
+	DealActivated DealActivationOutcome = iota
+	// The deal is valid, but was not activated because another deal for the same sector is not.
+	DealActivationSkipped
+	// The deal does not exist.
+	DealActivationNotFound
+	// The deal is listed more than once for the sector, or cannot be activated by the caller in a sector
+	// with the given expiration at the current epoch.
+	DealActivationInvalid
+	// The deal is already activated, possibly in an earlier sector in the same batch.
+	DealActivationAlreadyActive
+	// The deal's proposal is not pending activation.
+	DealActivationNotPending
+)
+
+type DealActivationResult struct {
+	DealID  abi.DealID
+	Outcome DealActivationOutcome
+}
+
+type SectorDealActivation struct {
+	// Whether the sector's deals were activated. Either all of a sector's deals are activated, or none.
+	Activated bool
+	// The weights of the sector's deals, which are zero if they were not activated.
+	DealSpace          uint64
+	DealWeight         abi.DealWeight
+	VerifiedDealWeight abi.DealWeight
+	// One result for each deal, in the order of the sector's deal IDs.
+	Deals []DealActivationResult
+}
+
+type ActivateDealsBatchReturn struct {
+	// One entry for each sector, in the order of the sectors.
+	Sectors []SectorDealActivation
+}
+
+// Activates the deals for a number of sectors being ProveCommitted.
+// Unlike ActivateDeals, invalid deals do not abort the call. A sector with any deal that cannot be activated
+// has none of its deals activated, and the results identify the deals at fault,
+// so that the caller may drop only the affected sectors.
+func (a Actor) ActivateDealsBatch(rt Runtime, params *ActivateDealsBatchParams) *ActivateDealsBatchReturn {
+	rt.ValidateImmediateCallerType(builtin.StorageMinerActorCodeID)
+	minerAddr := rt.Caller()
+	currEpoch := rt.CurrEpoch()
+
+	// Payment channels are queried before the state transaction.
+	var dealIDs []abi.DealID
+	for _, sector := range params.Sectors {
+		dealIDs = append(dealIDs, sector.DealIDs...)
+	}
+	paymentFailures := checkDealPaymentsForActivation(rt, dealIDs)
+
+	results := make([]SectorDealActivation, len(params.Sectors))
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
+			withPendingProposals(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for i, sector := range params.Sectors {
+			results[i] = activateSectorDeals(rt, msm, sector, minerAddr, currEpoch, paymentFailures)
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+
+	return &ActivateDealsBatchReturn{Sectors: results}
+}
+
 //type SectorDataSpec struct {
 //	DealIDs    []abi.DealID
 //	SectorType abi.RegisteredSealProof
@@ -527,7 +673,7 @@ func (a Actor) OnMinerSectorsTerminate(rt Runtime, params *OnMinerSectorsTermina
 	var st State
 	rt.StateTransaction(&st, func() {
 		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
-			withDealProposals(ReadOnlyPermission).build()
+			withDealProposals(ReadOnlyPermission).withDealsByEpoch(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal state")
 
 		for _, dealID := range params.DealIDs {
@@ -568,6 +714,16 @@ func (a Actor) OnMinerSectorsTerminate(rt Runtime, params *OnMinerSectorsTermina
 
 			err = msm.dealStates.Set(dealID, state)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal state %v", dealID)
+
+			// A deal that has been processed since activation is scheduled for its expiry.
+			// Unless it's due to expire anyway, bring its processing forward so the slashing isn't delayed.
+			// A deal yet to be processed remains scheduled shortly after its start.
+			if state.LastUpdatedEpoch != EpochUndefined && deal.EndEpoch > rt.CurrEpoch() {
+				err = msm.dealsByEpoch.Remove(GenRandNextEpoch(deal.EndEpoch, dealID), dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unschedule deal %d", dealID)
+				err = msm.dealsByEpoch.Put(GenRandNextEpoch(rt.CurrEpoch()+1, dealID), dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to reschedule deal %d", dealID)
+			}
 		}
 
 		err = msm.commitState()
@@ -576,6 +732,507 @@ func (a Actor) OnMinerSectorsTerminate(rt Runtime, params *OnMinerSectorsTermina
 	return nil
 }
 
+type GetActiveDealsParams struct {
+	DealIDs []abi.DealID
+}
+
+type GetActiveDealsReturn struct {
+	// The requested deals which are active, in the order requested.
+	DealIDs []abi.DealID
+}
+
+// Returns which of a set of deals are active: activated in a sector, not terminated, and not yet past their end epoch.
+// Deals that are unknown, not yet activated, or that have expired or been terminated are not active,
+// even if the market has not yet processed their removal.
+func (a Actor) GetActiveDeals(rt Runtime, params *GetActiveDealsParams) *GetActiveDealsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	proposals, err := AsDealProposalArray(store, st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	states, err := AsDealStateArray(store, st.States)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal states")
+
+	active := []abi.DealID{}
+	for _, dealID := range params.DealIDs {
+		proposal, found, err := proposals.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", dealID)
+		if !found || rt.CurrEpoch() >= proposal.EndEpoch {
+			continue
+		}
+		state, found, err := states.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal state %d", dealID)
+		if !found || state.SlashEpoch != EpochUndefined {
+			continue
+		}
+		active = append(active, dealID)
+	}
+	return &GetActiveDealsReturn{DealIDs: active}
+}
+
+type GetBalanceReturn struct {
+	Balance abi.TokenAmount // Total escrow balance, including locked funds.
+	Locked  abi.TokenAmount // Funds locked as collateral or for storage fees.
+}
+
+// Returns the escrow and locked balances held by the market for a client or provider.
+func (a Actor) GetBalance(rt Runtime, account *addr.Address) *GetBalanceReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	nominal, ok := rt.ResolveAddress(*account)
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalArgument, "failed to resolve address %v", *account)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	escrowTable, err := adt.AsBalanceTable(store, st.EscrowTable)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load escrow table")
+	lockedTable, err := adt.AsBalanceTable(store, st.LockedTable)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load locked table")
+
+	balance, err := escrowTable.Get(nominal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get escrow balance for %v", nominal)
+	locked, err := lockedTable.Get(nominal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get locked balance for %v", nominal)
+	return &GetBalanceReturn{
+		Balance: balance,
+		Locked:  locked,
+	}
+}
+
+type DealQueryParams struct {
+	DealID abi.DealID
+}
+
+// Returns the proposal for a deal. Deals that have expired or been terminated are removed once processed by cron,
+// after which they are not found.
+func (a Actor) GetDealProposal(rt Runtime, params *DealQueryParams) *DealProposal {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	proposals, err := AsDealProposalArray(adt.AsStore(rt), st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	proposal, found, err := proposals.Get(params.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", params.DealID)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no such deal %d", params.DealID)
+	}
+	return proposal
+}
+
+// Returns the state of an activated deal. Deals that are unknown or not yet activated are not found.
+func (a Actor) GetDealState(rt Runtime, params *DealQueryParams) *DealState {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	states, err := AsDealStateArray(adt.AsStore(rt), st.States)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal states")
+	state, found, err := states.Get(params.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal state %d", params.DealID)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no activated deal %d", params.DealID)
+	}
+	return state
+}
+
+type GetDealActivationReturn struct {
+	// Epoch at which the deal was activated, or -1 if not yet activated.
+	Activated abi.ChainEpoch
+	// Epoch at which the deal was terminated, or -1 if not terminated.
+	Terminated abi.ChainEpoch
+}
+
+// Returns the activation and termination epochs of a published deal.
+// Deals that are unknown, or that have been removed after expiry or termination, are not found.
+func (a Actor) GetDealActivation(rt Runtime, params *DealQueryParams) *GetDealActivationReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+	proposals, err := AsDealProposalArray(store, st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	states, err := AsDealStateArray(store, st.States)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal states")
+
+	_, found, err := proposals.Get(params.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", params.DealID)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no such deal %d", params.DealID)
+	}
+	// A deal without state has not been activated, and the returned state's epochs are undefined.
+	state, _, err := states.Get(params.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal state %d", params.DealID)
+	return &GetDealActivationReturn{
+		Activated:  state.SectorStartEpoch,
+		Terminated: state.SlashEpoch,
+	}
+}
+
+type GetDealsByProviderParams struct {
+	Provider addr.Address
+}
+
+type GetDealsByProviderReturn struct {
+	// IDs of the provider's deals which have not yet been removed, in increasing order.
+	DealIDs []abi.DealID
+}
+
+// Returns the IDs of all deals with a provider, whether pending, active, or awaiting removal.
+func (a Actor) GetDealsByProvider(rt Runtime, params *GetDealsByProviderParams) *GetDealsByProviderReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	provider, ok := rt.ResolveAddress(params.Provider)
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalArgument, "failed to resolve provider address %v", params.Provider)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	providerDeals, err := AsDealIndex(adt.AsStore(rt), st.ProviderDeals, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load provider deal index")
+
+	dealIDs := []abi.DealID{}
+	err = providerDeals.ForEach(provider, func(dealID abi.DealID) error {
+		dealIDs = append(dealIDs, dealID)
+		return nil
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deals for provider %v", provider)
+	sort.Slice(dealIDs, func(i, j int) bool {
+		return dealIDs[i] < dealIDs[j]
+	})
+	return &GetDealsByProviderReturn{DealIDs: dealIDs}
+}
+
+type CancelPendingDealParams struct {
+	DealID abi.DealID
+}
+
+// Cancels a published deal that has not yet been activated in a sector, at the request of its client or provider.
+// The funds locked for the deal are unlocked, and the cancelling party pays a fee from its locked funds to the other party.
+// A deal may be cancelled only before its start epoch, after which it is timed out by cron if not activated.
+func (a Actor) CancelPendingDeal(rt Runtime, params *CancelPendingDealParams) *abi.EmptyValue {
+	var st State
+	rt.StateReadonly(&st)
+	proposals, err := AsDealProposalArray(adt.AsStore(rt), st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	proposal, found, err := proposals.Get(params.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", params.DealID)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no such deal %d", params.DealID)
+	}
+
+	// Deal parties are stored as ID addresses.
+	cancelledByClient := rt.Caller() == proposal.Client
+	if cancelledByClient {
+		rt.ValidateImmediateCallerIs(proposal.Client)
+	} else {
+		owner, worker, controllers := builtin.RequestMinerControlAddrs(rt, proposal.Provider)
+		rt.ValidateImmediateCallerIs(append(controllers, owner, worker)...)
+	}
+
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(ReadOnlyPermission).
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).withDealsByEpoch(WritePermission).
+			withEscrowTable(WritePermission).withLockedTable(WritePermission).withDealIndexes(WritePermission).
+			withDealPayments(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		_, found, err := msm.dealStates.Get(params.DealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for deal %d", params.DealID)
+		if found {
+			rt.Abortf(exitcode.ErrForbidden, "deal %d is already activated", params.DealID)
+		}
+		if rt.CurrEpoch() >= proposal.StartEpoch {
+			rt.Abortf(exitcode.ErrForbidden, "deal %d start epoch %d has already elapsed", params.DealID, proposal.StartEpoch)
+		}
+
+		storageFee, err := msm.escrowStorageFee(proposal)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine escrow storage fee of deal %d", params.DealID)
+		err = msm.unlockBalance(proposal.Client, storageFee, ClientStorageFee)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unlock client storage fee")
+		err = msm.unlockBalance(proposal.Client, proposal.ClientCollateral, ClientCollateral)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unlock client collateral")
+		err = msm.unlockBalance(proposal.Provider, proposal.ProviderCollateral, ProviderCollateral)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unlock provider collateral")
+
+		payer, payee := proposal.Provider, proposal.Client
+		fee := DealCancellationFee(proposal.ProviderBalanceRequirement())
+		if cancelledByClient {
+			payer, payee = proposal.Client, proposal.Provider
+			fee = DealCancellationFee(big.Add(proposal.ClientCollateral, storageFee))
+		}
+		err = msm.escrowTable.MustSubtract(payer, fee)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to deduct cancellation fee from %v", payer)
+		err = msm.escrowTable.Add(payee, fee)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to pay cancellation fee to %v", payee)
+
+		pcid, err := proposal.Cid()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate CID for proposal %d", params.DealID)
+		err = msm.pendingDeals.Delete(abi.CidKey(pcid))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pending proposal %d (%v)", params.DealID, pcid)
+		err = msm.dealProposals.Delete(params.DealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal %d", params.DealID)
+		err = msm.unindexDeal(params.DealID, proposal)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unindex deal proposal %d", params.DealID)
+		err = msm.removeDealPayment(pcid)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove payment for deal %d", params.DealID)
+		// The deal is scheduled for its first processing, which is no earlier than its start epoch.
+		err = msm.dealsByEpoch.Remove(GenRandNextEpoch(proposal.StartEpoch, params.DealID), params.DealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove deal ops for deal %d", params.DealID)
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+
+	if proposal.VerifiedDeal {
+		code := rt.Send(
+			builtin.VerifiedRegistryActorAddr,
+			builtin.MethodsVerifiedRegistry.RestoreBytes,
+			&verifreg.RestoreBytesParams{
+				Address:  proposal.Client,
+				DealSize: big.NewIntUnsigned(uint64(proposal.PieceSize)),
+			},
+			abi.NewTokenAmount(0),
+			&builtin.Discard{},
+		)
+		builtin.RequireSuccess(rt, code, "failed to restore data cap for cancelled verified deal %d", params.DealID)
+	}
+	return nil
+}
+
+type ExtendDealsParams struct {
+	Extensions []ClientDealExtension
+}
+
+// Extends active deals to new end epochs, with new prices and collateral, as agreed by both parties.
+// The client agrees by signing each extension, and the provider by sending the message from its worker or a control address.
+// Payment up to the current epoch is made at the deal's existing price, and the new price applies from the current epoch.
+// Additional client funds and provider collateral required by the new terms are locked, and the deal's expiry
+// is rescheduled for its new end epoch.
+// The sector holding each deal must be active, and the deal may not be extended beyond its expiration.
+func (a Actor) ExtendDeals(rt Runtime, params *ExtendDealsParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	builtin.RequireParam(rt, len(params.Extensions) > 0, "no deal extensions")
+	builtin.RequireParam(rt, len(params.Extensions) <= DealExtensionsMax, "too many deal extensions %d > %d",
+		len(params.Extensions), DealExtensionsMax)
+	currEpoch := rt.CurrEpoch()
+
+	var st State
+	rt.StateReadonly(&st)
+	proposals, err := AsDealProposalArray(adt.AsStore(rt), st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+
+	// All deals must have the same provider, for which the caller must be authorized.
+	var provider addr.Address
+	seenDealIDs := make(map[abi.DealID]struct{}, len(params.Extensions))
+	for i, ext := range params.Extensions {
+		dealID := ext.Extension.DealID
+		if _, seen := seenDealIDs[dealID]; seen {
+			rt.Abortf(exitcode.ErrIllegalArgument, "deal %d extended more than once", dealID)
+		}
+		seenDealIDs[dealID] = struct{}{}
+
+		proposal, found, err := proposals.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", dealID)
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "no such deal %d", dealID)
+		}
+		if i == 0 {
+			provider = proposal.Provider
+		} else if proposal.Provider != provider {
+			rt.Abortf(exitcode.ErrIllegalArgument, "cannot extend deals from multiple providers in one batch")
+		}
+
+		err = dealExtensionIsSigned(rt, &ext, proposal.Client)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid extension for deal %d", dealID)
+	}
+
+	validateCallerIsProviderControl(rt, provider)
+
+	// The sector holding each deal bounds its extension.
+	// A terminated sector's info remains with the miner until compacted, and the market may not yet have been
+	// told of its termination, so the sector must also be active.
+	sectors := make(map[abi.SectorNumber]*builtin.MinerSectorInfo)
+	for _, ext := range params.Extensions {
+		sectorNo := ext.Extension.SectorNumber
+		sector, ok := sectors[sectorNo]
+		if !ok {
+			sector, ok = builtin.RequestMinerSectorInfo(rt, provider, sectorNo)
+			if !ok {
+				rt.Abortf(exitcode.ErrNotFound, "provider %v has no sector %d", provider, sectorNo)
+			}
+			if !builtin.RequestMinerSectorActive(rt, provider, sectorNo) {
+				rt.Abortf(exitcode.ErrForbidden, "sector %d of provider %v is not active", sectorNo, provider)
+			}
+			sectors[sectorNo] = sector
+		}
+		sectorHasDeal := false
+		for _, dealID := range sector.DealIDs {
+			sectorHasDeal = sectorHasDeal || dealID == ext.Extension.DealID
+		}
+		if !sectorHasDeal {
+			rt.Abortf(exitcode.ErrIllegalArgument, "sector %d does not hold deal %d", sectorNo, ext.Extension.DealID)
+		}
+	}
+
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealProposals(WritePermission).withDealStates(WritePermission).
+			withDealsByEpoch(WritePermission).withEscrowTable(WritePermission).withLockedTable(WritePermission).
+			withDealPayments(ReadOnlyPermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for _, ext := range params.Extensions {
+			msm.extendDeal(rt, &ext.Extension, sectors[ext.Extension.SectorNumber].Expiration, currEpoch)
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return nil
+}
+
+type SettleDealPaymentsParams struct {
+	DealIDs []abi.DealID
+}
+
+type SettleDealPaymentsReturn struct {
+	// The amount paid to the provider for each deal, in the order requested.
+	Payments []abi.TokenAmount
+}
+
+// Pays a provider for the storage of its active deals since each was last updated, up to the current epoch
+// or the deal's end. Cron processes an active deal only when it's first due after activation, when it's terminated
+// and when it expires, so a provider settles payments in between on demand.
+// All deals must have the same provider, and the caller must be its worker or a control address.
+// Deals which are not yet active, have not been processed since activation, or have been terminated are
+// paid nothing, and their payments are left to cron.
+func (a Actor) SettleDealPayments(rt Runtime, params *SettleDealPaymentsParams) *SettleDealPaymentsReturn {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	builtin.RequireParam(rt, len(params.DealIDs) > 0, "no deals to settle")
+	builtin.RequireParam(rt, len(params.DealIDs) <= DealSettlementsMax, "too many deals to settle %d > %d",
+		len(params.DealIDs), DealSettlementsMax)
+	currEpoch := rt.CurrEpoch()
+
+	var st State
+	rt.StateReadonly(&st)
+	proposals, err := AsDealProposalArray(adt.AsStore(rt), st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+
+	// All deals must have the same provider, for which the caller must be authorized.
+	var provider addr.Address
+	seenDealIDs := make(map[abi.DealID]struct{}, len(params.DealIDs))
+	for i, dealID := range params.DealIDs {
+		if _, seen := seenDealIDs[dealID]; seen {
+			rt.Abortf(exitcode.ErrIllegalArgument, "deal %d settled more than once", dealID)
+		}
+		seenDealIDs[dealID] = struct{}{}
+
+		proposal, found, err := proposals.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", dealID)
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "no such deal %d", dealID)
+		}
+		if i == 0 {
+			provider = proposal.Provider
+		} else if proposal.Provider != provider {
+			rt.Abortf(exitcode.ErrIllegalArgument, "cannot settle deals from multiple providers in one batch")
+		}
+	}
+	validateCallerIsProviderControl(rt, provider)
+
+	payments := make([]abi.TokenAmount, len(params.DealIDs))
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealProposals(ReadOnlyPermission).withDealStates(WritePermission).
+			withEscrowTable(WritePermission).withLockedTable(WritePermission).withDealPayments(ReadOnlyPermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for i, dealID := range params.DealIDs {
+			payments[i] = big.Zero()
+			deal, err := getDealProposal(msm.dealProposals, dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+			state, found, err := msm.dealStates.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for deal %d", dealID)
+			if !found {
+				rt.Log(rtt.INFO, "deal %d is not active, not settling", dealID)
+				continue
+			}
+			// Until first processed, a deal's proposal remains pending and its payment is made by cron.
+			if state.LastUpdatedEpoch == EpochUndefined {
+				rt.Log(rtt.INFO, "deal %d has not yet been processed since activation, not settling", dealID)
+				continue
+			}
+			// A terminated deal's payment is made by cron as it is slashed.
+			if state.SlashEpoch != EpochUndefined {
+				rt.Log(rtt.INFO, "deal %d was terminated at %d, not settling", dealID, state.SlashEpoch)
+				continue
+			}
+
+			payments[i] = msm.payDeal(rt, deal, state, currEpoch)
+			err = msm.dealStates.Set(dealID, state)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set state for deal %d", dealID)
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return &SettleDealPaymentsReturn{Payments: payments}
+}
+
+type VerifyDealPaymentParams struct {
+	// CID of the deal's proposal, with client and provider as ID addresses.
+	Proposal cid.Cid `checked:"true"` // only used as a key, never loaded
+}
+
+// Confirms to a payment channel that a voucher paying the storage fee of a deal may be redeemed, aborting otherwise.
+// A payment voucher names this method in its Extra, with the deal's proposal CID as parameters, so the channel redeems
+// it only while the deal is active: activated in a sector, not terminated, and not yet past its end epoch.
+// The provider must redeem the voucher before the deal ends.
+func (a Actor) VerifyDealPayment(rt Runtime, params *VerifyDealPaymentParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.PaymentChannelActorCodeID)
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	payments, err := adt.AsMap(store, st.DealPayments, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal payments")
+	var payment DealPayment
+	found, err := payments.Get(abi.CidKey(params.Proposal), &payment)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get payment for proposal %v", params.Proposal)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no deal paid through a payment channel with proposal %v", params.Proposal)
+	}
+	if rt.Caller() != payment.Channel {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d is paid through channel %v, not %v", payment.DealID, payment.Channel, rt.Caller())
+	}
+
+	proposals, err := AsDealProposalArray(store, st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	proposal, err := getDealProposal(proposals, payment.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", payment.DealID)
+	states, err := AsDealStateArray(store, st.States)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal states")
+	state, found, err := states.Get(payment.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for deal %d", payment.DealID)
+	if !found {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d is not activated", payment.DealID)
+	}
+	if state.SlashEpoch != EpochUndefined {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d was terminated at %d", payment.DealID, state.SlashEpoch)
+	}
+	if rt.CurrEpoch() >= proposal.EndEpoch {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d ended at %d", payment.DealID, proposal.EndEpoch)
+	}
+	return nil
+}
+
 func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.CronActorAddr)
 	amountSlashed := big.Zero()
@@ -588,11 +1245,20 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
 			withLockedTable(WritePermission).withEscrowTable(WritePermission).withDealsByEpoch(WritePermission).
-			withDealProposals(WritePermission).withPendingProposals(WritePermission).build()
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).withDealIndexes(WritePermission).
+			withDealPayments(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
+		// Deal ops are processed in increasing epoch order, up to a limit per tick.
+		// Ops are removed from their epoch as they are taken, so an epoch that is only partially
+		// processed retains just its unprocessed ops, which are carried to subsequent ticks.
+		dealOpsProcessed := uint64(0)
 		for i := st.LastCron + 1; i <= rt.CurrEpoch(); i++ {
-			err = msm.dealsByEpoch.ForEach(i, func(dealID abi.DealID) error {
+			dealIDs, more, err := msm.dealsByEpoch.PopMany(i, CronDealOpsMax-dealOpsProcessed)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to take deal ops for epoch %v", i)
+			dealOpsProcessed += uint64(len(dealIDs))
+
+			for _, dealID := range dealIDs {
 				deal, err := getDealProposal(msm.dealProposals, dealID)
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get dealId %d", dealID)
 
@@ -619,10 +1285,14 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					// Delete the proposal (but not state, which doesn't exist).
 					err = msm.dealProposals.Delete(dealID)
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal %d", dealID)
+					err = msm.unindexDeal(dealID, deal)
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unindex deal proposal %d", dealID)
+					err = msm.removeDealPayment(dcid)
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove payment for deal %d", dealID)
 
 					err = msm.pendingDeals.Delete(abi.CidKey(dcid))
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pending proposal %d (%v)", dealID, dcid)
-					return nil
+					continue
 				}
 
 				// if this is the first cron tick for the deal, it should be in the pending state.
@@ -631,7 +1301,7 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					builtin.RequireNoErr(rt, pdErr, exitcode.ErrIllegalState, "failed to delete pending proposal %v", dcid)
 				}
 
-				slashAmount, nextEpoch, removeDeal := msm.updatePendingDealState(rt, state, deal, rt.CurrEpoch())
+				slashAmount, nextEpoch, removeDeal := msm.updatePendingDealState(rt, dealID, state, deal, rt.CurrEpoch())
 				builtin.RequireState(rt, slashAmount.GreaterThanEqual(big.Zero()), "computed negative slash amount %v for deal %d", slashAmount, dealID)
 
 				if removeDeal {
@@ -643,6 +1313,10 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal state %d", dealID)
 					err = msm.dealProposals.Delete(dealID)
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal %d", dealID)
+					err = msm.unindexDeal(dealID, deal)
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unindex deal proposal %d", dealID)
+					err = msm.removeDealPayment(dcid)
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove payment for deal %d", dealID)
 				} else {
 					builtin.RequireState(rt, nextEpoch > rt.CurrEpoch(), "continuing deal %d next epoch %d should be in future", dealID, nextEpoch)
 					builtin.RequireState(rt, slashAmount.IsZero(), "continuing deal %d should not be slashed", dealID)
@@ -654,13 +1328,12 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 					updatesNeeded[nextEpoch] = append(updatesNeeded[nextEpoch], dealID)
 				}
+			}
 
-				return nil
-			})
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deal ops")
-
-			err = msm.dealsByEpoch.RemoveAll(i)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal ops for epoch %v", i)
+			if more {
+				break
+			}
+			st.LastCron = i
 		}
 
 		// Iterate changes in sorted order to ensure that loads/stores
@@ -678,8 +1351,6 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to reinsert deal IDs for epoch %v", epoch)
 		}
 
-		st.LastCron = rt.CurrEpoch()
-
 		err = msm.commitState()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
 	})
@@ -792,6 +1463,95 @@ func validateDealCanActivate(proposal *DealProposal, minerAddr addr.Address, sec
 	return nil
 }
 
+// Activates all of a sector's deals if every one of them can be activated, otherwise activates none.
+func activateSectorDeals(rt Runtime, msm *marketStateMutation, sector SectorDeals, minerAddr addr.Address, currEpoch abi.ChainEpoch,
+	paymentFailures map[abi.DealID]error) SectorDealActivation {
+	result := SectorDealActivation{
+		Activated:          true,
+		DealWeight:         big.Zero(),
+		VerifiedDealWeight: big.Zero(),
+		Deals:              make([]DealActivationResult, len(sector.DealIDs)),
+	}
+	seenDealIDs := make(map[abi.DealID]struct{}, len(sector.DealIDs))
+	for i, dealID := range sector.DealIDs {
+		outcome := checkDealActivation(rt, msm, dealID, seenDealIDs, minerAddr, sector.SectorExpiry, currEpoch, paymentFailures)
+		seenDealIDs[dealID] = struct{}{}
+		result.Deals[i] = DealActivationResult{DealID: dealID, Outcome: outcome}
+		result.Activated = result.Activated && outcome == DealActivated
+	}
+
+	if !result.Activated {
+		for i := range result.Deals {
+			if result.Deals[i].Outcome == DealActivated {
+				result.Deals[i].Outcome = DealActivationSkipped
+			}
+		}
+		return result
+	}
+
+	for _, dealID := range sector.DealIDs {
+		proposal, err := getDealProposal(msm.dealProposals, dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get dealId %d", dealID)
+
+		result.DealSpace += uint64(proposal.PieceSize)
+		if proposal.VerifiedDeal {
+			result.VerifiedDealWeight = big.Add(result.VerifiedDealWeight, DealWeight(proposal))
+		} else {
+			result.DealWeight = big.Add(result.DealWeight, DealWeight(proposal))
+		}
+
+		err = msm.dealStates.Set(dealID, &DealState{
+			SectorStartEpoch: currEpoch,
+			LastUpdatedEpoch: EpochUndefined,
+			SlashEpoch:       EpochUndefined,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal state %d", dealID)
+	}
+	return result
+}
+
+// Checks whether a deal can be activated in a sector, without modifying state.
+// Deals whose payment channels failed checks for activation are invalid.
+func checkDealActivation(rt Runtime, msm *marketStateMutation, dealID abi.DealID, seenDealIDs map[abi.DealID]struct{},
+	minerAddr addr.Address, sectorExpiry, currEpoch abi.ChainEpoch, paymentFailures map[abi.DealID]error) DealActivationOutcome {
+	if _, seen := seenDealIDs[dealID]; seen {
+		rt.Log(rtt.INFO, "deal %d present multiple times", dealID)
+		return DealActivationInvalid
+	}
+
+	proposal, found, err := msm.dealProposals.Get(dealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal %d", dealID)
+	if !found {
+		rt.Log(rtt.INFO, "no such deal %d", dealID)
+		return DealActivationNotFound
+	}
+	if err = validateDealCanActivate(proposal, minerAddr, sectorExpiry, currEpoch); err != nil {
+		rt.Log(rtt.INFO, "cannot activate deal %d: %s", dealID, err)
+		return DealActivationInvalid
+	}
+
+	_, found, err = msm.dealStates.Get(dealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for dealId %d", dealID)
+	if found {
+		rt.Log(rtt.INFO, "deal %d already included in another sector", dealID)
+		return DealActivationAlreadyActive
+	}
+
+	propc, err := proposal.Cid()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate proposal CID")
+	has, err := msm.pendingDeals.Has(abi.CidKey(propc))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get pending proposal %v", propc)
+	if !has {
+		rt.Log(rtt.INFO, "deal %d is not in the pending set (%s)", dealID, propc)
+		return DealActivationNotPending
+	}
+	if err, failed := paymentFailures[dealID]; failed {
+		rt.Log(rtt.INFO, "cannot activate deal %d: %s", dealID, err)
+		return DealActivationInvalid
+	}
+	return DealActivated
+}
+
 func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQAPower, baselinePower abi.StoragePower) error {
 	if err := dealProposalIsInternallyValid(rt, deal); err != nil {
 		return xerrors.Errorf("Invalid deal proposal %w", err)
@@ -846,6 +1606,111 @@ func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQ
 	return nil
 }
 
+// Checks a voucher paying the storage fee of a deal through a payment channel, and returns the deal's payment.
+// The voucher must be signed by the client on a channel from the client to the provider, and be redeemable for at least
+// the deal's total storage fee from the channel's unredeemed funds throughout the deal's term.
+// Its Extra must name VerifyDealPayment with the deal's proposal CID, so that the channel redeems it only while the deal
+// is active, and it may not be conditional on a secret or merge other lanes.
+func validateDealPayment(rt Runtime, proposal *DealProposal, pcid cid.Cid, sv *paych.SignedVoucher) (*DealPayment, error) {
+	channel, ok := rt.ResolveAddress(sv.ChannelAddr)
+	if !ok {
+		return nil, xerrors.Errorf("failed to resolve payment channel address %v", sv.ChannelAddr)
+	}
+	codeID, ok := rt.GetActorCodeCID(channel)
+	if !ok || !codeID.Equals(builtin.PaymentChannelActorCodeID) {
+		return nil, xerrors.Errorf("payment channel %v is not a payment channel actor", channel)
+	}
+
+	if sv.TimeLockMin > proposal.StartEpoch || (sv.TimeLockMax != 0 && sv.TimeLockMax < proposal.EndEpoch) {
+		return nil, xerrors.Errorf("payment voucher time lock [%d, %d] does not span deal term [%d, %d]",
+			sv.TimeLockMin, sv.TimeLockMax, proposal.StartEpoch, proposal.EndEpoch)
+	}
+	if len(sv.SecretHash) > 0 || len(sv.Merges) > 0 {
+		return nil, xerrors.Errorf("payment voucher is conditional on a secret or merges other lanes")
+	}
+	buf := bytes.Buffer{}
+	if err := (&VerifyDealPaymentParams{Proposal: pcid}).MarshalCBOR(&buf); err != nil {
+		return nil, xerrors.Errorf("failed to marshal payment verification params: %w", err)
+	}
+	if sv.Extra == nil || sv.Extra.Actor != builtin.StorageMarketActorAddr ||
+		sv.Extra.Method != builtin.MethodsMarket.VerifyDealPayment || !bytes.Equal(sv.Extra.Data, buf.Bytes()) {
+		return nil, xerrors.Errorf("payment voucher is not conditional on activation of deal with proposal %v", pcid)
+	}
+	if err := dealPaymentVoucherIsSigned(rt, sv, proposal.Client); err != nil {
+		return nil, err
+	}
+
+	payment := &DealPayment{Channel: channel, Voucher: *sv}
+	if err := checkDealPaymentFunded(rt, payment, proposal); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// Checks that a deal's payment channel is from the client to the provider and not settling, and that the channel's
+// unredeemed funds cover the deal's voucher, which must redeem at least the deal's total storage fee.
+// The funds are not reserved for the voucher, so may yet be redeemed by vouchers on other lanes.
+func checkDealPaymentFunded(rt Runtime, payment *DealPayment, proposal *DealProposal) error {
+	sv := &payment.Voucher
+	var lane paych.GetLaneReturn
+	code := rt.Send(payment.Channel, builtin.MethodsPaych.GetLane, &paych.GetLaneParams{Lane: sv.Lane}, big.Zero(), &lane)
+	if !code.IsSuccess() {
+		return xerrors.Errorf("failed to get lane %d of payment channel %v: exit code %d", sv.Lane, payment.Channel, code)
+	}
+	if lane.From != proposal.Client || lane.To != proposal.Provider {
+		return xerrors.Errorf("payment channel %v is from %v to %v, not from client %v to provider %v",
+			payment.Channel, lane.From, lane.To, proposal.Client, proposal.Provider)
+	}
+	if lane.SettlingAt != 0 {
+		return xerrors.Errorf("payment channel %v is settling at %d", payment.Channel, lane.SettlingAt)
+	}
+	if sv.Nonce <= lane.Lane.Nonce {
+		return xerrors.Errorf("payment voucher nonce %d is not greater than lane %d nonce %d", sv.Nonce, sv.Lane, lane.Lane.Nonce)
+	}
+	redeemable := big.Sub(sv.Amount, lane.Lane.Redeemed)
+	if redeemable.LessThan(proposal.TotalStorageFee()) {
+		return xerrors.Errorf("payment voucher redeems %v, less than storage fee %v", redeemable, proposal.TotalStorageFee())
+	}
+	if lane.Available.LessThan(redeemable) {
+		return xerrors.Errorf("payment channel %v has %v unredeemed, less than voucher redeems %v", payment.Channel, lane.Available, redeemable)
+	}
+	return nil
+}
+
+// Checks that the payment channels of deals to be activated still fund their vouchers, and returns the error for each
+// deal that is not funded. Deals paid from escrow, and deals that are not found, are not checked.
+// The channels are queried, so this must not be called in a state transaction.
+func checkDealPaymentsForActivation(rt Runtime, dealIDs []abi.DealID) map[abi.DealID]error {
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+	proposals, err := AsDealProposalArray(store, st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	payments, err := adt.AsMap(store, st.DealPayments, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal payments")
+
+	failures := make(map[abi.DealID]error)
+	for _, dealID := range dealIDs {
+		proposal, found, err := proposals.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", dealID)
+		if !found {
+			continue
+		}
+		pcid, err := proposal.Cid()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate proposal CID")
+		var payment DealPayment
+		found, err = payments.Get(abi.CidKey(pcid), &payment)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get payment for deal %d", dealID)
+		if !found {
+			continue
+		}
+		if err := checkDealPaymentFunded(rt, &payment, proposal); err != nil {
+			failures[dealID] = err
+		}
+	}
+	return failures
+}
+
 //
 // Helpers
 //
@@ -873,6 +1738,19 @@ func escrowAddress(rt Runtime, address addr.Address) (nominal addr.Address, reci
 	return nominal, nominal, []addr.Address{nominal}
 }
 
+// Aborts unless the immediate caller is the worker or a control address of a provider.
+func validateCallerIsProviderControl(rt Runtime, provider addr.Address) {
+	caller := rt.Caller()
+	_, worker, controllers := builtin.RequestMinerControlAddrs(rt, provider)
+	callerOk := caller == worker
+	for _, controller := range controllers {
+		callerOk = callerOk || caller == controller
+	}
+	if !callerOk {
+		rt.Abortf(exitcode.ErrForbidden, "caller %v is not worker or control address of provider %v", caller, provider)
+	}
+}
+
 func getDealProposal(proposals *DealArray, dealID abi.DealID) (*DealProposal, error) {
 	proposal, found, err := proposals.Get(dealID)
 	if err != nil {
@@ -8,8 +8,10 @@ import (
 	"golang.org/x/xerrors"
 )
 
-func (m *marketStateMutation) lockClientAndProviderBalances(proposal *DealProposal) error {
-	if err := m.maybeLockBalance(proposal.Client, proposal.ClientBalanceRequirement()); err != nil {
+// Locks the collateral of both parties to a deal, and the storage fee paid from the client's escrow,
+// which is zero for a deal paid through a payment channel.
+func (m *marketStateMutation) lockClientAndProviderBalances(proposal *DealProposal, storageFee abi.TokenAmount) error {
+	if err := m.maybeLockBalance(proposal.Client, big.Add(proposal.ClientCollateral, storageFee)); err != nil {
 		return xerrors.Errorf("failed to lock client funds: %w", err)
 	}
 	if err := m.maybeLockBalance(proposal.Provider, proposal.ProviderCollateral); err != nil {
@@ -17,7 +19,7 @@ func (m *marketStateMutation) lockClientAndProviderBalances(proposal *DealPropos
 	}
 
 	m.totalClientLockedCollateral = big.Add(m.totalClientLockedCollateral, proposal.ClientCollateral)
-	m.totalClientStorageFee = big.Add(m.totalClientStorageFee, proposal.TotalStorageFee())
+	m.totalClientStorageFee = big.Add(m.totalClientStorageFee, storageFee)
 	m.totalProviderLockedCollateral = big.Add(m.totalProviderLockedCollateral, proposal.ProviderCollateral)
 	return nil
 }
@@ -3,6 +3,7 @@ package market
 import (
 	"bytes"
 
+	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
@@ -10,6 +11,7 @@ import (
 	xerrors "golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/paych"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 )
 
@@ -52,7 +54,8 @@ type State struct {
 
 	// Metadata cached for efficient iteration over deals.
 	DealOpsByEpoch cid.Cid // SetMultimap, HAMT[epoch]Set
-	LastCron       abi.ChainEpoch
+	// The last epoch for which all deal ops have been processed by cron.
+	LastCron abi.ChainEpoch
 
 	// Total Client Collateral that is locked -> unlocked when deal is terminated
 	TotalClientLockedCollateral abi.TokenAmount
@@ -60,6 +63,16 @@ type State struct {
 	TotalProviderLockedCollateral abi.TokenAmount
 	// Total storage fee that is locked in escrow -> unlocked when payments are made
 	TotalClientStorageFee abi.TokenAmount
+
+	// Deals in Proposals indexed by their provider and client addresses.
+	// Invariant: each index holds exactly keys(Proposals), each under its proposal's provider or client.
+	ProviderDeals cid.Cid // DealIndex, HAMT[address]Set
+	ClientDeals   cid.Cid // DealIndex, HAMT[address]Set
+
+	// Deals whose storage fees are paid through a payment channel rather than from the client's escrow,
+	// keyed by the CID of the deal's proposal.
+	// Invariant: each key is the CID of the proposal in Proposals of the entry's deal.
+	DealPayments cid.Cid // HAMT[ProposalCid]DealPayment
 }
 
 func ConstructState(store adt.Store) (*State, error) {
@@ -84,6 +97,14 @@ func ConstructState(store adt.Store) (*State, error) {
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create empty balance table: %w", err)
 	}
+	emptyDealIndexCid, err := StoreEmptySetMultimap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty deal index: %w", err)
+	}
+	emptyDealPaymentsMapCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty deal payments map: %w", err)
+	}
 
 	return &State{
 		Proposals:        emptyProposalsArrayCid,
@@ -98,6 +119,10 @@ func ConstructState(store adt.Store) (*State, error) {
 		TotalClientLockedCollateral:   abi.NewTokenAmount(0),
 		TotalProviderLockedCollateral: abi.NewTokenAmount(0),
 		TotalClientStorageFee:         abi.NewTokenAmount(0),
+
+		ProviderDeals: emptyDealIndexCid,
+		ClientDeals:   emptyDealIndexCid,
+		DealPayments:  emptyDealPaymentsMapCid,
 	}, nil
 }
 
@@ -105,7 +130,7 @@ func ConstructState(store adt.Store) (*State, error) {
 // Deal state operations
 ////////////////////////////////////////////////////////////////////////////////
 
-func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealState, deal *DealProposal, epoch abi.ChainEpoch) (amountSlashed abi.TokenAmount, nextEpoch abi.ChainEpoch, removeDeal bool) {
+func (m *marketStateMutation) updatePendingDealState(rt Runtime, dealID abi.DealID, state *DealState, deal *DealProposal, epoch abi.ChainEpoch) (amountSlashed abi.TokenAmount, nextEpoch abi.ChainEpoch, removeDeal bool) {
 	amountSlashed = abi.NewTokenAmount(0)
 
 	everUpdated := state.LastUpdatedEpoch != EpochUndefined
@@ -135,9 +160,12 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 
 	numEpochsElapsed := paymentEndEpoch - paymentStartEpoch
 
+	pricePerEpoch, err := m.escrowPricePerEpoch(deal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine escrow price of deal %d", dealID)
+
 	{
 		// Process deal payment for the elapsed epochs.
-		totalPayment := big.Mul(big.NewInt(int64(numEpochsElapsed)), deal.StoragePricePerEpoch)
+		totalPayment := big.Mul(big.NewInt(int64(numEpochsElapsed)), pricePerEpoch)
 
 		// the transfer amount can be less than or equal to zero if a deal is slashed before or at the deal's start epoch.
 		if totalPayment.GreaterThan(big.Zero()) {
@@ -148,8 +176,15 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 	}
 
 	if everSlashed {
+		// The provider may have settled payment past the slash epoch before the termination was reported,
+		// in which case only the fee after that settlement remains locked.
+		remainingFrom := state.SlashEpoch
+		if everUpdated && state.LastUpdatedEpoch > remainingFrom {
+			remainingFrom = state.LastUpdatedEpoch
+		}
+
 		// unlock client collateral and locked storage fee
-		paymentRemaining, err := dealGetPaymentRemaining(deal, state.SlashEpoch)
+		paymentRemaining, err := dealGetPaymentRemaining(deal, pricePerEpoch, remainingFrom)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute remaining payment")
 
 		// unlock remaining storage fee
@@ -172,9 +207,10 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 		return amountSlashed, EpochUndefined, true
 	}
 
-	// We're explicitly not inspecting the end epoch and may process a deal's expiration late, in order to prevent an outsider
+	// Payments between now and the deal's end are settled on demand, so the deal is next processed for its expiry.
+	// We may process a deal's expiration late, spreading expirations over an interval in order to prevent an outsider
 	// from loading a cron tick by activating too many deals with the same end epoch.
-	nextEpoch = epoch + DealUpdatesInterval
+	nextEpoch = GenRandNextEpoch(deal.EndEpoch, dealID)
 
 	return amountSlashed, nextEpoch, false
 }
@@ -183,7 +219,9 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 // Slash a portion of provider's collateral, and unlock remaining collaterals
 // for both provider and client.
 func (m *marketStateMutation) processDealInitTimedOut(rt Runtime, deal *DealProposal) abi.TokenAmount {
-	if err := m.unlockBalance(deal.Client, deal.TotalStorageFee(), ClientStorageFee); err != nil {
+	storageFee, err := m.escrowStorageFee(deal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine escrow storage fee")
+	if err := m.unlockBalance(deal.Client, storageFee, ClientStorageFee); err != nil {
 		rt.Abortf(exitcode.ErrIllegalState, "failure unlocking client storage fee: %s", err)
 	}
 	if err := m.unlockBalance(deal.Client, deal.ClientCollateral, ClientCollateral); err != nil {
@@ -216,6 +254,187 @@ func (m *marketStateMutation) processDealExpired(rt Runtime, deal *DealProposal,
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed unlocking deal client balance")
 }
 
+// Applies new terms to an active deal from the current epoch, after paying for the epochs elapsed under its existing terms.
+// Locks or unlocks client funds and locks provider collateral to match the new terms, and reschedules the deal's
+// expiry for its new end epoch.
+func (m *marketStateMutation) extendDeal(rt Runtime, ext *DealExtension, sectorExpiration, epoch abi.ChainEpoch) {
+	deal, err := getDealProposal(m.dealProposals, ext.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", ext.DealID)
+	state, found, err := m.dealStates.Get(ext.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for deal %d", ext.DealID)
+	if !found {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d is not active", ext.DealID)
+	}
+	if state.SlashEpoch != EpochUndefined {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d was terminated at %d", ext.DealID, state.SlashEpoch)
+	}
+	// Until first processed, a deal's proposal remains pending and so can't be changed.
+	if state.LastUpdatedEpoch == EpochUndefined {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d has not yet been processed since activation", ext.DealID)
+	}
+	if epoch >= deal.EndEpoch {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d ended at %d", ext.DealID, deal.EndEpoch)
+	}
+	// The storage fee of a deal paid through a payment channel is fixed by the client's voucher.
+	paidByChannel, err := m.paidByChannel(deal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check payment of deal %d", ext.DealID)
+	if paidByChannel {
+		rt.Abortf(exitcode.ErrForbidden, "deal %d is paid through a payment channel", ext.DealID)
+	}
+
+	if ext.NewEndEpoch <= deal.EndEpoch {
+		rt.Abortf(exitcode.ErrIllegalArgument, "deal %d new end epoch %d must be after current end %d", ext.DealID, ext.NewEndEpoch, deal.EndEpoch)
+	}
+	if ext.NewEndEpoch > sectorExpiration {
+		rt.Abortf(exitcode.ErrIllegalArgument, "deal %d new end epoch %d exceeds sector expiration %d", ext.DealID, ext.NewEndEpoch, sectorExpiration)
+	}
+	_, maxDuration := DealDurationBounds(deal.PieceSize)
+	if ext.NewEndEpoch-epoch > maxDuration {
+		rt.Abortf(exitcode.ErrIllegalArgument, "deal %d extended duration %d exceeds max %d", ext.DealID, ext.NewEndEpoch-epoch, maxDuration)
+	}
+	minPrice, maxPrice := DealPricePerEpochBounds(deal.PieceSize, ext.NewEndEpoch-epoch)
+	if ext.NewStoragePricePerEpoch.LessThan(minPrice) || ext.NewStoragePricePerEpoch.GreaterThan(maxPrice) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "deal %d storage price out of bounds", ext.DealID)
+	}
+	if ext.NewClientCollateral.LessThan(deal.ClientCollateral) || ext.NewProviderCollateral.LessThan(deal.ProviderCollateral) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "deal %d collateral may not decrease", ext.DealID)
+	}
+
+	// Pay for the epochs elapsed under the existing terms.
+	m.payDeal(rt, deal, state, epoch)
+
+	// Adjust the locked storage fee to cover the remainder of the extended deal at the new price.
+	remainingFee, err := dealGetPaymentRemaining(deal, deal.StoragePricePerEpoch, epoch)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute remaining payment")
+	extendedFee := big.Mul(big.NewInt(int64(ext.NewEndEpoch-epoch)), ext.NewStoragePricePerEpoch)
+	if feeDelta := big.Sub(extendedFee, remainingFee); feeDelta.GreaterThan(big.Zero()) {
+		err = m.maybeLockBalance(deal.Client, feeDelta)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to lock client storage fee for deal %d", ext.DealID)
+		m.totalClientStorageFee = big.Add(m.totalClientStorageFee, feeDelta)
+	} else {
+		err = m.unlockBalance(deal.Client, feeDelta.Neg(), ClientStorageFee)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unlock client storage fee for deal %d", ext.DealID)
+	}
+
+	clientCollateralDelta := big.Sub(ext.NewClientCollateral, deal.ClientCollateral)
+	err = m.maybeLockBalance(deal.Client, clientCollateralDelta)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to lock client collateral for deal %d", ext.DealID)
+	m.totalClientLockedCollateral = big.Add(m.totalClientLockedCollateral, clientCollateralDelta)
+
+	providerCollateralDelta := big.Sub(ext.NewProviderCollateral, deal.ProviderCollateral)
+	err = m.maybeLockBalance(deal.Provider, providerCollateralDelta)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to lock provider collateral for deal %d", ext.DealID)
+	m.totalProviderLockedCollateral = big.Add(m.totalProviderLockedCollateral, providerCollateralDelta)
+
+	// Processed deals are scheduled for their expiry.
+	err = m.dealsByEpoch.Remove(GenRandNextEpoch(deal.EndEpoch, ext.DealID), ext.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unschedule deal %d", ext.DealID)
+	err = m.dealsByEpoch.Put(GenRandNextEpoch(ext.NewEndEpoch, ext.DealID), ext.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to reschedule deal %d", ext.DealID)
+
+	deal.EndEpoch = ext.NewEndEpoch
+	deal.StoragePricePerEpoch = ext.NewStoragePricePerEpoch
+	deal.ClientCollateral = ext.NewClientCollateral
+	deal.ProviderCollateral = ext.NewProviderCollateral
+	err = m.dealProposals.Set(ext.DealID, deal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal %d", ext.DealID)
+
+	err = m.dealStates.Set(ext.DealID, state)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set state for deal %d", ext.DealID)
+}
+
+// Pays the provider of an active deal for the epochs since it was last updated, up to the given epoch or the deal's
+// end if earlier, and advances the deal's last updated epoch to match. Returns the amount paid.
+// The caller is responsible for storing the updated deal state.
+func (m *marketStateMutation) payDeal(rt Runtime, deal *DealProposal, state *DealState, epoch abi.ChainEpoch) abi.TokenAmount {
+	paymentStart := deal.StartEpoch
+	if state.LastUpdatedEpoch > paymentStart {
+		paymentStart = state.LastUpdatedEpoch
+	}
+	paymentEnd := epoch
+	if deal.EndEpoch < paymentEnd {
+		paymentEnd = deal.EndEpoch
+	}
+	if paymentEnd <= paymentStart {
+		return big.Zero()
+	}
+
+	pricePerEpoch, err := m.escrowPricePerEpoch(deal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine escrow price of deal")
+	payment := big.Mul(big.NewInt(int64(paymentEnd-paymentStart)), pricePerEpoch)
+	err = m.transferBalance(deal.Client, deal.Provider, payment)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to transfer %v from %v to %v", payment, deal.Client, deal.Provider)
+	state.LastUpdatedEpoch = paymentEnd
+	return payment
+}
+
+// Adds a deal to the provider and client indexes.
+func (m *marketStateMutation) indexDeal(dealID abi.DealID, deal *DealProposal) error {
+	if err := m.providerDeals.Put(deal.Provider, dealID); err != nil {
+		return xerrors.Errorf("failed to index deal %d by provider %v: %w", dealID, deal.Provider, err)
+	}
+	if err := m.clientDeals.Put(deal.Client, dealID); err != nil {
+		return xerrors.Errorf("failed to index deal %d by client %v: %w", dealID, deal.Client, err)
+	}
+	return nil
+}
+
+// Removes a deal from the provider and client indexes.
+func (m *marketStateMutation) unindexDeal(dealID abi.DealID, deal *DealProposal) error {
+	if err := m.providerDeals.Remove(deal.Provider, dealID); err != nil {
+		return xerrors.Errorf("failed to remove deal %d from provider %v index: %w", dealID, deal.Provider, err)
+	}
+	if err := m.clientDeals.Remove(deal.Client, dealID); err != nil {
+		return xerrors.Errorf("failed to remove deal %d from client %v index: %w", dealID, deal.Client, err)
+	}
+	return nil
+}
+
+// Whether a deal's storage fee is paid through a payment channel rather than from the client's escrow.
+func (m *marketStateMutation) paidByChannel(deal *DealProposal) (bool, error) {
+	pcid, err := deal.Cid()
+	if err != nil {
+		return false, xerrors.Errorf("failed to calculate proposal CID: %w", err)
+	}
+	found, err := m.dealPayments.Has(abi.CidKey(pcid))
+	if err != nil {
+		return false, xerrors.Errorf("failed to get payment for proposal %v: %w", pcid, err)
+	}
+	return found, nil
+}
+
+// Returns the price per epoch of a deal paid from the client's escrow, which is zero for a deal paid through
+// a payment channel.
+func (m *marketStateMutation) escrowPricePerEpoch(deal *DealProposal) (abi.TokenAmount, error) {
+	paidByChannel, err := m.paidByChannel(deal)
+	if err != nil {
+		return big.Zero(), err
+	}
+	if paidByChannel {
+		return big.Zero(), nil
+	}
+	return deal.StoragePricePerEpoch, nil
+}
+
+// Returns the storage fee of a deal locked in the client's escrow, which is zero for a deal paid through
+// a payment channel.
+func (m *marketStateMutation) escrowStorageFee(deal *DealProposal) (abi.TokenAmount, error) {
+	pricePerEpoch, err := m.escrowPricePerEpoch(deal)
+	if err != nil {
+		return big.Zero(), err
+	}
+	return big.Mul(pricePerEpoch, big.NewInt(int64(deal.Duration()))), nil
+}
+
+// Removes the payment record of a deal paid through a payment channel, given its proposal CID.
+// Deals paid from escrow have none.
+func (m *marketStateMutation) removeDealPayment(pcid cid.Cid) error {
+	if _, err := m.dealPayments.TryDelete(abi.CidKey(pcid)); err != nil {
+		return xerrors.Errorf("failed to delete payment for proposal %v: %w", pcid, err)
+	}
+	return nil
+}
+
 func (m *marketStateMutation) generateStorageDealID() abi.DealID {
 	ret := m.nextDealId
 	m.nextDealId = m.nextDealId + abi.DealID(1)
@@ -241,7 +460,35 @@ func dealProposalIsInternallyValid(rt Runtime, proposal ClientDealProposal) erro
 	return nil
 }
 
-func dealGetPaymentRemaining(deal *DealProposal, slashEpoch abi.ChainEpoch) (abi.TokenAmount, error) {
+func dealPaymentVoucherIsSigned(rt Runtime, sv *paych.SignedVoucher, client addr.Address) error {
+	if sv.Signature == nil {
+		return xerrors.Errorf("payment voucher has no signature")
+	}
+	vb, err := paych.VoucherSigningBytes(sv)
+	if err != nil {
+		return xerrors.Errorf("payment voucher signature verification failed to serialize voucher: %w", err)
+	}
+	err = rt.VerifySignature(*sv.Signature, client, vb)
+	if err != nil {
+		return xerrors.Errorf("payment voucher signature invalid: %w", err)
+	}
+	return nil
+}
+
+func dealExtensionIsSigned(rt Runtime, ext *ClientDealExtension, client addr.Address) error {
+	buf := bytes.Buffer{}
+	err := ext.Extension.MarshalCBOR(&buf)
+	if err != nil {
+		return xerrors.Errorf("extension signature verification failed to marshal extension: %w", err)
+	}
+	err = rt.VerifySignature(ext.ClientSignature, client, buf.Bytes())
+	if err != nil {
+		return xerrors.Errorf("signature extension invalid: %w", err)
+	}
+	return nil
+}
+
+func dealGetPaymentRemaining(deal *DealProposal, pricePerEpoch abi.TokenAmount, slashEpoch abi.ChainEpoch) (abi.TokenAmount, error) {
 	if slashEpoch > deal.EndEpoch {
 		return big.Zero(), xerrors.Errorf("deal slash epoch %d after end epoch %d", slashEpoch, deal.EndEpoch)
 	}
@@ -256,7 +503,7 @@ func dealGetPaymentRemaining(deal *DealProposal, slashEpoch abi.ChainEpoch) (abi
 		return big.Zero(), xerrors.Errorf("deal remaining duration negative: %d", durationRemaining)
 	}
 
-	return big.Mul(big.NewInt(int64(durationRemaining)), deal.StoragePricePerEpoch), nil
+	return big.Mul(big.NewInt(int64(durationRemaining)), pricePerEpoch), nil
 }
 
 // MarketStateMutationPermission is the mutation permission on a state field
@@ -290,6 +537,13 @@ type marketStateMutation struct {
 	dpePermit    MarketStateMutationPermission
 	dealsByEpoch *SetMultimap
 
+	indexPermit   MarketStateMutationPermission
+	providerDeals *DealIndex
+	clientDeals   *DealIndex
+
+	paymentPermit MarketStateMutationPermission
+	dealPayments  *adt.Map
+
 	lockedPermit                  MarketStateMutationPermission
 	lockedTable                   *adt.BalanceTable
 	totalClientLockedCollateral   abi.TokenAmount
@@ -355,6 +609,27 @@ func (m *marketStateMutation) build() (*marketStateMutation, error) {
 		m.dealsByEpoch = dbe
 	}
 
+	if m.indexPermit != Invalid {
+		providerDeals, err := AsDealIndex(m.store, m.st.ProviderDeals, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load provider deal index: %w", err)
+		}
+		m.providerDeals = providerDeals
+		clientDeals, err := AsDealIndex(m.store, m.st.ClientDeals, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load client deal index: %w", err)
+		}
+		m.clientDeals = clientDeals
+	}
+
+	if m.paymentPermit != Invalid {
+		payments, err := adt.AsMap(m.store, m.st.DealPayments, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deal payments: %w", err)
+		}
+		m.dealPayments = payments
+	}
+
 	m.nextDealId = m.st.NextID
 
 	return m, nil
@@ -390,6 +665,16 @@ func (m *marketStateMutation) withDealsByEpoch(permit MarketStateMutationPermiss
 	return m
 }
 
+func (m *marketStateMutation) withDealIndexes(permit MarketStateMutationPermission) *marketStateMutation {
+	m.indexPermit = permit
+	return m
+}
+
+func (m *marketStateMutation) withDealPayments(permit MarketStateMutationPermission) *marketStateMutation {
+	m.paymentPermit = permit
+	return m
+}
+
 func (m *marketStateMutation) commitState() error {
 	var err error
 	if m.proposalPermit == WritePermission {
@@ -431,6 +716,21 @@ func (m *marketStateMutation) commitState() error {
 		}
 	}
 
+	if m.indexPermit == WritePermission {
+		if m.st.ProviderDeals, err = m.providerDeals.Root(); err != nil {
+			return xerrors.Errorf("failed to flush provider deal index: %w", err)
+		}
+		if m.st.ClientDeals, err = m.clientDeals.Root(); err != nil {
+			return xerrors.Errorf("failed to flush client deal index: %w", err)
+		}
+	}
+
+	if m.paymentPermit == WritePermission {
+		if m.st.DealPayments, err = m.dealPayments.Root(); err != nil {
+			return xerrors.Errorf("failed to flush deal payments: %w", err)
+		}
+	}
+
 	m.st.NextID = m.nextDealId
 	return nil
 }
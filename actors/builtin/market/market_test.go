@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"testing"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/paych"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/reward"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
@@ -108,6 +110,8 @@ func TestMarketActor(t *testing.T) {
 		assert.Equal(t, abi.DealID(0), state.NextID)
 		assert.Equal(t, emptyMultiMap, state.DealOpsByEpoch)
 		assert.Equal(t, abi.ChainEpoch(-1), state.LastCron)
+		assert.Equal(t, emptyMultiMap, state.ProviderDeals)
+		assert.Equal(t, emptyMultiMap, state.ClientDeals)
 	})
 
 	t.Run("AddBalance", func(t *testing.T) {
@@ -1259,6 +1263,1079 @@ func TestActivateDealFailures(t *testing.T) {
 
 }
 
+func TestActivateDealsBatch(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(10)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := endEpoch + 100
+
+	t.Run("activates deals and computes weights for each sector", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		vd := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		vd.VerifiedDeal = true
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		verifiedDealIDs := actor.publishDeals(rt, mAddrs, publishDealReq{deal: vd})
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+2)
+
+		ret := actor.activateDealsBatch(rt, provider, []market.SectorDeals{
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{verifiedDealIDs[0], dealId1}},
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId2}},
+		})
+		require.Len(t, ret.Sectors, 2)
+
+		d1 := actor.getDealProposal(rt, dealId1)
+		d2 := actor.getDealProposal(rt, dealId2)
+		assert.True(t, ret.Sectors[0].Activated)
+		assert.Equal(t, uint64(vd.PieceSize+d1.PieceSize), ret.Sectors[0].DealSpace)
+		assert.Equal(t, market.DealWeight(d1), ret.Sectors[0].DealWeight)
+		assert.Equal(t, market.DealWeight(&vd), ret.Sectors[0].VerifiedDealWeight)
+		assert.Equal(t, []market.DealActivationResult{
+			{DealID: verifiedDealIDs[0], Outcome: market.DealActivated},
+			{DealID: dealId1, Outcome: market.DealActivated},
+		}, ret.Sectors[0].Deals)
+
+		assert.True(t, ret.Sectors[1].Activated)
+		assert.Equal(t, uint64(d2.PieceSize), ret.Sectors[1].DealSpace)
+		assert.Equal(t, market.DealWeight(d2), ret.Sectors[1].DealWeight)
+		assert.Equal(t, big.Zero(), ret.Sectors[1].VerifiedDealWeight)
+
+		for _, dealID := range []abi.DealID{verifiedDealIDs[0], dealId1, dealId2} {
+			assert.Equal(t, currentEpoch, actor.getDealState(rt, dealID).SectorStartEpoch)
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("drops only sectors with a deal that cannot be activated", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		dealId3 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+2)
+		dealId4 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+3)
+		provider2 := tutil.NewIDAddr(t, 401)
+		otherProviderDeal := actor.generateAndPublishDeal(rt, client, &minerAddrs{owner, worker, provider2, nil}, startEpoch, endEpoch)
+
+		ret := actor.activateDealsBatch(rt, provider, []market.SectorDeals{
+			// Valid.
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId1}},
+			// Deal 1 was activated by the previous sector.
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId2, dealId1}},
+			// Unknown deal.
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{abi.DealID(42), dealId3}},
+			// Duplicate deal.
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId3, dealId3}},
+			// Sector expires before the deal ends.
+			{SectorExpiry: endEpoch, DealIDs: []abi.DealID{dealId4}},
+			// Deal for another provider.
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{otherProviderDeal}},
+		})
+		require.Len(t, ret.Sectors, 6)
+
+		assert.True(t, ret.Sectors[0].Activated)
+		expectedResults := [][]market.DealActivationResult{
+			{{DealID: dealId1, Outcome: market.DealActivated}},
+			{{DealID: dealId2, Outcome: market.DealActivationSkipped}, {DealID: dealId1, Outcome: market.DealActivationAlreadyActive}},
+			{{DealID: 42, Outcome: market.DealActivationNotFound}, {DealID: dealId3, Outcome: market.DealActivationSkipped}},
+			{{DealID: dealId3, Outcome: market.DealActivationSkipped}, {DealID: dealId3, Outcome: market.DealActivationInvalid}},
+			{{DealID: dealId4, Outcome: market.DealActivationInvalid}},
+			{{DealID: otherProviderDeal, Outcome: market.DealActivationInvalid}},
+		}
+		for i, sector := range ret.Sectors {
+			assert.Equal(t, expectedResults[i], sector.Deals)
+			if i > 0 {
+				assert.False(t, sector.Activated)
+				assert.Equal(t, uint64(0), sector.DealSpace)
+				assert.Equal(t, big.Zero(), sector.DealWeight)
+				assert.Equal(t, big.Zero(), sector.VerifiedDealWeight)
+			}
+		}
+
+		actor.assertDealsNotActivated(rt, currentEpoch, dealId2, dealId3, dealId4, otherProviderDeal)
+		actor.checkState(rt)
+	})
+
+	t.Run("fail when caller is not a StorageMinerActor", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.SetCaller(provider, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.ActivateDealsBatch, &market.ActivateDealsBatchParams{})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
+func TestCancelPendingDeal(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	providerCollateral := big.NewInt(1000)
+	clientCollateral := big.NewInt(100)
+
+	t.Run("client cancels and pays fee to provider", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		deal := actor.generateDealWithCollateralAndAddFunds(rt, client, mAddrs, providerCollateral, clientCollateral, startEpoch, endEpoch)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealID := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		p := actor.getDealProposal(rt, dealID)
+
+		actor.cancelPendingDeal(rt, client, dealID)
+
+		fee := market.DealCancellationFee(deal.ClientBalanceRequirement())
+		assert.True(t, fee.GreaterThan(big.Zero()))
+		assert.Equal(t, big.Sub(deal.ClientBalanceRequirement(), fee), actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Add(deal.ProviderCollateral, fee), actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, client))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, provider))
+		actor.assertLockedFundStates(rt, big.Zero(), big.Zero(), big.Zero())
+		actor.assertDealDeleted(rt, dealID, p)
+		actor.checkState(rt)
+
+		// Cron has nothing to process at the deal's scheduled epoch.
+		rt.SetEpoch(processEpoch(t, dealID, startEpoch))
+		actor.cronTick(rt)
+		assert.Equal(t, big.Sub(deal.ClientBalanceRequirement(), fee), actor.getEscrowBalance(rt, client))
+		actor.checkState(rt)
+	})
+
+	t.Run("provider cancels and pays fee to client", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		deal := actor.generateDealWithCollateralAndAddFunds(rt, client, mAddrs, providerCollateral, clientCollateral, startEpoch, endEpoch)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealID := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		p := actor.getDealProposal(rt, dealID)
+
+		expectGetControlAddresses(rt, provider, owner, worker)
+		actor.cancelPendingDeal(rt, worker, dealID, owner, worker)
+
+		fee := market.DealCancellationFee(deal.ProviderCollateral)
+		assert.True(t, fee.GreaterThan(big.Zero()))
+		assert.Equal(t, big.Add(deal.ClientBalanceRequirement(), fee), actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Sub(deal.ProviderCollateral, fee), actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, client))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, provider))
+		actor.assertDealDeleted(rt, dealID, p)
+		actor.checkState(rt)
+	})
+
+	t.Run("cancellation leaves other deals scheduled", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealID2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+
+		actor.cancelPendingDeal(rt, client, dealID1)
+
+		// The remaining deal times out as usual.
+		p2 := actor.getDealProposal(rt, dealID2)
+		rt.SetEpoch(processEpoch(t, dealID2, startEpoch))
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, p2.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		actor.assertDealDeleted(rt, dealID2, p2)
+		actor.checkState(rt)
+	})
+
+	t.Run("cancelling verified deal restores data cap", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealID := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, &verifreg.RestoreBytesParams{
+			Address:  client,
+			DealSize: big.NewIntUnsigned(uint64(deal.PieceSize)),
+		}, big.Zero(), nil, exitcode.Ok)
+		actor.cancelPendingDeal(rt, client, dealID)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for unknown deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "no such deal", func() {
+			rt.Call(actor.CancelPendingDeal, &market.CancelPendingDealParams{DealID: 42})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when caller is not a party to the deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		other := tutil.NewIDAddr(t, 999)
+		rt.SetCaller(other, builtin.AccountActorCodeID)
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.ExpectValidateCallerAddr(owner, worker)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.CancelPendingDeal, &market.CancelPendingDealParams{DealID: dealID})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when deal is activated", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		actor.activateDeals(rt, endEpoch+1, provider, currentEpoch, dealID)
+
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(client)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "already activated", func() {
+			rt.Call(actor.CancelPendingDeal, &market.CancelPendingDealParams{DealID: dealID})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when start epoch has elapsed", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		rt.SetEpoch(startEpoch)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(client)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "has already elapsed", func() {
+			rt.Call(actor.CancelPendingDeal, &market.CancelPendingDealParams{DealID: dealID})
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestExtendDeals(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := endEpoch + 400*builtin.EpochsInDay
+	sectorNumber := abi.SectorNumber(7)
+
+	// Publishes and activates a deal, then runs cron past its first processing.
+	setup := func(t *testing.T) (*mock.Runtime, *marketActorTestHarness, abi.DealID) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, currentEpoch, sectorExpiry)
+		rt.SetEpoch(processEpoch(t, dealID, startEpoch))
+		actor.cronTick(rt)
+		return rt, actor, dealID
+	}
+
+	extensionFor := func(dealID abi.DealID) market.DealExtension {
+		return market.DealExtension{
+			DealID:                  dealID,
+			SectorNumber:            sectorNumber,
+			NewEndEpoch:             endEpoch + 100*builtin.EpochsInDay,
+			NewStoragePricePerEpoch: big.NewInt(20),
+			NewProviderCollateral:   big.NewInt(30),
+			NewClientCollateral:     big.NewInt(25),
+		}
+	}
+
+	sectorFor := func(expiration abi.ChainEpoch, dealIDs ...abi.DealID) map[abi.SectorNumber]*builtin.MinerSectorInfo {
+		return map[abi.SectorNumber]*builtin.MinerSectorInfo{
+			sectorNumber: mkSectorInfo(sectorNumber, expiration, dealIDs...),
+		}
+	}
+
+	t.Run("extends deal with new terms", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		lastUpdated := rt.Epoch()
+		oldProposal := actor.getDealProposal(rt, dealID)
+		rt.SetEpoch(lastUpdated + 100)
+		ext := extensionFor(dealID)
+
+		// Fund the increase in storage fee and collateral.
+		remainingFee := big.Mul(big.NewInt(int64(endEpoch-rt.Epoch())), oldProposal.StoragePricePerEpoch)
+		extendedFee := big.Mul(big.NewInt(int64(ext.NewEndEpoch-rt.Epoch())), ext.NewStoragePricePerEpoch)
+		clientIncrease := big.Sum(big.Sub(extendedFee, remainingFee), big.Sub(ext.NewClientCollateral, oldProposal.ClientCollateral))
+		providerIncrease := big.Sub(ext.NewProviderCollateral, oldProposal.ProviderCollateral)
+		actor.addParticipantFunds(rt, client, clientIncrease)
+		actor.addProviderFunds(rt, providerIncrease, mAddrs)
+
+		clientEscrow := actor.getEscrowBalance(rt, client)
+		providerEscrow := actor.getEscrowBalance(rt, provider)
+
+		actor.extendDeals(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID), ext)
+
+		// Epochs since the last update are paid at the old price.
+		payment := big.Mul(big.NewInt(100), oldProposal.StoragePricePerEpoch)
+		assert.Equal(t, big.Sub(clientEscrow, payment), actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Add(providerEscrow, payment), actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, big.Add(extendedFee, ext.NewClientCollateral), actor.getLockedBalance(rt, client))
+		assert.Equal(t, ext.NewProviderCollateral, actor.getLockedBalance(rt, provider))
+		actor.assertLockedFundStates(rt, extendedFee, ext.NewProviderCollateral, ext.NewClientCollateral)
+
+		p := actor.getDealProposal(rt, dealID)
+		assert.Equal(t, ext.NewEndEpoch, p.EndEpoch)
+		assert.Equal(t, ext.NewStoragePricePerEpoch, p.StoragePricePerEpoch)
+		assert.Equal(t, ext.NewClientCollateral, p.ClientCollateral)
+		assert.Equal(t, ext.NewProviderCollateral, p.ProviderCollateral)
+		assert.Equal(t, rt.Epoch(), actor.getDealState(rt, dealID).LastUpdatedEpoch)
+		actor.checkState(rt)
+
+		// Later epochs are paid at the new price.
+		providerEscrow = actor.getEscrowBalance(rt, provider)
+		rt.SetEpoch(rt.Epoch() + market.DealUpdatesInterval)
+		payments := actor.settleDealPayments(rt, worker, mAddrs, dealID)
+		payment = big.Mul(big.NewInt(int64(market.DealUpdatesInterval)), ext.NewStoragePricePerEpoch)
+		assert.Equal(t, []abi.TokenAmount{payment}, payments)
+		assert.Equal(t, big.Add(providerEscrow, payment), actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, rt.Epoch(), actor.getDealState(rt, dealID).LastUpdatedEpoch)
+		actor.checkState(rt)
+
+		// The deal expires at its new end epoch.
+		rt.SetEpoch(market.GenRandNextEpoch(ext.NewEndEpoch, dealID))
+		actor.cronTick(rt)
+		actor.assertDealDeleted(rt, dealID, p)
+		actor.checkState(rt)
+	})
+
+	t.Run("lower price unlocks client storage fee", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+		ext.NewStoragePricePerEpoch = big.NewInt(1)
+		ext.NewClientCollateral = actor.getDealProposal(rt, dealID).ClientCollateral
+		ext.NewProviderCollateral = actor.getDealProposal(rt, dealID).ProviderCollateral
+
+		actor.extendDeals(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID), ext)
+
+		extendedFee := big.Mul(big.NewInt(int64(ext.NewEndEpoch-rt.Epoch())), ext.NewStoragePricePerEpoch)
+		assert.Equal(t, big.Add(extendedFee, ext.NewClientCollateral), actor.getLockedBalance(rt, client))
+		actor.assertLockedFundStates(rt, extendedFee, ext.NewProviderCollateral, ext.NewClientCollateral)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when new end epoch exceeds sector expiration", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "exceeds sector expiration", func() {
+			actor.extendDeals(rt, worker, mAddrs, sectorFor(ext.NewEndEpoch-1, dealID), ext)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when sector does not hold the deal", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "does not hold deal", func() {
+			actor.extendDeals(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID+1), ext)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when sector is not active", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		// The sector has been terminated, but the market not yet told.
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is not active", func() {
+			actor.extendDealsWithInactiveSectors(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID),
+				map[abi.SectorNumber]bool{sectorNumber: true}, ext)
+		})
+		assert.Equal(t, endEpoch, actor.getDealProposal(rt, dealID).EndEpoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when provider has no such sector", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "has no sector", func() {
+			actor.extendDeals(rt, worker, mAddrs, nil, ext)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when deal has not been processed since activation", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, currentEpoch, sectorExpiry)
+		ext := extensionFor(dealID)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "not yet been processed", func() {
+			actor.extendDeals(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID), ext)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when end epoch is not increased", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+		ext.NewEndEpoch = endEpoch
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must be after current end", func() {
+			actor.extendDeals(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID), ext)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when collateral decreases", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+		ext.NewProviderCollateral = big.Zero()
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "collateral may not decrease", func() {
+			actor.extendDeals(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID), ext)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when client has insufficient funds", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			actor.extendDeals(rt, worker, mAddrs, sectorFor(sectorExpiry, dealID), ext)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when deal is extended twice", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&ext), nil)
+		params := &market.ExtendDealsParams{Extensions: []market.ClientDealExtension{{Extension: ext}, {Extension: ext}}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "extended more than once", func() {
+			rt.Call(actor.ExtendDeals, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when client signature is invalid", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&ext), errors.New("bad signature"))
+		params := &market.ExtendDealsParams{Extensions: []market.ClientDealExtension{{Extension: ext}}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid extension", func() {
+			rt.Call(actor.ExtendDeals, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when caller is not provider worker or control address", func(t *testing.T) {
+		rt, actor, dealID := setup(t)
+		ext := extensionFor(dealID)
+
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&ext), nil)
+		expectGetControlAddresses(rt, provider, owner, worker)
+		params := &market.ExtendDealsParams{Extensions: []market.ClientDealExtension{{Extension: ext}}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is not worker or control address", func() {
+			rt.Call(actor.ExtendDeals, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
+func TestSettleDealPayments(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	control := tutil.NewIDAddr(t, 105)
+	mAddrs := &minerAddrs{owner, worker, provider, []address.Address{control}}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := endEpoch + 100
+
+	// Publishes and activates deals, then runs cron past their first processing.
+	setup := func(t *testing.T, count int) (*mock.Runtime, *marketActorTestHarness, []abi.DealID) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealIDs := make([]abi.DealID, count)
+		for i := range dealIDs {
+			dealIDs[i] = actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch+abi.ChainEpoch(i), currentEpoch, sectorExpiry)
+		}
+		rt.SetEpoch(processEpoch(t, dealIDs[count-1], startEpoch))
+		actor.cronTick(rt)
+		return rt, actor, dealIDs
+	}
+
+	t.Run("pays for epochs since each deal was last updated", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 2)
+		lastUpdated := rt.Epoch()
+		d := actor.getDealProposal(rt, dealIDs[0])
+		clientEscrow := actor.getEscrowBalance(rt, client)
+		clientLocked := actor.getLockedBalance(rt, client)
+		providerEscrow := actor.getEscrowBalance(rt, provider)
+
+		rt.SetEpoch(lastUpdated + 100)
+		payments := actor.settleDealPayments(rt, worker, mAddrs, dealIDs...)
+		payment := big.Mul(big.NewInt(100), d.StoragePricePerEpoch)
+		assert.Equal(t, []abi.TokenAmount{payment, payment}, payments)
+
+		total := big.Mul(big.NewInt(2), payment)
+		assert.Equal(t, big.Sub(clientEscrow, total), actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Sub(clientLocked, total), actor.getLockedBalance(rt, client))
+		assert.Equal(t, big.Add(providerEscrow, total), actor.getEscrowBalance(rt, provider))
+		for _, dealID := range dealIDs {
+			assert.Equal(t, rt.Epoch(), actor.getDealState(rt, dealID).LastUpdatedEpoch)
+		}
+		actor.checkState(rt)
+
+		// A control address may also settle, paying only for epochs since the last settlement.
+		rt.SetEpoch(rt.Epoch() + 10)
+		payments = actor.settleDealPayments(rt, control, mAddrs, dealIDs[1])
+		assert.Equal(t, []abi.TokenAmount{big.Mul(big.NewInt(10), d.StoragePricePerEpoch)}, payments)
+		actor.checkState(rt)
+	})
+
+	t.Run("pays no further than the end of a deal, leaving expiry to cron", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 1)
+		dealID := dealIDs[0]
+		d := actor.getDealProposal(rt, dealID)
+		lastUpdated := rt.Epoch()
+
+		rt.SetEpoch(endEpoch + 10)
+		payments := actor.settleDealPayments(rt, worker, mAddrs, dealID)
+		assert.Equal(t, []abi.TokenAmount{big.Mul(big.NewInt(int64(endEpoch-lastUpdated)), d.StoragePricePerEpoch)}, payments)
+		assert.Equal(t, endEpoch, actor.getDealState(rt, dealID).LastUpdatedEpoch)
+		assert.Equal(t, []abi.TokenAmount{big.Zero()}, actor.settleDealPayments(rt, worker, mAddrs, dealID))
+		actor.checkState(rt)
+
+		// Expiry makes no further payment and unlocks the collateral.
+		rt.SetEpoch(market.GenRandNextEpoch(endEpoch, dealID))
+		pay, slashed := actor.cronTickAndAssertBalances(rt, client, provider, rt.Epoch(), dealID)
+		assert.Equal(t, big.Zero(), pay)
+		assert.Equal(t, big.Zero(), slashed)
+		actor.assertDealDeleted(rt, dealID, d)
+		assert.Equal(t, big.Add(d.TotalStorageFee(), d.ProviderCollateral), actor.getEscrowBalance(rt, provider))
+		actor.checkState(rt)
+	})
+
+	t.Run("deals which cannot be settled are paid nothing", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 2)
+		settled, terminated := dealIDs[0], dealIDs[1]
+		published := actor.generateAndPublishDeal(rt, client, mAddrs, endEpoch, endEpoch+200*builtin.EpochsInDay)
+
+		// Activated, but not yet processed by cron.
+		now := rt.Epoch()
+		unprocessed := actor.publishAndActivateDeal(rt, client, mAddrs, now+10, endEpoch, now, sectorExpiry)
+		rt.SetEpoch(now + 1)
+		actor.terminateDeals(rt, provider, terminated)
+
+		rt.SetEpoch(now + 20)
+		d := actor.getDealProposal(rt, settled)
+		lastUpdated := actor.getDealState(rt, settled).LastUpdatedEpoch
+		payments := actor.settleDealPayments(rt, worker, mAddrs, published, settled, unprocessed, terminated)
+		payment := big.Mul(big.NewInt(int64(rt.Epoch()-lastUpdated)), d.StoragePricePerEpoch)
+		assert.Equal(t, []abi.TokenAmount{big.Zero(), payment, big.Zero(), big.Zero()}, payments)
+		assert.Equal(t, market.EpochUndefined, actor.getDealState(rt, unprocessed).LastUpdatedEpoch)
+		assert.Equal(t, lastUpdated, actor.getDealState(rt, terminated).LastUpdatedEpoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("settlement before a deferred termination unlocks only the deal's remaining fee", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 2)
+		dealID := dealIDs[0]
+		d := actor.getDealProposal(rt, dealID)
+		lastUpdated := actor.getDealState(rt, dealID).LastUpdatedEpoch
+		clientLocked := actor.getLockedBalance(rt, client)
+		clientEscrow := actor.getEscrowBalance(rt, client)
+		providerEscrow := actor.getEscrowBalance(rt, provider)
+
+		// The sector is terminated, but the miner reports the termination to the market only later,
+		// after the provider has settled past the termination epoch.
+		terminationEpoch := rt.Epoch() + 10
+		rt.SetEpoch(terminationEpoch + 100)
+		payments := actor.settleDealPayments(rt, worker, mAddrs, dealID)
+		payment := big.Mul(big.NewInt(int64(rt.Epoch()-lastUpdated)), d.StoragePricePerEpoch)
+		assert.Equal(t, []abi.TokenAmount{payment}, payments)
+
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.Call(actor.OnMinerSectorsTerminate, mkTerminateDealParams(terminationEpoch, dealID))
+		rt.Verify()
+
+		rt.SetEpoch(market.GenRandNextEpoch(rt.Epoch()+1, dealID))
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, d.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		actor.assertDealDeleted(rt, dealID, d)
+
+		// The locks of the other deal are untouched.
+		dealLocked := big.Add(big.Mul(big.NewInt(int64(d.EndEpoch-lastUpdated)), d.StoragePricePerEpoch), d.ClientCollateral)
+		assert.Equal(t, big.Sub(clientLocked, dealLocked), actor.getLockedBalance(rt, client))
+		assert.Equal(t, big.Sub(clientEscrow, payment), actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Sub(big.Add(providerEscrow, payment), d.ProviderCollateral), actor.getEscrowBalance(rt, provider))
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when no deals are given", func(t *testing.T) {
+		rt, actor, _ := setup(t, 1)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no deals to settle", func() {
+			rt.Call(actor.SettleDealPayments, &market.SettleDealPaymentsParams{})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when deal does not exist", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 1)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		params := &market.SettleDealPaymentsParams{DealIDs: []abi.DealID{dealIDs[0], 100}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "no such deal 100", func() {
+			rt.Call(actor.SettleDealPayments, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when deal is settled twice", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 1)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		params := &market.SettleDealPaymentsParams{DealIDs: []abi.DealID{dealIDs[0], dealIDs[0]}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "settled more than once", func() {
+			rt.Call(actor.SettleDealPayments, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when deals have different providers", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 1)
+		provider2 := tutil.NewIDAddr(t, 501)
+		other := actor.generateAndPublishDeal(rt, client, &minerAddrs{owner, worker, provider2, nil}, endEpoch, endEpoch+200*builtin.EpochsInDay)
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		params := &market.SettleDealPaymentsParams{DealIDs: []abi.DealID{dealIDs[0], other}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "multiple providers", func() {
+			rt.Call(actor.SettleDealPayments, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when caller is not provider worker or control address", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t, 1)
+		rt.SetCaller(owner, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		expectGetControlAddresses(rt, provider, owner, worker, control)
+		params := &market.SettleDealPaymentsParams{DealIDs: dealIDs}
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is not worker or control address", func() {
+			rt.Call(actor.SettleDealPayments, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
+func TestPaymentChannelDeals(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	channel := tutil.NewIDAddr(t, 105)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := endEpoch + 100
+
+	setup := func(t *testing.T) (*mock.Runtime, *marketActorTestHarness) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetAddressActorType(channel, builtin.PaymentChannelActorCodeID)
+		rt.SetEpoch(currentEpoch)
+		return rt, actor
+	}
+
+	// Generates a deal paid through the channel with a voucher for its storage fee, and funds its collateral.
+	channelDeal := func(rt *mock.Runtime, actor *marketActorTestHarness) (market.DealProposal, *paych.SignedVoucher) {
+		deal := generateDealProposal(client, provider, startEpoch, endEpoch)
+		actor.addProviderFunds(rt, deal.ProviderCollateral, mAddrs)
+		actor.addParticipantFunds(rt, client, deal.ClientCollateral)
+		return deal, mkPaymentVoucher(t, channel, &deal)
+	}
+
+	publish := func(rt *mock.Runtime, actor *marketActorTestHarness, deal market.DealProposal, voucher *paych.SignedVoucher) abi.DealID {
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		return actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal, voucher: voucher})[0]
+	}
+
+	t.Run("storage fee is paid through the channel rather than from escrow", func(t *testing.T) {
+		rt, actor := setup(t)
+		deal, voucher := channelDeal(rt, actor)
+		dealID := publish(rt, actor, deal, voucher)
+
+		// Only collateral is locked, and the payment is recorded against the proposal.
+		assert.Equal(t, deal.ClientCollateral, actor.getLockedBalance(rt, client))
+		actor.assertLockedFundStates(rt, big.Zero(), deal.ProviderCollateral, deal.ClientCollateral)
+		payment, found := actor.getDealPayment(rt, &deal)
+		require.True(t, found)
+		assert.Equal(t, dealID, payment.DealID)
+		assert.Equal(t, channel, payment.Channel)
+		assert.Equal(t, *voucher, payment.Voucher)
+		actor.checkState(rt)
+
+		expectGetLane(rt, voucher, fundedLane(client, provider, voucher))
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealID)
+		_, found = actor.getDealPayment(rt, &deal)
+		assert.True(t, found)
+		actor.checkState(rt)
+
+		// No payment is made from escrow, and collateral is unlocked at expiry.
+		rt.SetEpoch(processEpoch(t, dealID, startEpoch))
+		actor.cronTick(rt)
+		rt.SetEpoch(market.GenRandNextEpoch(endEpoch, dealID))
+		actor.cronTick(rt)
+		assert.Equal(t, deal.ClientCollateral, actor.getEscrowBalance(rt, client))
+		assert.Equal(t, deal.ProviderCollateral, actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, client))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, provider))
+		actor.assertLockedFundStates(rt, big.Zero(), big.Zero(), big.Zero())
+		actor.assertDealDeleted(rt, dealID, &deal)
+		_, found = actor.getDealPayment(rt, &deal)
+		assert.False(t, found)
+		actor.checkState(rt)
+	})
+
+	t.Run("publish drops deals with invalid payment terms", func(t *testing.T) {
+		tcs := map[string]struct {
+			setup func(*mock.Runtime, *market.DealProposal, *paych.SignedVoucher, *paych.GetLaneReturn)
+			// Whether the voucher passes the checks made before its signature is verified.
+			verifySig bool
+			sigErr    error
+			// Whether the voucher passes the checks made before its lane is queried.
+			getLane bool
+		}{
+			"channel is not a payment channel actor": {
+				setup: func(rt *mock.Runtime, _ *market.DealProposal, _ *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					rt.SetAddressActorType(channel, builtin.AccountActorCodeID)
+				},
+			},
+			"voucher not redeemable from start epoch": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					sv.TimeLockMin = startEpoch + 1
+				},
+			},
+			"voucher expires before end epoch": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					sv.TimeLockMax = endEpoch - 1
+				},
+			},
+			"voucher redemption conditional on a secret": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					sv.SecretHash = []byte("secret")
+				},
+			},
+			"voucher merges other lanes": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					sv.Merges = []paych.Merge{{Lane: 1, Nonce: 1}}
+				},
+			},
+			"voucher redemption not conditional on deal": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					sv.Extra = nil
+				},
+			},
+			"voucher redemption conditional on another method": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					sv.Extra.Method = builtin.MethodsMarket.GetDealActivation
+				},
+			},
+			"voucher redemption conditional on another deal": {
+				setup: func(_ *mock.Runtime, d *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					other := *d
+					other.EndEpoch++
+					sv.Extra = mkPaymentVoucher(t, channel, &other).Extra
+				},
+			},
+			"voucher signature is invalid": {
+				setup:     func(_ *mock.Runtime, _ *market.DealProposal, _ *paych.SignedVoucher, _ *paych.GetLaneReturn) {},
+				verifySig: true,
+				sigErr:    errors.New("error"),
+			},
+			"channel not from client": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, _ *paych.SignedVoucher, lane *paych.GetLaneReturn) {
+					lane.From = tutil.NewIDAddr(t, 106)
+				},
+				verifySig: true,
+				getLane:   true,
+			},
+			"channel not to provider": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, _ *paych.SignedVoucher, lane *paych.GetLaneReturn) {
+					lane.To = tutil.NewIDAddr(t, 106)
+				},
+				verifySig: true,
+				getLane:   true,
+			},
+			"channel is settling": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, _ *paych.SignedVoucher, lane *paych.GetLaneReturn) {
+					lane.SettlingAt = endEpoch
+				},
+				verifySig: true,
+				getLane:   true,
+			},
+			"voucher nonce already used on lane": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, lane *paych.GetLaneReturn) {
+					lane.Lane.Nonce = sv.Nonce
+				},
+				verifySig: true,
+				getLane:   true,
+			},
+			"voucher redeems less than storage fee": {
+				setup: func(_ *mock.Runtime, d *market.DealProposal, sv *paych.SignedVoucher, _ *paych.GetLaneReturn) {
+					sv.Amount = big.Sub(d.TotalStorageFee(), big.NewInt(1))
+				},
+				verifySig: true,
+				getLane:   true,
+			},
+			"lane already redeemed part of voucher": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, _ *paych.SignedVoucher, lane *paych.GetLaneReturn) {
+					lane.Lane.Redeemed = big.NewInt(1)
+				},
+				verifySig: true,
+				getLane:   true,
+			},
+			"channel does not hold voucher amount": {
+				setup: func(_ *mock.Runtime, _ *market.DealProposal, sv *paych.SignedVoucher, lane *paych.GetLaneReturn) {
+					lane.Available = big.Sub(sv.Amount, big.NewInt(1))
+				},
+				verifySig: true,
+				getLane:   true,
+			},
+		}
+
+		for name, tc := range tcs {
+			t.Run(name, func(t *testing.T) {
+				rt, actor := setup(t)
+				deal, voucher := channelDeal(rt, actor)
+				lane := fundedLane(client, provider, voucher)
+				tc.setup(rt, &deal, voucher, lane)
+				params := &market.PublishStorageDealsParams{
+					Deals:           []market.ClientDealProposal{{Proposal: deal}},
+					PaymentVouchers: []market.DealPaymentVoucher{{DealIndex: 0, Voucher: *voucher}},
+				}
+
+				rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+				expectGetControlAddresses(rt, provider, owner, worker)
+				expectQueryNetworkInfo(rt, actor)
+				rt.SetCaller(worker, builtin.AccountActorCodeID)
+				rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&deal), nil)
+				if tc.verifySig {
+					rt.ExpectVerifySignature(*voucher.Signature, client, voucherSigningBytes(t, voucher), tc.sigErr)
+				}
+				if tc.getLane {
+					expectGetLane(rt, voucher, lane)
+				}
+				rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+					rt.Call(actor.PublishStorageDeals, params)
+				})
+
+				rt.Verify()
+				actor.checkState(rt)
+			})
+		}
+	})
+
+	t.Run("publish fails with voucher for missing or repeated deal index", func(t *testing.T) {
+		for name, indexes := range map[string][]uint64{
+			"missing":  {1},
+			"repeated": {0, 0},
+		} {
+			t.Run(name, func(t *testing.T) {
+				rt, actor := setup(t)
+				deal, voucher := channelDeal(rt, actor)
+				params := &market.PublishStorageDealsParams{Deals: []market.ClientDealProposal{{Proposal: deal}}}
+				for _, idx := range indexes {
+					params.PaymentVouchers = append(params.PaymentVouchers, market.DealPaymentVoucher{DealIndex: idx, Voucher: *voucher})
+				}
+
+				rt.SetCaller(worker, builtin.AccountActorCodeID)
+				rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+				expectGetControlAddresses(rt, provider, owner, worker)
+				rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+					rt.Call(actor.PublishStorageDeals, params)
+				})
+				rt.Verify()
+				actor.checkState(rt)
+			})
+		}
+	})
+
+	t.Run("voucher is redeemable only while deal is active", func(t *testing.T) {
+		rt, actor := setup(t)
+		deal, voucher := channelDeal(rt, actor)
+		dealID := publish(rt, actor, deal, voucher)
+		pcid, err := deal.Cid()
+		require.NoError(t, err)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is not activated", func() {
+			actor.verifyDealPayment(rt, channel, pcid)
+		})
+
+		expectGetLane(rt, voucher, fundedLane(client, provider, voucher))
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealID)
+		actor.verifyDealPayment(rt, channel, pcid)
+
+		// Only the deal's channel may verify its payment, and only for a deal paid through a channel.
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is paid through channel", func() {
+			actor.verifyDealPayment(rt, tutil.NewIDAddr(t, 106), pcid)
+		})
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			actor.verifyDealPayment(rt, channel, tutil.MakeCID("other proposal", &market.PieceCIDPrefix))
+		})
+		rt.SetCaller(channel, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.PaymentChannelActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.VerifyDealPayment, &market.VerifyDealPaymentParams{Proposal: pcid})
+		})
+		rt.Verify()
+
+		// The voucher can no longer be redeemed once the deal ends.
+		rt.SetEpoch(endEpoch)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "ended at", func() {
+			actor.verifyDealPayment(rt, channel, pcid)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("voucher is not redeemable once deal is terminated", func(t *testing.T) {
+		rt, actor := setup(t)
+		deal, voucher := channelDeal(rt, actor)
+		dealID := publish(rt, actor, deal, voucher)
+		pcid, err := deal.Cid()
+		require.NoError(t, err)
+		expectGetLane(rt, voucher, fundedLane(client, provider, voucher))
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealID)
+
+		rt.SetEpoch(startEpoch + 10)
+		actor.terminateDeals(rt, provider, dealID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "was terminated", func() {
+			actor.verifyDealPayment(rt, channel, pcid)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("activation fails when channel no longer funds voucher", func(t *testing.T) {
+		rt, actor := setup(t)
+		deal, voucher := channelDeal(rt, actor)
+		dealID := publish(rt, actor, deal, voucher)
+
+		lane := fundedLane(client, provider, voucher)
+		lane.Available = big.Zero()
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		expectGetLane(rt, voucher, lane)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "cannot activate deal", func() {
+			rt.Call(actor.ActivateDeals, mkActivateDealParams(sectorExpiry, dealID))
+		})
+		rt.Verify()
+
+		expectGetLane(rt, voucher, lane)
+		ret := actor.activateDealsBatch(rt, provider, []market.SectorDeals{{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealID}}})
+		assert.False(t, ret.Sectors[0].Activated)
+		assert.Equal(t, market.DealActivationInvalid, ret.Sectors[0].Deals[0].Outcome)
+		actor.assertDealsNotActivated(rt, rt.Epoch(), dealID)
+
+		// The deal can be activated once the channel is funded again.
+		expectGetLane(rt, voucher, fundedLane(client, provider, voucher))
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealID)
+		actor.checkState(rt)
+	})
+
+	t.Run("cancelled deal removes its payment", func(t *testing.T) {
+		rt, actor := setup(t)
+		deal, voucher := channelDeal(rt, actor)
+		dealID := publish(rt, actor, deal, voucher)
+
+		actor.cancelPendingDeal(rt, client, dealID)
+		_, found := actor.getDealPayment(rt, &deal)
+		assert.False(t, found)
+		fee := market.DealCancellationFee(deal.ClientCollateral)
+		assert.Equal(t, big.Sub(deal.ClientCollateral, fee), actor.getEscrowBalance(rt, client))
+		actor.assertLockedFundStates(rt, big.Zero(), big.Zero(), big.Zero())
+		actor.checkState(rt)
+	})
+
+	t.Run("timed out deal removes its payment", func(t *testing.T) {
+		rt, actor := setup(t)
+		deal, voucher := channelDeal(rt, actor)
+		dealID := publish(rt, actor, deal, voucher)
+
+		rt.SetEpoch(processEpoch(t, dealID, startEpoch))
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil,
+			market.CollateralPenaltyForDealActivationMissed(deal.ProviderCollateral), nil, exitcode.Ok)
+		actor.cronTick(rt)
+
+		_, found := actor.getDealPayment(rt, &deal)
+		assert.False(t, found)
+		assert.Equal(t, deal.ClientCollateral, actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, client))
+		actor.assertDealDeleted(rt, dealID, &deal)
+		actor.checkState(rt)
+	})
+
+	t.Run("deal paid through channel cannot be extended", func(t *testing.T) {
+		rt, actor := setup(t)
+		deal, voucher := channelDeal(rt, actor)
+		dealID := publish(rt, actor, deal, voucher)
+		expectGetLane(rt, voucher, fundedLane(client, provider, voucher))
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealID)
+		rt.SetEpoch(processEpoch(t, dealID, startEpoch))
+		actor.cronTick(rt)
+
+		ext := market.DealExtension{
+			DealID:                  dealID,
+			SectorNumber:            7,
+			NewEndEpoch:             endEpoch + 10,
+			NewStoragePricePerEpoch: deal.StoragePricePerEpoch,
+			NewProviderCollateral:   deal.ProviderCollateral,
+			NewClientCollateral:     deal.ClientCollateral,
+		}
+		sectors := map[abi.SectorNumber]*builtin.MinerSectorInfo{7: mkSectorInfo(7, sectorExpiry, dealID)}
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "paid through a payment channel", func() {
+			actor.extendDeals(rt, worker, mAddrs, sectors, ext)
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestOnMinerSectorsTerminate(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -1542,15 +2619,16 @@ func TestCronTick(t *testing.T) {
 		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
 
 		// move the current epoch such that the deal's last updated field is set to the start epoch of the deal
-		// and the next tick for it is scheduled at the endepoch.
+		// and the next tick for it is scheduled for its expiry.
 		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
 		actor.cronTick(rt)
+		expiryEpoch := market.GenRandNextEpoch(endEpoch, dealId)
 
 		// update last updated to some time in the future (breaks state invariants)
-		actor.updateLastUpdated(rt, dealId, endEpoch+1000)
+		actor.updateLastUpdated(rt, dealId, expiryEpoch+1000)
 
-		// set current epoch of the deal to the end epoch so it's picked up for "processing" in the next cron tick.
-		rt.SetEpoch(endEpoch)
+		// set current epoch to the deal's expiry so it's picked up for "processing" in the next cron tick.
+		rt.SetEpoch(expiryEpoch)
 
 		rt.ExpectAbort(exitcode.ErrIllegalState, func() {
 			actor.cronTick(rt)
@@ -1621,6 +2699,136 @@ func TestCronTick(t *testing.T) {
 	})
 }
 
+func TestCronTickDealOpsLimit(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	// Each deal is first processed at an epoch offset from the start by its ID.
+	startEpoch := abi.ChainEpoch(market.DealUpdatesInterval)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100*builtin.EpochsInDay
+	dealCount := int(market.CronDealOpsMax) + 10
+
+	publishAndActivate := func(rt *mock.Runtime, actor *marketActorTestHarness) ([]publishDealReq, []abi.DealID) {
+		reqs := make([]publishDealReq, dealCount)
+		clientFunds, providerFunds := big.Zero(), big.Zero()
+		for i := range reqs {
+			reqs[i].deal = generateDealProposal(client, provider, startEpoch, endEpoch+abi.ChainEpoch(i))
+			clientFunds = big.Add(clientFunds, reqs[i].deal.ClientBalanceRequirement())
+			providerFunds = big.Add(providerFunds, reqs[i].deal.ProviderCollateral)
+		}
+		actor.addParticipantFunds(rt, client, clientFunds)
+		actor.addProviderFunds(rt, providerFunds, mAddrs)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealIDs := actor.publishDeals(rt, mAddrs, reqs...)
+		actor.activateDeals(rt, sectorExpiry, provider, rt.Epoch(), dealIDs...)
+		return reqs, dealIDs
+	}
+
+	// Returns the epoch of the first deal op beyond the limit, given the epochs of all deal ops.
+	firstCarriedEpoch := func(epochs []abi.ChainEpoch) abi.ChainEpoch {
+		sorted := append([]abi.ChainEpoch{}, epochs...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		return sorted[market.CronDealOpsMax]
+	}
+
+	t.Run("deal ops beyond the limit are carried over to subsequent ticks", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(abi.ChainEpoch(5))
+		reqs, dealIDs := publishAndActivate(rt, actor)
+
+		startOps := make([]abi.ChainEpoch, dealCount)
+		for i, dealID := range dealIDs {
+			startOps[i] = processEpoch(t, dealID, startEpoch)
+		}
+		carriedEpoch := firstCarriedEpoch(startOps)
+
+		// The first tick processes deal ops up to the limit, in order of epoch.
+		// Ops at earlier epochs are all processed, while the epoch at the limit is left partially processed.
+		firstTick := processEpoch(t, dealIDs[dealCount-1], startEpoch)
+		rt.SetEpoch(firstTick)
+		actor.cronTick(rt)
+		actor.assertLastCron(rt, carriedEpoch-1)
+		assert.Equal(t, int(market.CronDealOpsMax), actor.countDealsUpdatedAt(rt, firstTick, dealIDs...))
+		for i, dealID := range dealIDs {
+			if startOps[i] < carriedEpoch {
+				assert.Equal(t, firstTick, actor.getDealState(rt, dealID).LastUpdatedEpoch)
+			}
+		}
+		actor.checkState(rt)
+
+		// The next tick processes the deal ops carried over. All deals are now scheduled for their expiry.
+		secondTick := firstTick + 1
+		rt.SetEpoch(secondTick)
+		actor.cronTick(rt)
+		actor.assertLastCron(rt, secondTick)
+		assert.Equal(t, dealCount-int(market.CronDealOpsMax), actor.countDealsUpdatedAt(rt, secondTick, dealIDs...))
+		actor.checkState(rt)
+
+		// Expiries are likewise processed up to the limit, in order of epoch.
+		expiryOps := make([]abi.ChainEpoch, dealCount)
+		for i, dealID := range dealIDs {
+			expiryOps[i] = market.GenRandNextEpoch(reqs[i].deal.EndEpoch, dealID)
+		}
+		thirdTick := expiryOps[0]
+		for _, epoch := range expiryOps {
+			if epoch > thirdTick {
+				thirdTick = epoch
+			}
+		}
+		rt.SetEpoch(thirdTick)
+		actor.cronTick(rt)
+		actor.assertLastCron(rt, firstCarriedEpoch(expiryOps)-1)
+		assert.Equal(t, dealCount-int(market.CronDealOpsMax), actor.countDealsRemaining(rt, dealIDs...))
+		actor.checkState(rt)
+
+		// The next tick expires the remaining deals.
+		fourthTick := thirdTick + 1
+		rt.SetEpoch(fourthTick)
+		actor.cronTick(rt)
+		actor.assertLastCron(rt, fourthTick)
+		for i := range dealIDs {
+			actor.assertDealDeleted(rt, dealIDs[i], &reqs[i].deal)
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("deal ops in a single epoch beyond the limit are carried over to subsequent ticks", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(abi.ChainEpoch(5))
+		_, dealIDs := publishAndActivate(rt, actor)
+
+		// Move all deal ops into a single epoch.
+		opEpoch := processEpoch(t, dealIDs[dealCount-1], startEpoch)
+		ops := make(map[abi.DealID]abi.ChainEpoch, dealCount)
+		for _, dealID := range dealIDs {
+			ops[dealID] = processEpoch(t, dealID, startEpoch)
+		}
+		actor.moveDealOps(rt, ops, opEpoch)
+
+		// Each tick processes deal ops up to the limit, leaving the epoch partially processed.
+		firstTick := opEpoch + 10
+		rt.SetEpoch(firstTick)
+		actor.cronTick(rt)
+		actor.assertLastCron(rt, opEpoch-1)
+		assert.Equal(t, int(market.CronDealOpsMax), actor.countDealsUpdatedAt(rt, firstTick, dealIDs...))
+		actor.checkState(rt)
+
+		secondTick := firstTick + 1
+		rt.SetEpoch(secondTick)
+		actor.cronTick(rt)
+		actor.assertLastCron(rt, secondTick)
+		assert.Equal(t, dealCount-int(market.CronDealOpsMax), actor.countDealsUpdatedAt(rt, secondTick, dealIDs...))
+		actor.checkState(rt)
+
+		// All deals have been processed exactly once.
+		assert.Equal(t, int(market.CronDealOpsMax), actor.countDealsUpdatedAt(rt, firstTick, dealIDs...))
+	})
+}
+
 func TestRandomCronEpochDuringPublish(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -1645,18 +2853,16 @@ func TestRandomCronEpochDuringPublish(t *testing.T) {
 		rt.SetEpoch(startEpoch)
 		actor.cronTickNoChange(rt, client, provider)
 
-		// first cron tick at process epoch will make payment and schedule the deal for next epoch
+		// first cron tick at process epoch will make payment and schedule the deal for its expiry
 		processEpoch := processEpoch(t, dealId, startEpoch)
 		rt.SetEpoch(processEpoch)
 		pay, _ := actor.cronTickAndAssertBalances(rt, client, provider, processEpoch, dealId)
 		duration := big.Sub(big.NewInt(int64(processEpoch)), big.NewInt(int64(startEpoch)))
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 
-		// payment at next epoch
-		current := rt.SetEpoch(processEpoch + market.DealUpdatesInterval)
-		pay, _ = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
-		duration = big.Sub(big.NewInt(int64(current)), big.NewInt(int64(processEpoch)))
-		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
+		// no payment is made by cron before expiry
+		rt.SetEpoch(processEpoch + market.DealUpdatesInterval)
+		actor.cronTickNoChange(rt, client, provider)
 		actor.checkState(rt)
 	})
 
@@ -1797,17 +3003,17 @@ func TestLockedFundTrackingStates(t *testing.T) {
 	clc = big.Sub(clc, d3.ClientCollateral)
 	actor.assertLockedFundStates(rt, csf, plc, clc)
 
-	// deal1 and deal2 will now be charged at epoch curr + market.DealUpdatesInterval, so nothing changes before that.
-	curr = rt.SetEpoch(curr + market.DealUpdatesInterval - 1)
+	// deal1 and deal2 will not be processed by cron again until they expire, so nothing changes.
+	curr = rt.SetEpoch(curr + market.DealUpdatesInterval)
 	actor.cronTick(rt)
 	actor.assertLockedFundStates(rt, csf, plc, clc)
 
-	// one more round of payment for deal1 and deal2
-	curr = rt.SetEpoch(curr + 1)
+	// one more round of payment for deal1 and deal2 when they are settled
 	duration := big.NewInt(market.DealUpdatesInterval)
 	payment = big.Product(big.NewInt(2), d1.StoragePricePerEpoch, duration)
 	csf = big.Sub(csf, payment)
-	actor.cronTick(rt)
+	actor.settleDealPayments(rt, worker, m1, dealId1)
+	actor.settleDealPayments(rt, worker, m2, dealId2)
 	actor.assertLockedFundStates(rt, csf, plc, clc)
 
 	// slash deal1 at 201
@@ -1815,7 +3021,7 @@ func TestLockedFundTrackingStates(t *testing.T) {
 	actor.terminateDeals(rt, m1.provider, dealId1)
 
 	// cron tick to slash deal1 and expire deal2
-	rt.SetEpoch(endEpoch)
+	rt.SetEpoch(market.GenRandNextEpoch(endEpoch, dealId2))
 	csf = big.Zero()
 	clc = big.Zero()
 	plc = big.Zero()
@@ -1899,8 +3105,8 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 
 		//  publishing verified deals
 		rt.SetCaller(worker, builtin.AccountActorCodeID)
-		dealIds := actor.publishDeals(rt, mAddrs, publishDealReq{deal1},
-			publishDealReq{deal2}, publishDealReq{deal3})
+		dealIds := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal1},
+			publishDealReq{deal: deal2}, publishDealReq{deal: deal3})
 
 		// do a cron tick for it -> all should time out and get slashed
 		// ONLY deal1 and deal2 should be sent to the Registry actor
@@ -1990,30 +3196,27 @@ func TestCronTickDealExpiry(t *testing.T) {
 		require.EqualValues(t, pay, big.Mul(big.NewInt(5), d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// Setting the current epoch to anything less than next schedule wont make any payment
-		current = rt.SetEpoch(current + market.DealUpdatesInterval - 1)
+		// cron makes no further payment before the deal expires
+		current = rt.SetEpoch(current + market.DealUpdatesInterval)
 		actor.cronTickNoChange(rt, client, provider)
 
-		// however setting the current epoch to next schedle will make the payment
-		current = rt.SetEpoch(current + 1)
+		// however settling the deal makes the payment
 		duration := big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		pay = actor.settleDealPaymentsAndAssertBalances(rt, mAddrs, client, dealId)
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
-		require.EqualValues(t, big.Zero(), slashed)
 
-		// a second cron tick for the same epoch should not change anything
-		actor.cronTickNoChange(rt, client, provider)
+		// a second settlement in the same epoch should not change anything
+		pay = actor.settleDealPaymentsAndAssertBalances(rt, mAddrs, client, dealId)
+		require.EqualValues(t, big.Zero(), pay)
 
-		// next epoch schedule
+		// settle again later
 		current = rt.SetEpoch(current + market.DealUpdatesInterval)
-		duration = big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
-		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
-		require.EqualValues(t, big.Zero(), slashed)
+		pay = actor.settleDealPaymentsAndAssertBalances(rt, mAddrs, client, dealId)
+		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 
-		// setting epoch to greater than end will expire the deal, make the payment and unlock all funds
+		// processing the deal's expiry will make the remaining payment and unlock all funds
 		duration = big.NewInt(int64(endEpoch - current))
-		current = rt.SetEpoch(endEpoch + 300)
+		current = rt.SetEpoch(market.GenRandNextEpoch(endEpoch, dealId))
 		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 		require.EqualValues(t, big.Zero(), slashed)
@@ -2219,7 +3422,7 @@ func TestCronTickDealSlashing(t *testing.T) {
 		actor.checkState(rt)
 	})
 
-	t.Run("deal is correctly processed twice in the same crontick and slashed", func(t *testing.T) {
+	t.Run("terminating a processed deal brings forward its processing and it is slashed", func(t *testing.T) {
 		t.Parallel()
 		// start epoch should equal first processing epoch for logic to work
 		// 2880 + 0 % 2880 = 2880
@@ -2228,7 +3431,7 @@ func TestCronTickDealSlashing(t *testing.T) {
 		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
 		d := actor.getDealProposal(rt, dealId)
 
-		// move the current epoch to startEpoch so next cron epoch will be start + Interval
+		// move the current epoch to startEpoch so the deal is next scheduled for its expiry
 		current := rt.SetEpoch(processEpoch(t, dealId, startEpoch))
 		pay, slashed := actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 		require.EqualValues(t, big.Zero(), pay)
@@ -2238,8 +3441,10 @@ func TestCronTickDealSlashing(t *testing.T) {
 		slashEpoch := rt.SetEpoch(current + market.DealUpdatesInterval + 1)
 		actor.terminateDeals(rt, provider, dealId)
 
+		// the deal is processed within an interval of termination rather than at its expiry
 		duration := big.NewInt(int64(slashEpoch - current))
-		current = rt.SetEpoch(current + market.DealUpdatesInterval + 2)
+		current = rt.SetEpoch(market.GenRandNextEpoch(slashEpoch+1, dealId))
+		require.Less(t, int64(current), int64(slashEpoch+market.DealUpdatesInterval+1))
 		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 		require.EqualValues(t, d.ProviderCollateral, slashed)
@@ -2295,20 +3500,14 @@ func TestCronTickDealSlashing(t *testing.T) {
 		require.EqualValues(t, pay, big.Mul(big.NewInt(int64(5+processStart-startEpoch)), d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// Setting the current epoch to before the next schedule will NOT make any changes as the deal
-		// is still not scheduled
-		current = rt.SetEpoch(current + market.DealUpdatesInterval - 1)
-		actor.cronTickNoChange(rt, client, provider)
-
-		// a second cron tick for the same epoch should not change anything
+		// Cron will NOT make any changes before the deal's expiry as it is not scheduled
+		current = rt.SetEpoch(current + market.DealUpdatesInterval)
 		actor.cronTickNoChange(rt, client, provider)
 
-		//  make another payment
-		current = rt.SetEpoch(current + 1)
+		// make another payment by settling the deal
 		duration := big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		pay = actor.settleDealPaymentsAndAssertBalances(rt, mAddrs, client, dealId)
 		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
-		require.EqualValues(t, big.Zero(), slashed)
 
 		// a second cron tick for the same epoch should not change anything
 		actor.cronTickNoChange(rt, client, provider)
@@ -2318,12 +3517,18 @@ func TestCronTickDealSlashing(t *testing.T) {
 		duration = big.NewInt(int64(slashEpoch - current))
 		actor.terminateDeals(rt, provider, dealId)
 
-		// Setting the epoch to anything less than next schedule will not make any change even though the deal is slashed
-		current = rt.SetEpoch(current + market.DealUpdatesInterval - 1)
+		// the slashed deal can no longer be settled
+		pay = actor.settleDealPaymentsAndAssertBalances(rt, mAddrs, client, dealId)
+		require.EqualValues(t, big.Zero(), pay)
+
+		// Setting the epoch to anything less than the deal's rescheduled processing will not make any change
+		// even though the deal is slashed
+		slashProcessEpoch := market.GenRandNextEpoch(slashEpoch+1, dealId)
+		current = rt.SetEpoch(slashProcessEpoch - 1)
 		actor.cronTickNoChange(rt, client, provider)
 
-		// next epoch for cron schedule  -> payment will be made and deal will be slashed
-		current = rt.SetEpoch(current + 1)
+		// at the rescheduled processing -> payment will be made and deal will be slashed
+		current = rt.SetEpoch(slashProcessEpoch)
 		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
 		require.EqualValues(t, d.ProviderCollateral, slashed)
@@ -2347,12 +3552,11 @@ func TestCronTickDealSlashing(t *testing.T) {
 		require.EqualValues(t, pay, big.Mul(big.NewInt(int64(5+processStart-startEpoch)), d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		//  Incrementing the current epoch another update interval will make another payment
+		//  Incrementing the current epoch another update interval and settling will make another payment
 		current = rt.SetEpoch(current + market.DealUpdatesInterval)
 		duration := big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		pay = actor.settleDealPaymentsAndAssertBalances(rt, mAddrs, client, dealId)
 		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
-		require.EqualValues(t, big.Zero(), slashed)
 
 		// set current epoch to deal end epoch and attempt to slash it -> should not be slashed
 		// as deal is considered to be expired.
@@ -2360,10 +3564,10 @@ func TestCronTickDealSlashing(t *testing.T) {
 		rt.SetEpoch(endEpoch)
 		actor.terminateDeals(rt, provider, dealId)
 
-		// next epoch for cron schedule is endEpoch + 300 ->
-		// setting epoch to higher than that will cause deal to be expired, payment will be made
+		// the deal remains scheduled for its expiry ->
+		// processing it then will cause deal to be expired, payment will be made
 		// and deal will NOT be slashed
-		current = rt.SetEpoch(endEpoch + 300)
+		current = rt.SetEpoch(market.GenRandNextEpoch(endEpoch, dealId))
 		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
@@ -2791,6 +3995,209 @@ func TestVerifyDealsForActivation(t *testing.T) {
 	})
 }
 
+func TestGetActiveDeals(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+	start := abi.ChainEpoch(10)
+	end := start + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := end + 200
+
+	t.Run("reports activated deals which are neither expired nor terminated", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		published := actor.generateAndPublishDeal(rt, client, mAddrs, start, end)
+		activated := actor.publishAndActivateDeal(rt, client, mAddrs, start, end+1, currentEpoch, sectorExpiry)
+		terminated := actor.publishAndActivateDeal(rt, client, mAddrs, start, end+2, currentEpoch, sectorExpiry)
+		shortLived := actor.publishAndActivateDeal(rt, client, mAddrs, start, start+180*builtin.EpochsInDay, currentEpoch, sectorExpiry)
+		unknown := abi.DealID(100)
+
+		rt.SetEpoch(start + 1)
+		actor.terminateDeals(rt, provider, terminated)
+
+		requested := []abi.DealID{unknown, shortLived, terminated, activated, published}
+		assert.Equal(t, []abi.DealID{shortLived, activated}, actor.getActiveDeals(rt, requested...))
+
+		// A deal past its end epoch is not active even before cron has processed it.
+		rt.SetEpoch(start + 180*builtin.EpochsInDay)
+		assert.Equal(t, []abi.DealID{activated}, actor.getActiveDeals(rt, requested...))
+		actor.checkState(rt)
+	})
+
+	t.Run("no deals requested", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		assert.Empty(t, actor.getActiveDeals(rt))
+		actor.checkState(rt)
+	})
+}
+
+func TestMarketQueries(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+	start := abi.ChainEpoch(10)
+	end := start + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := end + 200
+
+	t.Run("balance reports escrow and locked funds", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, start, end)
+		actor.addParticipantFunds(rt, client, big.NewInt(7))
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})
+
+		ret := actor.queryBalance(rt, client)
+		assert.Equal(t, big.Add(deal.ClientBalanceRequirement(), big.NewInt(7)), ret.Balance)
+		assert.Equal(t, deal.ClientBalanceRequirement(), ret.Locked)
+
+		ret = actor.queryBalance(rt, provider)
+		assert.Equal(t, deal.ProviderCollateral, ret.Balance)
+		assert.Equal(t, deal.ProviderCollateral, ret.Locked)
+
+		// An account with no funds in escrow has zero balance.
+		ret = actor.queryBalance(rt, owner)
+		assert.Equal(t, big.Zero(), ret.Balance)
+		assert.Equal(t, big.Zero(), ret.Locked)
+		actor.checkState(rt)
+	})
+
+	t.Run("balance fails for unresolvable address", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		unknown := tutil.NewBLSAddr(t, 1)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "failed to resolve", func() {
+			rt.Call(actor.GetBalance, &unknown)
+		})
+		rt.Verify()
+	})
+
+	t.Run("deal proposal, state and activation", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		published := actor.generateAndPublishDeal(rt, client, mAddrs, start, end)
+		activated := actor.publishAndActivateDeal(rt, client, mAddrs, start, end+1, currentEpoch, sectorExpiry)
+		terminated := actor.publishAndActivateDeal(rt, client, mAddrs, start, end+2, currentEpoch, sectorExpiry)
+		rt.SetEpoch(start + 1)
+		actor.terminateDeals(rt, provider, terminated)
+
+		assert.Equal(t, actor.getDealProposal(rt, published), actor.queryDealProposal(rt, published))
+		assert.Equal(t, actor.getDealProposal(rt, activated), actor.queryDealProposal(rt, activated))
+		assert.Equal(t, actor.getDealState(rt, activated), actor.queryDealState(rt, activated))
+		assert.Equal(t, actor.getDealState(rt, terminated), actor.queryDealState(rt, terminated))
+
+		assert.Equal(t, &market.GetDealActivationReturn{Activated: market.EpochUndefined, Terminated: market.EpochUndefined},
+			actor.queryDealActivation(rt, published))
+		assert.Equal(t, &market.GetDealActivationReturn{Activated: currentEpoch, Terminated: market.EpochUndefined},
+			actor.queryDealActivation(rt, activated))
+		assert.Equal(t, &market.GetDealActivationReturn{Activated: currentEpoch, Terminated: start + 1},
+			actor.queryDealActivation(rt, terminated))
+
+		// A pending deal has a proposal but no state.
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "no activated deal", func() {
+			rt.Call(actor.GetDealState, &market.DealQueryParams{DealID: published})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("unknown deal is not found", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		params := &market.DealQueryParams{DealID: 100}
+		for _, method := range []interface{}{actor.GetDealProposal, actor.GetDealState, actor.GetDealActivation} {
+			rt.ExpectValidateCallerAny()
+			rt.ExpectAbort(exitcode.ErrNotFound, func() {
+				rt.Call(method, params)
+			})
+			rt.Verify()
+		}
+	})
+
+	t.Run("deals by provider", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealID1 := actor.generateAndPublishDeal(rt, client, mAddrs, start, end)
+		dealID2 := actor.publishAndActivateDeal(rt, client, mAddrs, start, end+1, currentEpoch, sectorExpiry)
+
+		provider2 := tutil.NewIDAddr(t, 501)
+		mAddrs2 := &minerAddrs{owner, worker, provider2, nil}
+		rt.SetAddressActorType(provider2, builtin.StorageMinerActorCodeID)
+		otherDealID := actor.generateAndPublishDeal(rt, client, mAddrs2, start, end)
+		dealID3 := actor.generateAndPublishDeal(rt, client, mAddrs, start, end+2)
+
+		assert.Equal(t, []abi.DealID{dealID1, dealID2, dealID3}, actor.queryDealsByProvider(rt, provider))
+		assert.Equal(t, []abi.DealID{otherDealID}, actor.queryDealsByProvider(rt, provider2))
+		assert.Empty(t, actor.queryDealsByProvider(rt, client))
+		actor.checkState(rt)
+	})
+}
+
+func TestDealIndexes(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+	start := abi.ChainEpoch(10)
+	end := start + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := end + 200
+
+	t.Run("deals are indexed while proposed and removed with their proposals", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		client2 := tutil.NewIDAddr(t, 105)
+		cancelled := actor.generateAndPublishDeal(rt, client, mAddrs, start, end)
+		timedOut := actor.generateAndPublishDeal(rt, client2, mAddrs, start, end+1)
+		activated := actor.publishAndActivateDeal(rt, client, mAddrs, start, end+2, currentEpoch, sectorExpiry)
+
+		assert.Equal(t, []abi.DealID{cancelled, timedOut, activated}, actor.queryDealsByProvider(rt, provider))
+		assert.ElementsMatch(t, []abi.DealID{cancelled, activated}, actor.indexedDeals(rt, client, false))
+		assert.ElementsMatch(t, []abi.DealID{timedOut}, actor.indexedDeals(rt, client2, false))
+		actor.checkState(rt)
+
+		actor.cancelPendingDeal(rt, client, cancelled)
+		assert.Equal(t, []abi.DealID{timedOut, activated}, actor.queryDealsByProvider(rt, provider))
+		assert.ElementsMatch(t, []abi.DealID{activated}, actor.indexedDeals(rt, client, false))
+		actor.checkState(rt)
+
+		// The unactivated deal times out when first processed.
+		rt.SetEpoch(processEpoch(t, timedOut, start))
+		slashed := market.CollateralPenaltyForDealActivationMissed(actor.getDealProposal(rt, timedOut).ProviderCollateral)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, slashed, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		assert.Equal(t, []abi.DealID{activated}, actor.queryDealsByProvider(rt, provider))
+		assert.Empty(t, actor.indexedDeals(rt, client2, false))
+		actor.checkState(rt)
+
+		// The terminated deal is removed from the indexes when cron next processes it.
+		rt.SetEpoch(processEpoch(t, activated, start) + 1)
+		actor.terminateDeals(rt, provider, activated)
+		rt.SetEpoch(processEpoch(t, activated, start) + market.DealUpdatesInterval)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, actor.getDealProposal(rt, activated).ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		assert.Empty(t, actor.queryDealsByProvider(rt, provider))
+		assert.Empty(t, actor.indexedDeals(rt, client, false))
+		actor.checkState(rt)
+
+		// Addresses without deals have no entry in the indexes.
+		var st market.State
+		rt.GetState(&st)
+		emptyIndex, err := market.StoreEmptySetMultimap(rt.AdtStore(), builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		assert.Equal(t, emptyIndex, st.ProviderDeals)
+		assert.Equal(t, emptyIndex, st.ClientDeals)
+	})
+}
+
 type marketActorTestHarness struct {
 	market.Actor
 	t testing.TB
@@ -2971,6 +4378,23 @@ func (h *marketActorTestHarness) cronTickAndAssertBalances(rt *mock.Runtime, cli
 	return
 }
 
+// Settles payment for a single deal as the provider's worker, asserting the transfer from client to provider.
+func (h *marketActorTestHarness) settleDealPaymentsAndAssertBalances(rt *mock.Runtime, minerAddrs *minerAddrs, client address.Address,
+	dealId abi.DealID) abi.TokenAmount {
+	cLocked := h.getLockedBalance(rt, client)
+	cEscrow := h.getEscrowBalance(rt, client)
+	pLocked := h.getLockedBalance(rt, minerAddrs.provider)
+	pEscrow := h.getEscrowBalance(rt, minerAddrs.provider)
+
+	payment := h.settleDealPayments(rt, minerAddrs.worker, minerAddrs, dealId)[0]
+
+	require.EqualValues(h.t, big.Sub(cEscrow, payment), h.getEscrowBalance(rt, client))
+	require.EqualValues(h.t, big.Sub(cLocked, payment), h.getLockedBalance(rt, client))
+	require.EqualValues(h.t, pLocked, h.getLockedBalance(rt, minerAddrs.provider))
+	require.EqualValues(h.t, big.Add(pEscrow, payment), h.getEscrowBalance(rt, minerAddrs.provider))
+	return payment
+}
+
 func (h *marketActorTestHarness) cronTick(rt *mock.Runtime) {
 	rt.ExpectValidateCallerAddr(builtin.CronActorAddr)
 	rt.SetCaller(builtin.CronActorAddr, builtin.CronActorCodeID)
@@ -2982,6 +4406,8 @@ func (h *marketActorTestHarness) cronTick(rt *mock.Runtime) {
 
 type publishDealReq struct {
 	deal market.DealProposal
+	// Voucher for a deal paid through a payment channel.
+	voucher *paych.SignedVoucher
 }
 
 func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *minerAddrs, publishDealReqs ...publishDealReq) []abi.DealID {
@@ -2999,7 +4425,7 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 
 	var params market.PublishStorageDealsParams
 
-	for _, pdr := range publishDealReqs {
+	for i, pdr := range publishDealReqs {
 		//  create a client proposal with a valid signature
 		buf := bytes.Buffer{}
 		require.NoError(h.t, pdr.deal.MarshalCBOR(&buf), "failed to marshal deal proposal")
@@ -3009,6 +4435,11 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 
 		// expect a call to verify the above signature
 		rt.ExpectVerifySignature(sig, pdr.deal.Client, buf.Bytes(), nil)
+		if pdr.voucher != nil {
+			params.PaymentVouchers = append(params.PaymentVouchers, market.DealPaymentVoucher{DealIndex: uint64(i), Voucher: *pdr.voucher})
+			rt.ExpectVerifySignature(*pdr.voucher.Signature, pdr.deal.Client, voucherSigningBytes(h.t, pdr.voucher), nil)
+			expectGetLane(rt, pdr.voucher, fundedLane(pdr.deal.Client, pdr.deal.Provider, pdr.voucher))
+		}
 		if pdr.deal.VerifiedDeal {
 			param := &verifreg.UseBytesParams{
 				Address:  pdr.deal.Client,
@@ -3062,6 +4493,14 @@ func (h *marketActorTestHarness) assertDealsNotActivated(rt *mock.Runtime, epoch
 	}
 }
 
+func (h *marketActorTestHarness) getActiveDeals(rt *mock.Runtime, dealIDs ...abi.DealID) []abi.DealID {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetActiveDeals, &market.GetActiveDealsParams{DealIDs: dealIDs}).(*market.GetActiveDealsReturn)
+	rt.Verify()
+	return ret.DealIDs
+}
+
 func (h *marketActorTestHarness) activateDeals(rt *mock.Runtime, sectorExpiry abi.ChainEpoch, provider address.Address, currentEpoch abi.ChainEpoch, dealIDs ...abi.DealID) {
 	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
 	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
@@ -3079,6 +4518,85 @@ func (h *marketActorTestHarness) activateDeals(rt *mock.Runtime, sectorExpiry ab
 	}
 }
 
+func (h *marketActorTestHarness) activateDealsBatch(rt *mock.Runtime, provider address.Address, sectors []market.SectorDeals) *market.ActivateDealsBatchReturn {
+	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+
+	ret := rt.Call(h.ActivateDealsBatch, &market.ActivateDealsBatchParams{Sectors: sectors}).(*market.ActivateDealsBatchReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) cancelPendingDeal(rt *mock.Runtime, caller address.Address, dealID abi.DealID, expectedCallers ...address.Address) {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	if len(expectedCallers) == 0 {
+		expectedCallers = []address.Address{caller}
+	}
+	rt.ExpectValidateCallerAddr(expectedCallers...)
+
+	ret := rt.Call(h.CancelPendingDeal, &market.CancelPendingDealParams{DealID: dealID})
+	rt.Verify()
+	require.Nil(h.t, ret)
+}
+
+func (h *marketActorTestHarness) extendDeals(rt *mock.Runtime, caller address.Address, minerAddrs *minerAddrs,
+	sectors map[abi.SectorNumber]*builtin.MinerSectorInfo, exts ...market.DealExtension) {
+	h.extendDealsWithInactiveSectors(rt, caller, minerAddrs, sectors, nil, exts...)
+}
+
+// Extends deals as extendDeals, where the miner reports the given sectors as inactive.
+func (h *marketActorTestHarness) extendDealsWithInactiveSectors(rt *mock.Runtime, caller address.Address, minerAddrs *minerAddrs,
+	sectors map[abi.SectorNumber]*builtin.MinerSectorInfo, inactive map[abi.SectorNumber]bool, exts ...market.DealExtension) {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+
+	params := &market.ExtendDealsParams{}
+	for i := range exts {
+		client := h.getDealProposal(rt, exts[i].DealID).Client
+		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&exts[i]), nil)
+		params.Extensions = append(params.Extensions, market.ClientDealExtension{Extension: exts[i]})
+	}
+	expectGetControlAddresses(rt, minerAddrs.provider, minerAddrs.owner, minerAddrs.worker, minerAddrs.control...)
+
+	// Sector info is requested once per sector, in order of first reference. Absent sectors are not found.
+	requested := make(map[abi.SectorNumber]bool)
+	for _, ext := range exts {
+		if requested[ext.SectorNumber] {
+			continue
+		}
+		requested[ext.SectorNumber] = true
+		sector, ok := sectors[ext.SectorNumber]
+		exitCode := exitcode.Ok
+		if !ok {
+			sector, exitCode = mkSectorInfo(ext.SectorNumber, 0), exitcode.ErrNotFound
+		}
+		rt.ExpectSend(minerAddrs.provider, builtin.MethodsMiner.GetSectorInfo, &builtin.MinerSectorParams{SectorNumber: ext.SectorNumber},
+			big.Zero(), sector, exitCode)
+		if ok {
+			rt.ExpectSend(minerAddrs.provider, builtin.MethodsMiner.IsSectorActive, &builtin.MinerSectorParams{SectorNumber: ext.SectorNumber},
+				big.Zero(), &builtin.MinerSectorActiveReturn{Active: !inactive[ext.SectorNumber]}, exitcode.Ok)
+		}
+	}
+
+	ret := rt.Call(h.ExtendDeals, params)
+	rt.Verify()
+	require.Nil(h.t, ret)
+}
+
+func (h *marketActorTestHarness) settleDealPayments(rt *mock.Runtime, caller address.Address, minerAddrs *minerAddrs,
+	dealIDs ...abi.DealID) []abi.TokenAmount {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	expectGetControlAddresses(rt, minerAddrs.provider, minerAddrs.owner, minerAddrs.worker, minerAddrs.control...)
+
+	ret := rt.Call(h.SettleDealPayments, &market.SettleDealPaymentsParams{DealIDs: dealIDs})
+	rt.Verify()
+	settleRet, ok := ret.(*market.SettleDealPaymentsReturn)
+	require.True(h.t, ok)
+	require.Len(h.t, settleRet.Payments, len(dealIDs))
+	return settleRet.Payments
+}
+
 func (h *marketActorTestHarness) getDealProposal(rt *mock.Runtime, dealID abi.DealID) *market.DealProposal {
 	var st market.State
 	rt.GetState(&st)
@@ -3111,6 +4629,118 @@ func (h *marketActorTestHarness) assertAccountZero(rt *mock.Runtime, addr addres
 	require.Equal(h.t, big.Zero(), b)
 }
 
+func (h *marketActorTestHarness) queryBalance(rt *mock.Runtime, account address.Address) *market.GetBalanceReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetBalance, &account).(*market.GetBalanceReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) queryDealProposal(rt *mock.Runtime, dealID abi.DealID) *market.DealProposal {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetDealProposal, &market.DealQueryParams{DealID: dealID}).(*market.DealProposal)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) queryDealState(rt *mock.Runtime, dealID abi.DealID) *market.DealState {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetDealState, &market.DealQueryParams{DealID: dealID}).(*market.DealState)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) queryDealActivation(rt *mock.Runtime, dealID abi.DealID) *market.GetDealActivationReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetDealActivation, &market.DealQueryParams{DealID: dealID}).(*market.GetDealActivationReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) queryDealsByProvider(rt *mock.Runtime, provider address.Address) []abi.DealID {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetDealsByProvider, &market.GetDealsByProviderParams{Provider: provider}).(*market.GetDealsByProviderReturn)
+	rt.Verify()
+	return ret.DealIDs
+}
+
+// Returns the deals indexed by a client, or by a provider.
+func (h *marketActorTestHarness) indexedDeals(rt *mock.Runtime, party address.Address, isProvider bool) []abi.DealID {
+	var st market.State
+	rt.GetState(&st)
+	root := st.ClientDeals
+	if isProvider {
+		root = st.ProviderDeals
+	}
+	index, err := market.AsDealIndex(rt.AdtStore(), root, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+
+	var dealIDs []abi.DealID
+	require.NoError(h.t, index.ForEach(party, func(dealID abi.DealID) error {
+		dealIDs = append(dealIDs, dealID)
+		return nil
+	}))
+	return dealIDs
+}
+
+func (h *marketActorTestHarness) getDealPayment(rt *mock.Runtime, deal *market.DealProposal) (*market.DealPayment, bool) {
+	var st market.State
+	rt.GetState(&st)
+
+	payments, err := adt.AsMap(adt.AsStore(rt), st.DealPayments, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+	pcid, err := deal.Cid()
+	require.NoError(h.t, err)
+
+	var payment market.DealPayment
+	found, err := payments.Get(abi.CidKey(pcid), &payment)
+	require.NoError(h.t, err)
+	return &payment, found
+}
+
+func (h *marketActorTestHarness) verifyDealPayment(rt *mock.Runtime, channel address.Address, proposal cid.Cid) {
+	rt.SetCaller(channel, builtin.PaymentChannelActorCodeID)
+	rt.ExpectValidateCallerType(builtin.PaymentChannelActorCodeID)
+	ret := rt.Call(h.VerifyDealPayment, &market.VerifyDealPaymentParams{Proposal: proposal})
+	rt.Verify()
+	require.Nil(h.t, ret)
+}
+
+func (h *marketActorTestHarness) assertLastCron(rt *mock.Runtime, lastCron abi.ChainEpoch) {
+	var st market.State
+	rt.GetState(&st)
+	assert.Equal(h.t, lastCron, st.LastCron)
+}
+
+// Counts the deals whose state was last updated at an epoch.
+func (h *marketActorTestHarness) countDealsUpdatedAt(rt *mock.Runtime, epoch abi.ChainEpoch, dealIDs ...abi.DealID) int {
+	count := 0
+	for _, dealID := range dealIDs {
+		if h.getDealState(rt, dealID).LastUpdatedEpoch == epoch {
+			count++
+		}
+	}
+	return count
+}
+
+// Counts the deals whose proposals have not been removed.
+func (h *marketActorTestHarness) countDealsRemaining(rt *mock.Runtime, dealIDs ...abi.DealID) int {
+	var st market.State
+	rt.GetState(&st)
+	proposals, err := market.AsDealProposalArray(adt.AsStore(rt), st.Proposals)
+	require.NoError(h.t, err)
+
+	count := 0
+	for _, dealID := range dealIDs {
+		_, found, err := proposals.Get(dealID)
+		require.NoError(h.t, err)
+		if found {
+			count++
+		}
+	}
+	return count
+}
+
 func (h *marketActorTestHarness) getEscrowBalance(rt *mock.Runtime, addr address.Address) abi.TokenAmount {
 	var st market.State
 	rt.GetState(&st)
@@ -3234,6 +4864,24 @@ func (h *marketActorTestHarness) updateLastUpdated(rt *mock.Runtime, dealId abi.
 	rt.ReplaceState(&st)
 }
 
+// Moves the scheduled deal ops for deals from their current epochs to a destination epoch.
+func (h *marketActorTestHarness) moveDealOps(rt *mock.Runtime, ops map[abi.DealID]abi.ChainEpoch, dest abi.ChainEpoch) {
+	var st market.State
+	rt.GetState(&st)
+	dealOps, err := market.AsSetMultimap(adt.AsStore(rt), st.DealOpsByEpoch, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+
+	dealIDs := make([]abi.DealID, 0, len(ops))
+	for dealID, epoch := range ops { //nolint:nomaprange
+		require.NoError(h.t, dealOps.Remove(epoch, dealID))
+		dealIDs = append(dealIDs, dealID)
+	}
+	require.NoError(h.t, dealOps.PutMany(dest, dealIDs))
+	st.DealOpsByEpoch, err = dealOps.Root()
+	require.NoError(h.t, err)
+	rt.ReplaceState(&st)
+}
+
 func (h *marketActorTestHarness) deleteDealProposal(rt *mock.Runtime, dealId abi.DealID) {
 	var st market.State
 	rt.GetState(&st)
@@ -3359,6 +5007,44 @@ func mkPublishStorageParams(proposals ...market.DealProposal) *market.PublishSto
 	return m
 }
 
+// Makes a voucher for a deal's storage fee, redeemable only while the deal is active.
+func mkPaymentVoucher(t testing.TB, channel address.Address, deal *market.DealProposal) *paych.SignedVoucher {
+	pcid, err := deal.Cid()
+	require.NoError(t, err)
+	return &paych.SignedVoucher{
+		ChannelAddr: channel,
+		TimeLockMin: deal.StartEpoch,
+		Extra: &paych.ModVerifyParams{
+			Actor:  builtin.StorageMarketActorAddr,
+			Method: builtin.MethodsMarket.VerifyDealPayment,
+			Data:   mustCbor(&market.VerifyDealPaymentParams{Proposal: pcid}),
+		},
+		Nonce:     1,
+		Amount:    deal.TotalStorageFee(),
+		Signature: &crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("voucher")},
+	}
+}
+
+// Returns the state of a voucher's lane on a channel from client to provider holding exactly the voucher's amount.
+func fundedLane(client, provider address.Address, sv *paych.SignedVoucher) *paych.GetLaneReturn {
+	return &paych.GetLaneReturn{
+		From:      client,
+		To:        provider,
+		Available: sv.Amount,
+		Lane:      paych.LaneState{Redeemed: big.Zero()},
+	}
+}
+
+func expectGetLane(rt *mock.Runtime, sv *paych.SignedVoucher, lane *paych.GetLaneReturn) {
+	rt.ExpectSend(sv.ChannelAddr, builtin.MethodsPaych.GetLane, &paych.GetLaneParams{Lane: sv.Lane}, big.Zero(), lane, exitcode.Ok)
+}
+
+func voucherSigningBytes(t testing.TB, sv *paych.SignedVoucher) []byte {
+	vb, err := paych.VoucherSigningBytes(sv)
+	require.NoError(t, err)
+	return vb
+}
+
 func mkActivateDealParams(sectorExpiry abi.ChainEpoch, dealIds ...abi.DealID) *market.ActivateDealsParams {
 	return &market.ActivateDealsParams{SectorExpiry: sectorExpiry, DealIDs: dealIds}
 }
@@ -3367,6 +5053,21 @@ func mkTerminateDealParams(epoch abi.ChainEpoch, dealIds ...abi.DealID) *market.
 	return &market.OnMinerSectorsTerminateParams{Epoch: epoch, DealIDs: dealIds}
 }
 
+func mkSectorInfo(sectorNo abi.SectorNumber, expiration abi.ChainEpoch, dealIDs ...abi.DealID) *builtin.MinerSectorInfo {
+	return &builtin.MinerSectorInfo{
+		SectorNumber:          sectorNo,
+		SealedCID:             tutil.MakeCID("commr", &miner.SealedCIDPrefix),
+		DealIDs:               dealIDs,
+		Expiration:            expiration,
+		DealWeight:            big.Zero(),
+		VerifiedDealWeight:    big.Zero(),
+		InitialPledge:         big.Zero(),
+		ExpectedDayReward:     big.Zero(),
+		ExpectedStoragePledge: big.Zero(),
+		ReplacedDayReward:     big.Zero(),
+	}
+}
+
 func expectGetControlAddresses(rt *mock.Runtime, provider address.Address, owner, worker address.Address, controls ...address.Address) {
 	result := &miner.GetControlAddressesReturn{Owner: owner, Worker: worker, ControlAddrs: controls}
 	rt.ExpectSend(
@@ -26,6 +26,16 @@ var DealMaxDuration = abi.ChainEpoch(540 * builtin.EpochsInDay) // PARAM_SPEC
 // DealMaxLabelSize is the maximum size of a deal label.
 const DealMaxLabelSize = 256
 
+// Maximum number of deal ops processed in a single cron tick.
+// Remaining deal ops are carried over to subsequent ticks.
+const CronDealOpsMax = 1_000 // PARAM_SPEC
+
+// Maximum number of deals that may be extended in a single message.
+const DealExtensionsMax = 256
+
+// Maximum number of deals whose payments may be settled in a single message.
+const DealSettlementsMax = 1_000
+
 // Bounds (inclusive) on deal duration
 func DealDurationBounds(_ abi.PaddedPieceSize) (min abi.ChainEpoch, max abi.ChainEpoch) {
 	return DealMinDuration, DealMaxDuration
@@ -61,6 +71,18 @@ func CollateralPenaltyForDealActivationMissed(providerCollateral abi.TokenAmount
 	return providerCollateral
 }
 
+// Fraction of a party's locked funds for a deal that the party pays to the other party
+// to cancel the deal before it is activated.
+var DealCancellationFeeRatio = builtin.BigFrac{
+	Numerator:   big.NewInt(1), // PARAM_SPEC
+	Denominator: big.NewInt(20),
+}
+
+// Fee paid by a party to cancel a deal for which it has the given amount locked.
+func DealCancellationFee(lockedAmount abi.TokenAmount) abi.TokenAmount {
+	return big.Div(big.Mul(lockedAmount, DealCancellationFeeRatio.Numerator), DealCancellationFeeRatio.Denominator)
+}
+
 // Computes the weight for a deal proposal, which is a function of its size and duration.
 func DealWeight(proposal *DealProposal) abi.DealWeight {
 	dealDuration := big.NewInt(int64(proposal.Duration()))
@@ -52,40 +52,89 @@ func (mm *SetMultimap) Root() (cid.Cid, error) {
 }
 
 func (mm *SetMultimap) Put(epoch abi.ChainEpoch, v abi.DealID) error {
-	// Load the hamt under key, or initialize a new empty one if not found.
+	return mm.putMany(abi.UIntKey(uint64(epoch)), []abi.DealID{v})
+}
+
+func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
+	return mm.putMany(abi.UIntKey(uint64(epoch)), vs)
+}
+
+// Removes a value for a key, expecting it to be present.
+func (mm *SetMultimap) Remove(epoch abi.ChainEpoch, v abi.DealID) error {
+	return mm.remove(abi.UIntKey(uint64(epoch)), v, false)
+}
+
+// Removes all values for a key.
+func (mm *SetMultimap) RemoveAll(key abi.ChainEpoch) error {
+	if _, err := mm.mp.TryDelete(abi.UIntKey(uint64(key))); err != nil {
+		return xerrors.Errorf("failed to delete set key %v: %w", key, err)
+	}
+	return nil
+}
+
+// Removes and returns up to max values for a key, and whether any values remain under the key.
+// The key is removed when its set becomes empty.
+// Only the returned values (and at most one more) are visited, so the cost is bounded by max
+// rather than the size of the set.
+func (mm *SetMultimap) PopMany(epoch abi.ChainEpoch, max uint64) ([]abi.DealID, bool, error) {
 	k := abi.UIntKey(uint64(epoch))
 	set, found, err := mm.get(k)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	if !found {
-		set, err = adt.MakeEmptySet(mm.store, mm.innerBitwidth)
+		return nil, false, nil
+	}
+
+	var vs []abi.DealID
+	more := false
+	err = set.ForEach(func(s string) error {
+		if uint64(len(vs)) >= max {
+			more = true
+			return errStopIteration
+		}
+		v, err := parseDealKey(s)
 		if err != nil {
 			return err
 		}
+		vs = append(vs, v)
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		return nil, false, xerrors.Errorf("failed to iterate set %v: %w", k, err)
 	}
 
-	// Add to the set.
-	if err = set.Put(dealKey(v)); err != nil {
-		return xerrors.Errorf("failed to add key to set %v: %w", epoch, err)
+	if !more {
+		if err = mm.mp.Delete(k); err != nil {
+			return nil, false, xerrors.Errorf("failed to delete set %v: %w", k, err)
+		}
+		return vs, false, nil
 	}
 
+	for _, v := range vs {
+		if err = set.Delete(dealKey(v)); err != nil {
+			return nil, false, xerrors.Errorf("failed to remove key from set %v: %w", k, err)
+		}
+	}
 	src, err := set.Root()
 	if err != nil {
-		return xerrors.Errorf("failed to flush set root: %w", err)
+		return nil, false, xerrors.Errorf("failed to flush set root: %w", err)
 	}
 	// Store the new set root under key.
 	newSetRoot := cbg.CborCid(src)
-	err = mm.mp.Put(k, &newSetRoot)
-	if err != nil {
-		return xerrors.Errorf("failed to store set: %w", err)
+	if err = mm.mp.Put(k, &newSetRoot); err != nil {
+		return nil, false, xerrors.Errorf("failed to store set: %w", err)
 	}
-	return nil
+	return vs, true, nil
 }
 
-func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
+// Iterates all entries for a key, iteration halts if the function returns an error.
+func (mm *SetMultimap) ForEach(epoch abi.ChainEpoch, fn func(id abi.DealID) error) error {
+	return mm.forEach(abi.UIntKey(uint64(epoch)), fn)
+}
+
+func (mm *SetMultimap) putMany(k abi.Keyer, vs []abi.DealID) error {
 	// Load the hamt under key, or initialize a new empty one if not found.
-	k := abi.UIntKey(uint64(epoch))
 	set, found, err := mm.get(k)
 	if err != nil {
 		return err
@@ -100,7 +149,7 @@ func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
 	// Add to the set.
 	for _, v := range vs {
 		if err = set.Put(dealKey(v)); err != nil {
-			return xerrors.Errorf("failed to add key to set %v: %w", epoch, err)
+			return xerrors.Errorf("failed to add key to set %v: %w", k, err)
 		}
 	}
 
@@ -117,17 +166,51 @@ func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
 	return nil
 }
 
-// Removes all values for a key.
-func (mm *SetMultimap) RemoveAll(key abi.ChainEpoch) error {
-	if _, err := mm.mp.TryDelete(abi.UIntKey(uint64(key))); err != nil {
-		return xerrors.Errorf("failed to delete set key %v: %w", key, err)
+// Removes a value for a key, expecting it to be present.
+// If dropEmpty is set, the key is removed when its set becomes empty.
+func (mm *SetMultimap) remove(k abi.Keyer, v abi.DealID, dropEmpty bool) error {
+	set, found, err := mm.get(k)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return xerrors.Errorf("no set for key %v", k)
+	}
+
+	if err = set.Delete(dealKey(v)); err != nil {
+		return xerrors.Errorf("failed to remove key from set %v: %w", k, err)
+	}
+
+	if dropEmpty {
+		empty := true
+		if err = set.ForEach(func(string) error {
+			empty = false
+			return errStopIteration
+		}); err != nil && err != errStopIteration {
+			return xerrors.Errorf("failed to iterate set %v: %w", k, err)
+		}
+		if empty {
+			if err = mm.mp.Delete(k); err != nil {
+				return xerrors.Errorf("failed to delete set %v: %w", k, err)
+			}
+			return nil
+		}
+	}
+
+	src, err := set.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to flush set root: %w", err)
+	}
+	// Store the new set root under key.
+	newSetRoot := cbg.CborCid(src)
+	if err = mm.mp.Put(k, &newSetRoot); err != nil {
+		return xerrors.Errorf("failed to store set: %w", err)
 	}
 	return nil
 }
 
-// Iterates all entries for a key, iteration halts if the function returns an error.
-func (mm *SetMultimap) ForEach(epoch abi.ChainEpoch, fn func(id abi.DealID) error) error {
-	set, found, err := mm.get(abi.UIntKey(uint64(epoch)))
+func (mm *SetMultimap) forEach(k abi.Keyer, fn func(id abi.DealID) error) error {
+	set, found, err := mm.get(k)
 	if err != nil {
 		return err
 	}
@@ -159,6 +242,8 @@ func (mm *SetMultimap) get(key abi.Keyer) (*adt.Set, bool, error) {
 	return set, found, nil
 }
 
+var errStopIteration = xerrors.New("stop")
+
 func dealKey(e abi.DealID) abi.Keyer {
 	return abi.UIntKey(uint64(e))
 }
@@ -18,6 +18,7 @@ import (
 
 type DealSummary struct {
 	Provider         address.Address
+	Client           address.Address
 	StartEpoch       abi.ChainEpoch
 	EndEpoch         abi.ChainEpoch
 	SectorStartEpoch abi.ChainEpoch
@@ -32,6 +33,7 @@ type StateSummary struct {
 	LockTableCount       uint64
 	DealOpEpochCount     uint64
 	DealOpCount          uint64
+	DealPaymentCount     uint64
 }
 
 // Checks internal invariants of market state.
@@ -54,7 +56,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 	// Proposals
 	//
 
-	proposalCids := make(map[cid.Cid]struct{})
+	proposalCids := make(map[cid.Cid]abi.DealID)
 	maxDealID := int64(-1)
 	proposalStats := make(map[abi.DealID]*DealSummary)
 	expectedDealOps := make(map[abi.DealID]struct{})
@@ -75,12 +77,13 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 			}
 
 			// keep some state
-			proposalCids[pcid] = struct{}{}
+			proposalCids[pcid] = abi.DealID(dealID)
 			if dealID > maxDealID {
 				maxDealID = dealID
 			}
 			proposalStats[abi.DealID(dealID)] = &DealSummary{
 				Provider:         proposal.Provider,
+				Client:           proposal.Client,
 				StartEpoch:       proposal.StartEpoch,
 				EndEpoch:         proposal.EndEpoch,
 				SectorStartEpoch: abi.ChainEpoch(-1),
@@ -218,6 +221,8 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 	// Deal Ops by Epoch
 	//
 
+	acc.Require(st.LastCron <= currEpoch, "last cron epoch %d is after current epoch %d", st.LastCron, currEpoch)
+
 	dealOpEpochCount := uint64(0)
 	dealOpCount := uint64(0)
 	if dealOps, err := AsSetMultimap(store, st.DealOpsByEpoch, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth); err != nil {
@@ -231,10 +236,17 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 				return xerrors.Errorf("deal ops has key that is not an int: %s: %w", key, err)
 			}
 
+			acc.Require(abi.ChainEpoch(epoch) > st.LastCron, "deal ops remain at epoch %d, already processed by cron at %d", epoch, st.LastCron)
+
 			dealOpEpochCount++
 			return dealOps.ForEach(abi.ChainEpoch(epoch), func(id abi.DealID) error {
-				_, found := proposalStats[id]
+				stats, found := proposalStats[id]
 				acc.Require(found, "deal op found for deal id %d with missing proposal at epoch %d", id, epoch)
+				// Once processed, a deal is next processed for its expiry, unless terminated.
+				if found && stats.LastUpdatedEpoch != EpochUndefined && stats.SlashEpoch == EpochUndefined && stats.EndEpoch > currEpoch {
+					expiryEpoch := GenRandNextEpoch(stats.EndEpoch, id)
+					acc.Require(abi.ChainEpoch(epoch) == expiryEpoch, "deal op for deal id %d at epoch %d, expected at expiry %d", id, epoch, expiryEpoch)
+				}
 				delete(expectedDealOps, id)
 				dealOpCount++
 				return nil
@@ -245,6 +257,37 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 
 	acc.Require(len(expectedDealOps) == 0, "missing deal ops for proposals: %v", expectedDealOps)
 
+	//
+	// Deal Payments
+	//
+
+	dealPaymentCount := uint64(0)
+	if dealPayments, err := adt.AsMap(store, st.DealPayments, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading deal payments: %v", err)
+	} else {
+		var payment DealPayment
+		err = dealPayments.ForEach(&payment, func(key string) error {
+			proposalCID, err := cid.Parse([]byte(key))
+			if err != nil {
+				return xerrors.Errorf("deal payments has key that is not a cid: %s: %w", key, err)
+			}
+			dealID, found := proposalCids[proposalCID]
+			acc.Require(found, "deal payment for proposal %v which is not in proposals", proposalCID)
+			acc.Require(!found || dealID == payment.DealID, "deal payment for proposal %v of deal %d records deal %d", proposalCID, dealID, payment.DealID)
+			acc.Require(payment.Channel.Protocol() == address.ID, "payment channel address for deal %d is not an ID address", payment.DealID)
+			dealPaymentCount++
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating deal payments")
+	}
+
+	//
+	// Deal Indexes
+	//
+
+	checkDealIndex(acc, store, st.ProviderDeals, "provider", proposalStats, func(d *DealSummary) address.Address { return d.Provider })
+	checkDealIndex(acc, store, st.ClientDeals, "client", proposalStats, func(d *DealSummary) address.Address { return d.Client })
+
 	return &StateSummary{
 		Deals:                proposalStats,
 		PendingProposalCount: pendingProposalCount,
@@ -252,5 +295,37 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 		LockTableCount:       lockTableCount,
 		DealOpEpochCount:     dealOpEpochCount,
 		DealOpCount:          dealOpCount,
+		DealPaymentCount:     dealPaymentCount,
 	}, acc
 }
+
+// Checks that a deal index holds exactly the proposed deals, each under the address of the party it indexes.
+func checkDealIndex(acc *builtin.MessageAccumulator, store adt.Store, root cid.Cid, party string,
+	proposals map[abi.DealID]*DealSummary, partyAddr func(*DealSummary) address.Address) {
+	index, err := AsDealIndex(store, root, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		acc.Addf("error loading %s deal index: %v", party, err)
+		return
+	}
+
+	indexed := make(map[abi.DealID]struct{})
+	err = index.ForAll(func(a address.Address, dealID abi.DealID) error {
+		_, duplicate := indexed[dealID]
+		acc.Require(!duplicate, "deal %d indexed under more than one %s", dealID, party)
+		indexed[dealID] = struct{}{}
+
+		deal, found := proposals[dealID]
+		if !found {
+			acc.Addf("%s deal index has deal %d with missing proposal", party, dealID)
+			return nil
+		}
+		acc.Require(partyAddr(deal) == a, "deal %d indexed under %s %v, but proposal %s is %v", dealID, party, a, party, partyAddr(deal))
+		return nil
+	})
+	acc.RequireNoError(err, "error iterating %s deal index", party)
+
+	for dealID := range proposals { //nolint:nomaprange
+		_, found := indexed[dealID]
+		acc.Require(found, "deal %d missing from %s deal index", dealID, party)
+	}
+}
@@ -49,7 +49,8 @@ var MethodsPaych = struct {
 	UpdateChannelState abi.MethodNum
 	Settle             abi.MethodNum
 	Collect            abi.MethodNum
-}{MethodConstructor, 2, 3, 4}
+	GetLane            abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5}
 
 var MethodsMarket = struct {
 	Constructor              abi.MethodNum
@@ -61,7 +62,18 @@ var MethodsMarket = struct {
 	OnMinerSectorsTerminate  abi.MethodNum
 	ComputeDataCommitment    abi.MethodNum
 	CronTick                 abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	GetActiveDeals           abi.MethodNum
+	ActivateDealsBatch       abi.MethodNum
+	CancelPendingDeal        abi.MethodNum
+	ExtendDeals              abi.MethodNum
+	GetBalance               abi.MethodNum
+	GetDealProposal          abi.MethodNum
+	GetDealState             abi.MethodNum
+	GetDealActivation        abi.MethodNum
+	GetDealsByProvider       abi.MethodNum
+	SettleDealPayments       abi.MethodNum
+	VerifyDealPayment        abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
 
 var MethodsPower = struct {
 	Constructor              abi.MethodNum
@@ -73,37 +85,55 @@ var MethodsPower = struct {
 	Deprecated1              abi.MethodNum
 	SubmitPoRepForBulkVerify abi.MethodNum
 	CurrentTotalPower        abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	ReportConsensusFaults    abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 
 var MethodsMiner = struct {
-	Constructor              abi.MethodNum
-	ControlAddresses         abi.MethodNum
-	ChangeWorkerAddress      abi.MethodNum
-	ChangePeerID             abi.MethodNum
-	SubmitWindowedPoSt       abi.MethodNum
-	PreCommitSector          abi.MethodNum
-	ProveCommitSector        abi.MethodNum
-	ExtendSectorExpiration   abi.MethodNum
-	TerminateSectors         abi.MethodNum
-	DeclareFaults            abi.MethodNum
-	DeclareFaultsRecovered   abi.MethodNum
-	OnDeferredCronEvent      abi.MethodNum
-	CheckSectorProven        abi.MethodNum
-	ApplyRewards             abi.MethodNum
-	ReportConsensusFault     abi.MethodNum
-	WithdrawBalance          abi.MethodNum
-	ConfirmSectorProofsValid abi.MethodNum
-	ChangeMultiaddrs         abi.MethodNum
-	CompactPartitions        abi.MethodNum
-	CompactSectorNumbers     abi.MethodNum
-	ConfirmUpdateWorkerKey   abi.MethodNum
-	RepayDebt                abi.MethodNum
-	ChangeOwnerAddress       abi.MethodNum
-	DisputeWindowedPoSt      abi.MethodNum
-	PreCommitSectorBatch     abi.MethodNum
-	ProveCommitAggregate     abi.MethodNum
-	ProveReplicaUpdates      abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27}
+	Constructor                    abi.MethodNum
+	ControlAddresses               abi.MethodNum
+	ChangeWorkerAddress            abi.MethodNum
+	ChangePeerID                   abi.MethodNum
+	SubmitWindowedPoSt             abi.MethodNum
+	PreCommitSector                abi.MethodNum
+	ProveCommitSector              abi.MethodNum
+	ExtendSectorExpiration         abi.MethodNum
+	TerminateSectors               abi.MethodNum
+	DeclareFaults                  abi.MethodNum
+	DeclareFaultsRecovered         abi.MethodNum
+	OnDeferredCronEvent            abi.MethodNum
+	CheckSectorProven              abi.MethodNum
+	ApplyRewards                   abi.MethodNum
+	ReportConsensusFault           abi.MethodNum
+	WithdrawBalance                abi.MethodNum
+	ConfirmSectorProofsValid       abi.MethodNum
+	ChangeMultiaddrs               abi.MethodNum
+	CompactPartitions              abi.MethodNum
+	CompactSectorNumbers           abi.MethodNum
+	ConfirmUpdateWorkerKey         abi.MethodNum
+	RepayDebt                      abi.MethodNum
+	ChangeOwnerAddress             abi.MethodNum
+	DisputeWindowedPoSt            abi.MethodNum
+	PreCommitSectorBatch           abi.MethodNum
+	ProveCommitAggregate           abi.MethodNum
+	ProveReplicaUpdates            abi.MethodNum
+	GetSectorInfo                  abi.MethodNum
+	GetAvailableBalance            abi.MethodNum
+	GetVestingFunds                abi.MethodNum
+	GetDeadlineInfo                abi.MethodNum
+	IsSectorActive                 abi.MethodNum
+	ExtendSectorExpiration2        abi.MethodNum
+	MovePartitions                 abi.MethodNum
+	PreviewTerminationFees         abi.MethodNum
+	ChangeBeneficiary              abi.MethodNum
+	GetBeneficiary                 abi.MethodNum
+	CancelWorkerKeyChange          abi.MethodNum
+	ProveCommitSectorsNI           abi.MethodNum
+	ProveReplicaUpdates2           abi.MethodNum
+	SubmitWindowedPoStAggregate    abi.MethodNum
+	ChangeDebtRepaymentPolicy      abi.MethodNum
+	ExtendPreCommits               abi.MethodNum
+	DeclareFaultsWithRecoveryEpoch abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44}
 
 var MethodsVerifiedRegistry = struct {
 	Constructor                 abi.MethodNum
@@ -60,6 +60,36 @@ func (q BitfieldQueue) AddToQueueValues(epoch abi.ChainEpoch, values ...uint64)
 	return q.AddToQueue(epoch, bitfield.NewFromSet(values))
 }
 
+// Removes values from the queue entry for an epoch, deleting the entry if it becomes empty.
+// Unlike Cut, other values are not shifted.
+func (q BitfieldQueue) RemoveFromQueue(rawEpoch abi.ChainEpoch, values bitfield.BitField) error {
+	epoch := q.quant.QuantizeUp(rawEpoch)
+	var bf bitfield.BitField
+	if found, err := q.Array.Get(uint64(epoch), &bf); err != nil {
+		return xerrors.Errorf("failed to lookup queue epoch %v: %w", epoch, err)
+	} else if !found {
+		return nil
+	}
+
+	bf, err := bitfield.SubtractBitField(bf, values)
+	if err != nil {
+		return xerrors.Errorf("failed to subtract bitfields for queue epoch %v: %w", epoch, err)
+	}
+
+	if empty, err := bf.IsEmpty(); err != nil {
+		return xerrors.Errorf("failed to decode bitfield for queue epoch %v: %w", epoch, err)
+	} else if empty {
+		if err = q.Array.Delete(uint64(epoch)); err != nil {
+			return xerrors.Errorf("failed to delete queue epoch %v: %w", epoch, err)
+		}
+		return nil
+	}
+	if err = q.Array.Set(uint64(epoch), bf); err != nil {
+		return xerrors.Errorf("failed to set queue epoch %v: %w", epoch, err)
+	}
+	return nil
+}
+
 // Cut cuts the elements from the bits in the given bitfield out of the queue,
 // shifting other bits down and removing any newly empty entries.
 //
@@ -223,6 +223,23 @@ func TestBitfieldQueue(t *testing.T) {
 			Equals(t, queue)
 	})
 
+	t.Run("removes elements without shifting", func(t *testing.T) {
+		queue := emptyBitfieldQueueWithQuantizing(t, builtin.NewQuantSpec(5, 3), testAmtBitwidth)
+
+		require.NoError(t, queue.AddToQueueValues(abi.ChainEpoch(7), 1, 2, 3))
+		require.NoError(t, queue.AddToQueueValues(abi.ChainEpoch(12), 5, 6))
+
+		// Removal quantizes the epoch in the same way as addition.
+		require.NoError(t, queue.RemoveFromQueue(abi.ChainEpoch(5), bitfield.NewFromSet([]uint64{2, 5})))
+		require.NoError(t, queue.RemoveFromQueue(abi.ChainEpoch(13), bitfield.NewFromSet([]uint64{5, 6})))
+		// Removing from an absent epoch does nothing.
+		require.NoError(t, queue.RemoveFromQueue(abi.ChainEpoch(20), bitfield.NewFromSet([]uint64{1})))
+
+		ExpectBQ().
+			Add(abi.ChainEpoch(8), 1, 3).
+			Equals(t, queue)
+	})
+
 	t.Run("adds empty bitfield to queue", func(t *testing.T) {
 		queue := emptyBitfieldQueue(t, testAmtBitwidth)
 
@@ -13,4 +13,5 @@ const (
 	BurnMethodRepayDebt                BurnMethod = "RepayDebt"
 	BurnMethodProcessEarlyTerminations BurnMethod = "ProcessEarlyTerminations"
 	BurnMethodHandleProvingDeadline    BurnMethod = "HandleProvingDeadline "
+	BurnMethodExtendPreCommits         BurnMethod = "ExtendPreCommits"
 )
@@ -8,7 +8,9 @@ import (
 
 	address "github.com/filecoin-project/go-address"
 	abi "github.com/filecoin-project/go-state-types/abi"
+	miner1 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	proof "github.com/filecoin-project/specs-actors/actors/runtime/proof"
+	miner "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
 	cid "github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
@@ -16,7 +18,7 @@ import (
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{143}
+var lengthBufState = []byte{144}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -117,6 +119,13 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 	if err := cbg.WriteBool(w, t.DeadlineCronActive); err != nil {
 		return err
 	}
+
+	// t.ScheduledRecoveries (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.ScheduledRecoveries); err != nil {
+		return xerrors.Errorf("failed to write cid field t.ScheduledRecoveries: %w", err)
+	}
+
 	return nil
 }
 
@@ -134,7 +143,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 15 {
+	if extra != 16 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -323,10 +332,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	default:
 		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
 	}
+	// t.ScheduledRecoveries (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.ScheduledRecoveries: %w", err)
+		}
+
+		t.ScheduledRecoveries = c
+
+	}
 	return nil
 }
 
-var lengthBufMinerInfo = []byte{139}
+var lengthBufMinerInfo = []byte{143}
 
 func (t *MinerInfo) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -441,6 +462,26 @@ func (t *MinerInfo) MarshalCBOR(w io.Writer) error {
 	if err := t.PendingOwnerAddress.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.Beneficiary (address.Address) (struct)
+	if err := t.Beneficiary.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.BeneficiaryTerm (miner.BeneficiaryTerm) (struct)
+	if err := t.BeneficiaryTerm.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.PendingBeneficiaryTerm (miner.PendingBeneficiaryChange) (struct)
+	if err := t.PendingBeneficiaryTerm.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.DebtRepaymentPolicy (miner.DebtRepaymentPolicy) (struct)
+	if err := t.DebtRepaymentPolicy.MarshalCBOR(w); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -458,7 +499,7 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 11 {
+	if extra != 15 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -692,6 +733,52 @@ func (t *MinerInfo) UnmarshalCBOR(r io.Reader) error {
 			}
 		}
 
+	}
+	// t.Beneficiary (address.Address) (struct)
+
+	{
+
+		if err := t.Beneficiary.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Beneficiary: %w", err)
+		}
+
+	}
+	// t.BeneficiaryTerm (miner.BeneficiaryTerm) (struct)
+
+	{
+
+		if err := t.BeneficiaryTerm.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.BeneficiaryTerm: %w", err)
+		}
+
+	}
+	// t.PendingBeneficiaryTerm (miner.PendingBeneficiaryChange) (struct)
+
+	{
+
+		b, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b != cbg.CborNull[0] {
+			if err := br.UnreadByte(); err != nil {
+				return err
+			}
+			t.PendingBeneficiaryTerm = new(PendingBeneficiaryChange)
+			if err := t.PendingBeneficiaryTerm.UnmarshalCBOR(br); err != nil {
+				return xerrors.Errorf("unmarshaling t.PendingBeneficiaryTerm pointer: %w", err)
+			}
+		}
+
+	}
+	// t.DebtRepaymentPolicy (miner.DebtRepaymentPolicy) (struct)
+
+	{
+
+		if err := t.DebtRepaymentPolicy.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.DebtRepaymentPolicy: %w", err)
+		}
+
 	}
 	return nil
 }
@@ -1367,7 +1454,7 @@ func (t *PowerPair) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
-var lengthBufSectorPreCommitOnChainInfo = []byte{133}
+var lengthBufSectorPreCommitOnChainInfo = []byte{134}
 
 func (t *SectorPreCommitOnChainInfo) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -1410,6 +1497,17 @@ func (t *SectorPreCommitOnChainInfo) MarshalCBOR(w io.Writer) error {
 	if err := t.VerifiedDealWeight.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.ProveCommitExtension (abi.ChainEpoch) (int64)
+	if t.ProveCommitExtension >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ProveCommitExtension)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.ProveCommitExtension-1)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1427,7 +1525,7 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 5 {
+	if extra != 6 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -1492,6 +1590,31 @@ func (t *SectorPreCommitOnChainInfo) UnmarshalCBOR(r io.Reader) error {
 		}
 
 	}
+	// t.ProveCommitExtension (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.ProveCommitExtension = abi.ChainEpoch(extraI)
+	}
 	return nil
 }
 
@@ -2277,37 +2400,44 @@ func (t *WorkerKeyChange) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
-var lengthBufVestingFunds = []byte{129}
+var lengthBufBeneficiaryTerm = []byte{131}
 
-func (t *VestingFunds) MarshalCBOR(w io.Writer) error {
+func (t *BeneficiaryTerm) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufVestingFunds); err != nil {
+	if _, err := w.Write(lengthBufBeneficiaryTerm); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.Funds ([]miner.VestingFund) (slice)
-	if len(t.Funds) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.Funds was too long")
+	// t.Quota (big.Int) (struct)
+	if err := t.Quota.MarshalCBOR(w); err != nil {
+		return err
 	}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Funds))); err != nil {
+	// t.UsedQuota (big.Int) (struct)
+	if err := t.UsedQuota.MarshalCBOR(w); err != nil {
 		return err
 	}
-	for _, v := range t.Funds {
-		if err := v.MarshalCBOR(w); err != nil {
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (t *VestingFunds) UnmarshalCBOR(r io.Reader) error {
-	*t = VestingFunds{}
+func (t *BeneficiaryTerm) UnmarshalCBOR(r io.Reader) error {
+	*t = BeneficiaryTerm{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -2320,75 +2450,104 @@ func (t *VestingFunds) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 1 {
+	if extra != 3 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Funds ([]miner.VestingFund) (slice)
+	// t.Quota (big.Int) (struct)
 
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
+	{
 
-	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Funds: array too large (%d)", extra)
-	}
+		if err := t.Quota.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Quota: %w", err)
+		}
 
-	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
 	}
+	// t.UsedQuota (big.Int) (struct)
 
-	if extra > 0 {
-		t.Funds = make([]VestingFund, extra)
-	}
+	{
 
-	for i := 0; i < int(extra); i++ {
+		if err := t.UsedQuota.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.UsedQuota: %w", err)
+		}
 
-		var v VestingFund
-		if err := v.UnmarshalCBOR(br); err != nil {
+	}
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
 			return err
 		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
 
-		t.Funds[i] = v
+		t.Expiration = abi.ChainEpoch(extraI)
 	}
-
 	return nil
 }
 
-var lengthBufVestingFund = []byte{130}
+var lengthBufPendingBeneficiaryChange = []byte{133}
 
-func (t *VestingFund) MarshalCBOR(w io.Writer) error {
+func (t *PendingBeneficiaryChange) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufVestingFund); err != nil {
+	if _, err := w.Write(lengthBufPendingBeneficiaryChange); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.Epoch (abi.ChainEpoch) (int64)
-	if t.Epoch >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+	// t.NewBeneficiary (address.Address) (struct)
+	if err := t.NewBeneficiary.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewQuota (big.Int) (struct)
+	if err := t.NewQuota.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewExpiration (abi.ChainEpoch) (int64)
+	if t.NewExpiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NewExpiration)); err != nil {
 			return err
 		}
 	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.NewExpiration-1)); err != nil {
 			return err
 		}
 	}
 
-	// t.Amount (big.Int) (struct)
-	if err := t.Amount.MarshalCBOR(w); err != nil {
+	// t.ApprovedByBeneficiary (bool) (bool)
+	if err := cbg.WriteBool(w, t.ApprovedByBeneficiary); err != nil {
+		return err
+	}
+
+	// t.ApprovedByNominee (bool) (bool)
+	if err := cbg.WriteBool(w, t.ApprovedByNominee); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t *VestingFund) UnmarshalCBOR(r io.Reader) error {
-	*t = VestingFund{}
+func (t *PendingBeneficiaryChange) UnmarshalCBOR(r io.Reader) error {
+	*t = PendingBeneficiaryChange{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -2401,11 +2560,29 @@ func (t *VestingFund) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 2 {
+	if extra != 5 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Epoch (abi.ChainEpoch) (int64)
+	// t.NewBeneficiary (address.Address) (struct)
+
+	{
+
+		if err := t.NewBeneficiary.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewBeneficiary: %w", err)
+		}
+
+	}
+	// t.NewQuota (big.Int) (struct)
+
+	{
+
+		if err := t.NewQuota.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewQuota: %w", err)
+		}
+
+	}
+	// t.NewExpiration (abi.ChainEpoch) (int64)
 	{
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
@@ -2428,47 +2605,67 @@ func (t *VestingFund) UnmarshalCBOR(r io.Reader) error {
 			return fmt.Errorf("wrong type for int64 field: %d", maj)
 		}
 
-		t.Epoch = abi.ChainEpoch(extraI)
+		t.NewExpiration = abi.ChainEpoch(extraI)
 	}
-	// t.Amount (big.Int) (struct)
-
-	{
+	// t.ApprovedByBeneficiary (bool) (bool)
 
-		if err := t.Amount.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Amount: %w", err)
-		}
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.ApprovedByBeneficiary = false
+	case 21:
+		t.ApprovedByBeneficiary = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.ApprovedByNominee (bool) (bool)
 
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.ApprovedByNominee = false
+	case 21:
+		t.ApprovedByNominee = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
 	}
 	return nil
 }
 
-var lengthBufWindowedPoSt = []byte{130}
+var lengthBufVestingFunds = []byte{129}
 
-func (t *WindowedPoSt) MarshalCBOR(w io.Writer) error {
+func (t *VestingFunds) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufWindowedPoSt); err != nil {
+	if _, err := w.Write(lengthBufVestingFunds); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.Partitions (bitfield.BitField) (struct)
-	if err := t.Partitions.MarshalCBOR(w); err != nil {
-		return err
-	}
-
-	// t.Proofs ([]proof.PoStProof) (slice)
-	if len(t.Proofs) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.Proofs was too long")
+	// t.Funds ([]miner.VestingFund) (slice)
+	if len(t.Funds) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Funds was too long")
 	}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Proofs))); err != nil {
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Funds))); err != nil {
 		return err
 	}
-	for _, v := range t.Proofs {
+	for _, v := range t.Funds {
 		if err := v.MarshalCBOR(w); err != nil {
 			return err
 		}
@@ -2476,8 +2673,8 @@ func (t *WindowedPoSt) MarshalCBOR(w io.Writer) error {
 	return nil
 }
 
-func (t *WindowedPoSt) UnmarshalCBOR(r io.Reader) error {
-	*t = WindowedPoSt{}
+func (t *VestingFunds) UnmarshalCBOR(r io.Reader) error {
+	*t = VestingFunds{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -2490,16 +2687,191 @@ func (t *WindowedPoSt) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 2 {
+	if extra != 1 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Partitions (bitfield.BitField) (struct)
-
-	{
+	// t.Funds ([]miner.VestingFund) (slice)
 
-		if err := t.Partitions.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Partitions: %w", err)
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Funds: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Funds = make([]VestingFund, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v VestingFund
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Funds[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufVestingFund = []byte{130}
+
+func (t *VestingFund) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufVestingFund); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Amount (big.Int) (struct)
+	if err := t.Amount.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *VestingFund) UnmarshalCBOR(r io.Reader) error {
+	*t = VestingFund{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	// t.Amount (big.Int) (struct)
+
+	{
+
+		if err := t.Amount.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Amount: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufWindowedPoSt = []byte{131}
+
+func (t *WindowedPoSt) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufWindowedPoSt); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Partitions (bitfield.BitField) (struct)
+	if err := t.Partitions.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Proofs ([]proof.PoStProof) (slice)
+	if len(t.Proofs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Proofs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Proofs))); err != nil {
+		return err
+	}
+	for _, v := range t.Proofs {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.Aggregated (bool) (bool)
+	if err := cbg.WriteBool(w, t.Aggregated); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *WindowedPoSt) UnmarshalCBOR(r io.Reader) error {
+	*t = WindowedPoSt{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Partitions (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Partitions.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Partitions: %w", err)
 		}
 
 	}
@@ -2532,5 +2904,2738 @@ func (t *WindowedPoSt) UnmarshalCBOR(r io.Reader) error {
 		t.Proofs[i] = v
 	}
 
+	// t.Aggregated (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Aggregated = false
+	case 21:
+		t.Aggregated = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	return nil
+}
+
+var lengthBufGetSectorInfoParams = []byte{129}
+
+func (t *GetSectorInfoParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetSectorInfoParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *GetSectorInfoParams) UnmarshalCBOR(r io.Reader) error {
+	*t = GetSectorInfoParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	return nil
+}
+
+var lengthBufGetDeadlineInfoReturn = []byte{135}
+
+func (t *GetDeadlineInfoReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetDeadlineInfoReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CurrentEpoch (abi.ChainEpoch) (int64)
+	if t.CurrentEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.CurrentEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.CurrentEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.PeriodStart (abi.ChainEpoch) (int64)
+	if t.PeriodStart >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.PeriodStart)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.PeriodStart-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Index (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Index)); err != nil {
+		return err
+	}
+
+	// t.Open (abi.ChainEpoch) (int64)
+	if t.Open >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Open)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Open-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Close (abi.ChainEpoch) (int64)
+	if t.Close >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Close)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Close-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Challenge (abi.ChainEpoch) (int64)
+	if t.Challenge >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Challenge)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Challenge-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.FaultCutoff (abi.ChainEpoch) (int64)
+	if t.FaultCutoff >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.FaultCutoff)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.FaultCutoff-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *GetDeadlineInfoReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetDeadlineInfoReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 7 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.CurrentEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.CurrentEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.PeriodStart (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.PeriodStart = abi.ChainEpoch(extraI)
+	}
+	// t.Index (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Index = uint64(extra)
+
+	}
+	// t.Open (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Open = abi.ChainEpoch(extraI)
+	}
+	// t.Close (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Close = abi.ChainEpoch(extraI)
+	}
+	// t.Challenge (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Challenge = abi.ChainEpoch(extraI)
+	}
+	// t.FaultCutoff (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.FaultCutoff = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufIsSectorActiveParams = []byte{129}
+
+func (t *IsSectorActiveParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufIsSectorActiveParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *IsSectorActiveParams) UnmarshalCBOR(r io.Reader) error {
+	*t = IsSectorActiveParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	return nil
+}
+
+var lengthBufIsSectorActiveReturn = []byte{129}
+
+func (t *IsSectorActiveReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufIsSectorActiveReturn); err != nil {
+		return err
+	}
+
+	// t.Active (bool) (bool)
+	if err := cbg.WriteBool(w, t.Active); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *IsSectorActiveReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = IsSectorActiveReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Active (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Active = false
+	case 21:
+		t.Active = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	return nil
+}
+
+var lengthBufExtendSectorExpiration2Params = []byte{129}
+
+func (t *ExtendSectorExpiration2Params) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExtendSectorExpiration2Params); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Extensions ([]miner.ExpirationExtension2) (slice)
+	if len(t.Extensions) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Extensions was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Extensions))); err != nil {
+		return err
+	}
+	for _, v := range t.Extensions {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ExtendSectorExpiration2Params) UnmarshalCBOR(r io.Reader) error {
+	*t = ExtendSectorExpiration2Params{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Extensions ([]miner.ExpirationExtension2) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Extensions: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Extensions = make([]ExpirationExtension2, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ExpirationExtension2
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Extensions[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufMovePartitionsParams = []byte{131}
+
+func (t *MovePartitionsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufMovePartitionsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.OrigDeadline (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.OrigDeadline)); err != nil {
+		return err
+	}
+
+	// t.DestDeadline (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DestDeadline)); err != nil {
+		return err
+	}
+
+	// t.Partitions (bitfield.BitField) (struct)
+	if err := t.Partitions.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *MovePartitionsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = MovePartitionsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.OrigDeadline (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.OrigDeadline = uint64(extra)
+
+	}
+	// t.DestDeadline (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DestDeadline = uint64(extra)
+
+	}
+	// t.Partitions (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Partitions.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Partitions: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufPreviewTerminationFeesParams = []byte{129}
+
+func (t *PreviewTerminationFeesParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPreviewTerminationFeesParams); err != nil {
+		return err
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+	if err := t.Sectors.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *PreviewTerminationFeesParams) UnmarshalCBOR(r io.Reader) error {
+	*t = PreviewTerminationFeesParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Sectors.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Sectors: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufTerminationFeePreview = []byte{131}
+
+func (t *TerminationFeePreview) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufTerminationFeePreview); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Sectors ([]miner.SectorTerminationFee) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.TotalFee (big.Int) (struct)
+	if err := t.TotalFee.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.PledgeReleased (big.Int) (struct)
+	if err := t.PledgeReleased.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *TerminationFeePreview) UnmarshalCBOR(r io.Reader) error {
+	*t = TerminationFeePreview{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sectors ([]miner.SectorTerminationFee) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Sectors: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Sectors = make([]SectorTerminationFee, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v SectorTerminationFee
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Sectors[i] = v
+	}
+
+	// t.TotalFee (big.Int) (struct)
+
+	{
+
+		if err := t.TotalFee.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.TotalFee: %w", err)
+		}
+
+	}
+	// t.PledgeReleased (big.Int) (struct)
+
+	{
+
+		if err := t.PledgeReleased.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.PledgeReleased: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufChangeBeneficiaryParams = []byte{131}
+
+func (t *ChangeBeneficiaryParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufChangeBeneficiaryParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.NewBeneficiary (address.Address) (struct)
+	if err := t.NewBeneficiary.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewQuota (big.Int) (struct)
+	if err := t.NewQuota.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewExpiration (abi.ChainEpoch) (int64)
+	if t.NewExpiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NewExpiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.NewExpiration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ChangeBeneficiaryParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ChangeBeneficiaryParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.NewBeneficiary (address.Address) (struct)
+
+	{
+
+		if err := t.NewBeneficiary.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewBeneficiary: %w", err)
+		}
+
+	}
+	// t.NewQuota (big.Int) (struct)
+
+	{
+
+		if err := t.NewQuota.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewQuota: %w", err)
+		}
+
+	}
+	// t.NewExpiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.NewExpiration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufGetBeneficiaryReturn = []byte{130}
+
+func (t *GetBeneficiaryReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetBeneficiaryReturn); err != nil {
+		return err
+	}
+
+	// t.Active (miner.ActiveBeneficiary) (struct)
+	if err := t.Active.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Proposed (miner.PendingBeneficiaryChange) (struct)
+	if err := t.Proposed.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *GetBeneficiaryReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetBeneficiaryReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Active (miner.ActiveBeneficiary) (struct)
+
+	{
+
+		if err := t.Active.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Active: %w", err)
+		}
+
+	}
+	// t.Proposed (miner.PendingBeneficiaryChange) (struct)
+
+	{
+
+		b, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b != cbg.CborNull[0] {
+			if err := br.UnreadByte(); err != nil {
+				return err
+			}
+			t.Proposed = new(PendingBeneficiaryChange)
+			if err := t.Proposed.UnmarshalCBOR(br); err != nil {
+				return xerrors.Errorf("unmarshaling t.Proposed pointer: %w", err)
+			}
+		}
+
+	}
+	return nil
+}
+
+var lengthBufCancelWorkerKeyChangeReturn = []byte{130}
+
+func (t *CancelWorkerKeyChangeReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCancelWorkerKeyChangeReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CancelledWorker (address.Address) (struct)
+	if err := t.CancelledWorker.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.EffectiveAt (abi.ChainEpoch) (int64)
+	if t.EffectiveAt >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.EffectiveAt)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.EffectiveAt-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CancelWorkerKeyChangeReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = CancelWorkerKeyChangeReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.CancelledWorker (address.Address) (struct)
+
+	{
+
+		if err := t.CancelledWorker.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.CancelledWorker: %w", err)
+		}
+
+	}
+	// t.EffectiveAt (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.EffectiveAt = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufSubmitWindowedPoStReturn = []byte{134}
+
+func (t *SubmitWindowedPoStReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSubmitWindowedPoStReturn); err != nil {
+		return err
+	}
+
+	// t.PowerDelta (miner.PowerPair) (struct)
+	if err := t.PowerDelta.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewFaultySectors (bitfield.BitField) (struct)
+	if err := t.NewFaultySectors.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewFaultyPower (miner.PowerPair) (struct)
+	if err := t.NewFaultyPower.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RecoveredSectors (bitfield.BitField) (struct)
+	if err := t.RecoveredSectors.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RecoveredPower (miner.PowerPair) (struct)
+	if err := t.RecoveredPower.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RetractedRecoveryPower (miner.PowerPair) (struct)
+	if err := t.RetractedRecoveryPower.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SubmitWindowedPoStReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = SubmitWindowedPoStReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 6 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.PowerDelta (miner.PowerPair) (struct)
+
+	{
+
+		if err := t.PowerDelta.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.PowerDelta: %w", err)
+		}
+
+	}
+	// t.NewFaultySectors (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.NewFaultySectors.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewFaultySectors: %w", err)
+		}
+
+	}
+	// t.NewFaultyPower (miner.PowerPair) (struct)
+
+	{
+
+		if err := t.NewFaultyPower.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewFaultyPower: %w", err)
+		}
+
+	}
+	// t.RecoveredSectors (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.RecoveredSectors.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.RecoveredSectors: %w", err)
+		}
+
+	}
+	// t.RecoveredPower (miner.PowerPair) (struct)
+
+	{
+
+		if err := t.RecoveredPower.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.RecoveredPower: %w", err)
+		}
+
+	}
+	// t.RetractedRecoveryPower (miner.PowerPair) (struct)
+
+	{
+
+		if err := t.RetractedRecoveryPower.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.RetractedRecoveryPower: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufProveCommitSectorsNIParams = []byte{131}
+
+func (t *ProveCommitSectorsNIParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufProveCommitSectorsNIParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Sectors ([]miner.SectorNIActivationInfo) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.SealProof (abi.RegisteredSealProof) (int64)
+	if t.SealProof >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SealProof)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.SealProof-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.AggregateProof ([]uint8) (slice)
+	if len(t.AggregateProof) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.AggregateProof was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.AggregateProof))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.AggregateProof[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ProveCommitSectorsNIParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ProveCommitSectorsNIParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sectors ([]miner.SectorNIActivationInfo) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Sectors: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Sectors = make([]SectorNIActivationInfo, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v SectorNIActivationInfo
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Sectors[i] = v
+	}
+
+	// t.SealProof (abi.RegisteredSealProof) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.SealProof = abi.RegisteredSealProof(extraI)
+	}
+	// t.AggregateProof ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return fmt.Errorf("t.AggregateProof: byte array too large (%d)", extra)
+	}
+	if maj != cbg.MajByteString {
+		return fmt.Errorf("expected byte array")
+	}
+
+	if extra > 0 {
+		t.AggregateProof = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.AggregateProof[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+var lengthBufProveReplicaUpdates2Params = []byte{129}
+
+func (t *ProveReplicaUpdates2Params) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufProveReplicaUpdates2Params); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Updates ([]miner.ReplicaUpdate) (slice)
+	if len(t.Updates) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Updates was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Updates))); err != nil {
+		return err
+	}
+	for _, v := range t.Updates {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ProveReplicaUpdates2Params) UnmarshalCBOR(r io.Reader) error {
+	*t = ProveReplicaUpdates2Params{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Updates ([]miner.ReplicaUpdate) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Updates: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Updates = make([]miner.ReplicaUpdate, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v miner.ReplicaUpdate
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Updates[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufProveReplicaUpdates2Return = []byte{129}
+
+func (t *ProveReplicaUpdates2Return) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufProveReplicaUpdates2Return); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Results ([]miner.ReplicaUpdateResult) (slice)
+	if len(t.Results) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Results was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Results))); err != nil {
+		return err
+	}
+	for _, v := range t.Results {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ProveReplicaUpdates2Return) UnmarshalCBOR(r io.Reader) error {
+	*t = ProveReplicaUpdates2Return{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Results ([]miner.ReplicaUpdateResult) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Results: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Results = make([]ReplicaUpdateResult, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ReplicaUpdateResult
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Results[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufSubmitWindowedPoStAggregateParams = []byte{133}
+
+func (t *SubmitWindowedPoStAggregateParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSubmitWindowedPoStAggregateParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deadline (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Deadline)); err != nil {
+		return err
+	}
+
+	// t.Partitions ([]miner.PoStPartition) (slice)
+	if len(t.Partitions) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Partitions was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Partitions))); err != nil {
+		return err
+	}
+	for _, v := range t.Partitions {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.AggregateProof (proof.PoStProof) (struct)
+	if err := t.AggregateProof.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ChainCommitEpoch (abi.ChainEpoch) (int64)
+	if t.ChainCommitEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ChainCommitEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.ChainCommitEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.ChainCommitRand (abi.Randomness) (slice)
+	if len(t.ChainCommitRand) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.ChainCommitRand was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.ChainCommitRand))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.ChainCommitRand[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SubmitWindowedPoStAggregateParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SubmitWindowedPoStAggregateParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Deadline (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Deadline = uint64(extra)
+
+	}
+	// t.Partitions ([]miner.PoStPartition) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Partitions: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Partitions = make([]miner1.PoStPartition, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v miner1.PoStPartition
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Partitions[i] = v
+	}
+
+	// t.AggregateProof (proof.PoStProof) (struct)
+
+	{
+
+		if err := t.AggregateProof.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.AggregateProof: %w", err)
+		}
+
+	}
+	// t.ChainCommitEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.ChainCommitEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.ChainCommitRand (abi.Randomness) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return fmt.Errorf("t.ChainCommitRand: byte array too large (%d)", extra)
+	}
+	if maj != cbg.MajByteString {
+		return fmt.Errorf("expected byte array")
+	}
+
+	if extra > 0 {
+		t.ChainCommitRand = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.ChainCommitRand[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+var lengthBufDebtRepaymentPolicy = []byte{130}
+
+func (t *DebtRepaymentPolicy) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDebtRepaymentPolicy); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Priority (miner.DebtRepaymentPriority) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Priority)); err != nil {
+		return err
+	}
+
+	// t.MaxRepayment (big.Int) (struct)
+	if err := t.MaxRepayment.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DebtRepaymentPolicy) UnmarshalCBOR(r io.Reader) error {
+	*t = DebtRepaymentPolicy{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Priority (miner.DebtRepaymentPriority) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Priority = DebtRepaymentPriority(extra)
+
+	}
+	// t.MaxRepayment (big.Int) (struct)
+
+	{
+
+		if err := t.MaxRepayment.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MaxRepayment: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufExtendPreCommitsParams = []byte{130}
+
+func (t *ExtendPreCommitsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExtendPreCommitsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Sectors (bitfield.BitField) (struct)
+	if err := t.Sectors.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Extension (abi.ChainEpoch) (int64)
+	if t.Extension >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Extension)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Extension-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ExtendPreCommitsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ExtendPreCommitsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Sectors.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Sectors: %w", err)
+		}
+
+	}
+	// t.Extension (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Extension = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufDeclareFaultsWithRecoveryEpochParams = []byte{130}
+
+func (t *DeclareFaultsWithRecoveryEpochParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDeclareFaultsWithRecoveryEpochParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Faults ([]miner.FaultDeclaration) (slice)
+	if len(t.Faults) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Faults was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Faults))); err != nil {
+		return err
+	}
+	for _, v := range t.Faults {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.RecoveryEpoch (abi.ChainEpoch) (int64)
+	if t.RecoveryEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.RecoveryEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.RecoveryEpoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *DeclareFaultsWithRecoveryEpochParams) UnmarshalCBOR(r io.Reader) error {
+	*t = DeclareFaultsWithRecoveryEpochParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Faults ([]miner.FaultDeclaration) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Faults: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Faults = make([]miner1.FaultDeclaration, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v miner1.FaultDeclaration
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Faults[i] = v
+	}
+
+	// t.RecoveryEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.RecoveryEpoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufExpirationExtension2 = []byte{131}
+
+func (t *ExpirationExtension2) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExpirationExtension2); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deadline (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Deadline)); err != nil {
+		return err
+	}
+
+	// t.Partition (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Partition)); err != nil {
+		return err
+	}
+
+	// t.Sectors ([]miner.SectorExpiration) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ExpirationExtension2) UnmarshalCBOR(r io.Reader) error {
+	*t = ExpirationExtension2{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Deadline (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Deadline = uint64(extra)
+
+	}
+	// t.Partition (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Partition = uint64(extra)
+
+	}
+	// t.Sectors ([]miner.SectorExpiration) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Sectors: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Sectors = make([]SectorExpiration, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v SectorExpiration
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Sectors[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufSectorExpiration = []byte{130}
+
+func (t *SectorExpiration) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorExpiration); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	// t.NewExpiration (abi.ChainEpoch) (int64)
+	if t.NewExpiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NewExpiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.NewExpiration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SectorExpiration) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorExpiration{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	// t.NewExpiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.NewExpiration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufSectorTerminationFee = []byte{131}
+
+func (t *SectorTerminationFee) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorTerminationFee); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	// t.Fee (big.Int) (struct)
+	if err := t.Fee.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.InitialPledge (big.Int) (struct)
+	if err := t.InitialPledge.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SectorTerminationFee) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorTerminationFee{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	// t.Fee (big.Int) (struct)
+
+	{
+
+		if err := t.Fee.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Fee: %w", err)
+		}
+
+	}
+	// t.InitialPledge (big.Int) (struct)
+
+	{
+
+		if err := t.InitialPledge.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.InitialPledge: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufActiveBeneficiary = []byte{130}
+
+func (t *ActiveBeneficiary) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufActiveBeneficiary); err != nil {
+		return err
+	}
+
+	// t.Beneficiary (address.Address) (struct)
+	if err := t.Beneficiary.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Term (miner.BeneficiaryTerm) (struct)
+	if err := t.Term.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ActiveBeneficiary) UnmarshalCBOR(r io.Reader) error {
+	*t = ActiveBeneficiary{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Beneficiary (address.Address) (struct)
+
+	{
+
+		if err := t.Beneficiary.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Beneficiary: %w", err)
+		}
+
+	}
+	// t.Term (miner.BeneficiaryTerm) (struct)
+
+	{
+
+		if err := t.Term.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Term: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufSectorNIActivationInfo = []byte{133}
+
+func (t *SectorNIActivationInfo) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorNIActivationInfo); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	// t.SealedCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.SealedCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.SealedCID: %w", err)
+	}
+
+	// t.SealRandEpoch (abi.ChainEpoch) (int64)
+	if t.SealRandEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SealRandEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.SealRandEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	if t.Expiration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Expiration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Expiration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *SectorNIActivationInfo) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorNIActivationInfo{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	// t.SealedCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.SealedCID: %w", err)
+		}
+
+		t.SealedCID = c
+
+	}
+	// t.SealRandEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.SealRandEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.DealIDs ([]abi.DealID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
+		}
+
+		t.DealIDs[i] = abi.DealID(val)
+	}
+
+	// t.Expiration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Expiration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufReplicaUpdateResult = []byte{130}
+
+func (t *ReplicaUpdateResult) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufReplicaUpdateResult); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SectorNumber)); err != nil {
+		return err
+	}
+
+	// t.Outcome (miner.ReplicaUpdateOutcome) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Outcome)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *ReplicaUpdateResult) UnmarshalCBOR(r io.Reader) error {
+	*t = ReplicaUpdateResult{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.SectorNumber (abi.SectorNumber) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.SectorNumber = abi.SectorNumber(extra)
+
+	}
+	// t.Outcome (miner.ReplicaUpdateOutcome) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Outcome = ReplicaUpdateOutcome(extra)
+
+	}
+	return nil
+}
+
+var lengthBufSectorEvent = []byte{133}
+
+func (t *SectorEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSectorEvent); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Type (miner.SectorEventType) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Type)); err != nil {
+		return err
+	}
+
+	// t.Deadline (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Deadline)); err != nil {
+		return err
+	}
+
+	// t.Partition (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Partition)); err != nil {
+		return err
+	}
+
+	// t.Sectors (bitfield.BitField) (struct)
+	if err := t.Sectors.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.PowerDelta (miner.PowerPair) (struct)
+	if err := t.PowerDelta.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SectorEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = SectorEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Type (miner.SectorEventType) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Type = SectorEventType(extra)
+
+	}
+	// t.Deadline (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Deadline = uint64(extra)
+
+	}
+	// t.Partition (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Partition = uint64(extra)
+
+	}
+	// t.Sectors (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Sectors.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Sectors: %w", err)
+		}
+
+	}
+	// t.PowerDelta (miner.PowerPair) (struct)
+
+	{
+
+		if err := t.PowerDelta.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.PowerDelta: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufWorkerKeyChangedEvent = []byte{130}
+
+func (t *WorkerKeyChangedEvent) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufWorkerKeyChangedEvent); err != nil {
+		return err
+	}
+
+	// t.OldWorker (address.Address) (struct)
+	if err := t.OldWorker.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NewWorker (address.Address) (struct)
+	if err := t.NewWorker.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *WorkerKeyChangedEvent) UnmarshalCBOR(r io.Reader) error {
+	*t = WorkerKeyChangedEvent{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.OldWorker (address.Address) (struct)
+
+	{
+
+		if err := t.OldWorker.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.OldWorker: %w", err)
+		}
+
+	}
+	// t.NewWorker (address.Address) (struct)
+
+	{
+
+		if err := t.NewWorker.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.NewWorker: %w", err)
+		}
+
+	}
 	return nil
 }
@@ -92,6 +92,16 @@ type WindowedPoSt struct {
 	// this array will always have a single element (independent of number
 	// of partitions).
 	Proofs []proof.PoStProof
+	// Whether Proofs holds a single proof aggregating the proofs of all the partitions.
+	Aggregated bool
+}
+
+// PartitionSectorChange records the sectors of one partition affected by an operation on a deadline,
+// and the resulting change in active power.
+type PartitionSectorChange struct {
+	Partition  uint64
+	Sectors    bitfield.BitField
+	PowerDelta PowerPair
 }
 
 // Bitwidth of AMTs determined empirically from mutation patterns and projections of mainnet data.
@@ -290,18 +300,20 @@ func (d *Deadline) AddExpirationPartitions(store adt.Store, expirationEpoch abi.
 }
 
 // PopExpiredSectors terminates expired sectors from all partitions.
-// Returns the expired sector aggregates.
-func (dl *Deadline) PopExpiredSectors(store adt.Store, until abi.ChainEpoch, quant builtin.QuantSpec) (*ExpirationSet, error) {
+// Returns the expired sector aggregates, and the sectors expiring on-time and early from each partition.
+func (dl *Deadline) PopExpiredSectors(store adt.Store, until abi.ChainEpoch, quant builtin.QuantSpec) (
+	expired *ExpirationSet, onTime, early []PartitionSectorChange, err error,
+) {
 	expiredPartitions, modified, err := dl.popExpiredPartitions(store, until, quant)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	} else if !modified {
-		return NewExpirationSetEmpty(), nil // nothing to do.
+		return NewExpirationSetEmpty(), nil, nil, nil // nothing to do.
 	}
 
 	partitions, err := dl.PartitionsArray(store)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	var onTimeSectors []bitfield.BitField
@@ -331,19 +343,34 @@ func (dl *Deadline) PopExpiredSectors(store adt.Store, until abi.ChainEpoch, qua
 		allFaultyPower = allFaultyPower.Add(partExpiration.FaultyPower)
 		allOnTimePledge = big.Add(allOnTimePledge, partExpiration.OnTimePledge)
 
+		if empty, err := partExpiration.OnTimeSectors.IsEmpty(); err != nil {
+			return xerrors.Errorf("failed to count on-time expirations from partition %d: %w", partIdx, err)
+		} else if !empty {
+			// Sectors expiring early are all faulty, so all the active power expiring is on-time.
+			onTime = append(onTime, PartitionSectorChange{
+				Partition:  partIdx,
+				Sectors:    partExpiration.OnTimeSectors,
+				PowerDelta: partExpiration.ActivePower.Neg(),
+			})
+		}
 		if empty, err := partExpiration.EarlySectors.IsEmpty(); err != nil {
 			return xerrors.Errorf("failed to count early expirations from partition %d: %w", partIdx, err)
 		} else if !empty {
 			partitionsWithEarlyTerminations = append(partitionsWithEarlyTerminations, partIdx)
+			early = append(early, PartitionSectorChange{
+				Partition:  partIdx,
+				Sectors:    partExpiration.EarlySectors,
+				PowerDelta: NewPowerPairZero(),
+			})
 		}
 
 		return partitions.Set(partIdx, &partition)
 	}); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	if dl.Partitions, err = partitions.Root(); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Update early expiration bitmap.
@@ -353,42 +380,44 @@ func (dl *Deadline) PopExpiredSectors(store adt.Store, until abi.ChainEpoch, qua
 
 	allOnTimeSectors, err := bitfield.MultiMerge(onTimeSectors...)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	allEarlySectors, err := bitfield.MultiMerge(earlySectors...)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Update live sector count.
 	onTimeCount, err := allOnTimeSectors.Count()
 	if err != nil {
-		return nil, xerrors.Errorf("failed to count on-time expired sectors: %w", err)
+		return nil, nil, nil, xerrors.Errorf("failed to count on-time expired sectors: %w", err)
 	}
 	earlyCount, err := allEarlySectors.Count()
 	if err != nil {
-		return nil, xerrors.Errorf("failed to count early expired sectors: %w", err)
+		return nil, nil, nil, xerrors.Errorf("failed to count early expired sectors: %w", err)
 	}
 	dl.LiveSectors -= onTimeCount + earlyCount
 
 	dl.FaultyPower = dl.FaultyPower.Sub(allFaultyPower)
 
-	return NewExpirationSet(allOnTimeSectors, allEarlySectors, allOnTimePledge, allActivePower, allFaultyPower), nil
+	return NewExpirationSet(allOnTimeSectors, allEarlySectors, allOnTimePledge, allActivePower, allFaultyPower), onTime, early, nil
 }
 
 // Adds sectors to a deadline. It's the caller's responsibility to make sure
 // that this deadline isn't currently "open" (i.e., being proved at this point
 // in time).
 // The sectors are assumed to be non-faulty.
-// Returns the power of the added sectors (which is active yet if proven=false).
+// Returns the power of the added sectors (which is active yet if proven=false),
+// and the sectors added to each partition.
 func (dl *Deadline) AddSectors(
 	store adt.Store, partitionSize uint64, proven bool, sectors []*SectorOnChainInfo,
 	ssize abi.SectorSize, quant builtin.QuantSpec,
-) (PowerPair, error) {
+) (PowerPair, []PartitionSectorChange, error) {
 	totalPower := NewPowerPairZero()
 	if len(sectors) == 0 {
-		return totalPower, nil
+		return totalPower, nil, nil
 	}
+	var changes []PartitionSectorChange
 
 	// First update partitions, consuming the sectors
 	partitionDeadlineUpdates := make(map[abi.ChainEpoch][]uint64)
@@ -398,7 +427,7 @@ func (dl *Deadline) AddSectors(
 	{
 		partitions, err := dl.PartitionsArray(store)
 		if err != nil {
-			return NewPowerPairZero(), err
+			return NewPowerPairZero(), nil, err
 		}
 
 		partIdx := partitions.Length()
@@ -410,20 +439,20 @@ func (dl *Deadline) AddSectors(
 			// Get/create partition to update.
 			partition := new(Partition)
 			if found, err := partitions.Get(partIdx, partition); err != nil {
-				return NewPowerPairZero(), err
+				return NewPowerPairZero(), nil, err
 			} else if !found {
 				// This case will usually happen zero times.
 				// It would require adding more than a full partition in one go to happen more than once.
 				partition, err = ConstructPartition(store)
 				if err != nil {
-					return NewPowerPairZero(), err
+					return NewPowerPairZero(), nil, err
 				}
 			}
 
 			// Figure out which (if any) sectors we want to add to this partition.
 			sectorCount, err := partition.Sectors.Count()
 			if err != nil {
-				return NewPowerPairZero(), err
+				return NewPowerPairZero(), nil, err
 			}
 			if sectorCount >= partitionSize {
 				continue
@@ -436,14 +465,28 @@ func (dl *Deadline) AddSectors(
 			// Add sectors to partition.
 			partitionPower, err := partition.AddSectors(store, proven, partitionNewSectors, ssize, quant)
 			if err != nil {
-				return NewPowerPairZero(), err
+				return NewPowerPairZero(), nil, err
 			}
 			totalPower = totalPower.Add(partitionPower)
 
+			activatedPower := NewPowerPairZero()
+			if proven {
+				activatedPower = partitionPower
+			}
+			partitionSectorNos := make([]uint64, len(partitionNewSectors))
+			for i, sector := range partitionNewSectors {
+				partitionSectorNos[i] = uint64(sector.SectorNumber)
+			}
+			changes = append(changes, PartitionSectorChange{
+				Partition:  partIdx,
+				Sectors:    bitfield.NewFromSet(partitionSectorNos),
+				PowerDelta: activatedPower,
+			})
+
 			// Save partition back.
 			err = partitions.Set(partIdx, partition)
 			if err != nil {
-				return NewPowerPairZero(), err
+				return NewPowerPairZero(), nil, err
 			}
 
 			// Record deadline -> partition mapping so we can later update the deadlines.
@@ -460,7 +503,7 @@ func (dl *Deadline) AddSectors(
 		// Save partitions back.
 		dl.Partitions, err = partitions.Root()
 		if err != nil {
-			return NewPowerPairZero(), err
+			return NewPowerPairZero(), nil, err
 		}
 	}
 
@@ -468,19 +511,19 @@ func (dl *Deadline) AddSectors(
 	{
 		deadlineExpirations, err := LoadBitfieldQueue(store, dl.ExpirationsEpochs, quant, DeadlineExpirationAmtBitwidth)
 		if err != nil {
-			return NewPowerPairZero(), xerrors.Errorf("failed to load expiration epochs: %w", err)
+			return NewPowerPairZero(), nil, xerrors.Errorf("failed to load expiration epochs: %w", err)
 		}
 
 		if err = deadlineExpirations.AddManyToQueueValues(partitionDeadlineUpdates); err != nil {
-			return NewPowerPairZero(), xerrors.Errorf("failed to add expirations for new deadlines: %w", err)
+			return NewPowerPairZero(), nil, xerrors.Errorf("failed to add expirations for new deadlines: %w", err)
 		}
 
 		if dl.ExpirationsEpochs, err = deadlineExpirations.Root(); err != nil {
-			return NewPowerPairZero(), err
+			return NewPowerPairZero(), nil, err
 		}
 	}
 
-	return totalPower, nil
+	return totalPower, changes, nil
 }
 
 func (dl *Deadline) PopEarlyTerminations(store adt.Store, maxPartitions, maxSectors uint64) (result TerminationResult, hasMore bool, err error) {
@@ -590,11 +633,11 @@ func (dl *Deadline) TerminateSectors(
 	partitionSectors PartitionSectorMap,
 	ssize abi.SectorSize,
 	quant builtin.QuantSpec,
-) (powerLost PowerPair, err error) {
+) (powerLost PowerPair, changes []PartitionSectorChange, err error) {
 
 	partitions, err := dl.PartitionsArray(store)
 	if err != nil {
-		return NewPowerPairZero(), err
+		return NewPowerPairZero(), nil, err
 	}
 
 	powerLost = NewPowerPairZero()
@@ -629,18 +672,28 @@ func (dl *Deadline) TerminateSectors(
 
 		// Aggregate power lost from active sectors
 		powerLost = powerLost.Add(removed.ActivePower)
+
+		removedSectors, err := bitfield.MergeBitFields(removed.OnTimeSectors, removed.EarlySectors)
+		if err != nil {
+			return xerrors.Errorf("failed to merge terminated sectors in partition %d: %w", partIdx, err)
+		}
+		changes = append(changes, PartitionSectorChange{
+			Partition:  partIdx,
+			Sectors:    removedSectors,
+			PowerDelta: removed.ActivePower.Neg(),
+		})
 		return nil
 	}); err != nil {
-		return NewPowerPairZero(), err
+		return NewPowerPairZero(), nil, err
 	}
 
 	// save partitions back
 	dl.Partitions, err = partitions.Root()
 	if err != nil {
-		return NewPowerPairZero(), xerrors.Errorf("failed to persist partitions: %w", err)
+		return NewPowerPairZero(), nil, xerrors.Errorf("failed to persist partitions: %w", err)
 	}
 
-	return powerLost, nil
+	return powerLost, changes, nil
 }
 
 // RemovePartitions removes the specified partitions, shifting the remaining
@@ -793,13 +846,256 @@ func (dl *Deadline) RemovePartitions(store adt.Store, toRemove bitfield.BitField
 	return live, dead, removedPower, nil
 }
 
+// PopTerminatedPartitions removes fully-terminated partitions from the end of this deadline, so that the indices
+// of the remaining partitions are unchanged. A removed partition's index may be re-used by a later partition.
+// At most maxPartitions partitions are inspected, stopping at the first with live sectors, and partitions are
+// removed only while the total number of sectors removed does not exceed maxSectors.
+// The deadline must have no pending early terminations.
+// Returns the (terminated) sectors of the removed partitions, and the number of partitions removed.
+func (dl *Deadline) PopTerminatedPartitions(store adt.Store, maxPartitions, maxSectors uint64, quant builtin.QuantSpec) (
+	dead bitfield.BitField, removed uint64, err error,
+) {
+	noEarlyTerminations, err := dl.EarlyTerminations.IsEmpty()
+	if err != nil {
+		return bitfield.BitField{}, 0, xerrors.Errorf("failed to check for early terminations: %w", err)
+	}
+	if !noEarlyTerminations {
+		return bitfield.BitField{}, 0, xerrors.Errorf("cannot remove partitions from deadline with early terminations")
+	}
+
+	partitions, err := dl.PartitionsArray(store)
+	if err != nil {
+		return bitfield.BitField{}, 0, xerrors.Errorf("failed to load partitions: %w", err)
+	}
+
+	var toRemove []uint64
+	var allDeadSectors []bitfield.BitField
+	sectorCount := uint64(0)
+	for partIdx := partitions.Length(); partIdx > 0 && uint64(len(toRemove)) < maxPartitions; partIdx-- {
+		var partition Partition
+		found, err := partitions.Get(partIdx-1, &partition)
+		if err != nil {
+			return bitfield.BitField{}, 0, xerrors.Errorf("failed to load partition %d: %w", partIdx-1, err)
+		}
+		if !found {
+			return bitfield.BitField{}, 0, xerrors.Errorf("partition %d not found", partIdx-1)
+		}
+
+		live, err := partition.LiveSectors()
+		if err != nil {
+			return bitfield.BitField{}, 0, xerrors.Errorf("failed to compute live sectors for partition %d: %w", partIdx-1, err)
+		}
+		if noLive, err := live.IsEmpty(); err != nil {
+			return bitfield.BitField{}, 0, xerrors.Errorf("failed to check live sectors for partition %d: %w", partIdx-1, err)
+		} else if !noLive {
+			break
+		}
+
+		terminated, err := partition.Terminated.Count()
+		if err != nil {
+			return bitfield.BitField{}, 0, xerrors.Errorf("failed to count terminated sectors for partition %d: %w", partIdx-1, err)
+		}
+		if sectorCount+terminated > maxSectors {
+			break
+		}
+		sectorCount += terminated
+		toRemove = append(toRemove, partIdx-1)
+		allDeadSectors = append(allDeadSectors, partition.Terminated)
+	}
+
+	if len(toRemove) == 0 {
+		return bitfield.NewFromSet(nil), 0, nil
+	}
+
+	for _, partIdx := range toRemove {
+		if err := partitions.Delete(partIdx); err != nil {
+			return bitfield.BitField{}, 0, xerrors.Errorf("failed to remove partition %d: %w", partIdx, err)
+		}
+	}
+	dl.Partitions, err = partitions.Root()
+	if err != nil {
+		return bitfield.BitField{}, 0, xerrors.Errorf("failed to persist new partition table: %w", err)
+	}
+
+	dead, err = bitfield.MultiMerge(allDeadSectors...)
+	if err != nil {
+		return bitfield.BitField{}, 0, xerrors.Errorf("failed to merge dead sector bitfields: %w", err)
+	}
+	dl.TotalSectors -= sectorCount
+
+	// Removing trailing partitions shifts no other partition in the expiration queue.
+	expirationEpochs, err := LoadBitfieldQueue(store, dl.ExpirationsEpochs, quant, DeadlineExpirationAmtBitwidth)
+	if err != nil {
+		return bitfield.BitField{}, 0, xerrors.Errorf("failed to load expiration queue: %w", err)
+	}
+	if err = expirationEpochs.Cut(bitfield.NewFromSet(toRemove)); err != nil {
+		return bitfield.BitField{}, 0, xerrors.Errorf("failed cut removed partitions from deadline expiration queue: %w", err)
+	}
+	if dl.ExpirationsEpochs, err = expirationEpochs.Root(); err != nil {
+		return bitfield.BitField{}, 0, xerrors.Errorf("failed persist deadline expiration queue: %w", err)
+	}
+
+	return dead, uint64(len(toRemove)), nil
+}
+
+// MovePartitions removes the specified partitions from this deadline, shifting the remaining ones to the left,
+// and appends them to the destination deadline whole, with their faulty, recovering, unproven and terminated
+// sectors, and pending early terminations.
+// The partitions' expiration queues are re-quantized to the destination deadline.
+func (dl *Deadline) MovePartitions(store adt.Store, dest *Deadline, toMove bitfield.BitField, sectors Sectors,
+	ssize abi.SectorSize, origQuant, destQuant builtin.QuantSpec) error {
+	origPartitions, err := dl.PartitionsArray(store)
+	if err != nil {
+		return xerrors.Errorf("failed to load partitions: %w", err)
+	}
+	destPartitions, err := dest.PartitionsArray(store)
+	if err != nil {
+		return xerrors.Errorf("failed to load destination partitions: %w", err)
+	}
+
+	partitionCount := origPartitions.Length()
+	toMoveSet, err := toMove.AllMap(partitionCount)
+	if err != nil {
+		return xc.ErrIllegalArgument.Wrapf("failed to expand partitions into map: %w", err)
+	}
+
+	// Nothing to do.
+	if len(toMoveSet) == 0 {
+		return nil
+	}
+
+	for partIdx := range toMoveSet { //nolint:nomaprange
+		if partIdx >= partitionCount {
+			return xc.ErrIllegalArgument.Wrapf("partition index %d out of range [0, %d)", partIdx, partitionCount)
+		}
+	}
+	if destPartitions.Length()+uint64(len(toMoveSet)) > MaxPartitionsPerDeadline {
+		return xc.ErrIllegalArgument.Wrapf("cannot move %d partitions to deadline with %d partitions, max %d",
+			len(toMoveSet), destPartitions.Length(), MaxPartitionsPerDeadline)
+	}
+
+	keptPartitions, err := adt.MakeEmptyArray(store, DeadlinePartitionsAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to create empty array for initializing partitions: %w", err)
+	}
+	destExpirations := make(map[abi.ChainEpoch][]uint64)
+	var destEarlyTerminations []uint64
+
+	// Define all of these out here to save allocations.
+	var (
+		lazyPartition cbg.Deferred
+		byteReader    bytes.Reader
+		partition     Partition
+	)
+	if err = origPartitions.ForEach(&lazyPartition, func(partIdx int64) error {
+		// If we're keeping the partition as-is, append it to the kept partitions array.
+		if _, ok := toMoveSet[uint64(partIdx)]; !ok {
+			return keptPartitions.AppendContinuous(&lazyPartition)
+		}
+
+		byteReader.Reset(lazyPartition.Raw)
+		err := partition.UnmarshalCBOR(&byteReader)
+		byteReader.Reset(nil)
+		if err != nil {
+			return xc.ErrIllegalState.Wrapf("failed to decode partition %d: %w", partIdx, err)
+		}
+
+		destIdx := destPartitions.Length()
+		epochs, err := partition.requantizeExpirations(store, sectors, ssize, origQuant, destQuant)
+		if err != nil {
+			return xc.ErrIllegalState.Wrapf("failed to requantize expirations of partition %d: %w", partIdx, err)
+		}
+		for _, epoch := range epochs {
+			destExpirations[epoch] = append(destExpirations[epoch], destIdx)
+		}
+
+		earlyTerminated, err := adt.AsArray(store, partition.EarlyTerminated, PartitionEarlyTerminationArrayAmtBitwidth)
+		if err != nil {
+			return xc.ErrIllegalState.Wrapf("failed to load early terminations of partition %d: %w", partIdx, err)
+		}
+		if earlyTerminated.Length() > 0 {
+			destEarlyTerminations = append(destEarlyTerminations, destIdx)
+		}
+
+		allCount, err := partition.Sectors.Count()
+		if err != nil {
+			return xc.ErrIllegalState.Wrapf("failed to count sectors of partition %d: %w", partIdx, err)
+		}
+		liveSectors, err := partition.LiveSectors()
+		if err != nil {
+			return xc.ErrIllegalState.Wrapf("failed to calculate live sectors for partition %d: %w", partIdx, err)
+		}
+		liveCount, err := liveSectors.Count()
+		if err != nil {
+			return xc.ErrIllegalState.Wrapf("failed to count live sectors of partition %d: %w", partIdx, err)
+		}
+
+		dl.LiveSectors -= liveCount
+		dl.TotalSectors -= allCount
+		dl.FaultyPower = dl.FaultyPower.Sub(partition.FaultyPower)
+		dest.LiveSectors += liveCount
+		dest.TotalSectors += allCount
+		dest.FaultyPower = dest.FaultyPower.Add(partition.FaultyPower)
+
+		return destPartitions.AppendContinuous(&partition)
+	}); err != nil {
+		return xerrors.Errorf("while moving partitions: %w", err)
+	}
+
+	if dl.Partitions, err = keptPartitions.Root(); err != nil {
+		return xerrors.Errorf("failed to persist partitions: %w", err)
+	}
+	if dest.Partitions, err = destPartitions.Root(); err != nil {
+		return xerrors.Errorf("failed to persist destination partitions: %w", err)
+	}
+
+	// Shift the remaining partitions' indices in this deadline's partition bitfields and expiration queue.
+	if dl.EarlyTerminations, err = bitfield.CutBitField(dl.EarlyTerminations, toMove); err != nil {
+		return xerrors.Errorf("failed to cut moved partitions from early terminations: %w", err)
+	}
+	if dl.PartitionsPoSted, err = bitfield.CutBitField(dl.PartitionsPoSted, toMove); err != nil {
+		return xerrors.Errorf("failed to cut moved partitions from partitions proven: %w", err)
+	}
+	{
+		expirationEpochs, err := LoadBitfieldQueue(store, dl.ExpirationsEpochs, origQuant, DeadlineExpirationAmtBitwidth)
+		if err != nil {
+			return xerrors.Errorf("failed to load expiration queue: %w", err)
+		}
+		if err = expirationEpochs.Cut(toMove); err != nil {
+			return xerrors.Errorf("failed cut moved partitions from deadline expiration queue: %w", err)
+		}
+		if dl.ExpirationsEpochs, err = expirationEpochs.Root(); err != nil {
+			return xerrors.Errorf("failed persist deadline expiration queue: %w", err)
+		}
+	}
+
+	// Record the moved partitions' early terminations and expirations in the destination deadline.
+	if dest.EarlyTerminations, err = bitfield.MergeBitFields(dest.EarlyTerminations, bitfield.NewFromSet(destEarlyTerminations)); err != nil {
+		return xerrors.Errorf("failed to record early terminations of moved partitions: %w", err)
+	}
+	{
+		expirationEpochs, err := LoadBitfieldQueue(store, dest.ExpirationsEpochs, destQuant, DeadlineExpirationAmtBitwidth)
+		if err != nil {
+			return xerrors.Errorf("failed to load destination expiration queue: %w", err)
+		}
+		if err = expirationEpochs.AddManyToQueueValues(destExpirations); err != nil {
+			return xerrors.Errorf("failed to add moved partitions to destination expiration queue: %w", err)
+		}
+		if dest.ExpirationsEpochs, err = expirationEpochs.Root(); err != nil {
+			return xerrors.Errorf("failed persist destination expiration queue: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (dl *Deadline) RecordFaults(
 	store adt.Store, sectors Sectors, ssize abi.SectorSize, quant builtin.QuantSpec,
 	faultExpirationEpoch abi.ChainEpoch, partitionSectors PartitionSectorMap,
-) (powerDelta PowerPair, err error) {
+) (powerDelta PowerPair, changes []PartitionSectorChange, err error) {
 	partitions, err := dl.PartitionsArray(store)
 	if err != nil {
-		return NewPowerPairZero(), err
+		return NewPowerPairZero(), nil, err
 	}
 
 	// Record partitions with some fault, for subsequently indexing in the deadline.
@@ -826,6 +1122,11 @@ func (dl *Deadline) RecordFaults(
 			return xerrors.Errorf("failed to count new faults: %w", err)
 		} else if !empty {
 			partitionsWithFault = append(partitionsWithFault, partIdx)
+			changes = append(changes, PartitionSectorChange{
+				Partition:  partIdx,
+				Sectors:    newFaults,
+				PowerDelta: partitionPowerDelta,
+			})
 		}
 
 		err = partitions.Set(partIdx, &partition)
@@ -835,20 +1136,20 @@ func (dl *Deadline) RecordFaults(
 
 		return nil
 	}); err != nil {
-		return NewPowerPairZero(), err
+		return NewPowerPairZero(), nil, err
 	}
 
 	dl.Partitions, err = partitions.Root()
 	if err != nil {
-		return NewPowerPairZero(), xc.ErrIllegalState.Wrapf("failed to store partitions root: %w", err)
+		return NewPowerPairZero(), nil, xc.ErrIllegalState.Wrapf("failed to store partitions root: %w", err)
 	}
 
 	err = dl.AddExpirationPartitions(store, faultExpirationEpoch, partitionsWithFault, quant)
 	if err != nil {
-		return NewPowerPairZero(), xc.ErrIllegalState.Wrapf("failed to update expirations for partitions with faults: %w", err)
+		return NewPowerPairZero(), nil, xc.ErrIllegalState.Wrapf("failed to update expirations for partitions with faults: %w", err)
 	}
 
-	return powerDelta, nil
+	return powerDelta, changes, nil
 }
 
 func (dl *Deadline) DeclareFaultsRecovered(
@@ -891,17 +1192,18 @@ func (dl *Deadline) DeclareFaultsRecovered(
 }
 
 // ProcessDeadlineEnd processes all PoSt submissions, marking unproven sectors as
-// faulty and clearing failed recoveries. It returns the power delta, and any
-// power that should be penalized (new faults and failed recoveries).
+// faulty and clearing failed recoveries. It returns the power delta, any
+// power that should be penalized (new faults and failed recoveries), and the
+// sectors newly marked faulty in each partition.
 func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec, faultExpirationEpoch abi.ChainEpoch, sectors cid.Cid) (
-	powerDelta, penalizedPower PowerPair, err error,
+	powerDelta, penalizedPower PowerPair, faults []PartitionSectorChange, err error,
 ) {
 	powerDelta = NewPowerPairZero()
 	penalizedPower = NewPowerPairZero()
 
 	partitions, err := dl.PartitionsArray(store)
 	if err != nil {
-		return powerDelta, penalizedPower, xerrors.Errorf("failed to load partitions: %w", err)
+		return powerDelta, penalizedPower, nil, xerrors.Errorf("failed to load partitions: %w", err)
 	}
 
 	detectedAny := false
@@ -909,7 +1211,7 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 	for partIdx := uint64(0); partIdx < partitions.Length(); partIdx++ {
 		proven, err := dl.PartitionsPoSted.IsSet(partIdx)
 		if err != nil {
-			return powerDelta, penalizedPower, xerrors.Errorf("failed to check submission for partition %d: %w", partIdx, err)
+			return powerDelta, penalizedPower, nil, xerrors.Errorf("failed to check submission for partition %d: %w", partIdx, err)
 		}
 		if proven {
 			continue
@@ -918,10 +1220,10 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 		var partition Partition
 		found, err := partitions.Get(partIdx, &partition)
 		if err != nil {
-			return powerDelta, penalizedPower, xerrors.Errorf("failed to load partition %d: %w", partIdx, err)
+			return powerDelta, penalizedPower, nil, xerrors.Errorf("failed to load partition %d: %w", partIdx, err)
 		}
 		if !found {
-			return powerDelta, penalizedPower, xerrors.Errorf("no partition %d", partIdx)
+			return powerDelta, penalizedPower, nil, xerrors.Errorf("no partition %d", partIdx)
 		}
 
 		// If we have no recovering power/sectors, and all power is faulty, skip
@@ -933,9 +1235,10 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 		// Ok, we actually need to process this partition. Make sure we save the partition state back.
 		detectedAny = true
 
+		priorFaults := partition.Faults
 		partPowerDelta, partPenalizedPower, partNewFaultyPower, err := partition.RecordMissedPost(store, faultExpirationEpoch, quant)
 		if err != nil {
-			return powerDelta, penalizedPower, xerrors.Errorf("failed to record missed PoSt for partition %v: %w", partIdx, err)
+			return powerDelta, penalizedPower, nil, xerrors.Errorf("failed to record missed PoSt for partition %v: %w", partIdx, err)
 		}
 
 		// We marked some sectors faulty, we need to record the new
@@ -943,12 +1246,22 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 		// the miner for failing to recover power.
 		if !partNewFaultyPower.IsZero() {
 			rescheduledPartitions = append(rescheduledPartitions, partIdx)
+
+			newFaults, err := bitfield.SubtractBitField(partition.Faults, priorFaults)
+			if err != nil {
+				return powerDelta, penalizedPower, nil, xerrors.Errorf("failed to determine new faults for partition %d: %w", partIdx, err)
+			}
+			faults = append(faults, PartitionSectorChange{
+				Partition:  partIdx,
+				Sectors:    newFaults,
+				PowerDelta: partPowerDelta,
+			})
 		}
 
 		// Save new partition state.
 		err = partitions.Set(partIdx, &partition)
 		if err != nil {
-			return powerDelta, penalizedPower, xerrors.Errorf("failed to update partition %v: %w", partIdx, err)
+			return powerDelta, penalizedPower, nil, xerrors.Errorf("failed to update partition %v: %w", partIdx, err)
 		}
 
 		dl.FaultyPower = dl.FaultyPower.Add(partNewFaultyPower)
@@ -961,13 +1274,13 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 	if detectedAny {
 		dl.Partitions, err = partitions.Root()
 		if err != nil {
-			return powerDelta, penalizedPower, xc.ErrIllegalState.Wrapf("failed to store partitions: %w", err)
+			return powerDelta, penalizedPower, nil, xc.ErrIllegalState.Wrapf("failed to store partitions: %w", err)
 		}
 	}
 
 	err = dl.AddExpirationPartitions(store, faultExpirationEpoch, rescheduledPartitions, quant)
 	if err != nil {
-		return powerDelta, penalizedPower, xc.ErrIllegalState.Wrapf("failed to update deadline expiration queue: %w", err)
+		return powerDelta, penalizedPower, nil, xc.ErrIllegalState.Wrapf("failed to update deadline expiration queue: %w", err)
 	}
 
 	// Reset PoSt submissions, snapshot proofs.
@@ -976,7 +1289,7 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 	dl.OptimisticPoStSubmissionsSnapshot = dl.OptimisticPoStSubmissions
 	dl.OptimisticPoStSubmissions, err = adt.StoreEmptyArray(store, DeadlineOptimisticPoStSubmissionsAmtBitwidth)
 	if err != nil {
-		return powerDelta, penalizedPower, xerrors.Errorf("failed to clear pending proofs array: %w", err)
+		return powerDelta, penalizedPower, nil, xerrors.Errorf("failed to clear pending proofs array: %w", err)
 	}
 	// only snapshot sectors if there's a proof that might be disputed (this is equivalent to asking if the OptimisticPoStSubmissionsSnapshot is empty)
 	if dl.OptimisticPoStSubmissions != dl.OptimisticPoStSubmissionsSnapshot {
@@ -984,13 +1297,13 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 	} else {
 		emptySectorsSnapshotArrayCid, err := adt.StoreEmptyArray(store, SectorsAmtBitwidth)
 		if err != nil {
-			return powerDelta, penalizedPower, xc.ErrIllegalState.Wrapf("failed to zero out the sectors snapshot: %w", err)
+			return powerDelta, penalizedPower, nil, xc.ErrIllegalState.Wrapf("failed to zero out the sectors snapshot: %w", err)
 		}
 
 		dl.SectorsSnapshot = emptySectorsSnapshotArrayCid
 	}
 
-	return powerDelta, penalizedPower, nil
+	return powerDelta, penalizedPower, faults, nil
 }
 
 type PoStResult struct {
@@ -1004,6 +1317,12 @@ type PoStResult struct {
 	IgnoredSectors bitfield.BitField
 	// Bitfield of partitions that were proven.
 	Partitions bitfield.BitField
+	// Skipped sectors newly recorded as faulty.
+	NewFaultySectors bitfield.BitField
+	// Sectors recovered from faults.
+	RecoveredSectors bitfield.BitField
+	// New faults and recoveries in each proven partition, with their power deltas.
+	PartitionFaults, PartitionRecoveries []PartitionSectorChange
 }
 
 // RecordProvenSectors processes a series of posts, recording proven partitions
@@ -1047,6 +1366,9 @@ func (dl *Deadline) RecordProvenSectors(
 
 	allSectors := make([]bitfield.BitField, 0, len(postPartitions))
 	allIgnored := make([]bitfield.BitField, 0, len(postPartitions))
+	allNewFaults := make([]bitfield.BitField, 0, len(postPartitions))
+	allRecovered := make([]bitfield.BitField, 0, len(postPartitions))
+	var partitionFaults, partitionRecoveries []PartitionSectorChange
 	newFaultyPowerTotal := NewPowerPairZero()
 	retractedRecoveryPowerTotal := NewPowerPairZero()
 	recoveredPowerTotal := NewPowerPairZero()
@@ -1065,23 +1387,44 @@ func (dl *Deadline) RecordProvenSectors(
 
 		// Process new faults and accumulate new faulty power.
 		// This updates the faults in partition state ahead of calculating the sectors to include for proof.
+		priorFaults := partition.Faults
 		newPowerDelta, newFaultPower, retractedRecoveryPower, hasNewFaults, err := partition.RecordSkippedFaults(
 			store, sectors, ssize, quant, faultExpiration, post.Skipped,
 		)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to add skipped faults to partition %d: %w", post.Index, err)
 		}
+		newFaults, err := bitfield.SubtractBitField(partition.Faults, priorFaults)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to determine new faults for partition %d: %w", post.Index, err)
+		}
+		allNewFaults = append(allNewFaults, newFaults)
 
 		// If we have new faulty power, we've added some faults. We need
 		// to record the new expiration in the deadline.
 		if hasNewFaults {
 			rescheduledPartitions = append(rescheduledPartitions, post.Index)
+			partitionFaults = append(partitionFaults, PartitionSectorChange{
+				Partition:  post.Index,
+				Sectors:    newFaults,
+				PowerDelta: newPowerDelta,
+			})
 		}
 
+		// Retracted recoveries have been removed, so all remaining recoveries are recovered by this proof.
+		recovered := partition.Recoveries
+		allRecovered = append(allRecovered, recovered)
 		recoveredPower, err := partition.RecoverFaults(store, sectors, ssize, quant)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to recover faulty sectors for partition %d: %w", post.Index, err)
 		}
+		if !recoveredPower.IsZero() {
+			partitionRecoveries = append(partitionRecoveries, PartitionSectorChange{
+				Partition:  post.Index,
+				Sectors:    recovered,
+				PowerDelta: recoveredPower,
+			})
+		}
 
 		// Finally, activate power for newly proven sectors.
 		newPowerDelta = newPowerDelta.Add(partition.ActivateUnproven())
@@ -1129,6 +1472,14 @@ func (dl *Deadline) RecordProvenSectors(
 	if err != nil {
 		return nil, xc.ErrIllegalState.Wrapf("failed to merge ignored sectors bitfields: %w", err)
 	}
+	allNewFaultNos, err := bitfield.MultiMerge(allNewFaults...)
+	if err != nil {
+		return nil, xc.ErrIllegalState.Wrapf("failed to merge new fault bitfields: %w", err)
+	}
+	allRecoveredNos, err := bitfield.MultiMerge(allRecovered...)
+	if err != nil {
+		return nil, xc.ErrIllegalState.Wrapf("failed to merge recovered sectors bitfields: %w", err)
+	}
 
 	return &PoStResult{
 		Sectors:                allSectorNos,
@@ -1138,12 +1489,20 @@ func (dl *Deadline) RecordProvenSectors(
 		RecoveredPower:         recoveredPowerTotal,
 		RetractedRecoveryPower: retractedRecoveryPowerTotal,
 		Partitions:             partitionIndexes,
+		NewFaultySectors:       allNewFaultNos,
+		RecoveredSectors:       allRecoveredNos,
+		PartitionFaults:        partitionFaults,
+		PartitionRecoveries:    partitionRecoveries,
 	}, nil
 }
 
 // RecordPoStProofs records a set of optimistically accepted PoSt proofs
 // (usually one), associating them with the given partitions.
-func (dl *Deadline) RecordPoStProofs(store adt.Store, partitions bitfield.BitField, proofs []proof.PoStProof) error {
+// If aggregated, the proofs must comprise a single aggregate of the proofs of each partition.
+func (dl *Deadline) RecordPoStProofs(store adt.Store, partitions bitfield.BitField, proofs []proof.PoStProof, aggregated bool) error {
+	if aggregated && len(proofs) != 1 {
+		return xc.ErrIllegalArgument.Wrapf("expected exactly one aggregate proof, got %d", len(proofs))
+	}
 	proofArr, err := dl.OptimisticProofsArray(store)
 	if err != nil {
 		return xerrors.Errorf("failed to load proofs: %w", err)
@@ -1151,6 +1510,7 @@ func (dl *Deadline) RecordPoStProofs(store adt.Store, partitions bitfield.BitFie
 	err = proofArr.AppendContinuous(&WindowedPoSt{
 		Partitions: partitions,
 		Proofs:     proofs,
+		Aggregated: aggregated,
 	})
 	if err != nil {
 		return xerrors.Errorf("failed to store proof: %w", err)
@@ -1165,29 +1525,29 @@ func (dl *Deadline) RecordPoStProofs(store adt.Store, partitions bitfield.BitFie
 }
 
 // TakePoStProofs removes and returns a PoSt proof by index, along with the
-// associated partitions. This method takes the PoSt from the PoSt submissions
-// snapshot.
-func (dl *Deadline) TakePoStProofs(store adt.Store, idx uint64) (partitions bitfield.BitField, proofs []proof.PoStProof, err error) {
+// associated partitions and whether the proof is an aggregate. This method takes
+// the PoSt from the PoSt submissions snapshot.
+func (dl *Deadline) TakePoStProofs(store adt.Store, idx uint64) (partitions bitfield.BitField, proofs []proof.PoStProof, aggregated bool, err error) {
 	proofArr, err := dl.OptimisticProofsSnapshotArray(store)
 	if err != nil {
-		return bitfield.New(), nil, xerrors.Errorf("failed to load proofs: %w", err)
+		return bitfield.New(), nil, false, xerrors.Errorf("failed to load proofs: %w", err)
 	}
 
 	// Extract and remove the proof from the proofs array, leaving a hole.
 	// This will not affect concurrent attempts to refute other proofs.
 	var post WindowedPoSt
 	if found, err := proofArr.Pop(idx, &post); err != nil {
-		return bitfield.New(), nil, xerrors.Errorf("failed to retrieve proof %d: %w", idx, err)
+		return bitfield.New(), nil, false, xerrors.Errorf("failed to retrieve proof %d: %w", idx, err)
 	} else if !found {
-		return bitfield.New(), nil, xc.ErrIllegalArgument.Wrapf("proof %d not found", idx)
+		return bitfield.New(), nil, false, xc.ErrIllegalArgument.Wrapf("proof %d not found", idx)
 	}
 
 	root, err := proofArr.Root()
 	if err != nil {
-		return bitfield.New(), nil, xerrors.Errorf("failed to save proofs: %w", err)
+		return bitfield.New(), nil, false, xerrors.Errorf("failed to save proofs: %w", err)
 	}
 	dl.OptimisticPoStSubmissionsSnapshot = root
-	return post.Partitions, post.Proofs, nil
+	return post.Partitions, post.Proofs, post.Aggregated, nil
 }
 
 // DisputeInfo includes all the information necessary to dispute a post to the
@@ -61,7 +61,7 @@ func TestDeadlines(t *testing.T) {
 	// Partition 3: sectors 9
 	addSectors := func(t *testing.T, store adt.Store, dl *miner.Deadline, prove bool) {
 		power := miner.PowerForSectors(sectorSize, sectors)
-		activatedPower, err := dl.AddSectors(store, partitionSize, false, sectors, sectorSize, quantSpec)
+		activatedPower, _, err := dl.AddSectors(store, partitionSize, false, sectors, sectorSize, quantSpec)
 		require.NoError(t, err)
 		assert.True(t, activatedPower.Equals(power))
 
@@ -86,7 +86,7 @@ func TestDeadlines(t *testing.T) {
 		sectorArrRoot, err := sectorArr.Root()
 		require.NoError(t, err)
 
-		faultyPower, recoveryPower, err := dl.ProcessDeadlineEnd(store, quantSpec, 0, sectorArrRoot)
+		faultyPower, recoveryPower, _, err := dl.ProcessDeadlineEnd(store, quantSpec, 0, sectorArrRoot)
 		require.NoError(t, err)
 		require.True(t, faultyPower.IsZero())
 		require.True(t, recoveryPower.IsZero())
@@ -105,7 +105,7 @@ func TestDeadlines(t *testing.T) {
 	addThenTerminate := func(t *testing.T, store adt.Store, dl *miner.Deadline, proveFirst bool) {
 		addSectors(t, store, dl, proveFirst)
 
-		removedPower, err := dl.TerminateSectors(store, sectorsArr(t, store, sectors), 15, miner.PartitionSectorMap{
+		removedPower, _, err := dl.TerminateSectors(store, sectorsArr(t, store, sectors), 15, miner.PartitionSectorMap{
 			0: bf(1, 3),
 			1: bf(6),
 		}, sectorSize, quantSpec)
@@ -176,7 +176,7 @@ func TestDeadlines(t *testing.T) {
 		addSectors(t, store, dl, proveFirst)
 
 		// Mark faulty.
-		powerDelta, err := dl.RecordFaults(
+		powerDelta, _, err := dl.RecordFaults(
 			store, sectorsArr(t, store, sectors), sectorSize, quantSpec, 9,
 			map[uint64]bitfield.BitField{
 				0: bf(1),
@@ -339,6 +339,147 @@ func TestDeadlines(t *testing.T) {
 			).assert(t, store, dl)
 	})
 
+	// Adds and proves sectors according to addSectors, terminates all of partitions 1 and 2 and sector 1 of
+	// partition 0, then pops early terminations.
+	addThenTerminateTrailing := func(t *testing.T, store adt.Store, dl *miner.Deadline) {
+		addSectors(t, store, dl, true)
+
+		_, _, err := dl.TerminateSectors(store, sectorsArr(t, store, sectors), 15, miner.PartitionSectorMap{
+			0: bf(1),
+			1: bf(5, 6, 7, 8),
+			2: bf(9),
+		}, sectorSize, quantSpec)
+		require.NoError(t, err)
+		_, more, err := dl.PopEarlyTerminations(store, 100, 100)
+		require.NoError(t, err)
+		require.False(t, more)
+	}
+
+	t.Run("pops trailing terminated partitions", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		dl := emptyDeadline(t, store)
+		addThenTerminateTrailing(t, store, dl)
+
+		// Partition 0 has live sectors, so inspection stops there.
+		dead, removed, err := dl.PopTerminatedPartitions(store, 3, 100, quantSpec)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), removed)
+		assertBitfieldEquals(t, dead, 5, 6, 7, 8, 9)
+
+		dlState.withTerminations(1).
+			withPartitions(
+				bf(1, 2, 3, 4),
+			).assert(t, store, dl)
+	})
+
+	t.Run("does not pop terminated partitions ahead of live ones", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		dl := emptyDeadline(t, store)
+		addSectors(t, store, dl, true)
+
+		_, _, err := dl.TerminateSectors(store, sectorsArr(t, store, sectors), 15, miner.PartitionSectorMap{
+			1: bf(5, 6, 7, 8),
+		}, sectorSize, quantSpec)
+		require.NoError(t, err)
+		_, _, err = dl.PopEarlyTerminations(store, 100, 100)
+		require.NoError(t, err)
+
+		dead, removed, err := dl.PopTerminatedPartitions(store, 3, 100, quantSpec)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), removed)
+		assertBitfieldEquals(t, dead)
+
+		dlState.withTerminations(5, 6, 7, 8).
+			withPartitions(
+				bf(1, 2, 3, 4),
+				bf(5, 6, 7, 8),
+				bf(9),
+			).assert(t, store, dl)
+	})
+
+	t.Run("pops terminated partitions within limits", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		dl := emptyDeadline(t, store)
+		addThenTerminateTrailing(t, store, dl)
+
+		// Only one partition is inspected.
+		dead, removed, err := dl.PopTerminatedPartitions(store, 1, 100, quantSpec)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), removed)
+		assertBitfieldEquals(t, dead, 9)
+
+		// Removing partition 1 would exceed the sector limit.
+		dead, removed, err = dl.PopTerminatedPartitions(store, 3, 3, quantSpec)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), removed)
+		assertBitfieldEquals(t, dead)
+
+		dlState.withTerminations(1, 5, 6, 7, 8).
+			withPartitions(
+				bf(1, 2, 3, 4),
+				bf(5, 6, 7, 8),
+			).assert(t, store, dl)
+	})
+
+	t.Run("cannot pop terminated partitions with early terminations", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		dl := emptyDeadline(t, store)
+		addThenTerminate(t, store, dl, true)
+
+		_, _, err := dl.PopTerminatedPartitions(store, 3, 100, quantSpec)
+		require.Error(t, err, "should have failed to remove partitions with early terminations")
+	})
+
+	t.Run("moves partitions with faults and early terminations", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		dl := emptyDeadline(t, store)
+		addThenTerminate(t, store, dl, true)
+
+		_, _, err := dl.RecordFaults(
+			store, sectorsArr(t, store, sectors), sectorSize, quantSpec, 9,
+			map[uint64]bitfield.BitField{1: bf(5, 7)},
+		)
+		require.NoError(t, err)
+
+		dest := emptyDeadline(t, store)
+		destQuant := builtin.NewQuantSpec(4, 3)
+		err = dl.MovePartitions(store, dest, bf(1), sectorsArr(t, store, sectors), sectorSize, quantSpec, destQuant)
+		require.NoError(t, err)
+
+		// The remaining partitions shift left, keeping their early terminations.
+		dlState.withTerminations(1, 3).
+			withPartitions(
+				bf(1, 2, 3, 4),
+				bf(9),
+			).assert(t, store, dl)
+		assertBitfieldEquals(t, dl.EarlyTerminations, 0)
+		assert.True(t, dl.FaultyPower.IsZero())
+
+		// The moved partition keeps its faults and early terminations, with expirations quantized to the destination.
+		dlState.withQuantSpec(destQuant).
+			withTerminations(6).
+			withFaults(5, 7).
+			withPartitions(
+				bf(5, 6, 7, 8),
+			).assert(t, store, dest)
+		assertBitfieldEquals(t, dest.EarlyTerminations, 0)
+		assert.True(t, dest.FaultyPower.Equals(sectorPower(t, 5, 7)))
+
+		earlyTerminations, more, err := dest.PopEarlyTerminations(store, 100, 100)
+		require.NoError(t, err)
+		assert.False(t, more)
+		assertBitfieldEquals(t, earlyTerminations.Sectors[15], 6)
+	})
+
+	t.Run("cannot move missing partition", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		dl := emptyDeadline(t, store)
+		addSectors(t, store, dl, true)
+
+		err := dl.MovePartitions(store, emptyDeadline(t, store), bf(3), sectorsArr(t, store, sectors), sectorSize, quantSpec, quantSpec)
+		require.Error(t, err, "should have failed to move missing partition")
+	})
+
 	t.Run("fails to remove partitions with faulty sectors", func(t *testing.T) {
 		store := ipld.NewADTStore(context.Background())
 
@@ -357,7 +498,7 @@ func TestDeadlines(t *testing.T) {
 		addThenMarkFaulty(t, store, dl, true) // 1, 5, 6 faulty
 
 		sectorArr := sectorsArr(t, store, sectors)
-		removedPower, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
+		removedPower, _, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
 			0: bf(1, 3),
 			1: bf(6),
 		}, sectorSize, quantSpec)
@@ -383,7 +524,7 @@ func TestDeadlines(t *testing.T) {
 		addThenMarkFaulty(t, store, dl, false) // 1, 5, 6 faulty
 
 		sectorArr := sectorsArr(t, store, sectors)
-		removedPower, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
+		removedPower, _, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
 			0: bf(1, 3),
 			1: bf(6),
 		}, sectorSize, quantSpec)
@@ -409,7 +550,7 @@ func TestDeadlines(t *testing.T) {
 		addThenMarkFaulty(t, store, dl, false) // 1, 5, 6 faulty
 
 		sectorArr := sectorsArr(t, store, sectors)
-		_, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
+		_, _, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
 			0: bf(6),
 		}, sectorSize, quantSpec)
 		require.Error(t, err)
@@ -423,7 +564,7 @@ func TestDeadlines(t *testing.T) {
 		addThenMarkFaulty(t, store, dl, false) // 1, 5, 6 faulty
 
 		sectorArr := sectorsArr(t, store, sectors)
-		_, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
+		_, _, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
 			4: bf(6),
 		}, sectorSize, quantSpec)
 		require.Error(t, err)
@@ -437,7 +578,7 @@ func TestDeadlines(t *testing.T) {
 		addThenTerminate(t, store, dl, false) // terminates 1, 3, & 6
 
 		sectorArr := sectorsArr(t, store, sectors)
-		_, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
+		_, _, err := dl.TerminateSectors(store, sectorArr, 15, miner.PartitionSectorMap{
 			0: bf(1, 2),
 		}, sectorSize, quantSpec)
 		require.Error(t, err)
@@ -452,7 +593,7 @@ func TestDeadlines(t *testing.T) {
 		addThenMarkFaulty(t, store, dl, true)
 
 		// We expect all sectors but 7 to have expired at this point.
-		exp, err := dl.PopExpiredSectors(store, 9, quantSpec)
+		exp, onTime, early, err := dl.PopExpiredSectors(store, 9, quantSpec)
 		require.NoError(t, err)
 
 		onTimeExpected := bf(1, 2, 3, 4, 5, 8, 9)
@@ -461,6 +602,19 @@ func TestDeadlines(t *testing.T) {
 		assertBitfieldsEqual(t, onTimeExpected, exp.OnTimeSectors)
 		assertBitfieldsEqual(t, earlyExpected, exp.EarlySectors)
 
+		// Expirations are also reported by partition.
+		require.Len(t, onTime, 3)
+		for i, expected := range []bitfield.BitField{bf(1, 2, 3, 4), bf(5, 8), bf(9)} {
+			assert.EqualValues(t, i, onTime[i].Partition)
+			assertBitfieldsEqual(t, expected, onTime[i].Sectors)
+		}
+		// Faulty sector 1 has already lost its power.
+		assert.True(t, onTime[0].PowerDelta.Equals(sectorPower(t, 2, 3, 4).Neg()))
+		require.Len(t, early, 1)
+		assert.EqualValues(t, 1, early[0].Partition)
+		assertBitfieldsEqual(t, earlyExpected, early[0].Sectors)
+		assert.True(t, early[0].PowerDelta.IsZero())
+
 		dlState.withTerminations(1, 2, 3, 4, 5, 6, 8, 9).
 			withPartitions(
 				bf(1, 2, 3, 4),
@@ -494,7 +648,7 @@ func TestDeadlines(t *testing.T) {
 		addSectors(t, store, dl, false)
 
 		// Try to pop some expirations.
-		_, err := dl.PopExpiredSectors(store, 9, quantSpec)
+		_, _, _, err := dl.PopExpiredSectors(store, 9, quantSpec)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "cannot pop expired sectors from a partition with unproven sectors")
 	})
@@ -506,7 +660,7 @@ func TestDeadlines(t *testing.T) {
 		addSectors(t, store, dl, true)
 
 		// add an inactive sector
-		power, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
+		power, _, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
 		require.NoError(t, err)
 		expectedPower := miner.PowerForSectors(sectorSize, extraSectors)
 		assert.True(t, expectedPower.Equals(power))
@@ -520,6 +674,8 @@ func TestDeadlines(t *testing.T) {
 		require.NoError(t, err)
 		assertBitfieldEquals(t, postResult1.Sectors, 1, 2, 3, 4, 5, 6, 7, 8)
 		assertEmptyBitfield(t, postResult1.IgnoredSectors)
+		assertEmptyBitfield(t, postResult1.NewFaultySectors)
+		assertEmptyBitfield(t, postResult1.RecoveredSectors)
 		require.True(t, postResult1.NewFaultyPower.Equals(miner.NewPowerPairZero()))
 		require.True(t, postResult1.RetractedRecoveryPower.Equals(miner.NewPowerPairZero()))
 		require.True(t, postResult1.RecoveredPower.Equals(miner.NewPowerPairZero()))
@@ -556,7 +712,7 @@ func TestDeadlines(t *testing.T) {
 		sectorArrRoot, err := sectorArr.Root()
 		require.NoError(t, err)
 
-		powerDelta, penalizedPower, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
+		powerDelta, penalizedPower, _, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
 		require.NoError(t, err)
 
 		// No power delta for successful post.
@@ -579,7 +735,7 @@ func TestDeadlines(t *testing.T) {
 		addThenMarkFaulty(t, store, dl, true)
 
 		// add an inactive sector
-		power, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
+		power, _, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
 		require.NoError(t, err)
 		expectedPower := miner.PowerForSectors(sectorSize, extraSectors)
 		assert.True(t, expectedPower.Equals(power))
@@ -617,10 +773,12 @@ func TestDeadlines(t *testing.T) {
 		assertBitfieldEquals(t, postResult.Sectors, 1, 2, 3, 4, 5, 6, 7, 8)
 		assertBitfieldEquals(t, postResult.IgnoredSectors, 1, 5, 7)
 		// sector 7 is newly faulty
+		assertBitfieldEquals(t, postResult.NewFaultySectors, 7)
 		require.True(t, postResult.NewFaultyPower.Equals(sectorPower(t, 7)))
 		// we failed to recover 1 (retracted)
 		require.True(t, postResult.RetractedRecoveryPower.Equals(sectorPower(t, 1)))
 		// we recovered 6
+		assertBitfieldEquals(t, postResult.RecoveredSectors, 6)
 		require.True(t, postResult.RecoveredPower.Equals(sectorPower(t, 6)))
 		// no power delta from these deadlines.
 		require.True(t, postResult.PowerDelta.IsZero())
@@ -638,7 +796,7 @@ func TestDeadlines(t *testing.T) {
 		sectorArrRoot, err := sectorArr.Root()
 		require.NoError(t, err)
 
-		powerDelta, penalizedPower, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
+		powerDelta, penalizedPower, _, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
 		require.NoError(t, err)
 
 		expFaultPower := sectorPower(t, 9, 10)
@@ -666,7 +824,7 @@ func TestDeadlines(t *testing.T) {
 		addSectors(t, store, dl, true)
 
 		// add an inactive sector
-		power, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
+		power, _, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
 		require.NoError(t, err)
 		expectedPower := miner.PowerForSectors(sectorSize, extraSectors)
 		assert.True(t, expectedPower.Equals(power))
@@ -699,7 +857,7 @@ func TestDeadlines(t *testing.T) {
 		sectorArrRoot, err := sectorArr.Root()
 		require.NoError(t, err)
 
-		powerDelta, penalizedPower, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
+		powerDelta, penalizedPower, _, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
 		require.NoError(t, err)
 
 		// All posts submitted, no power delta, no extra penalties.
@@ -723,7 +881,7 @@ func TestDeadlines(t *testing.T) {
 		addSectors(t, store, dl, true)
 
 		// add an inactive sector
-		power, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
+		power, _, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
 		require.NoError(t, err)
 		expectedPower := miner.PowerForSectors(sectorSize, extraSectors)
 		assert.True(t, expectedPower.Equals(power))
@@ -745,7 +903,7 @@ func TestDeadlines(t *testing.T) {
 		addSectors(t, store, dl, true)
 
 		// add an inactive sector
-		power, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
+		power, _, err := dl.AddSectors(store, partitionSize, false, extraSectors, sectorSize, quantSpec)
 		require.NoError(t, err)
 		expectedPower := miner.PowerForSectors(sectorSize, extraSectors)
 		assert.True(t, expectedPower.Equals(power))
@@ -776,7 +934,7 @@ func TestDeadlines(t *testing.T) {
 		}))
 
 		// Retract recovery for sector 1.
-		powerDelta, err := dl.RecordFaults(store, sectorArr, sectorSize, quantSpec, 13, map[uint64]bitfield.BitField{
+		powerDelta, _, err := dl.RecordFaults(store, sectorArr, sectorSize, quantSpec, 13, map[uint64]bitfield.BitField{
 			0: bf(1),
 		})
 
@@ -823,7 +981,7 @@ func TestDeadlines(t *testing.T) {
 		sectorArrRoot, err := sectorArr.Root()
 		require.NoError(t, err)
 
-		newFaultyPower, failedRecoveryPower, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
+		newFaultyPower, failedRecoveryPower, _, err := dl.ProcessDeadlineEnd(store, quantSpec, 13, sectorArrRoot)
 		require.NoError(t, err)
 
 		// No power changes.
@@ -847,7 +1005,7 @@ func TestDeadlines(t *testing.T) {
 		sectorArr := sectorsArr(t, store, allSectors)
 
 		// Declare sectors 1 & 6 faulty.
-		_, err := dl.RecordFaults(store, sectorArr, sectorSize, quantSpec, 17, map[uint64]bitfield.BitField{
+		_, _, err := dl.RecordFaults(store, sectorArr, sectorSize, quantSpec, 17, map[uint64]bitfield.BitField{
 			0: bf(1),
 			4: bf(6),
 		})
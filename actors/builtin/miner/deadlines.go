@@ -3,6 +3,7 @@ package miner
 import (
 	"errors"
 
+	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/dline"
 	"golang.org/x/xerrors"
@@ -58,6 +59,74 @@ func FindSector(store adt.Store, deadlines *Deadlines, sectorNum abi.SectorNumbe
 	return 0, 0, xerrors.Errorf("sector %d not due at any deadline", sectorNum)
 }
 
+// FindSectors returns the deadline and partition indices of a set of sector numbers, searching deadlines and
+// partitions in order and stopping once all have been found.
+// It returns an error if any sector number is not tracked by deadlines.
+func FindSectors(store adt.Store, deadlines *Deadlines, sectorNos bitfield.BitField) (DeadlineSectorMap, error) {
+	remaining := sectorNos
+	dsm := make(DeadlineSectorMap)
+	for dlIdx := range deadlines.Due {
+		dl, err := deadlines.LoadDeadline(store, uint64(dlIdx))
+		if err != nil {
+			return nil, err
+		}
+		if remaining, err = findSectorsInDeadline(store, dl, uint64(dlIdx), remaining, dsm); err != nil {
+			return nil, err
+		}
+		if empty, err := remaining.IsEmpty(); err != nil {
+			return nil, err
+		} else if empty {
+			return dsm, nil
+		}
+	}
+
+	first, err := remaining.First()
+	if err != nil {
+		return nil, err
+	}
+	return nil, xerrors.Errorf("sector %d not due at any deadline", first)
+}
+
+// Adds the partition indices of those of a set of sector numbers assigned to a deadline to a sector map,
+// searching partitions in order and stopping once all have been found.
+// Returns the sector numbers not found in the deadline.
+func findSectorsInDeadline(store adt.Store, dl *Deadline, dlIdx uint64, sectorNos bitfield.BitField, dsm DeadlineSectorMap) (bitfield.BitField, error) {
+	remaining := sectorNos
+	partitions, err := adt.AsArray(store, dl.Partitions, DeadlinePartitionsAmtBitwidth)
+	if err != nil {
+		return bitfield.BitField{}, err
+	}
+	stopErr := errors.New("stop")
+	var partition Partition
+	err = partitions.ForEach(&partition, func(partIdx int64) error {
+		found, err := bitfield.IntersectBitField(remaining, partition.Sectors)
+		if err != nil {
+			return err
+		}
+		if empty, err := found.IsEmpty(); err != nil {
+			return err
+		} else if empty {
+			return nil
+		}
+		if err := dsm.Add(dlIdx, uint64(partIdx), found); err != nil {
+			return err
+		}
+		if remaining, err = bitfield.SubtractBitField(remaining, found); err != nil {
+			return err
+		}
+		if empty, err := remaining.IsEmpty(); err != nil {
+			return err
+		} else if empty {
+			return stopErr
+		}
+		return nil
+	})
+	if err != nil && err != stopErr {
+		return bitfield.BitField{}, err
+	}
+	return remaining, nil
+}
+
 // Returns true if the deadline at the given index is currently mutable. A
 // "mutable" deadline may have new sectors assigned to it.
 func deadlineIsMutable(provingPeriodStart abi.ChainEpoch, dlIdx uint64, currentEpoch abi.ChainEpoch) bool {
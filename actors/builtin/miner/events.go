@@ -0,0 +1,106 @@
+package miner
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
+)
+
+// SectorEventType identifies a transition in the lifecycle of a sector.
+type SectorEventType uint64
+
+const (
+	// Sectors were pre-committed. Pre-committed sectors are not yet assigned to a deadline.
+	SectorEventPreCommitted SectorEventType = iota
+	// Pre-committed sectors were proven and assigned to a partition.
+	// Activated sectors gain power only when first proven in a Window PoSt.
+	SectorEventActivated
+	// Sectors were declared, skipped or detected faulty.
+	SectorEventFaulted
+	// Faulty sectors were proven recovered in a Window PoSt.
+	SectorEventRecovered
+	// Sectors' commitments were extended.
+	SectorEventExtended
+	// Sectors were upgraded with a replica update.
+	SectorEventUpdated
+	// Sectors were terminated early, either by the miner or after being faulty for too long.
+	SectorEventTerminated
+	// Sectors reached the end of their committed lifetime.
+	SectorEventExpired
+)
+
+func (t SectorEventType) String() string {
+	switch t {
+	case SectorEventPreCommitted:
+		return "precommitted"
+	case SectorEventActivated:
+		return "activated"
+	case SectorEventFaulted:
+		return "faulted"
+	case SectorEventRecovered:
+		return "recovered"
+	case SectorEventExtended:
+		return "extended"
+	case SectorEventUpdated:
+		return "updated"
+	case SectorEventTerminated:
+		return "terminated"
+	case SectorEventExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// SectorEvent records a lifecycle transition of some sectors in a single partition.
+// Events are emitted for observers outside the chain and do not form part of the actor's state.
+type SectorEvent struct {
+	Type SectorEventType
+	// Location of the sectors. Zero for pre-committed sectors, which have no location.
+	Deadline  uint64
+	Partition uint64
+	Sectors   bitfield.BitField
+	// Change in the miner's claimed power resulting from the transition.
+	PowerDelta PowerPair
+}
+
+var _ runtime.Event = (*SectorEvent)(nil)
+
+func (e *SectorEvent) EventType() string {
+	return "sector-" + e.Type.String()
+}
+
+// Builds events for the changes to sectors in each partition of a deadline.
+func newSectorEvents(eventType SectorEventType, dlIdx uint64, changes []PartitionSectorChange) []*SectorEvent {
+	events := make([]*SectorEvent, 0, len(changes))
+	for _, change := range changes {
+		events = append(events, &SectorEvent{
+			Type:       eventType,
+			Deadline:   dlIdx,
+			Partition:  change.Partition,
+			Sectors:    change.Sectors,
+			PowerDelta: change.PowerDelta,
+		})
+	}
+	return events
+}
+
+func emitSectorEvents(rt Runtime, events []*SectorEvent) {
+	for _, event := range events {
+		rt.EmitEvent(event)
+	}
+}
+
+// WorkerKeyChangedEvent records a pending worker key change taking effect, either when confirmed by the owner
+// or when processed by the deadline cron.
+type WorkerKeyChangedEvent struct {
+	OldWorker addr.Address
+	NewWorker addr.Address
+}
+
+var _ runtime.Event = (*WorkerKeyChangedEvent)(nil)
+
+func (e *WorkerKeyChangedEvent) EventType() string {
+	return "worker-key-changed"
+}
@@ -80,6 +80,23 @@ func (a Actor) Exports() []interface{} {
 		25:                        a.PreCommitSectorBatch,
 		26:                        a.ProveCommitAggregate,
 		27:                        a.ProveReplicaUpdates,
+		28:                        a.GetSectorInfo,
+		29:                        a.GetAvailableBalance,
+		30:                        a.GetVestingFunds,
+		31:                        a.GetDeadlineInfo,
+		32:                        a.IsSectorActive,
+		33:                        a.ExtendSectorExpiration2,
+		34:                        a.MovePartitions,
+		35:                        a.PreviewTerminationFees,
+		36:                        a.ChangeBeneficiary,
+		37:                        a.GetBeneficiary,
+		38:                        a.CancelWorkerKeyChange,
+		39:                        a.ProveCommitSectorsNI,
+		40:                        a.ProveReplicaUpdates2,
+		41:                        a.SubmitWindowedPoStAggregate,
+		42:                        a.ChangeDebtRepaymentPolicy,
+		43:                        a.ExtendPreCommits,
+		44:                        a.DeclareFaultsWithRecoveryEpoch,
 	}
 }
 
@@ -171,6 +188,7 @@ type ChangeWorkerAddressParams = miner0.ChangeWorkerAddressParams
 // ChangeWorkerAddress will ALWAYS overwrite the existing control addresses with the control addresses passed in the params.
 // If a nil addresses slice is passed, the control addresses will be cleared.
 // A worker change will be scheduled if the worker passed in the params is different from the existing worker.
+// An already scheduled change is left in place; it may be revoked with CancelWorkerKeyChange.
 func (a Actor) ChangeWorkerAddress(rt Runtime, params *ChangeWorkerAddressParams) *abi.EmptyValue {
 	checkControlAddresses(rt, params.NewControlAddrs)
 
@@ -208,20 +226,57 @@ func (a Actor) ChangeWorkerAddress(rt Runtime, params *ChangeWorkerAddressParams
 }
 
 // Triggers a worker address change if a change has been requested and its effective epoch has arrived.
+// A WorkerKeyChangedEvent is emitted if the worker is changed.
 func (a Actor) ConfirmUpdateWorkerKey(rt Runtime, params *abi.EmptyValue) *abi.EmptyValue {
 	var st State
+	var event *WorkerKeyChangedEvent
 	rt.StateTransaction(&st, func() {
 		info := getMinerInfo(rt, &st)
 
 		// Only the Owner is allowed to change the newWorker.
 		rt.ValidateImmediateCallerIs(info.Owner)
 
-		processPendingWorker(info, rt, &st)
+		event = processPendingWorker(info, rt, &st)
 	})
+	if event != nil {
+		rt.EmitEvent(event)
+	}
 
 	return nil
 }
 
+type CancelWorkerKeyChangeReturn struct {
+	// The worker address that would have taken effect.
+	CancelledWorker addr.Address
+	// The epoch at which the cancelled change would have taken effect.
+	EffectiveAt abi.ChainEpoch
+}
+
+// Revokes a pending worker address change, leaving the current worker in place.
+// Only the owner may cancel a change, and only while one is pending.
+func (a Actor) CancelWorkerKeyChange(rt Runtime, _ *abi.EmptyValue) *CancelWorkerKeyChangeReturn {
+	var st State
+	var ret CancelWorkerKeyChangeReturn
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+
+		// Only the Owner is allowed to change the newWorker.
+		rt.ValidateImmediateCallerIs(info.Owner)
+
+		if info.PendingWorkerKey == nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "no pending worker key change to cancel")
+		}
+		ret.CancelledWorker = info.PendingWorkerKey.NewWorker
+		ret.EffectiveAt = info.PendingWorkerKey.EffectiveAt
+		info.PendingWorkerKey = nil
+
+		err := st.SaveInfo(adt.AsStore(rt), info)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "could not save miner info")
+	})
+
+	return &ret
+}
+
 // Proposes or confirms a change of owner address.
 // If invoked by the current owner, proposes a new owner address for confirmation. If the proposed address is the
 // current owner address, revokes any existing proposal.
@@ -249,6 +304,10 @@ func (a Actor) ChangeOwnerAddress(rt Runtime, newAddress *addr.Address) *abi.Emp
 				rt.Abortf(exitcode.ErrIllegalArgument, "expected confirmation of %v, got %v",
 					info.PendingOwnerAddress, newAddress)
 			}
+			// A beneficiary left as the owner follows the owner.
+			if info.Beneficiary == info.Owner {
+				info.Beneficiary = *info.PendingOwnerAddress
+			}
 			info.Owner = *info.PendingOwnerAddress
 		}
 
@@ -263,6 +322,135 @@ func (a Actor) ChangeOwnerAddress(rt Runtime, newAddress *addr.Address) *abi.Emp
 	return nil
 }
 
+type ChangeBeneficiaryParams struct {
+	NewBeneficiary addr.Address
+	NewQuota       abi.TokenAmount
+	NewExpiration  abi.ChainEpoch
+}
+
+// Proposes or approves a change of beneficiary address and term.
+// If invoked by the owner, proposes a new beneficiary with the given quota and expiration, replacing any
+// existing proposal. A proposal to make the owner the beneficiary must have zero quota and expiration.
+// If invoked by the current beneficiary or the nominee with the same proposal, records their approval.
+// The change takes effect once both have approved. The current beneficiary's approval is implied if its
+// term has expired or its quota is used up, and the owner's proposal counts as the owner's approval.
+func (a Actor) ChangeBeneficiary(rt Runtime, params *ChangeBeneficiaryParams) *abi.EmptyValue {
+	newBeneficiary := resolveControlAddress(rt, params.NewBeneficiary)
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		if rt.Caller() == info.Owner {
+			rt.ValidateImmediateCallerIs(info.Owner)
+			if newBeneficiary != info.Owner {
+				if !params.NewQuota.GreaterThan(big.Zero()) {
+					rt.Abortf(exitcode.ErrIllegalArgument, "beneficiary quota %v must be positive", params.NewQuota)
+				}
+				if params.NewExpiration <= rt.CurrEpoch() {
+					rt.Abortf(exitcode.ErrIllegalArgument, "beneficiary expiration %d must be after current epoch %d", params.NewExpiration, rt.CurrEpoch())
+				}
+			} else {
+				if !params.NewQuota.IsZero() {
+					rt.Abortf(exitcode.ErrIllegalArgument, "owner beneficiary quota %v must be zero", params.NewQuota)
+				}
+				if params.NewExpiration != 0 {
+					rt.Abortf(exitcode.ErrIllegalArgument, "owner beneficiary expiration %d must be zero", params.NewExpiration)
+				}
+			}
+
+			remainingQuota := info.BeneficiaryTerm.Available(rt.CurrEpoch())
+			info.PendingBeneficiaryTerm = &PendingBeneficiaryChange{
+				NewBeneficiary:        newBeneficiary,
+				NewQuota:              params.NewQuota,
+				NewExpiration:         params.NewExpiration,
+				ApprovedByBeneficiary: remainingQuota.IsZero(),
+				ApprovedByNominee:     newBeneficiary == info.Owner,
+			}
+		} else {
+			pending := info.PendingBeneficiaryTerm
+			if pending == nil {
+				rt.Abortf(exitcode.ErrForbidden, "no pending beneficiary change to approve")
+			}
+			rt.ValidateImmediateCallerIs(info.Beneficiary, pending.NewBeneficiary)
+			if pending.NewBeneficiary != newBeneficiary {
+				rt.Abortf(exitcode.ErrIllegalArgument, "expected beneficiary %v, got %v", pending.NewBeneficiary, newBeneficiary)
+			}
+			if !pending.NewQuota.Equals(params.NewQuota) {
+				rt.Abortf(exitcode.ErrIllegalArgument, "expected quota %v, got %v", pending.NewQuota, params.NewQuota)
+			}
+			if pending.NewExpiration != params.NewExpiration {
+				rt.Abortf(exitcode.ErrIllegalArgument, "expected expiration %d, got %d", pending.NewExpiration, params.NewExpiration)
+			}
+
+			if rt.Caller() == info.Beneficiary {
+				pending.ApprovedByBeneficiary = true
+			}
+			if rt.Caller() == pending.NewBeneficiary {
+				pending.ApprovedByNominee = true
+			}
+		}
+
+		if pending := info.PendingBeneficiaryTerm; pending.ApprovedByBeneficiary && pending.ApprovedByNominee {
+			// A new beneficiary starts with none of its quota used.
+			if pending.NewBeneficiary != info.Beneficiary {
+				info.BeneficiaryTerm.UsedQuota = big.Zero()
+			}
+			info.Beneficiary = pending.NewBeneficiary
+			info.BeneficiaryTerm.Quota = pending.NewQuota
+			info.BeneficiaryTerm.Expiration = pending.NewExpiration
+			info.PendingBeneficiaryTerm = nil
+		}
+
+		err := st.SaveInfo(adt.AsStore(rt), info)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save miner info")
+	})
+	return nil
+}
+
+type ActiveBeneficiary struct {
+	Beneficiary addr.Address
+	Term        BeneficiaryTerm
+}
+
+type GetBeneficiaryReturn struct {
+	Active   ActiveBeneficiary
+	Proposed *PendingBeneficiaryChange
+}
+
+// Returns the current beneficiary and its term, and any proposed change.
+func (a Actor) GetBeneficiary(rt Runtime, _ *abi.EmptyValue) *GetBeneficiaryReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	var st State
+	rt.StateReadonly(&st)
+	info := getMinerInfo(rt, &st)
+	return &GetBeneficiaryReturn{
+		Active: ActiveBeneficiary{
+			Beneficiary: info.Beneficiary,
+			Term:        info.BeneficiaryTerm,
+		},
+		Proposed: info.PendingBeneficiaryTerm,
+	}
+}
+
+// Sets the policy by which fee debt is repaid automatically at the end of each deadline.
+// Debt may still be repaid in full at any time with RepayDebt.
+func (a Actor) ChangeDebtRepaymentPolicy(rt Runtime, params *DebtRepaymentPolicy) *abi.EmptyValue {
+	if err := params.Validate(); err != nil {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid debt repayment policy: %s", err)
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(info.Owner)
+
+		info.DebtRepaymentPolicy = *params
+		err := st.SaveInfo(adt.AsStore(rt), info)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save miner info")
+	})
+	return nil
+}
+
 //type ChangePeerIDParams struct {
 //	NewID abi.PeerID
 //}
@@ -335,29 +523,98 @@ type PoStPartition = miner0.PoStPartition
 //}
 type SubmitWindowedPoStParams = miner0.SubmitWindowedPoStParams
 
-// Invoked by miner's worker address to submit their fallback post
-func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams) *abi.EmptyValue {
-	currEpoch := rt.CurrEpoch()
-	store := adt.AsStore(rt)
-	var st State
+// Describes the effect of a Window PoSt submission.
+// No penalty is charged on submission. Sectors skipped as faults incur the continued fault fee at
+// the end of each deadline in which they remain faulty.
+type SubmitWindowedPoStReturn struct {
+	// Power activated or deactivated (positive or negative) by the submission.
+	PowerDelta PowerPair
+	// Skipped sectors newly recorded as faulty.
+	NewFaultySectors bitfield.BitField
+	// Power of the newly faulty sectors.
+	NewFaultyPower PowerPair
+	// Previously faulty sectors recovered by the submission.
+	RecoveredSectors bitfield.BitField
+	// Power of the recovered sectors.
+	RecoveredPower PowerPair
+	// Power of sectors declared recovering but skipped, and so remaining faulty.
+	RetractedRecoveryPower PowerPair
+}
 
+// Invoked by miner's worker address to submit their fallback post
+func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams) *SubmitWindowedPoStReturn {
 	// Verify that the miner has passed exactly 1 proof.
 	if len(params.Proofs) != 1 {
 		rt.Abortf(exitcode.ErrIllegalArgument, "expected exactly one proof, got %d", len(params.Proofs))
 	}
 
-	if !CanWindowPoStProof(params.Proofs[0].PoStProof) {
-		rt.Abortf(exitcode.ErrIllegalArgument, "proof type %d not allowed", params.Proofs[0].PoStProof)
+	return submitWindowedPoSt(rt, windowedPoStSubmission{
+		deadline:         params.Deadline,
+		partitions:       params.Partitions,
+		proof:            params.Proofs[0],
+		aggregated:       false,
+		chainCommitEpoch: params.ChainCommitEpoch,
+		chainCommitRand:  params.ChainCommitRand,
+	})
+}
+
+// Information submitted by a miner to provide a Window PoSt for many partitions as a single aggregate proof.
+type SubmitWindowedPoStAggregateParams struct {
+	// The deadline index which the submission targets.
+	Deadline uint64
+	// The partitions being proven.
+	Partitions []PoStPartition
+	// A single proof aggregating the Window PoSt proofs of all the partitions, tagged with their proof type.
+	AggregateProof proof.PoStProof
+	// The epoch at which the proof is being committed to a particular chain.
+	ChainCommitEpoch abi.ChainEpoch
+	// The ticket randomness on the chain at the chain commit epoch.
+	ChainCommitRand abi.Randomness
+}
+
+// Invoked by miner's worker address to submit a Window PoSt proving partitions of the current deadline
+// with a single aggregated proof, rather than one proof per partition.
+// The submission is otherwise processed exactly as for SubmitWindowedPoSt, including optimistic
+// acceptance and subsequent dispute if it recovers no power.
+func (a Actor) SubmitWindowedPoStAggregate(rt Runtime, params *SubmitWindowedPoStAggregateParams) *SubmitWindowedPoStReturn {
+	return submitWindowedPoSt(rt, windowedPoStSubmission{
+		deadline:         params.Deadline,
+		partitions:       params.Partitions,
+		proof:            params.AggregateProof,
+		aggregated:       true,
+		chainCommitEpoch: params.ChainCommitEpoch,
+		chainCommitRand:  params.ChainCommitRand,
+	})
+}
+
+// A Window PoSt submission comprising a single proof, which may aggregate the proofs of each partition.
+type windowedPoStSubmission struct {
+	deadline         uint64
+	partitions       []PoStPartition
+	proof            proof.PoStProof
+	aggregated       bool
+	chainCommitEpoch abi.ChainEpoch
+	chainCommitRand  abi.Randomness
+}
+
+func submitWindowedPoSt(rt Runtime, params windowedPoStSubmission) *SubmitWindowedPoStReturn {
+	currEpoch := rt.CurrEpoch()
+	store := adt.AsStore(rt)
+	var st State
+	proofs := []proof.PoStProof{params.proof}
+
+	if !CanWindowPoStProof(params.proof.PoStProof) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "proof type %d not allowed", params.proof.PoStProof)
 	}
 
-	if params.Deadline >= WPoStPeriodDeadlines {
-		rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d of %d", params.Deadline, WPoStPeriodDeadlines)
+	if params.deadline >= WPoStPeriodDeadlines {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d of %d", params.deadline, WPoStPeriodDeadlines)
 	}
 	// Technically, ChainCommitRand should be _exactly_ 32 bytes. However:
 	// 1. It's convenient to allow smaller slices when testing.
 	// 2. Nothing bad will happen if the caller provides too little randomness.
-	if len(params.ChainCommitRand) > abi.RandomnessLength {
-		rt.Abortf(exitcode.ErrIllegalArgument, "expected at most %d bytes of randomness, got %d", abi.RandomnessLength, len(params.ChainCommitRand))
+	if len(params.chainCommitRand) > abi.RandomnessLength {
+		rt.Abortf(exitcode.ErrIllegalArgument, "expected at most %d bytes of randomness, got %d", abi.RandomnessLength, len(params.chainCommitRand))
 	}
 
 	var postResult *PoStResult
@@ -370,19 +627,23 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
 
 		// Make sure the miner is using the correct proof type.
-		if params.Proofs[0].PoStProof != info.WindowPoStProofType {
-			rt.Abortf(exitcode.ErrIllegalArgument, "expected proof of type %d, got proof of type %d", info.WindowPoStProofType, params.Proofs[0])
+		if params.proof.PoStProof != info.WindowPoStProofType {
+			rt.Abortf(exitcode.ErrIllegalArgument, "expected proof of type %d, got proof of type %d", info.WindowPoStProofType, params.proof.PoStProof)
 		}
 
 		// Make sure the proof size doesn't exceed the max. We could probably check for an exact match, but this is safer.
-		if maxSize := maxProofSize * uint64(len(params.Partitions)); uint64(len(params.Proofs[0].ProofBytes)) > maxSize {
+		maxSize := maxProofSize * uint64(len(params.partitions))
+		if params.aggregated {
+			maxSize = MaxAggregatePoStProofSize(uint64(len(params.partitions)))
+		}
+		if uint64(len(params.proof.ProofBytes)) > maxSize {
 			rt.Abortf(exitcode.ErrIllegalArgument, "expected proof to be smaller than %d bytes", maxSize)
 		}
 
 		// Validate that the miner didn't try to prove too many partitions at once.
 		submissionPartitionLimit := loadPartitionsSectorsMax(info.WindowPoStPartitionSectors)
-		if uint64(len(params.Partitions)) > submissionPartitionLimit {
-			rt.Abortf(exitcode.ErrIllegalArgument, "too many partitions %d, limit %d", len(params.Partitions), submissionPartitionLimit)
+		if uint64(len(params.partitions)) > submissionPartitionLimit {
+			rt.Abortf(exitcode.ErrIllegalArgument, "too many partitions %d, limit %d", len(params.partitions), submissionPartitionLimit)
 		}
 
 		currDeadline := st.DeadlineInfo(currEpoch)
@@ -395,21 +656,21 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 		}
 
 		// The miner may only submit a proof for the current deadline.
-		if params.Deadline != currDeadline.Index {
+		if params.deadline != currDeadline.Index {
 			rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d at epoch %d, expected %d",
-				params.Deadline, currEpoch, currDeadline.Index)
+				params.deadline, currEpoch, currDeadline.Index)
 		}
 
 		// Verify that the PoSt was committed to the chain at most WPoStChallengeLookback+WPoStChallengeWindow in the past.
-		if params.ChainCommitEpoch < currDeadline.Challenge {
-			rt.Abortf(exitcode.ErrIllegalArgument, "expected chain commit epoch %d to be after %d", params.ChainCommitEpoch, currDeadline.Challenge)
+		if params.chainCommitEpoch < currDeadline.Challenge {
+			rt.Abortf(exitcode.ErrIllegalArgument, "expected chain commit epoch %d to be after %d", params.chainCommitEpoch, currDeadline.Challenge)
 		}
-		if params.ChainCommitEpoch >= currEpoch {
-			rt.Abortf(exitcode.ErrIllegalArgument, "chain commit epoch %d must be less than the current epoch %d", params.ChainCommitEpoch, currEpoch)
+		if params.chainCommitEpoch >= currEpoch {
+			rt.Abortf(exitcode.ErrIllegalArgument, "chain commit epoch %d must be less than the current epoch %d", params.chainCommitEpoch, currEpoch)
 		}
 		// Verify the chain commit randomness.
-		commRand := rt.GetRandomnessFromTickets(crypto.DomainSeparationTag_PoStChainCommit, params.ChainCommitEpoch, nil)
-		if !bytes.Equal(commRand, params.ChainCommitRand) {
+		commRand := rt.GetRandomnessFromTickets(crypto.DomainSeparationTag_PoStChainCommit, params.chainCommitEpoch, nil)
+		if !bytes.Equal(commRand, params.chainCommitRand) {
 			rt.Abortf(exitcode.ErrIllegalArgument, "post commit randomness mismatched")
 		}
 
@@ -419,8 +680,8 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 		deadlines, err := st.LoadDeadlines(adt.AsStore(rt))
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
 
-		deadline, err := deadlines.LoadDeadline(store, params.Deadline)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", params.Deadline)
+		deadline, err := deadlines.LoadDeadline(store, params.deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", params.deadline)
 
 		// Record proven sectors/partitions, returning updates to power and the final set of sectors
 		// proven/skipped.
@@ -432,16 +693,16 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 		// While we could perform _all_ operations at the end of challenge window, we do as we can here to avoid
 		// overloading cron.
 		faultExpiration := currDeadline.Last() + FaultMaxAge
-		postResult, err = deadline.RecordProvenSectors(store, sectors, info.SectorSize, QuantSpecForDeadline(currDeadline), faultExpiration, params.Partitions)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to process post submission for deadline %d", params.Deadline)
+		postResult, err = deadline.RecordProvenSectors(store, sectors, info.SectorSize, QuantSpecForDeadline(currDeadline), faultExpiration, params.partitions)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to process post submission for deadline %d", params.deadline)
 
 		// Make sure we actually proved something.
 
 		provenSectors, err := bitfield.SubtractBitField(postResult.Sectors, postResult.IgnoredSectors)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine proven sectors for deadline %d", params.Deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine proven sectors for deadline %d", params.deadline)
 
 		noSectors, err := provenSectors.IsEmpty()
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine if any sectors were proven", params.Deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to determine if any sectors were proven", params.deadline)
 		if noSectors {
 			// Abort verification if all sectors are (now) faults. There's nothing to prove.
 			// It's not rational for a miner to submit a Window PoSt marking *all* non-faulty sectors as skipped,
@@ -452,19 +713,19 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 
 		// If we're not recovering power, record the proof for optimistic verification.
 		if postResult.RecoveredPower.IsZero() {
-			err = deadline.RecordPoStProofs(store, postResult.Partitions, params.Proofs)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record proof for optimistic verification", params.Deadline)
+			err = deadline.RecordPoStProofs(store, postResult.Partitions, proofs, params.aggregated)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record proof for optimistic verification", params.deadline)
 		} else {
 			// otherwise, check the proof
 			sectorInfos, err := sectors.LoadForProof(postResult.Sectors, postResult.IgnoredSectors)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors for post verification")
 
-			err = verifyWindowedPost(rt, currDeadline.Challenge, sectorInfos, params.Proofs)
+			err = verifyWindowedPost(rt, currDeadline.Challenge, sectorInfos, proofs, params.aggregated)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "window post failed")
 		}
 
-		err = deadlines.UpdateDeadline(store, params.Deadline, deadline)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update deadline %d", params.Deadline)
+		err = deadlines.UpdateDeadline(store, params.deadline, deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update deadline %d", params.deadline)
 
 		err = st.SaveDeadlines(store, deadlines)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
@@ -476,11 +737,21 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 	// https://github.com/filecoin-project/specs-actors/issues/414
 	requestUpdatePower(rt, postResult.PowerDelta)
 
+	emitSectorEvents(rt, newSectorEvents(SectorEventFaulted, params.deadline, postResult.PartitionFaults))
+	emitSectorEvents(rt, newSectorEvents(SectorEventRecovered, params.deadline, postResult.PartitionRecoveries))
+
 	rt.StateReadonly(&st)
 	err := st.CheckBalanceInvariants(rt.CurrentBalance())
 	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
 
-	return nil
+	return &SubmitWindowedPoStReturn{
+		PowerDelta:             postResult.PowerDelta,
+		NewFaultySectors:       postResult.NewFaultySectors,
+		NewFaultyPower:         postResult.NewFaultyPower,
+		RecoveredSectors:       postResult.RecoveredSectors,
+		RecoveredPower:         postResult.RecoveredPower,
+		RetractedRecoveryPower: postResult.RetractedRecoveryPower,
+	}
 }
 
 // type DisputeWindowedPoStParams struct {
@@ -511,6 +782,7 @@ func (a Actor) DisputeWindowedPoSt(rt Runtime, params *DisputeWindowedPoStParams
 	toReward := abi.NewTokenAmount(0)
 	pledgeDelta := abi.NewTokenAmount(0)
 	powerDelta := NewPowerPairZero()
+	var newFaults []PartitionSectorChange
 	var st State
 	rt.StateTransaction(&st, func() {
 		dlInfo := st.DeadlineInfo(currEpoch)
@@ -542,7 +814,7 @@ func (a Actor) DisputeWindowedPoSt(rt Runtime, params *DisputeWindowedPoStParams
 			// This operation REMOVES the PoSt from the snapshot so
 			// it can't be disputed again. If this method fails,
 			// this operation must be rolled back.
-			partitions, proofs, err := dlCurrent.TakePoStProofs(store, params.PoStIndex)
+			partitions, proofs, aggregated, err := dlCurrent.TakePoStProofs(store, params.PoStIndex)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load proof for dispute")
 
 			// Load the partition info we need for the dispute.
@@ -560,7 +832,7 @@ func (a Actor) DisputeWindowedPoSt(rt Runtime, params *DisputeWindowedPoStParams
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors to dispute window post")
 
 			// Check proof, we fail if validation succeeds.
-			err = verifyWindowedPost(rt, targetDeadline.Challenge, sectorInfos, proofs)
+			err = verifyWindowedPost(rt, targetDeadline.Challenge, sectorInfos, proofs, aggregated)
 			if err == nil {
 				rt.Abortf(exitcode.ErrIllegalArgument, "failed to dispute valid post")
 				return
@@ -574,7 +846,7 @@ func (a Actor) DisputeWindowedPoSt(rt Runtime, params *DisputeWindowedPoStParams
 			// However, some of these sectors may have been
 			// terminated. That's fine, we'll skip them.
 			faultExpirationEpoch := targetDeadline.Last() + FaultMaxAge
-			powerDelta, err = dlCurrent.RecordFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, disputeInfo.DisputedSectors)
+			powerDelta, newFaults, err = dlCurrent.RecordFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, disputeInfo.DisputedSectors)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare faults")
 
 			err = deadlinesCurrent.UpdateDeadline(store, params.Deadline, dlCurrent)
@@ -618,6 +890,7 @@ func (a Actor) DisputeWindowedPoSt(rt Runtime, params *DisputeWindowedPoStParams
 	})
 
 	requestUpdatePower(rt, powerDelta)
+	emitSectorEvents(rt, newSectorEvents(SectorEventFaulted, params.Deadline, newFaults))
 
 	if !toReward.IsZero() {
 		// Try to send the reward to the reporter.
@@ -844,6 +1117,11 @@ func (a Actor) PreCommitSectorBatch(rt Runtime, params *PreCommitSectorBatchPara
 	})
 
 	burnFunds(rt, feeToBurn, BurnMethodPreCommitSectorBatch)
+	emitSectorEvents(rt, []*SectorEvent{{
+		Type:       SectorEventPreCommitted,
+		Sectors:    sectorNumbers,
+		PowerDelta: NewPowerPairZero(),
+	}})
 	rt.StateReadonly(&st)
 	err = st.CheckBalanceInvariants(rt.CurrentBalance())
 	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
@@ -857,6 +1135,114 @@ func (a Actor) PreCommitSectorBatch(rt Runtime, params *PreCommitSectorBatchPara
 	return nil
 }
 
+type ExtendPreCommitsParams struct {
+	Sectors   bitfield.BitField
+	Extension abi.ChainEpoch
+}
+
+// Extends the prove-commit deadline of pre-committed sectors in exchange for an additional deposit,
+// deferring the clean up (and deposit burn) of sectors that are not proven in time.
+// A sector's deadline may be extended in total by at most MaxPreCommitExtension, and its commitment
+// must still meet the minimum sector lifetime if proven at the extended deadline.
+// Note that deals in an extended sector must still start no earlier than the sector's activation.
+func (a Actor) ExtendPreCommits(rt Runtime, params *ExtendPreCommitsParams) *abi.EmptyValue {
+	currEpoch := rt.CurrEpoch()
+	if params.Extension <= 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "extension %d must be positive", params.Extension)
+	}
+	if params.Extension > MaxPreCommitExtension {
+		rt.Abortf(exitcode.ErrIllegalArgument, "extension %d exceeds maximum %d", params.Extension, MaxPreCommitExtension)
+	}
+	count, err := params.Sectors.Count()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to count sectors")
+	if count == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no sectors to extend")
+	}
+	if count > PreCommitSectorBatchMaxSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors to extend %d, max %d", count, PreCommitSectorBatchMaxSize)
+	}
+
+	rewardStats := requestCurrentEpochBlockReward(rt)
+	pwrTotal := requestCurrentTotalPower(rt)
+
+	store := adt.AsStore(rt)
+	var st State
+	feeToBurn := abi.NewTokenAmount(0)
+	rt.StateTransaction(&st, func() {
+		// available balance already accounts for fee debt so it is correct to call
+		// this before RepayDebts.
+		availableBalance, err := st.GetAvailableBalance(rt.CurrentBalance())
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate available balance")
+		feeToBurn = RepayDebtsOrAbort(rt, &st)
+
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		if ConsensusFaultActive(info, currEpoch) {
+			rt.Abortf(exitcode.ErrForbidden, "pre-commit extension not allowed during active consensus fault")
+		}
+
+		precommits, err := st.GetAllPrecommittedSectors(store, params.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to load pre-committed sectors")
+
+		totalDepositRequired := big.Zero()
+		oldCleanUps := map[abi.ChainEpoch][]uint64{}
+		newCleanUps := map[abi.ChainEpoch][]uint64{}
+		for _, precommit := range precommits {
+			sectorNo := precommit.Info.SectorNumber
+			msd, ok := MaxProveCommitDuration[precommit.Info.SealProof]
+			if !ok {
+				rt.Abortf(exitcode.ErrIllegalState, "no max seal duration for proof type: %d", precommit.Info.SealProof)
+			}
+			proveCommitDue := precommit.PreCommitEpoch + msd + precommit.ProveCommitExtension
+			if currEpoch > proveCommitDue {
+				rt.Abortf(exitcode.ErrIllegalArgument, "pre-commitment for %d expired at %d", sectorNo, proveCommitDue)
+			}
+			newExtension := precommit.ProveCommitExtension + params.Extension
+			if newExtension > MaxPreCommitExtension {
+				rt.Abortf(exitcode.ErrIllegalArgument, "total extension %d for sector %d exceeds maximum %d", newExtension, sectorNo, MaxPreCommitExtension)
+			}
+			newProveCommitDue := proveCommitDue + params.Extension
+			validateExpiration(rt, newProveCommitDue, precommit.Info.Expiration, precommit.Info.SealProof)
+
+			// Charge for the extension using the sector's estimated weight at the current epoch,
+			// as for the pre-commit deposit.
+			duration := precommit.Info.Expiration - currEpoch
+			sectorWeight := QAPowerForWeight(info.SectorSize, duration, precommit.DealWeight, precommit.VerifiedDealWeight)
+			depositReq := PreCommitExtensionDeposit(rewardStats.ThisEpochRewardSmoothed, pwrTotal.QualityAdjPowerSmoothed, sectorWeight, params.Extension, msd)
+			totalDepositRequired = big.Add(totalDepositRequired, depositReq)
+
+			precommit.PreCommitDeposit = big.Add(precommit.PreCommitDeposit, depositReq)
+			precommit.ProveCommitExtension = newExtension
+
+			oldCleanUp := proveCommitDue + ExpiredPreCommitCleanUpDelay
+			oldCleanUps[oldCleanUp] = append(oldCleanUps[oldCleanUp], uint64(sectorNo))
+			newCleanUp := newProveCommitDue + ExpiredPreCommitCleanUpDelay
+			newCleanUps[newCleanUp] = append(newCleanUps[newCleanUp], uint64(sectorNo))
+		}
+
+		if availableBalance.LessThan(totalDepositRequired) {
+			rt.Abortf(exitcode.ErrInsufficientFunds, "insufficient funds %v for pre-commit extension deposit: %v", availableBalance, totalDepositRequired)
+		}
+		err = st.AddPreCommitDeposit(totalDepositRequired)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add pre-commit deposit %v", totalDepositRequired)
+
+		err = st.UpdatePrecommittedSectors(store, precommits...)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to write pre-committed sectors")
+
+		err = st.RemovePreCommitCleanUps(store, oldCleanUps)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove pre-commit expiry from queue")
+		err = st.AddPreCommitCleanUps(store, newCleanUps)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add pre-commit expiry to queue")
+	})
+
+	burnFunds(rt, feeToBurn, BurnMethodExtendPreCommits)
+	rt.StateReadonly(&st)
+	err = st.CheckBalanceInvariants(rt.CurrentBalance())
+	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
+	return nil
+}
+
 //type ProveCommitAggregateParams struct {
 //	SectorNumbers  bitfield.BitField
 //	AggregateProof []byte
@@ -898,7 +1284,7 @@ func (a Actor) ProveCommitAggregate(rt Runtime, params *ProveCommitAggregatePara
 		if !ok {
 			rt.Abortf(exitcode.ErrIllegalState, "no max seal duration for proof type: %d", precommit.Info.SealProof)
 		}
-		proveCommitDue := precommit.PreCommitEpoch + msd
+		proveCommitDue := precommit.PreCommitEpoch + msd + precommit.ProveCommitExtension
 		if rt.CurrEpoch() > proveCommitDue {
 			rt.Log(rtt.WARN, "skipping commitment for sector %d, too late at %d, due %d", precommit.Info.SectorNumber, rt.CurrEpoch(), proveCommitDue)
 		} else {
@@ -960,7 +1346,7 @@ func (a Actor) ProveCommitAggregate(rt Runtime, params *ProveCommitAggregatePara
 	rew := requestCurrentEpochBlockReward(rt)
 	pwr := requestCurrentTotalPower(rt)
 
-	confirmSectorProofsValid(rt, precommitsToConfirm, rew.ThisEpochBaselinePower, rew.ThisEpochRewardSmoothed, pwr.QualityAdjPowerSmoothed)
+	confirmSectorProofsValid(rt, precommitsToConfirm, true, rew.ThisEpochBaselinePower, rew.ThisEpochRewardSmoothed, pwr.QualityAdjPowerSmoothed)
 
 	// Compute and burn the aggregate network fee. We need to re-load the state as
 	// confirmSectorProofsValid can change it.
@@ -982,6 +1368,202 @@ func (a Actor) ProveCommitAggregate(rt Runtime, params *ProveCommitAggregatePara
 	return nil
 }
 
+type SectorNIActivationInfo struct {
+	SectorNumber  abi.SectorNumber
+	SealedCID     cid.Cid `checked:"true"` // CommR
+	SealRandEpoch abi.ChainEpoch
+	DealIDs       []abi.DealID
+	Expiration    abi.ChainEpoch
+}
+
+type ProveCommitSectorsNIParams struct {
+	Sectors        []SectorNIActivationInfo
+	SealProof      abi.RegisteredSealProof
+	AggregateProof []byte
+}
+
+// Verifies an aggregate non-interactive proof of replication for new sectors, which have not been pre-committed.
+// If valid, the sectors' deals are activated, sector numbers are allocated and the sectors are assigned
+// to deadlines and charged initial pledge, all in this one message.
+// No pre-commit deposit is required, as there is no pre-commitment to be abandoned.
+// As with ProveCommitAggregate, sectors whose deals fail to activate are dropped rather than failing the batch.
+func (a Actor) ProveCommitSectorsNI(rt Runtime, params *ProveCommitSectorsNIParams) *abi.EmptyValue {
+	currEpoch := rt.CurrEpoch()
+	if len(params.Sectors) > MaxAggregatedSectors {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors addressed, addressed %d want <= %d", len(params.Sectors), MaxAggregatedSectors)
+	} else if len(params.Sectors) < MinAggregatedSectors {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too few sectors addressed, addressed %d want >= %d", len(params.Sectors), MinAggregatedSectors)
+	}
+	if uint64(len(params.AggregateProof)) > MaxAggregateProofSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "sector prove-commit proof of size %d exceeds max size of %d",
+			len(params.AggregateProof), MaxAggregateProofSize)
+	}
+	if !CanPreCommitSealProof(params.SealProof) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "unsupported seal proof type %v", params.SealProof)
+	}
+
+	// Check per-sector preconditions before sending other messages.
+	challengeEarliest := currEpoch - MaxPreCommitRandomnessLookback
+	sectorsDeals := make([]market.SectorDeals, len(params.Sectors))
+	sectorNumbers := bitfield.New()
+	for i, sector := range params.Sectors {
+		set, err := sectorNumbers.IsSet(uint64(sector.SectorNumber))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "error checking sector number")
+		if set {
+			rt.Abortf(exitcode.ErrIllegalArgument, "duplicate sector number %d", sector.SectorNumber)
+		}
+		sectorNumbers.Set(uint64(sector.SectorNumber))
+
+		if sector.SectorNumber > abi.MaxSectorNumber {
+			rt.Abortf(exitcode.ErrIllegalArgument, "sector number %d out of range 0..(2^63-1)", sector.SectorNumber)
+		}
+		if !sector.SealedCID.Defined() {
+			rt.Abortf(exitcode.ErrIllegalArgument, "sealed CID undefined")
+		}
+		if sector.SealedCID.Prefix() != SealedCIDPrefix {
+			rt.Abortf(exitcode.ErrIllegalArgument, "sealed CID had wrong prefix")
+		}
+		if sector.SealRandEpoch >= currEpoch {
+			rt.Abortf(exitcode.ErrIllegalArgument, "seal challenge epoch %v must be before now %v", sector.SealRandEpoch, currEpoch)
+		}
+		if sector.SealRandEpoch < challengeEarliest {
+			rt.Abortf(exitcode.ErrIllegalArgument, "seal challenge epoch %v too old, must be after %v", sector.SealRandEpoch, challengeEarliest)
+		}
+		validateExpiration(rt, currEpoch, sector.Expiration, params.SealProof)
+
+		sectorsDeals[i] = market.SectorDeals{
+			SectorExpiry: sector.Expiration,
+			DealIDs:      sector.DealIDs,
+		}
+	}
+
+	store := adt.AsStore(rt)
+	var st State
+	var info *MinerInfo
+	rt.StateTransaction(&st, func() {
+		info = getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		if ConsensusFaultActive(info, currEpoch) {
+			rt.Abortf(exitcode.ErrForbidden, "prove-commit not allowed during active consensus fault")
+		}
+		// Sectors must have the same Window PoSt proof type as the miner's recorded seal type.
+		sectorWPoStProof, err := params.SealProof.RegisteredWindowPoStProof()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to lookup Window PoSt proof type for sector seal proof %d", params.SealProof)
+		if sectorWPoStProof != info.WindowPoStProofType {
+			rt.Abortf(exitcode.ErrIllegalArgument, "sector Window PoSt proof type %d must match miner Window PoSt proof type %d (seal proof type %d)",
+				sectorWPoStProof, info.WindowPoStProofType, params.SealProof)
+		}
+
+		// Numbers are allocated for all sectors, including any later dropped for failing to activate deals.
+		err = st.AllocateSectorNumbers(store, sectorNumbers, DenyCollisions)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to allocate sector ids %v", sectorNumbers)
+	})
+
+	dealWeights := requestDealWeights(rt, sectorsDeals)
+	if len(dealWeights.Sectors) != len(params.Sectors) {
+		rt.Abortf(exitcode.ErrIllegalState, "deal weight request returned %d records, expected %d",
+			len(dealWeights.Sectors), len(params.Sectors))
+	}
+
+	// Describe each sector as though pre-committed now, with no deposit, so that it may be activated
+	// in the same way as proven pre-commitments.
+	dealCountMax := SectorDealsMax(info.SectorSize)
+	computeDataCommitmentsInputs := make([]*market.SectorDataSpec, len(params.Sectors))
+	precommits := make([]*SectorPreCommitOnChainInfo, len(params.Sectors))
+	for i, sector := range params.Sectors {
+		if uint64(len(sector.DealIDs)) > dealCountMax {
+			rt.Abortf(exitcode.ErrIllegalArgument, "too many deals for sector %d > %d", len(sector.DealIDs), dealCountMax)
+		}
+		dealWeight := dealWeights.Sectors[i]
+		if dealWeight.DealSpace > uint64(info.SectorSize) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "deals too large to fit in sector %d > %d", dealWeight.DealSpace, info.SectorSize)
+		}
+
+		computeDataCommitmentsInputs[i] = &market.SectorDataSpec{
+			SectorType: params.SealProof,
+			DealIDs:    sector.DealIDs,
+		}
+		precommits[i] = &SectorPreCommitOnChainInfo{
+			Info: SectorPreCommitInfo{
+				SealProof:     params.SealProof,
+				SectorNumber:  sector.SectorNumber,
+				SealedCID:     sector.SealedCID,
+				SealRandEpoch: sector.SealRandEpoch,
+				DealIDs:       sector.DealIDs,
+				Expiration:    sector.Expiration,
+			},
+			PreCommitDeposit:   big.Zero(),
+			PreCommitEpoch:     currEpoch,
+			DealWeight:         dealWeight.DealWeight,
+			VerifiedDealWeight: dealWeight.VerifiedDealWeight,
+		}
+	}
+
+	commDs := requestUnsealedSectorCIDs(rt, computeDataCommitmentsInputs...)
+	receiver := rt.Receiver()
+	minerActorID, err := addr.IDFromAddress(receiver)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "runtime provided non-ID receiver address %s", receiver)
+	buf := new(bytes.Buffer)
+	err = receiver.MarshalCBOR(buf)
+	receiverBytes := buf.Bytes()
+	builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to marshal address for seal verification challenge")
+
+	svis := make([]proof.NonInteractiveSealVerifyInfo, len(params.Sectors))
+	for i, sector := range params.Sectors {
+		svInfoRandomness := rt.GetRandomnessFromTickets(crypto.DomainSeparationTag_SealRandomness, sector.SealRandEpoch, receiverBytes)
+		svis[i] = proof.NonInteractiveSealVerifyInfo{
+			Number:      sector.SectorNumber,
+			Randomness:  abi.SealRandomness(svInfoRandomness),
+			SealedCID:   sector.SealedCID,
+			UnsealedCID: commDs[i],
+		}
+	}
+	err = rt.VerifyAggregateNonInteractiveSeals(proof.AggregateNonInteractiveSealVerifyProofAndInfos{
+		Miner:          abi.ActorID(minerActorID),
+		SealProof:      params.SealProof,
+		AggregateProof: abi.RegisteredAggregationProof_SnarkPackV1,
+		Proof:          params.AggregateProof,
+		Infos:          svis,
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "aggregate non-interactive seal verify failed")
+
+	rew := requestCurrentEpochBlockReward(rt)
+	pwr := requestCurrentTotalPower(rt)
+
+	activatedCount := confirmSectorProofsValid(rt, precommits, false, rew.ThisEpochBaselinePower, rew.ThisEpochRewardSmoothed, pwr.QualityAdjPowerSmoothed)
+
+	// Charge the aggregate network fee for the activated sectors through fee debt,
+	// to consolidate its burn with any outstanding debt.
+	var feeToBurn abi.TokenAmount
+	var needsCron bool
+	rt.StateTransaction(&st, func() {
+		aggregateFee := AggregateProveCommitNetworkFee(activatedCount, rt.BaseFee())
+		err := st.ApplyPenalty(aggregateFee)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to apply penalty")
+		feeToBurn = RepayDebtsOrAbort(rt, &st)
+
+		// Without a pre-commitment, the deadline cron may not yet be active.
+		// It's needed only once some sector is activated.
+		if activatedCount > 0 && !st.DeadlineCronActive {
+			needsCron = true
+			st.DeadlineCronActive = true
+		}
+	})
+	burnFunds(rt, feeToBurn, BurnMethodProveCommitAggregate)
+
+	rt.StateReadonly(&st)
+	err = st.CheckBalanceInvariants(rt.CurrentBalance())
+	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
+	if needsCron {
+		newDlInfo := st.DeadlineInfo(currEpoch)
+		enrollCronEvent(rt, newDlInfo.Last(), &CronEventPayload{
+			EventType: CronEventProvingDeadline,
+		})
+	}
+	return nil
+}
+
 //type ProveCommitSectorParams struct {
 //	SectorNumber abi.SectorNumber
 //	ReplicaProof        []byte
@@ -1021,7 +1603,7 @@ func (a Actor) ProveCommitSector(rt Runtime, params *ProveCommitSectorParams) *a
 	if !ok {
 		rt.Abortf(exitcode.ErrIllegalState, "no max seal duration for proof type: %d", precommit.Info.SealProof)
 	}
-	proveCommitDue := precommit.PreCommitEpoch + msd
+	proveCommitDue := precommit.PreCommitEpoch + msd + precommit.ProveCommitExtension
 	if rt.CurrEpoch() > proveCommitDue {
 		rt.Abortf(exitcode.ErrIllegalArgument, "commitment proof for %d too late at %d, due %d", sectorNo, rt.CurrEpoch(), proveCommitDue)
 	}
@@ -1066,13 +1648,16 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 	precommittedSectors, err := st.FindPrecommittedSectors(store, params.Sectors...)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pre-committed sectors")
 
-	confirmSectorProofsValid(rt, precommittedSectors, params.RewardBaselinePower, params.RewardSmoothed, params.QualityAdjPowerSmoothed)
+	confirmSectorProofsValid(rt, precommittedSectors, true, params.RewardBaselinePower, params.RewardSmoothed, params.QualityAdjPowerSmoothed)
 
 	return nil
 }
 
-func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainInfo, thisEpochBaselinePower big.Int,
-	thisEpochRewardSmoothed smoothing.FilterEstimate, qualityAdjPowerSmoothed smoothing.FilterEstimate) {
+// Activates sectors with verified proofs of replication. Pre-committed sectors have their pre-commitments
+// removed and deposits unlocked. Sectors proven without pre-commitment must already have their numbers allocated.
+// Returns the number of sectors activated.
+func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainInfo, preCommitted bool, thisEpochBaselinePower big.Int,
+	thisEpochRewardSmoothed smoothing.FilterEstimate, qualityAdjPowerSmoothed smoothing.FilterEstimate) int {
 
 	circulatingSupply := rt.TotalFilCircSupply()
 
@@ -1086,25 +1671,28 @@ func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainIn
 	// a constant number of them.
 
 	activation := rt.CurrEpoch()
-	// Pre-commits for new sectors.
-	var validPreCommits []*SectorPreCommitOnChainInfo
+	// Check (and activate) storage deals associated to sectors in a single batch.
+	var sectorsDeals []market.SectorDeals
 	for _, precommit := range preCommits {
 		if len(precommit.Info.DealIDs) > 0 {
-			// Check (and activate) storage deals associated to sector. Abort if checks failed.
-			// TODO: we should batch these calls...
-			// https://github.com/filecoin-project/specs-actors/issues/474
-			code := rt.Send(
-				builtin.StorageMarketActorAddr,
-				builtin.MethodsMarket.ActivateDeals,
-				&market.ActivateDealsParams{
-					DealIDs:      precommit.Info.DealIDs,
-					SectorExpiry: precommit.Info.Expiration,
-				},
-				abi.NewTokenAmount(0),
-				&builtin.Discard{},
-			)
+			sectorsDeals = append(sectorsDeals, market.SectorDeals{
+				DealIDs:      precommit.Info.DealIDs,
+				SectorExpiry: precommit.Info.Expiration,
+			})
+		}
+	}
+	activations := requestActivateDeals(rt, sectorsDeals)
+	builtin.RequirePredicate(rt, len(activations.Sectors) == len(sectorsDeals), exitcode.ErrIllegalState,
+		"deal activation request returned %d records, expected %d", len(activations.Sectors), len(sectorsDeals))
 
-			if code != exitcode.Ok {
+	// Pre-commits for new sectors, dropping those whose deals failed to activate.
+	var validPreCommits []*SectorPreCommitOnChainInfo
+	activationIdx := 0
+	for _, precommit := range preCommits {
+		if len(precommit.Info.DealIDs) > 0 {
+			activated := activations.Sectors[activationIdx].Activated
+			activationIdx++
+			if !activated {
 				rt.Log(rtt.INFO, "failed to activate deals on sector %d, dropping from prove commit set", precommit.Info.SectorNumber)
 				continue
 			}
@@ -1122,6 +1710,7 @@ func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainIn
 	depositToUnlock := big.Zero()
 	newSectors := make([]*SectorOnChainInfo, 0)
 	newlyVested := big.Zero()
+	var activated DeadlineSectorMap
 	var st State
 	store := adt.AsStore(rt)
 	rt.StateTransaction(&st, func() {
@@ -1171,10 +1760,12 @@ func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainIn
 		err := st.PutSectors(store, newSectors...)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put new sectors")
 
-		err = st.DeletePrecommittedSectors(store, newSectorNos...)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete precommited sectors")
+		if preCommitted {
+			err = st.DeletePrecommittedSectors(store, newSectorNos...)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete precommited sectors")
+		}
 
-		err = st.AssignSectorsToDeadlines(store, rt.CurrEpoch(), newSectors, info.WindowPoStPartitionSectors, info.SectorSize)
+		activated, err = st.AssignSectorsToDeadlines(store, rt.CurrEpoch(), newSectors, info.WindowPoStPartitionSectors, info.SectorSize)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to assign new sectors to deadlines")
 
 		// Unlock deposit for successful proofs, make it available for lock-up as initial pledge.
@@ -1195,6 +1786,24 @@ func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainIn
 
 	// Request pledge update for activated sector.
 	notifyPledgeChanged(rt, big.Sub(totalPledge, newlyVested))
+
+	// Activated sectors gain power only when first proven, so activation has no power delta.
+	var events []*SectorEvent
+	err := activated.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+		return pm.ForEach(func(partIdx uint64, sectorNos bitfield.BitField) error {
+			events = append(events, &SectorEvent{
+				Type:       SectorEventActivated,
+				Deadline:   dlIdx,
+				Partition:  partIdx,
+				Sectors:    sectorNos,
+				PowerDelta: NewPowerPairZero(),
+			})
+			return nil
+		})
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record activated sectors")
+	emitSectorEvents(rt, events)
+	return len(newSectors)
 }
 
 //type CheckSectorProvenParams struct {
@@ -1222,6 +1831,150 @@ func (a Actor) CheckSectorProven(rt Runtime, params *CheckSectorProvenParams) *a
 	return nil
 }
 
+/////////////
+// Queries //
+/////////////
+
+type GetSectorInfoParams struct {
+	SectorNumber abi.SectorNumber
+}
+
+// Returns the on-chain info for a sector.
+// The info of a terminated sector remains until its partition is compacted, so a sector being found does not mean
+// it is active. Aborts with ErrNotFound if the sector was never proven, or was terminated and has been compacted.
+func (a Actor) GetSectorInfo(rt Runtime, params *GetSectorInfoParams) *SectorOnChainInfo {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	if params.SectorNumber > abi.MaxSectorNumber {
+		rt.Abortf(exitcode.ErrIllegalArgument, "sector number out of range")
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	sector, found, err := st.GetSector(adt.AsStore(rt), params.SectorNumber)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sector %d", params.SectorNumber)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "sector %d not found", params.SectorNumber)
+	}
+	return sector
+}
+
+// Returns the amount the owner could withdraw at the current epoch: the unlocked balance, including funds
+// that have vested but are not yet unlocked, less fee debt. This may be negative if the miner is in debt.
+func (a Actor) GetAvailableBalance(rt Runtime, _ *abi.EmptyValue) *abi.TokenAmount {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	vested, err := st.CheckVestedFunds(adt.AsStore(rt), rt.CurrEpoch())
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check vested funds")
+	available, err := st.GetAvailableBalance(rt.CurrentBalance())
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate available balance")
+
+	available = big.Add(available, vested)
+	return &available
+}
+
+// Returns the schedule of locked funds yet to vest. Entries for funds that have already vested
+// but not yet been unlocked are included.
+func (a Actor) GetVestingFunds(rt Runtime, _ *abi.EmptyValue) *VestingFunds {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	funds, err := st.LoadVestingFunds(adt.AsStore(rt))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load vesting funds")
+	return funds
+}
+
+type GetDeadlineInfoReturn struct {
+	CurrentEpoch abi.ChainEpoch // Epoch at which this info was calculated.
+	PeriodStart  abi.ChainEpoch // First epoch of the proving period (<= CurrentEpoch).
+	Index        uint64         // A deadline index, in [0..WPoStPeriodDeadlines) unless period elapsed.
+	Open         abi.ChainEpoch // First epoch from which a proof may be submitted (>= CurrentEpoch).
+	Close        abi.ChainEpoch // First epoch from which a proof may no longer be submitted (>= Open).
+	Challenge    abi.ChainEpoch // Epoch at which to sample the chain for challenge (< Open).
+	FaultCutoff  abi.ChainEpoch // First epoch at which a fault declaration is rejected (< Open).
+}
+
+// Returns the miner's current proving deadline.
+func (a Actor) GetDeadlineInfo(rt Runtime, _ *abi.EmptyValue) *GetDeadlineInfoReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	dlInfo := st.DeadlineInfo(rt.CurrEpoch())
+	return &GetDeadlineInfoReturn{
+		CurrentEpoch: dlInfo.CurrentEpoch,
+		PeriodStart:  dlInfo.PeriodStart,
+		Index:        dlInfo.Index,
+		Open:         dlInfo.Open,
+		Close:        dlInfo.Close,
+		Challenge:    dlInfo.Challenge,
+		FaultCutoff:  dlInfo.FaultCutoff,
+	}
+}
+
+type IsSectorActiveParams struct {
+	SectorNumber abi.SectorNumber
+}
+
+type IsSectorActiveReturn struct {
+	Active bool
+}
+
+// Returns whether a sector is active, i.e. proven, neither faulty nor terminated, and so contributing power.
+// Sectors unknown to the miner are not active.
+func (a Actor) IsSectorActive(rt Runtime, params *IsSectorActiveParams) *IsSectorActiveReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	if params.SectorNumber > abi.MaxSectorNumber {
+		rt.Abortf(exitcode.ErrIllegalArgument, "sector number out of range")
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	// A sector absent from the sectors AMT was never proven or has been compacted, so cannot be active.
+	// This avoids searching every deadline for it.
+	if _, found, err := st.GetSector(store, params.SectorNumber); err != nil {
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sector %d", params.SectorNumber)
+	} else if !found {
+		return &IsSectorActiveReturn{Active: false}
+	}
+
+	status, err := st.LoadSectorStatus(store, params.SectorNumber)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load status of sector %d", params.SectorNumber)
+	return &IsSectorActiveReturn{Active: status.Status == SectorStatusActive}
+}
+
+type PreviewTerminationFeesParams struct {
+	Sectors bitfield.BitField
+}
+
+// Computes the fees that would be charged, and the pledge released, were the given sectors terminated
+// at the current epoch, using the current network reward and power estimates. Nothing is terminated.
+func (a Actor) PreviewTerminationFees(rt Runtime, params *PreviewTerminationFeesParams) *TerminationFeePreview {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	count, err := params.Sectors.Count()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to count sectors")
+	if count > AddressedSectorsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors %d, max %d", count, AddressedSectorsMax)
+	}
+
+	rewardStats := requestCurrentEpochBlockReward(rt)
+	pwrTotal := requestCurrentTotalPower(rt)
+
+	var st State
+	rt.StateReadonly(&st)
+	preview, err := st.PreviewTerminationFees(adt.AsStore(rt), params.Sectors, rt.CurrEpoch(),
+		rewardStats.ThisEpochRewardSmoothed, pwrTotal.QualityAdjPowerSmoothed)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute termination fees")
+	return preview
+}
+
 /////////////////////////
 // Sector Modification //
 /////////////////////////
@@ -1275,6 +2028,7 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 
 	powerDelta := NewPowerPairZero()
 	pledgeDelta := big.Zero()
+	var events []*SectorEvent
 	store := adt.AsStore(rt)
 	var st State
 	rt.StateTransaction(&st, func() {
@@ -1329,41 +2083,7 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors in deadline %v partition %v", dlIdx, decl.Partition)
 				newSectors := make([]*SectorOnChainInfo, len(oldSectors))
 				for i, sector := range oldSectors {
-					if !CanExtendSealProofType(sector.SealProof) {
-						rt.Abortf(exitcode.ErrForbidden, "cannot extend expiration for sector %v with unsupported seal type %v",
-							sector.SectorNumber, sector.SealProof)
-					}
-					// This can happen if the sector should have already expired, but hasn't
-					// because the end of its deadline hasn't passed yet.
-					if sector.Expiration < currEpoch {
-						rt.Abortf(exitcode.ErrForbidden, "cannot extend expiration for expired sector %v, expired at %d, now %d",
-							sector.SectorNumber,
-							sector.Expiration,
-							currEpoch,
-						)
-					}
-					if decl.NewExpiration < sector.Expiration {
-						rt.Abortf(exitcode.ErrIllegalArgument, "cannot reduce sector %v's expiration to %d from %d",
-							sector.SectorNumber, decl.NewExpiration, sector.Expiration)
-					}
-					validateExpiration(rt, sector.Activation, decl.NewExpiration, sector.SealProof)
-
-					// Remove "spent" deal weights
-					newDealWeight := big.Div(
-						big.Mul(sector.DealWeight, big.NewInt(int64(sector.Expiration-currEpoch))),
-						big.NewInt(int64(sector.Expiration-sector.Activation)),
-					)
-					newVerifiedDealWeight := big.Div(
-						big.Mul(sector.VerifiedDealWeight, big.NewInt(int64(sector.Expiration-currEpoch))),
-						big.NewInt(int64(sector.Expiration-sector.Activation)),
-					)
-
-					newSector := *sector
-					newSector.Expiration = decl.NewExpiration
-					newSector.DealWeight = newDealWeight
-					newSector.VerifiedDealWeight = newVerifiedDealWeight
-
-					newSectors[i] = &newSector
+					newSectors[i] = extendSector(rt, currEpoch, sector, decl.NewExpiration)
 				}
 
 				// Overwrite sector infos.
@@ -1373,6 +2093,13 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 				// Remove old sectors from partition and assign new sectors.
 				partitionPowerDelta, partitionPledgeDelta, err := partition.ReplaceSectors(store, oldSectors, newSectors, info.SectorSize, quant)
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to replace sector expirations at deadline %v partition %v", dlIdx, decl.Partition)
+				events = append(events, &SectorEvent{
+					Type:       SectorEventExtended,
+					Deadline:   dlIdx,
+					Partition:  decl.Partition,
+					Sectors:    decl.Sectors,
+					PowerDelta: partitionPowerDelta,
+				})
 
 				powerDelta = powerDelta.Add(partitionPowerDelta)
 				pledgeDelta = big.Add(pledgeDelta, partitionPledgeDelta) // expected to be zero, see note below.
@@ -1380,13 +2107,177 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 				err = partitions.Set(decl.Partition, &partition)
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %v partition %v", dlIdx, decl.Partition)
 
-				// Record the new partition expiration epoch for setting outside this loop over declarations.
-				prevEpochPartitions, ok := partitionsByNewEpoch[decl.NewExpiration]
-				partitionsByNewEpoch[decl.NewExpiration] = append(prevEpochPartitions, decl.Partition)
-				if !ok {
-					epochsToReschedule = append(epochsToReschedule, decl.NewExpiration)
-				}
-			}
+				// Record the new partition expiration epoch for setting outside this loop over declarations.
+				prevEpochPartitions, ok := partitionsByNewEpoch[decl.NewExpiration]
+				partitionsByNewEpoch[decl.NewExpiration] = append(prevEpochPartitions, decl.Partition)
+				if !ok {
+					epochsToReschedule = append(epochsToReschedule, decl.NewExpiration)
+				}
+			}
+
+			deadline.Partitions, err = partitions.Root()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save partitions for deadline %d", dlIdx)
+
+			// Record partitions in deadline expiration queue
+			for _, epoch := range epochsToReschedule {
+				pIdxs := partitionsByNewEpoch[epoch]
+				err := deadline.AddExpirationPartitions(store, epoch, pIdxs, quant)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add expiration partitions to deadline %v epoch %v: %v",
+					dlIdx, epoch, pIdxs)
+			}
+
+			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %d", dlIdx)
+		}
+
+		st.Sectors, err = sectors.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save sectors")
+
+		err = st.SaveDeadlines(store, deadlines)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+	})
+
+	requestUpdatePower(rt, powerDelta)
+	emitSectorEvents(rt, events)
+	// Note: the pledge delta is expected to be zero, since pledge is not re-calculated for the extension.
+	// But in case that ever changes, we can do the right thing here.
+	notifyPledgeChanged(rt, pledgeDelta)
+	return nil
+}
+
+type ExtendSectorExpiration2Params struct {
+	Extensions []ExpirationExtension2
+}
+
+// Extends the expirations of sectors in one partition, each to its own new expiration epoch.
+type ExpirationExtension2 struct {
+	Deadline  uint64
+	Partition uint64
+	Sectors   []SectorExpiration
+}
+
+type SectorExpiration struct {
+	SectorNumber  abi.SectorNumber
+	NewExpiration abi.ChainEpoch
+}
+
+// Changes the expiration epochs of sectors to new, later ones, which may differ between sectors.
+// Sectors are addressed by deadline and partition, as for ExtendSectorExpiration, and each partition's
+// expiration queue is rescheduled once no matter how many distinct new expirations are given for it.
+// The sectors must not be terminated or faulty, and a sector may appear at most once.
+// The sectors' power is recomputed for the new expirations.
+func (a Actor) ExtendSectorExpiration2(rt Runtime, params *ExtendSectorExpiration2Params) *abi.EmptyValue {
+	if uint64(len(params.Extensions)) > DeclarationsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many declarations %d, max %d", len(params.Extensions), DeclarationsMax)
+	}
+
+	// Check the sector count before building bitfields to bound the work done for oversized params.
+	var sectorCount uint64
+	for _, decl := range params.Extensions {
+		sectorCount += uint64(len(decl.Sectors))
+	}
+	if sectorCount > AddressedSectorsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument,
+			"too many sectors for declaration %d, max %d",
+			sectorCount, AddressedSectorsMax,
+		)
+	}
+
+	toExtend := make(DeadlineSectorMap)
+	newExpirations := make(map[abi.SectorNumber]abi.ChainEpoch, sectorCount)
+	for _, decl := range params.Extensions {
+		sectorNos := make([]uint64, 0, len(decl.Sectors))
+		for _, se := range decl.Sectors {
+			if _, ok := newExpirations[se.SectorNumber]; ok {
+				rt.Abortf(exitcode.ErrIllegalArgument, "sector %d extended more than once", se.SectorNumber)
+			}
+			newExpirations[se.SectorNumber] = se.NewExpiration
+			sectorNos = append(sectorNos, uint64(se.SectorNumber))
+		}
+		err := toExtend.AddValues(decl.Deadline, decl.Partition, sectorNos...)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"failed to record sectors for deadline %d, partition %d", decl.Deadline, decl.Partition)
+	}
+	err := toExtend.Check(AddressedPartitionsMax, AddressedSectorsMax)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid extension declarations")
+
+	currEpoch := rt.CurrEpoch()
+
+	powerDelta := NewPowerPairZero()
+	pledgeDelta := big.Zero()
+	var events []*SectorEvent
+	store := adt.AsStore(rt)
+	var st State
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		deadlines, err := st.LoadDeadlines(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+		sectors, err := LoadSectors(store, st.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+
+		err = toExtend.ForEach(func(dlIdx uint64, partitionSectors PartitionSectorMap) error {
+			deadline, err := deadlines.LoadDeadline(store, dlIdx)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+
+			partitions, err := deadline.PartitionsArray(store)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load partitions for deadline %d", dlIdx)
+
+			quant := st.QuantSpecForDeadline(dlIdx)
+
+			// Group modified partitions by epoch to which they are extended. Duplicates are ok.
+			partitionsByNewEpoch := map[abi.ChainEpoch][]uint64{}
+			// Remember iteration order of epochs.
+			var epochsToReschedule []abi.ChainEpoch
+
+			err = partitionSectors.ForEach(func(partIdx uint64, sectorNos bitfield.BitField) error {
+				var partition Partition
+				found, err := partitions.Get(partIdx, &partition)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %v partition %v", dlIdx, partIdx)
+				if !found {
+					rt.Abortf(exitcode.ErrNotFound, "no such deadline %v partition %v", dlIdx, partIdx)
+				}
+
+				oldSectors, err := sectors.Load(sectorNos)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors in deadline %v partition %v", dlIdx, partIdx)
+				newSectors := make([]*SectorOnChainInfo, len(oldSectors))
+				for i, sector := range oldSectors {
+					newExpiration := newExpirations[sector.SectorNumber]
+					newSectors[i] = extendSector(rt, currEpoch, sector, newExpiration)
+
+					prevEpochPartitions, ok := partitionsByNewEpoch[newExpiration]
+					partitionsByNewEpoch[newExpiration] = append(prevEpochPartitions, partIdx)
+					if !ok {
+						epochsToReschedule = append(epochsToReschedule, newExpiration)
+					}
+				}
+
+				// Overwrite sector infos.
+				err = sectors.Store(newSectors...)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update sectors %v", sectorNos)
+
+				// Remove old sectors from partition and assign new sectors, in one pass over the expiration queue.
+				partitionPowerDelta, partitionPledgeDelta, err := partition.ReplaceSectors(store, oldSectors, newSectors, info.SectorSize, quant)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to replace sector expirations at deadline %v partition %v", dlIdx, partIdx)
+				events = append(events, &SectorEvent{
+					Type:       SectorEventExtended,
+					Deadline:   dlIdx,
+					Partition:  partIdx,
+					Sectors:    sectorNos,
+					PowerDelta: partitionPowerDelta,
+				})
+
+				powerDelta = powerDelta.Add(partitionPowerDelta)
+				pledgeDelta = big.Add(pledgeDelta, partitionPledgeDelta) // expected to be zero, see note below.
+
+				err = partitions.Set(partIdx, &partition)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %v partition %v", dlIdx, partIdx)
+				return nil
+			})
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to extend sectors in deadline %d", dlIdx)
 
 			deadline.Partitions, err = partitions.Root()
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save partitions for deadline %d", dlIdx)
@@ -1401,7 +2292,9 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 
 			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %d", dlIdx)
-		}
+			return nil
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to extend sectors")
 
 		st.Sectors, err = sectors.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save sectors")
@@ -1411,8 +2304,8 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 	})
 
 	requestUpdatePower(rt, powerDelta)
+	emitSectorEvents(rt, events)
 	// Note: the pledge delta is expected to be zero, since pledge is not re-calculated for the extension.
-	// But in case that ever changes, we can do the right thing here.
 	notifyPledgeChanged(rt, pledgeDelta)
 	return nil
 }
@@ -1482,6 +2375,7 @@ func (a Actor) TerminateSectors(rt Runtime, params *TerminateSectorsParams) *Ter
 	store := adt.AsStore(rt)
 	currEpoch := rt.CurrEpoch()
 	powerDelta := NewPowerPairZero()
+	var events []*SectorEvent
 	rt.StateTransaction(&st, func() {
 		hadEarlyTerminations = havePendingEarlyTerminations(rt, &st)
 
@@ -1508,8 +2402,9 @@ func (a Actor) TerminateSectors(rt Runtime, params *TerminateSectorsParams) *Ter
 			deadline, err := deadlines.LoadDeadline(store, dlIdx)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
 
-			removedPower, err := deadline.TerminateSectors(store, sectors, currEpoch, partitionSectors, info.SectorSize, quant)
+			removedPower, terminated, err := deadline.TerminateSectors(store, sectors, currEpoch, partitionSectors, info.SectorSize, quant)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to terminate sectors in deadline %d", dlIdx)
+			events = append(events, newSectorEvents(SectorEventTerminated, dlIdx, terminated)...)
 
 			st.EarlyTerminations.Set(dlIdx)
 
@@ -1545,6 +2440,7 @@ func (a Actor) TerminateSectors(rt Runtime, params *TerminateSectorsParams) *Ter
 	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
 
 	requestUpdatePower(rt, powerDelta)
+	emitSectorEvents(rt, events)
 	return &TerminateSectorsReturn{Done: !more}
 }
 
@@ -1568,70 +2464,81 @@ type DeclareFaultsParams = miner0.DeclareFaultsParams
 type FaultDeclaration = miner0.FaultDeclaration
 
 func (a Actor) DeclareFaults(rt Runtime, params *DeclareFaultsParams) *abi.EmptyValue {
-	if len(params.Faults) > DeclarationsMax {
-		rt.Abortf(exitcode.ErrIllegalArgument,
-			"too many fault declarations for a single message: %d > %d",
-			len(params.Faults), DeclarationsMax,
-		)
-	}
-
-	toProcess := make(DeadlineSectorMap)
-	for _, term := range params.Faults {
-		err := toProcess.Add(term.Deadline, term.Partition, term.Sectors)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
-			"failed to process deadline %d, partition %d", term.Deadline, term.Partition,
-		)
-	}
-	err := toProcess.Check(AddressedPartitionsMax, AddressedSectorsMax)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "cannot process requested parameters")
+	toProcess := faultDeclarationsToProcess(rt, params.Faults)
 
 	store := adt.AsStore(rt)
 	var st State
 	powerDelta := NewPowerPairZero()
+	var events []*SectorEvent
 	rt.StateTransaction(&st, func() {
 		info := getMinerInfo(rt, &st)
 		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
 
-		deadlines, err := st.LoadDeadlines(store)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+		powerDelta, events = recordDeclaredFaults(rt, store, &st, info, toProcess)
+	})
 
-		sectors, err := LoadSectors(store, st.Sectors)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+	// Remove power for new faulty sectors.
+	// NOTE: It would be permissible to delay the power loss until the deadline closes, but that would require
+	// additional accounting state.
+	// https://github.com/filecoin-project/specs-actors/issues/414
+	requestUpdatePower(rt, powerDelta)
+	emitSectorEvents(rt, events)
 
-		currEpoch := rt.CurrEpoch()
-		err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
-			targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid fault declaration deadline %d", dlIdx)
+	// Payment of penalty for declared faults is deferred to the deadline cron.
+	return nil
+}
 
-			err = validateFRDeclarationDeadline(targetDeadline)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed fault declaration at deadline %d", dlIdx)
+type DeclareFaultsWithRecoveryEpochParams struct {
+	Faults []FaultDeclaration
+	// The epoch from which the faulty sectors are expected to be recovered.
+	RecoveryEpoch abi.ChainEpoch
+}
 
-			deadline, err := deadlines.LoadDeadline(store, dlIdx)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+// Declares faults, as for DeclareFaults, and schedules the faulty sectors to be declared recovered ahead of the
+// first challenge window of their deadline to open at or after the recovery epoch, and at least two challenge
+// windows after the current epoch.
+// The recovery is declared by the deadline cron ending the challenge window before the previous one.
+// As for DeclareFaultsRecovered, the sectors regain power only when proven by a Window PoSt.
+// Sectors that are no longer faulty when the recovery is declared are ignored, and the scheduled recovery is
+// dropped if the miner then has fee debt or an active consensus fault.
+func (a Actor) DeclareFaultsWithRecoveryEpoch(rt Runtime, params *DeclareFaultsWithRecoveryEpochParams) *abi.EmptyValue {
+	toProcess := faultDeclarationsToProcess(rt, params.Faults)
 
-			faultExpirationEpoch := targetDeadline.Last() + FaultMaxAge
-			deadlinePowerDelta, err := deadline.RecordFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, pm)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare faults for deadline %d", dlIdx)
+	currEpoch := rt.CurrEpoch()
+	if params.RecoveryEpoch > currEpoch+FaultMaxAge {
+		rt.Abortf(exitcode.ErrIllegalArgument, "recovery epoch %d must be no later than %d, after which faulty sectors are terminated",
+			params.RecoveryEpoch, currEpoch+FaultMaxAge)
+	}
 
-			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store deadline %d partitions", dlIdx)
+	store := adt.AsStore(rt)
+	var st State
+	powerDelta := NewPowerPairZero()
+	var events []*SectorEvent
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
 
-			powerDelta = powerDelta.Add(deadlinePowerDelta)
-			return nil
-		})
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deadlines")
+		powerDelta, events = recordDeclaredFaults(rt, store, &st, info, toProcess)
 
-		err = st.SaveDeadlines(store, deadlines)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+		// The earliest challenge window with a recovery that can still be declared by a deadline cron.
+		earliestOpen := currEpoch + 2*WPoStChallengeWindow + 1
+		if params.RecoveryEpoch > earliestOpen {
+			earliestOpen = params.RecoveryEpoch
+		}
+		periodStart := st.CurrentProvingPeriodStart(currEpoch)
+		err := toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+			recoveryOpen := nextChallengeWindowOpen(periodStart, dlIdx, earliestOpen)
+			sectorNos, err := pm.Sectors()
+			if err != nil {
+				return err
+			}
+			return st.AddScheduledRecoveries(store, recoveryOpen, sectorNos)
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to schedule recoveries")
 	})
 
-	// Remove power for new faulty sectors.
-	// NOTE: It would be permissible to delay the power loss until the deadline closes, but that would require
-	// additional accounting state.
-	// https://github.com/filecoin-project/specs-actors/issues/414
 	requestUpdatePower(rt, powerDelta)
-
-	// Payment of penalty for declared faults is deferred to the deadline cron.
+	emitSectorEvents(rt, events)
 	return nil
 }
 
@@ -1778,7 +2685,7 @@ func (a Actor) CompactPartitions(rt Runtime, params *CompactPartitionsParams) *a
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load moved sectors")
 
 		proven := true
-		addedPower, err := deadline.AddSectors(store, info.WindowPoStPartitionSectors, proven, sectors, info.SectorSize, quant)
+		addedPower, _, err := deadline.AddSectors(store, info.WindowPoStPartitionSectors, proven, sectors, info.SectorSize, quant)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add back moved sectors")
 
 		if !removedPower.Equals(addedPower) {
@@ -1793,6 +2700,95 @@ func (a Actor) CompactPartitions(rt Runtime, params *CompactPartitionsParams) *a
 	return nil
 }
 
+type MovePartitionsParams struct {
+	OrigDeadline uint64
+	DestDeadline uint64
+	Partitions   bitfield.BitField
+}
+
+// Moves whole partitions from one deadline to another, to rebalance proving load between deadlines.
+// The addressed partitions are removed from the origin deadline and appended to the destination deadline as they are,
+// including faulty, recovering, unproven and terminated sectors, and un-processed early terminations.
+// Sector expirations are re-quantized to the destination deadline.
+// Both deadlines must be available for compaction, i.e. neither being challenged, next to be challenged,
+// nor disputable. The destination deadline must be due to be challenged no later than the origin deadline
+// so that moving a partition never extends the interval between proofs of its sectors.
+func (a Actor) MovePartitions(rt Runtime, params *MovePartitionsParams) *abi.EmptyValue {
+	if params.OrigDeadline >= WPoStPeriodDeadlines {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid origin deadline %v", params.OrigDeadline)
+	}
+	if params.DestDeadline >= WPoStPeriodDeadlines {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid destination deadline %v", params.DestDeadline)
+	}
+	if params.OrigDeadline == params.DestDeadline {
+		rt.Abortf(exitcode.ErrIllegalArgument, "origin and destination deadlines must differ, both %d", params.OrigDeadline)
+	}
+
+	partitionCount, err := params.Partitions.Count()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to parse partitions bitfield")
+
+	store := adt.AsStore(rt)
+	var st State
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		currEpoch := rt.CurrEpoch()
+		provingPeriodStart := st.CurrentProvingPeriodStart(currEpoch)
+		for _, dlIdx := range []uint64{params.OrigDeadline, params.DestDeadline} {
+			if !deadlineAvailableForCompaction(provingPeriodStart, dlIdx, currEpoch) {
+				rt.Abortf(exitcode.ErrForbidden,
+					"cannot move partitions to or from deadline %d during its challenge window, or the prior challenge window, or before %d epochs have passed since its last challenge window ended", dlIdx, WPoStDisputeWindow)
+			}
+		}
+		origNext := NewDeadlineInfo(provingPeriodStart, params.OrigDeadline, currEpoch).NextNotElapsed()
+		destNext := NewDeadlineInfo(provingPeriodStart, params.DestDeadline, currEpoch).NextNotElapsed()
+		if destNext.Open > origNext.Open {
+			rt.Abortf(exitcode.ErrForbidden, "cannot move partitions from deadline %d to deadline %d which is next challenged later, at %d after %d",
+				params.OrigDeadline, params.DestDeadline, destNext.Open, origNext.Open)
+		}
+
+		submissionPartitionLimit := loadPartitionsSectorsMax(info.WindowPoStPartitionSectors)
+		if partitionCount > submissionPartitionLimit {
+			rt.Abortf(exitcode.ErrIllegalArgument, "too many partitions %d, limit %d", partitionCount, submissionPartitionLimit)
+		}
+
+		origQuant := st.QuantSpecForDeadline(params.OrigDeadline)
+		destQuant := st.QuantSpecForDeadline(params.DestDeadline)
+
+		deadlines, err := st.LoadDeadlines(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+		origDeadline, err := deadlines.LoadDeadline(store, params.OrigDeadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", params.OrigDeadline)
+		destDeadline, err := deadlines.LoadDeadline(store, params.DestDeadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", params.DestDeadline)
+
+		sectors, err := LoadSectors(store, st.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors")
+
+		err = origDeadline.MovePartitions(store, destDeadline, params.Partitions, sectors, info.SectorSize, origQuant, destQuant)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to move partitions from deadline %d to deadline %d",
+			params.OrigDeadline, params.DestDeadline)
+
+		// Early terminations moved with the partitions are processed from the destination deadline.
+		noEarlyTerminations, err := destDeadline.EarlyTerminations.IsEmpty()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check early terminations of deadline %d", params.DestDeadline)
+		if !noEarlyTerminations {
+			st.EarlyTerminations.Set(params.DestDeadline)
+		}
+
+		err = deadlines.UpdateDeadline(store, params.OrigDeadline, origDeadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update deadline %d", params.OrigDeadline)
+		err = deadlines.UpdateDeadline(store, params.DestDeadline, destDeadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update deadline %d", params.DestDeadline)
+
+		err = st.SaveDeadlines(store, deadlines)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+	})
+	return nil
+}
+
 //type CompactSectorNumbersParams struct {
 //	MaskSectorNumbers bitfield.BitField
 //}
@@ -1895,7 +2891,12 @@ func (a Actor) ReportConsensusFault(rt Runtime, params *ReportConsensusFaultPara
 	// Note: only the first report of any fault is processed because it sets the
 	// ConsensusFaultElapsed state variable to an epoch after the fault, and reports prior to
 	// that epoch are no longer valid.
-	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	// Batches of reports are forwarded by the power actor, which passes the reward on to the original reporter.
+	if rt.Caller() == builtin.StoragePowerActorAddr {
+		rt.ValidateImmediateCallerIs(builtin.StoragePowerActorAddr)
+	} else {
+		rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	}
 	reporter := rt.Caller()
 
 	fault, err := rt.VerifyConsensusFault(params.BlockHeader1, params.BlockHeader2, params.BlockHeaderExtra)
@@ -1972,9 +2973,10 @@ func (a Actor) ReportConsensusFault(rt Runtime, params *ReportConsensusFaultPara
 //}
 type WithdrawBalanceParams = miner0.WithdrawBalanceParams
 
-// Attempt to withdraw the specified amount from the miner's available balance.
-// Only owner key has permission to withdraw.
+// Attempt to withdraw the specified amount from the miner's available balance to the beneficiary.
+// Only the owner and the beneficiary have permission to withdraw.
 // If less than the specified amount is available, yields the entire available balance.
+// A beneficiary other than the owner may withdraw no more than its remaining quota, until its term expires.
 // Returns the amount withdrawn.
 func (a Actor) WithdrawBalance(rt Runtime, params *WithdrawBalanceParams) *abi.TokenAmount {
 	var st State
@@ -1985,12 +2987,13 @@ func (a Actor) WithdrawBalance(rt Runtime, params *WithdrawBalanceParams) *abi.T
 	newlyVested := big.Zero()
 	feeToBurn := big.Zero()
 	availableBalance := big.Zero()
+	amountWithdrawn := big.Zero()
 	rt.StateTransaction(&st, func() {
 		var err error
 		info = getMinerInfo(rt, &st)
-		// Only the owner is allowed to withdraw the balance as it belongs to/is controlled by the owner
-		// and not the worker.
-		rt.ValidateImmediateCallerIs(info.Owner)
+		// Only the owner and beneficiary are allowed to withdraw the balance as it belongs to/is controlled
+		// by the owner and not the worker.
+		rt.ValidateImmediateCallerIs(info.Owner, info.Beneficiary)
 
 		// Ensure we don't have any pending terminations.
 		if count, err := st.EarlyTerminations.Count(); err != nil {
@@ -2013,17 +3016,32 @@ func (a Actor) WithdrawBalance(rt Runtime, params *WithdrawBalanceParams) *abi.T
 		availableBalance, err = st.GetAvailableBalance(rt.CurrentBalance())
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate available balance")
 
+		amountWithdrawn = big.Min(availableBalance, params.AmountRequested)
+		if info.Beneficiary != info.Owner {
+			// A beneficiary other than the owner is limited by its term.
+			remainingQuota := info.BeneficiaryTerm.Available(rt.CurrEpoch())
+			if remainingQuota.IsZero() {
+				rt.Abortf(exitcode.ErrForbidden, "beneficiary term expired at %d or quota %v used up",
+					info.BeneficiaryTerm.Expiration, info.BeneficiaryTerm.Quota)
+			}
+			amountWithdrawn = big.Min(amountWithdrawn, remainingQuota)
+			if amountWithdrawn.GreaterThan(big.Zero()) {
+				info.BeneficiaryTerm.UsedQuota = big.Add(info.BeneficiaryTerm.UsedQuota, amountWithdrawn)
+				err = st.SaveInfo(adt.AsStore(rt), info)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save miner info")
+			}
+		}
+
 		// Verify unlocked funds cover both InitialPledgeRequirement and FeeDebt
 		// and repay fee debt now.
 		feeToBurn = RepayDebtsOrAbort(rt, &st)
 	})
 
-	amountWithdrawn := big.Min(availableBalance, params.AmountRequested)
 	builtin.RequireState(rt, amountWithdrawn.GreaterThanEqual(big.Zero()), "negative amount to withdraw: %v", amountWithdrawn)
 	builtin.RequireState(rt, amountWithdrawn.LessThanEqual(availableBalance), "amount to withdraw %v < available %v", amountWithdrawn, availableBalance)
 
 	if amountWithdrawn.GreaterThan(abi.NewTokenAmount(0)) {
-		code := rt.Send(info.Owner, builtin.MethodSend, nil, amountWithdrawn, &builtin.Discard{})
+		code := rt.Send(info.Beneficiary, builtin.MethodSend, nil, amountWithdrawn, &builtin.Discard{})
 		builtin.RequireSuccess(rt, code, "failed to withdraw balance")
 	}
 
@@ -2064,9 +3082,78 @@ type ReplicaUpdate = miner7.ReplicaUpdate
 type ProveReplicaUpdatesParams = miner7.ProveReplicaUpdatesParams
 
 func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams) *bitfield.BitField {
+	results := proveReplicaUpdates(rt, params.Updates, replicaUpdateRules{})
+
+	succeededSectors := bitfield.New()
+	for _, result := range results {
+		if result.Outcome == ReplicaUpdateSucceeded {
+			succeededSectors.Set(uint64(result.SectorNumber))
+		}
+	}
+	return &succeededSectors
+}
+
+// ReplicaUpdateOutcome describes whether a replica update succeeded, or why it was skipped.
+type ReplicaUpdateOutcome uint64
+
+const (
+	ReplicaUpdateSucceeded ReplicaUpdateOutcome = iota
+	// The sector is updated by an earlier entry in the same batch.
+	ReplicaUpdateDuplicate
+	// The update parameters are malformed or exceed policy limits.
+	ReplicaUpdateInvalidParams
+	// The sector's deadline is the current or next to be proven.
+	ReplicaUpdateImmutableDeadline
+	// The sector is not active at the given location, e.g. it is faulty, terminated or unproven.
+	ReplicaUpdateSectorNotActive
+	// The sector carries deals which are still active.
+	ReplicaUpdateActiveDeals
+	// The market rejected activation of the new deals.
+	ReplicaUpdateDealActivationFailed
+)
+
+type ReplicaUpdateResult struct {
+	SectorNumber abi.SectorNumber
+	Outcome      ReplicaUpdateOutcome
+}
+
+type ProveReplicaUpdates2Params struct {
+	Updates []ReplicaUpdate
+}
+
+type ProveReplicaUpdates2Return struct {
+	// The outcome of each update, in the order of the updates in the parameters.
+	Results []ReplicaUpdateResult
+}
+
+// Proves replica updates as for ProveReplicaUpdates, but also permits updating sectors which already carry deals,
+// provided none of those deals is still active in the market. The existing deals are replaced by the new ones.
+// The sector's initial pledge is reset to the requirement for its new power, which may release pledge.
+// Returns the outcome of every update, whether or not it succeeded, and does not abort if none succeed.
+func (a Actor) ProveReplicaUpdates2(rt Runtime, params *ProveReplicaUpdates2Params) *ProveReplicaUpdates2Return {
+	results := proveReplicaUpdates(rt, params.Updates, replicaUpdateRules{
+		allowInactiveDeals: true,
+		resetPledge:        true,
+		allowNoneValid:     true,
+	})
+	return &ProveReplicaUpdates2Return{Results: results}
+}
+
+// Rules that differ between versions of the replica update method.
+type replicaUpdateRules struct {
+	// Whether a sector with deals may be updated once none of those deals remains active.
+	allowInactiveDeals bool
+	// Whether a sector's initial pledge is reset to the requirement at update, rather than only ever increased.
+	resetPledge bool
+	// Whether to return the outcomes, rather than abort, when no update is valid.
+	allowNoneValid bool
+}
+
+// Updates sectors with new replicas, skipping invalid updates. Returns the outcome of each update.
+func proveReplicaUpdates(rt Runtime, updates []ReplicaUpdate, rules replicaUpdateRules) []ReplicaUpdateResult {
 	// Validate inputs
 
-	builtin.RequireParam(rt, len(params.Updates) <= ProveReplicaUpdatesMaxSize, "too many updates (%d > %d)", len(params.Updates), ProveReplicaUpdatesMaxSize)
+	builtin.RequireParam(rt, len(updates) <= ProveReplicaUpdatesMaxSize, "too many updates (%d > %d)", len(updates), ProveReplicaUpdatesMaxSize)
 
 	store := adt.AsStore(rt)
 	var stReadOnly State
@@ -2082,21 +3169,24 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 	pledgeDelta := big.Zero()
 
 	type updateAndSectorInfo struct {
+		index      int
 		update     *ReplicaUpdate
 		sectorInfo *SectorOnChainInfo
 	}
 
-	var sectorsDeals []market.SectorDeals
-	var sectorsDataSpec []*market.SectorDataSpec
-	var validatedUpdates []*updateAndSectorInfo
+	results := make([]ReplicaUpdateResult, len(updates))
+	var candidates []*updateAndSectorInfo
+	var existingDeals []abi.DealID
 	sectorNumbers := bitfield.New()
-	for i := range params.Updates {
-		update := params.Updates[i]
+	for i := range updates {
+		update := updates[i]
+		results[i] = ReplicaUpdateResult{SectorNumber: update.SectorID, Outcome: ReplicaUpdateInvalidParams}
 		// Bitfied.IsSet() is fast when there are only locally-set values.
 		set, err := sectorNumbers.IsSet(uint64(update.SectorID))
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "error checking sector number")
 		if set {
 			rt.Log(rtt.INFO, "duplicate sector being updated %d, skipping", update.SectorID)
+			results[i].Outcome = ReplicaUpdateDuplicate
 			continue
 		}
 
@@ -2136,6 +3226,7 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 		// We assume that deadlines are immutable when being proven.
 		if !deadlineIsMutable(stReadOnly.CurrentProvingPeriodStart(rt.CurrEpoch()), update.Deadline, rt.CurrEpoch()) {
 			rt.Log(rtt.INFO, "cannot upgrade sectors in immutable deadline %d, skipping sector %d", update.Deadline, update.SectorID)
+			results[i].Outcome = ReplicaUpdateImmutableDeadline
 			continue
 		}
 
@@ -2145,17 +3236,52 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 
 		if !healthy {
 			rt.Log(rtt.INFO, "sector isn't healthy, skipping sector %d", update.SectorID)
+			results[i].Outcome = ReplicaUpdateSectorNotActive
 			continue
 		}
 
 		sectorInfo, err := sectors.MustGet(update.SectorID)
 		if err != nil {
 			rt.Log(rtt.INFO, "failed to get sector, skipping sector %d", update.SectorID)
+			results[i].Outcome = ReplicaUpdateSectorNotActive
 			continue
 		}
 
 		if len(sectorInfo.DealIDs) != 0 {
-			rt.Log(rtt.INFO, "cannot update sector with deals, skipping sector %d", update.SectorID)
+			if !rules.allowInactiveDeals {
+				rt.Log(rtt.INFO, "cannot update sector with deals, skipping sector %d", update.SectorID)
+				results[i].Outcome = ReplicaUpdateActiveDeals
+				continue
+			}
+			existingDeals = append(existingDeals, sectorInfo.DealIDs...)
+		}
+
+		candidates = append(candidates, &updateAndSectorInfo{
+			index:      i,
+			update:     &update,
+			sectorInfo: sectorInfo,
+		})
+	}
+
+	// Sectors may be updated only once all their existing deals have ended.
+	activeDeals := map[abi.DealID]bool{}
+	for _, dealID := range requestActiveDeals(rt, existingDeals) {
+		activeDeals[dealID] = true
+	}
+
+	var sectorsDeals []market.SectorDeals
+	var sectorsDataSpec []*market.SectorDataSpec
+	var validatedUpdates []*updateAndSectorInfo
+	for _, candidate := range candidates {
+		update := candidate.update
+		sectorInfo := candidate.sectorInfo
+		hasActiveDeals := false
+		for _, dealID := range sectorInfo.DealIDs {
+			hasActiveDeals = hasActiveDeals || activeDeals[dealID]
+		}
+		if hasActiveDeals {
+			rt.Log(rtt.INFO, "cannot update sector with active deals, skipping sector %d", update.SectorID)
+			results[candidate.index].Outcome = ReplicaUpdateActiveDeals
 			continue
 		}
 
@@ -2172,13 +3298,12 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 
 		if code != exitcode.Ok {
 			rt.Log(rtt.INFO, "failed to activate deals, skipping sector %d", update.SectorID)
+			results[candidate.index].Outcome = ReplicaUpdateDealActivationFailed
 			continue
 		}
 
-		validatedUpdates = append(validatedUpdates, &updateAndSectorInfo{
-			update:     &update,
-			sectorInfo: sectorInfo,
-		})
+		results[candidate.index].Outcome = ReplicaUpdateSucceeded
+		validatedUpdates = append(validatedUpdates, candidate)
 
 		sectorsDeals = append(sectorsDeals, market.SectorDeals{DealIDs: update.Deals, SectorExpiry: sectorInfo.Expiration})
 		sectorsDataSpec = append(sectorsDataSpec, &market.SectorDataSpec{
@@ -2187,6 +3312,9 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 		})
 	}
 
+	if len(validatedUpdates) == 0 && rules.allowNoneValid {
+		return results
+	}
 	builtin.RequireParam(rt, len(validatedUpdates) > 0, "no valid updates")
 
 	// Errors past this point cause the ProveReplicaUpdates call to fail (no more skipping sectors)
@@ -2225,6 +3353,7 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 	powRet := requestCurrentTotalPower(rt)
 
 	succeededSectors := bitfield.New()
+	var events []*SectorEvent
 	var st State
 	rt.StateTransaction(&st, func() {
 		deadlines, err := st.LoadDeadlines(store)
@@ -2282,17 +3411,7 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 				initialPledgeAtUpgrade := InitialPledgeForPower(pwr, rewRet.ThisEpochBaselinePower, rewRet.ThisEpochRewardSmoothed,
 					powRet.QualityAdjPowerSmoothed, rt.TotalFilCircSupply())
 
-				if initialPledgeAtUpgrade.GreaterThan(updateWithDetails.sectorInfo.InitialPledge) {
-					deficit := big.Sub(initialPledgeAtUpgrade, updateWithDetails.sectorInfo.InitialPledge)
-
-					unlockedBalance, err := st.GetUnlockedBalance(rt.CurrentBalance())
-					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate unlocked balance")
-					builtin.RequirePredicate(rt, unlockedBalance.GreaterThanEqual(deficit), exitcode.ErrInsufficientFunds, "insufficient funds for new initial pledge requirement %s, available: %s, skipping sector %d",
-						deficit, unlockedBalance, updateWithDetails.sectorInfo.SectorNumber)
-
-					err = st.AddInitialPledge(deficit)
-					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add initial pledge")
-
+				if rules.resetPledge || initialPledgeAtUpgrade.GreaterThan(updateWithDetails.sectorInfo.InitialPledge) {
 					newSectorInfo.InitialPledge = initialPledgeAtUpgrade
 				}
 
@@ -2314,6 +3433,23 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to replace sector at deadline %d partition %d", updateWithDetails.update.Deadline, updateWithDetails.update.Partition)
 
+				// The partition's pledge changes by the difference between the new and old sectors' pledge.
+				if partitionPledgeDelta.GreaterThan(big.Zero()) {
+					unlockedBalance, err := st.GetUnlockedBalance(rt.CurrentBalance())
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate unlocked balance")
+					builtin.RequirePredicate(rt, unlockedBalance.GreaterThanEqual(partitionPledgeDelta), exitcode.ErrInsufficientFunds, "insufficient funds for new initial pledge requirement %s, available: %s, skipping sector %d",
+						partitionPledgeDelta, unlockedBalance, updateWithDetails.sectorInfo.SectorNumber)
+				}
+				err = st.AddInitialPledge(partitionPledgeDelta)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add initial pledge")
+				events = append(events, &SectorEvent{
+					Type:       SectorEventUpdated,
+					Deadline:   dlIdx,
+					Partition:  updateWithDetails.update.Partition,
+					Sectors:    bitfield.NewFromSet([]uint64{uint64(newSectorInfo.SectorNumber)}),
+					PowerDelta: partitionPowerDelta,
+				})
+
 				powerDelta = powerDelta.Add(partitionPowerDelta)
 				pledgeDelta = big.Add(pledgeDelta, partitionPledgeDelta)
 
@@ -2351,8 +3487,9 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 
 	notifyPledgeChanged(rt, pledgeDelta)
 	requestUpdatePower(rt, powerDelta)
+	emitSectorEvents(rt, events)
 
-	return &succeededSectors
+	return results
 }
 
 //////////
@@ -2503,10 +3640,15 @@ func handleProvingDeadline(rt Runtime,
 	powerDeltaTotal := NewPowerPairZero()
 	penaltyTotal := abi.NewTokenAmount(0)
 	pledgeDeltaTotal := abi.NewTokenAmount(0)
+	var events []*SectorEvent
+	var workerEvent *WorkerKeyChangedEvent
 
 	var continueCron bool
 	var st State
 	rt.StateTransaction(&st, func() {
+		// Miner info is not changed in this transaction other than by processing a pending worker change.
+		info := getMinerInfo(rt, &st)
+
 		{
 			// Vest locked funds.
 			// This happens first so that any subsequent penalties are taken
@@ -2518,8 +3660,7 @@ func handleProvingDeadline(rt Runtime,
 
 		{
 			// Process pending worker change if any
-			info := getMinerInfo(rt, &st)
-			processPendingWorker(info, rt, &st)
+			workerEvent = processPendingWorker(info, rt, &st)
 		}
 
 		{
@@ -2536,8 +3677,12 @@ func handleProvingDeadline(rt Runtime,
 		hadEarlyTerminations = havePendingEarlyTerminations(rt, &st)
 
 		{
+			dlIdx := st.DeadlineInfo(currEpoch).Index
 			result, err := st.AdvanceDeadline(store, currEpoch)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to advance deadline")
+			events = append(events, newSectorEvents(SectorEventFaulted, dlIdx, result.DetectedFaults)...)
+			events = append(events, newSectorEvents(SectorEventExpired, dlIdx, result.OnTimeExpired)...)
+			events = append(events, newSectorEvents(SectorEventTerminated, dlIdx, result.EarlyExpired)...)
 
 			// Faults detected by this missed PoSt pay no penalty, but sectors that were already faulty
 			// and remain faulty through this deadline pay the fault fee.
@@ -2554,12 +3699,59 @@ func handleProvingDeadline(rt Runtime,
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to apply penalty")
 			rt.Log(rtt.DEBUG, "storage provider %s penalized %s for continued fault", rt.Receiver(), penaltyTarget)
 
-			penaltyFromVesting, penaltyFromBalance, err := st.RepayPartialDebtInPriorityOrder(store, currEpoch, rt.CurrentBalance())
+			// Repay fee debt as directed by the miner's repayment policy.
+			penaltyFromVesting, penaltyFromBalance, err := st.RepayPartialDebtByPolicy(store, currEpoch, rt.CurrentBalance(), &info.DebtRepaymentPolicy)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unlock penalty")
 			penaltyTotal = big.Add(penaltyFromVesting, penaltyFromBalance)
 			pledgeDeltaTotal = big.Sub(pledgeDeltaTotal, penaltyFromVesting)
 		}
 
+		{
+			// Declare recoveries scheduled ahead of the challenge window of the deadline two after the
+			// new current deadline, which is the last deadline for which declarations are permitted.
+			recoveryDlIdx := (st.CurrentDeadline + 2) % WPoStPeriodDeadlines
+			recoveryDlInfo := NewDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), recoveryDlIdx, currEpoch).NextNotElapsed()
+			scheduled, err := st.PopScheduledRecoveries(store, recoveryDlInfo.Open)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to pop scheduled recoveries")
+
+			if st.IsDebtFree() && !ConsensusFaultActive(info, currEpoch) {
+				missing, err := st.DeclareScheduledRecoveries(store, recoveryDlIdx, scheduled, info.SectorSize)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare scheduled recoveries for deadline %d", recoveryDlIdx)
+				if count, err := missing.Count(); err != nil {
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to count missing scheduled recoveries")
+				} else if count > 0 {
+					rt.Log(rtt.INFO, "storage provider %s dropped scheduled recoveries of %d sectors no longer in deadline %d", rt.Receiver(), count, recoveryDlIdx)
+				}
+			} else if empty, err := scheduled.IsEmpty(); err != nil {
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check scheduled recoveries")
+			} else if !empty {
+				rt.Log(rtt.INFO, "storage provider %s dropped scheduled recoveries for deadline %d with fee debt or consensus fault", rt.Receiver(), recoveryDlIdx)
+			}
+		}
+
+		{
+			// Gradually remove fully-terminated partitions and their sector infos from state.
+			// Each deadline is compacted in turn, the one compacted being two after the new current deadline
+			// so that it remains mutable through the next deadline.
+			// Only trailing partitions are removed, so the indices of other partitions are not changed
+			// underneath messages that address them.
+			// Note that this cron stops once the miner has no pledge, vesting funds or pre-commitments,
+			// so a miner whose sectors have all terminated is not compacted further in the background.
+			// Such a miner may still remove its terminated partitions with CompactPartitions.
+			compactDlIdx := (st.CurrentDeadline + 2) % WPoStPeriodDeadlines
+			if deadlineAvailableForCompaction(st.CurrentProvingPeriodStart(currEpoch), compactDlIdx, currEpoch) {
+				removed, err := st.CompactTerminatedPartitions(store, compactDlIdx, CronCompactionPartitionsMax, CronCompactionSectorsMax)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compact deadline %d", compactDlIdx)
+				if removed > 0 {
+					rt.Log(rtt.DEBUG, "storage provider %s removed %d terminated partitions from deadline %d", rt.Receiver(), removed, compactDlIdx)
+				}
+			}
+
+			// Mask the unused sector numbers below the miner's lowest sector.
+			_, err := st.CompactAllocatedSectors(store)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compact allocated sector numbers")
+		}
+
 		continueCron = st.ContinueDeadlineCron()
 		if !continueCron {
 			st.DeadlineCronActive = false
@@ -2567,6 +3759,10 @@ func handleProvingDeadline(rt Runtime,
 	})
 	// Remove power for new faults, and burn penalties.
 	requestUpdatePower(rt, powerDeltaTotal)
+	emitSectorEvents(rt, events)
+	if workerEvent != nil {
+		rt.EmitEvent(workerEvent)
+	}
 	burnFunds(rt, penaltyTotal, BurnMethodHandleProvingDeadline)
 	notifyPledgeChanged(rt, pledgeDeltaTotal)
 
@@ -2624,6 +3820,45 @@ func validateExpiration(rt Runtime, activation, expiration abi.ChainEpoch, sealP
 	}
 }
 
+// Validates an extension of a sector's expiration and returns the updated sector info.
+// The sector must be of an extendable seal type, not yet expired, and the new expiration not earlier than the current one.
+func extendSector(rt Runtime, currEpoch abi.ChainEpoch, sector *SectorOnChainInfo, newExpiration abi.ChainEpoch) *SectorOnChainInfo {
+	if !CanExtendSealProofType(sector.SealProof) {
+		rt.Abortf(exitcode.ErrForbidden, "cannot extend expiration for sector %v with unsupported seal type %v",
+			sector.SectorNumber, sector.SealProof)
+	}
+	// This can happen if the sector should have already expired, but hasn't
+	// because the end of its deadline hasn't passed yet.
+	if sector.Expiration < currEpoch {
+		rt.Abortf(exitcode.ErrForbidden, "cannot extend expiration for expired sector %v, expired at %d, now %d",
+			sector.SectorNumber,
+			sector.Expiration,
+			currEpoch,
+		)
+	}
+	if newExpiration < sector.Expiration {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot reduce sector %v's expiration to %d from %d",
+			sector.SectorNumber, newExpiration, sector.Expiration)
+	}
+	validateExpiration(rt, sector.Activation, newExpiration, sector.SealProof)
+
+	// Remove "spent" deal weights
+	newDealWeight := big.Div(
+		big.Mul(sector.DealWeight, big.NewInt(int64(sector.Expiration-currEpoch))),
+		big.NewInt(int64(sector.Expiration-sector.Activation)),
+	)
+	newVerifiedDealWeight := big.Div(
+		big.Mul(sector.VerifiedDealWeight, big.NewInt(int64(sector.Expiration-currEpoch))),
+		big.NewInt(int64(sector.Expiration-sector.Activation)),
+	)
+
+	newSector := *sector
+	newSector.Expiration = newExpiration
+	newSector.DealWeight = newDealWeight
+	newSector.VerifiedDealWeight = newVerifiedDealWeight
+	return &newSector
+}
+
 func enrollCronEvent(rt Runtime, eventEpoch abi.ChainEpoch, callbackPayload *CronEventPayload) {
 	payload := new(bytes.Buffer)
 	err := callbackPayload.MarshalCBOR(payload)
@@ -2693,7 +3928,9 @@ func havePendingEarlyTerminations(rt Runtime, st *State) bool {
 	return !noEarlyTerminations
 }
 
-func verifyWindowedPost(rt Runtime, challengeEpoch abi.ChainEpoch, sectors []*SectorOnChainInfo, proofs []proof.PoStProof) error {
+// Verifies Window PoSt proofs for the given sectors. If aggregated, the proofs must comprise
+// a single aggregate of the proofs of each partition.
+func verifyWindowedPost(rt Runtime, challengeEpoch abi.ChainEpoch, sectors []*SectorOnChainInfo, proofs []proof.PoStProof, aggregated bool) error {
 	minerActorID, err := addr.IDFromAddress(rt.Receiver())
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "runtime provided bad receiver address %v", rt.Receiver())
 
@@ -2713,6 +3950,23 @@ func verifyWindowedPost(rt Runtime, challengeEpoch abi.ChainEpoch, sectors []*Se
 		}
 	}
 
+	if aggregated {
+		if len(proofs) != 1 {
+			return xerrors.Errorf("expected exactly one aggregate proof, got %d", len(proofs))
+		}
+		aggInfo := proof.AggregateWindowPoStVerifyInfo{
+			Randomness:        abi.PoStRandomness(postRandomness),
+			AggregateProof:    abi.RegisteredAggregationProof_SnarkPackV1,
+			Proof:             proofs[0],
+			ChallengedSectors: sectorProofInfo,
+			Prover:            abi.ActorID(minerActorID),
+		}
+		if err = rt.VerifyAggregatePoSt(aggInfo); err != nil {
+			return fmt.Errorf("invalid aggregate PoSt %+v: %w", aggInfo, err)
+		}
+		return nil
+	}
+
 	// Get public inputs
 	pvInfo := proof.WindowPoStVerifyInfo{
 		Randomness:        abi.PoStRandomness(postRandomness),
@@ -2803,6 +4057,40 @@ func requestUnsealedSectorCIDs(rt Runtime, dataCommitmentInputs ...*market.Secto
 	return unsealedCIDs
 }
 
+// Returns those of the given deals which are still active in the market.
+func requestActiveDeals(rt Runtime, dealIDs []abi.DealID) []abi.DealID {
+	if len(dealIDs) == 0 {
+		return nil
+	}
+	var ret market.GetActiveDealsReturn
+	code := rt.Send(
+		builtin.StorageMarketActorAddr,
+		builtin.MethodsMarket.GetActiveDeals,
+		&market.GetActiveDealsParams{DealIDs: dealIDs},
+		abi.NewTokenAmount(0),
+		&ret,
+	)
+	builtin.RequireSuccess(rt, code, "failed to query active deals")
+	return ret.DealIDs
+}
+
+// Activates the deals of each of a number of sectors. Each sector's deals are either all activated or not at all.
+func requestActivateDeals(rt Runtime, sectors []market.SectorDeals) *market.ActivateDealsBatchReturn {
+	if len(sectors) == 0 {
+		return &market.ActivateDealsBatchReturn{}
+	}
+	var ret market.ActivateDealsBatchReturn
+	code := rt.Send(
+		builtin.StorageMarketActorAddr,
+		builtin.MethodsMarket.ActivateDealsBatch,
+		&market.ActivateDealsBatchParams{Sectors: sectors},
+		abi.NewTokenAmount(0),
+		&ret,
+	)
+	builtin.RequireSuccess(rt, code, "failed to activate deals")
+	return &ret
+}
+
 func requestDealWeights(rt Runtime, sectors []market.SectorDeals) *market.VerifyDealsForActivationReturn {
 	// Short-circuit if there are no deals in any of the sectors.
 	dealCount := 0
@@ -2959,16 +4247,20 @@ func currentDeadlineIndex(currEpoch abi.ChainEpoch, periodStart abi.ChainEpoch)
 }
 
 // Update worker address with pending worker key if exists and delay has passed
-func processPendingWorker(info *MinerInfo, rt Runtime, st *State) {
+// Changes the worker if a pending change has become effective, returning an event recording the change.
+// Returns nil if the worker is not changed.
+func processPendingWorker(info *MinerInfo, rt Runtime, st *State) *WorkerKeyChangedEvent {
 	if info.PendingWorkerKey == nil || rt.CurrEpoch() < info.PendingWorkerKey.EffectiveAt {
-		return
+		return nil
 	}
 
+	event := &WorkerKeyChangedEvent{OldWorker: info.Worker, NewWorker: info.PendingWorkerKey.NewWorker}
 	info.Worker = info.PendingWorkerKey.NewWorker
 	info.PendingWorkerKey = nil
 
 	err := st.SaveInfo(adt.AsStore(rt), info)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "could not save miner info")
+	return event
 }
 
 // Computes deadline information for a fault or recovery declaration.
@@ -2983,6 +4275,75 @@ func declarationDeadlineInfo(periodStart abi.ChainEpoch, deadlineIdx uint64, cur
 	return deadline, nil
 }
 
+// Validates fault declarations and groups the declared sectors by deadline and partition.
+func faultDeclarationsToProcess(rt Runtime, faults []FaultDeclaration) DeadlineSectorMap {
+	if len(faults) > DeclarationsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument,
+			"too many fault declarations for a single message: %d > %d",
+			len(faults), DeclarationsMax,
+		)
+	}
+
+	toProcess := make(DeadlineSectorMap)
+	for _, term := range faults {
+		err := toProcess.Add(term.Deadline, term.Partition, term.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"failed to process deadline %d, partition %d", term.Deadline, term.Partition,
+		)
+	}
+	err := toProcess.Check(AddressedPartitionsMax, AddressedSectorsMax)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "cannot process requested parameters")
+	return toProcess
+}
+
+// Records declared faults at each deadline, returning the change in power and the events for newly faulty sectors.
+func recordDeclaredFaults(rt Runtime, store adt.Store, st *State, info *MinerInfo, toProcess DeadlineSectorMap) (PowerPair, []*SectorEvent) {
+	deadlines, err := st.LoadDeadlines(store)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+	sectors, err := LoadSectors(store, st.Sectors)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+
+	powerDelta := NewPowerPairZero()
+	var events []*SectorEvent
+	currEpoch := rt.CurrEpoch()
+	err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+		targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid fault declaration deadline %d", dlIdx)
+
+		err = validateFRDeclarationDeadline(targetDeadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed fault declaration at deadline %d", dlIdx)
+
+		deadline, err := deadlines.LoadDeadline(store, dlIdx)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+
+		faultExpirationEpoch := targetDeadline.Last() + FaultMaxAge
+		deadlinePowerDelta, newFaults, err := deadline.RecordFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, pm)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare faults for deadline %d", dlIdx)
+		events = append(events, newSectorEvents(SectorEventFaulted, dlIdx, newFaults)...)
+
+		err = deadlines.UpdateDeadline(store, dlIdx, deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store deadline %d partitions", dlIdx)
+
+		powerDelta = powerDelta.Add(deadlinePowerDelta)
+		return nil
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deadlines")
+
+	err = st.SaveDeadlines(store, deadlines)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+	return powerDelta, events
+}
+
+// Returns the opening epoch of the first challenge window of a deadline to open at or after an epoch.
+func nextChallengeWindowOpen(periodStart abi.ChainEpoch, dlIdx uint64, epoch abi.ChainEpoch) abi.ChainEpoch {
+	dlInfo := NewDeadlineInfo(periodStart, dlIdx, epoch).NextNotElapsed()
+	if dlInfo.Open < epoch {
+		dlInfo = NewDeadlineInfo(dlInfo.PeriodStart+WPoStProvingPeriod, dlIdx, epoch)
+	}
+	return dlInfo.Open
+}
+
 // Checks that a fault or recovery declaration at a specific deadline is outside the exclusion window for the deadline.
 func validateFRDeclarationDeadline(deadline *dline.Info) error {
 	if deadline.FaultCutoffPassed() {
@@ -3008,9 +4369,7 @@ func terminationPenalty(sectorSize abi.SectorSize, currEpoch abi.ChainEpoch,
 	rewardEstimate, networkQAPowerEstimate smoothing.FilterEstimate, sectors []*SectorOnChainInfo) abi.TokenAmount {
 	totalFee := big.Zero()
 	for _, s := range sectors {
-		sectorPower := QAPowerForSector(sectorSize, s)
-		fee := PledgePenaltyForTermination(s.ExpectedDayReward, currEpoch-s.Activation, s.ExpectedStoragePledge,
-			networkQAPowerEstimate, sectorPower, rewardEstimate, s.ReplacedDayReward, s.ReplacedSectorAge)
+		fee := TerminationPenaltyForSector(sectorSize, currEpoch, rewardEstimate, networkQAPowerEstimate, s)
 		totalFee = big.Add(fee, totalFee)
 	}
 	return totalFee
@@ -889,13 +889,13 @@ func TestProveCommit(t *testing.T) {
 		// Set the right epoch for all following tests
 		rt.SetEpoch(precommitEpoch + miner.PreCommitChallengeDelay + 1)
 
-		// Invalid deals (market ActivateDeals aborts)
-		verifyDealsExit := map[abi.SectorNumber]exitcode.ExitCode{
-			precommit.Info.SectorNumber: exitcode.ErrIllegalArgument,
+		// Invalid deals (market fails to activate them)
+		failedDealActivations := map[abi.SectorNumber]bool{
+			precommit.Info.SectorNumber: true,
 		}
 		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
 			actor.proveCommitSectorAndConfirm(rt, precommit, makeProveCommit(sectorNo), proveCommitConf{
-				verifyDealsExit: verifyDealsExit,
+				failedDealActivations: failedDealActivations,
 			})
 		})
 		rt.Reset()
@@ -990,8 +990,8 @@ func TestProveCommit(t *testing.T) {
 		actor.proveCommitSector(rt, preCommitB, makeProveCommit(sectorNoB))
 
 		conf := proveCommitConf{
-			verifyDealsExit: map[abi.SectorNumber]exitcode.ExitCode{
-				sectorNoA: exitcode.ErrIllegalArgument,
+			failedDealActivations: map[abi.SectorNumber]bool{
+				sectorNoA: true,
 			},
 		}
 		actor.confirmSectorProofsValid(rt, conf, preCommitA, preCommitB)
@@ -1222,6 +1222,302 @@ func TestAggregateProveCommit(t *testing.T) {
 	})
 }
 
+func TestProveCommitSectorsNI(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	epoch := periodOffset + 1
+
+	setup := func(t *testing.T) (*actorHarness, *mock.Runtime, abi.ChainEpoch) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		rt.SetEpoch(epoch)
+		actor.constructAndVerify(rt)
+		expiration := actor.deadline(rt).PeriodEnd() + defaultSectorExpiration*miner.WPoStProvingPeriod
+		return actor, rt, expiration
+	}
+
+	t.Run("activates sectors without pre-commitment", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		params := actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch-1, expiration)
+		actor.proveCommitSectorsNI(rt, params, proveCommitNIConf{firstForMiner: true}, big.Zero())
+
+		st := getState(rt)
+		assert.True(t, st.DeadlineCronActive)
+		assert.Equal(t, big.Zero(), st.PreCommitDeposits)
+
+		qaPower := miner.QAPowerForWeight(actor.sectorSize, expiration-rt.Epoch(), big.Zero(), big.Zero())
+		expectedPledge := miner.InitialPledgeForPower(qaPower, actor.baselinePower, actor.epochRewardSmooth,
+			actor.epochQAPowerSmooth, rt.TotalFilCircSupply())
+		assert.Equal(t, big.Mul(big.NewInt(int64(len(params.Sectors))), expectedPledge), st.InitialPledge)
+
+		activated := bitfield.New()
+		for _, sector := range params.Sectors {
+			_, found, err := st.GetPrecommittedSector(rt.AdtStore(), sector.SectorNumber)
+			require.NoError(t, err)
+			assert.False(t, found)
+
+			onChain := actor.getSector(rt, sector.SectorNumber)
+			assert.Equal(t, sector.SealedCID, onChain.SealedCID)
+			assert.Equal(t, sector.Expiration, onChain.Expiration)
+			assert.Equal(t, rt.Epoch(), onChain.Activation)
+			assert.Equal(t, expectedPledge, onChain.InitialPledge)
+			activated.Set(uint64(sector.SectorNumber))
+		}
+
+		// Sectors are assigned to deadlines, and activation is the only event.
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), params.Sectors[0].SectorNumber)
+		require.NoError(t, err)
+		assertSectorEvents(t, rt, &miner.SectorEvent{
+			Type:       miner.SectorEventActivated,
+			Deadline:   dlIdx,
+			Partition:  pIdx,
+			Sectors:    activated,
+			PowerDelta: miner.NewPowerPairZero(),
+		})
+		actor.checkState(rt)
+
+		// Sector numbers were allocated.
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "already allocated", func() {
+			rt.Call(actor.a.ProveCommitSectorsNI, params)
+		})
+	})
+
+	t.Run("drops sectors whose deals fail to activate", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		params := actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch-1, expiration)
+		params.Sectors[0].DealIDs = []abi.DealID{1}
+		params.Sectors[1].DealIDs = []abi.DealID{2}
+		sectorWeight := big.Mul(big.NewInt(int64(actor.sectorSize)), big.NewInt(int64(expiration-rt.Epoch())))
+		weights := []market.SectorWeights{
+			{DealSpace: uint64(actor.sectorSize), DealWeight: big.Zero(), VerifiedDealWeight: sectorWeight},
+			{DealSpace: uint64(actor.sectorSize), DealWeight: big.Zero(), VerifiedDealWeight: sectorWeight},
+		}
+		actor.proveCommitSectorsNI(rt, params, proveCommitNIConf{
+			sectorWeights:         weights,
+			failedDealActivations: map[abi.SectorNumber]bool{100: true},
+			firstForMiner:         true,
+		}, big.Zero())
+
+		st := getState(rt)
+		_, found, err := st.GetSector(rt.AdtStore(), 100)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		verified := actor.getSector(rt, 101)
+		assert.Equal(t, sectorWeight, verified.VerifiedDealWeight)
+		assert.Equal(t, []abi.DealID{2}, verified.DealIDs)
+		for _, sno := range []abi.SectorNumber{102, 103} {
+			actor.getSector(rt, sno)
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("burns aggregate fee", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		baseFee := big.NewInt(1e9)
+		rt.SetBaseFee(baseFee)
+		params := actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch-1, expiration)
+		actor.proveCommitSectorsNI(rt, params, proveCommitNIConf{firstForMiner: true}, baseFee)
+		actor.checkState(rt)
+	})
+
+	t.Run("burns aggregate fee only for activated sectors", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		baseFee := big.NewInt(1e9)
+		rt.SetBaseFee(baseFee)
+		params := actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch-1, expiration)
+		params.Sectors[0].DealIDs = []abi.DealID{1}
+		params.Sectors[1].DealIDs = []abi.DealID{2}
+		failed := map[abi.SectorNumber]bool{100: true, 101: true}
+		require.True(t, miner.AggregateProveCommitNetworkFee(len(params.Sectors)-len(failed), baseFee).
+			LessThan(miner.AggregateProveCommitNetworkFee(len(params.Sectors), baseFee)))
+
+		balanceBefore := rt.Balance()
+		actor.proveCommitSectorsNI(rt, params, proveCommitNIConf{
+			failedDealActivations: failed,
+			firstForMiner:         true,
+		}, baseFee)
+		expectedFee := miner.AggregateProveCommitNetworkFee(len(params.Sectors)-len(failed), baseFee)
+		assert.Equal(t, big.Sub(balanceBefore, expectedFee), rt.Balance())
+		actor.checkState(rt)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		params := actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors-1, epoch-1, expiration)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too few sectors", func() {
+			rt.Call(actor.a.ProveCommitSectorsNI, params)
+		})
+
+		params = actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch-1, expiration)
+		params.Sectors[1].SectorNumber = params.Sectors[0].SectorNumber
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "duplicate sector number", func() {
+			rt.Call(actor.a.ProveCommitSectorsNI, params)
+		})
+
+		params = actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch, expiration)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must be before now", func() {
+			rt.Call(actor.a.ProveCommitSectorsNI, params)
+		})
+
+		params = actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch-1, epoch+miner.MinSectorExpiration-1)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must exceed", func() {
+			rt.Call(actor.a.ProveCommitSectorsNI, params)
+		})
+
+		params = actor.makeProveCommitSectorsNI(100, miner.MinAggregatedSectors, epoch-1, expiration)
+		params.SealProof = abi.RegisteredSealProof_StackedDrg32GiBV1
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "unsupported seal proof type", func() {
+			rt.Call(actor.a.ProveCommitSectorsNI, params)
+		})
+		rt.Reset()
+	})
+}
+
+func TestExtendPreCommits(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	precommitEpoch := periodOffset + 1
+
+	setup := func(t *testing.T) (*mock.Runtime, *actorHarness, *miner.SectorPreCommitOnChainInfo) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		rt.SetEpoch(precommitEpoch)
+		actor.constructAndVerify(rt)
+		dlInfo := actor.deadline(rt)
+
+		// Leave room for the sector to meet its minimum lifetime if proven at the maximum extension.
+		expiration := dlInfo.PeriodEnd() + 2*defaultSectorExpiration*miner.WPoStProvingPeriod
+		params := actor.makePreCommit(100, precommitEpoch-1, expiration, nil)
+		precommit := actor.preCommitSector(rt, params, preCommitConf{}, true)
+		return rt, actor, precommit
+	}
+
+	t.Run("extended pre-commit can be proven after original deadline", func(t *testing.T) {
+		rt, actor, precommit := setup(t)
+		sectorNo := precommit.Info.SectorNumber
+		msd := miner.MaxProveCommitDuration[precommit.Info.SealProof]
+		extension := abi.ChainEpoch(builtin.EpochsInDay)
+
+		rt.SetEpoch(precommitEpoch + miner.PreCommitChallengeDelay + 1)
+		actor.extendPreCommits(rt, bf(uint64(sectorNo)), extension)
+
+		// The extension is charged at the sector's current deposit, pro-rated by its length.
+		pwrEstimate := miner.QAPowerForWeight(actor.sectorSize, precommit.Info.Expiration-rt.Epoch(), big.Zero(), big.Zero())
+		expectedDeposit := miner.PreCommitExtensionDeposit(actor.epochRewardSmooth, actor.epochQAPowerSmooth, pwrEstimate, extension, msd)
+		require.True(t, expectedDeposit.GreaterThan(big.Zero()))
+
+		extended := actor.getPreCommit(rt, sectorNo)
+		assert.Equal(t, extension, extended.ProveCommitExtension)
+		assert.Equal(t, big.Add(precommit.PreCommitDeposit, expectedDeposit), extended.PreCommitDeposit)
+		assert.Equal(t, extended.PreCommitDeposit, getState(rt).PreCommitDeposits)
+		actor.checkState(rt)
+
+		// A further extension accumulates.
+		actor.extendPreCommits(rt, bf(uint64(sectorNo)), extension)
+		extended = actor.getPreCommit(rt, sectorNo)
+		assert.Equal(t, 2*extension, extended.ProveCommitExtension)
+		actor.checkState(rt)
+
+		// Prove after the original deadline has passed.
+		rt.SetEpoch(precommitEpoch + msd + extension)
+		rt.SetBalance(big.Mul(big.NewInt(1000), big.NewInt(1e18)))
+		actor.proveCommitSectorAndConfirm(rt, extended, makeProveCommit(sectorNo), proveCommitConf{})
+		assert.Equal(t, big.Zero(), getState(rt).PreCommitDeposits)
+		actor.checkState(rt)
+	})
+
+	t.Run("extended pre-commit cannot be proven after extended deadline", func(t *testing.T) {
+		rt, actor, precommit := setup(t)
+		sectorNo := precommit.Info.SectorNumber
+		msd := miner.MaxProveCommitDuration[precommit.Info.SealProof]
+		extension := abi.ChainEpoch(builtin.EpochsInDay)
+		actor.extendPreCommits(rt, bf(uint64(sectorNo)), extension)
+
+		rt.SetEpoch(precommitEpoch + msd + extension + 1)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too late", func() {
+			actor.proveCommitSectorAndConfirm(rt, actor.getPreCommit(rt, sectorNo), makeProveCommit(sectorNo), proveCommitConf{})
+		})
+		rt.Reset()
+
+		// Nor extended again.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "expired", func() {
+			actor.extendPreCommits(rt, bf(uint64(sectorNo)), extension)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("invalid extensions rejected", func(t *testing.T) {
+		rt, actor, precommit := setup(t)
+		sectorNo := uint64(precommit.Info.SectorNumber)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must be positive", func() {
+			actor.extendPreCommits(rt, bf(sectorNo), 0)
+		})
+		rt.Reset()
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "exceeds maximum", func() {
+			actor.extendPreCommits(rt, bf(sectorNo), miner.MaxPreCommitExtension+1)
+		})
+		rt.Reset()
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no sectors", func() {
+			actor.extendPreCommits(rt, bf(), 1)
+		})
+		rt.Reset()
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "not found", func() {
+			actor.extendPreCommits(rt, bf(sectorNo, sectorNo+1), 1)
+		})
+		rt.Reset()
+
+		// The total extension is capped.
+		actor.extendPreCommits(rt, bf(sectorNo), miner.MaxPreCommitExtension-1)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "total extension", func() {
+			actor.extendPreCommits(rt, bf(sectorNo), 2)
+		})
+		rt.Reset()
+		actor.checkState(rt)
+	})
+
+	t.Run("extension must leave minimum sector lifetime", func(t *testing.T) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		rt.SetEpoch(precommitEpoch)
+		actor.constructAndVerify(rt)
+
+		// Commit with the shortest expiration permitted.
+		msd := miner.MaxProveCommitDuration[actor.sealProofType]
+		expiration := precommitEpoch + msd + miner.MinSectorExpiration
+		params := actor.makePreCommit(100, precommitEpoch-1, expiration, nil)
+		actor.preCommitSector(rt, params, preCommitConf{}, true)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "total sector lifetime", func() {
+			actor.extendPreCommits(rt, bf(100), 1)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("insufficient funds for extension deposit", func(t *testing.T) {
+		rt, actor, precommit := setup(t)
+
+		// Leave only the existing deposit in the balance.
+		rt.SetBalance(getState(rt).PreCommitDeposits)
+		rt.ExpectAbortContainsMessage(exitcode.ErrInsufficientFunds, "insufficient funds", func() {
+			actor.extendPreCommits(rt, bf(uint64(precommit.Info.SectorNumber)), builtin.EpochsInDay)
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestBatchMethodNetworkFees(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 
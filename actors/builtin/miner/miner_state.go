@@ -7,6 +7,7 @@ import (
 
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-bitfield"
+	rlepluslazy "github.com/filecoin-project/go-bitfield/rle"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/dline"
@@ -79,11 +80,16 @@ type State struct {
 
 	// True when miner cron is active, false otherwise
 	DeadlineCronActive bool
+
+	// Faulty sectors to be declared recovered by the deadline cron, keyed by the opening epoch of the
+	// challenge window ahead of which the recovery is declared.
+	ScheduledRecoveries cid.Cid // BitFieldQueue (AMT[Epoch]*BitField)
 }
 
 // Bitwidth of AMTs determined empirically from mutation patterns and projections of mainnet data.
 const PrecommitCleanUpAmtBitwidth = 6
 const SectorsAmtBitwidth = 5
+const ScheduledRecoveriesAmtBitwidth = 6
 
 type MinerInfo struct {
 	// Account that owns this miner.
@@ -126,6 +132,19 @@ type MinerInfo struct {
 	// A proposed new owner account for this miner.
 	// Must be confirmed by a message from the pending address itself.
 	PendingOwnerAddress *addr.Address
+
+	// Account receiving funds withdrawn from this miner.
+	// Defaults to the owner, in which case the beneficiary term does not apply.
+	Beneficiary addr.Address // Must be an ID-address.
+
+	// Limits on the funds that a beneficiary other than the owner may withdraw.
+	BeneficiaryTerm BeneficiaryTerm
+
+	// A proposed change of beneficiary, pending approval by the current beneficiary and the nominee.
+	PendingBeneficiaryTerm *PendingBeneficiaryChange
+
+	// How fee debt is repaid automatically at the end of each deadline.
+	DebtRepaymentPolicy DebtRepaymentPolicy
 }
 
 type WorkerKeyChange struct {
@@ -133,6 +152,66 @@ type WorkerKeyChange struct {
 	EffectiveAt abi.ChainEpoch
 }
 
+type BeneficiaryTerm struct {
+	// The total amount the current beneficiary may withdraw.
+	Quota abi.TokenAmount
+	// The amount the current beneficiary has already withdrawn.
+	UsedQuota abi.TokenAmount
+	// The epoch at which the beneficiary's right to withdraw lapses.
+	Expiration abi.ChainEpoch
+}
+
+// The amount the beneficiary may still withdraw at an epoch, zero once the term has expired.
+func (t *BeneficiaryTerm) Available(currEpoch abi.ChainEpoch) abi.TokenAmount {
+	if t.IsExpired(currEpoch) {
+		return big.Zero()
+	}
+	return big.Max(big.Sub(t.Quota, t.UsedQuota), big.Zero())
+}
+
+func (t *BeneficiaryTerm) IsUsedUp() bool {
+	return t.UsedQuota.GreaterThanEqual(t.Quota)
+}
+
+func (t *BeneficiaryTerm) IsExpired(currEpoch abi.ChainEpoch) bool {
+	return t.Expiration <= currEpoch
+}
+
+type PendingBeneficiaryChange struct {
+	NewBeneficiary        addr.Address // Must be an ID address
+	NewQuota              abi.TokenAmount
+	NewExpiration         abi.ChainEpoch
+	ApprovedByBeneficiary bool
+	ApprovedByNominee     bool
+}
+
+// The order in which a miner's funds are drawn on to repay fee debt.
+type DebtRepaymentPriority uint64
+
+const (
+	// Repay from unvested funds, then from unlocked balance.
+	DebtRepaymentVestingFirst DebtRepaymentPriority = iota
+	// Repay from unlocked balance, then from unvested funds.
+	DebtRepaymentBalanceFirst
+)
+
+type DebtRepaymentPolicy struct {
+	Priority DebtRepaymentPriority
+	// The most fee debt repaid automatically at the end of a single deadline.
+	// Zero places no limit on repayment.
+	MaxRepayment abi.TokenAmount
+}
+
+func (p *DebtRepaymentPolicy) Validate() error {
+	if p.Priority != DebtRepaymentVestingFirst && p.Priority != DebtRepaymentBalanceFirst {
+		return xerrors.Errorf("invalid debt repayment priority %d", p.Priority)
+	}
+	if p.MaxRepayment.Nil() || p.MaxRepayment.LessThan(big.Zero()) {
+		return xerrors.Errorf("invalid maximum debt repayment %v", p.MaxRepayment)
+	}
+	return nil
+}
+
 // Information provided by a miner when pre-committing a sector.
 type SectorPreCommitInfo struct {
 	SealProof       abi.RegisteredSealProof
@@ -155,6 +234,8 @@ type SectorPreCommitOnChainInfo struct {
 	PreCommitEpoch     abi.ChainEpoch
 	DealWeight         abi.DealWeight // Integral of active deals over sector lifetime
 	VerifiedDealWeight abi.DealWeight // Integral of active verified deals over sector lifetime
+	// Epochs added to the maximum prove-commit duration by ExtendPreCommits.
+	ProveCommitExtension abi.ChainEpoch
 }
 
 // Information stored on-chain for a proven sector.
@@ -188,6 +269,10 @@ func ConstructState(store adt.Store, infoCid cid.Cid, periodStart abi.ChainEpoch
 	if err != nil {
 		return nil, xerrors.Errorf("failed to construct empty sectors array: %w", err)
 	}
+	emptyScheduledRecoveriesArrayCid, err := adt.StoreEmptyArray(store, ScheduledRecoveriesAmtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to construct empty scheduled recoveries array: %w", err)
+	}
 
 	emptyBitfield := bitfield.NewFromSet(nil)
 	emptyBitfieldCid, err := store.Put(store.Context(), emptyBitfield)
@@ -232,6 +317,7 @@ func ConstructState(store adt.Store, infoCid cid.Cid, periodStart abi.ChainEpoch
 		Deadlines:                  emptyDeadlinesCid,
 		EarlyTerminations:          bitfield.New(),
 		DeadlineCronActive:         false,
+		ScheduledRecoveries:        emptyScheduledRecoveriesArrayCid,
 	}, nil
 }
 
@@ -259,6 +345,17 @@ func ConstructMinerInfo(owner, worker addr.Address, controlAddrs []addr.Address,
 		WindowPoStPartitionSectors: partitionSectors,
 		ConsensusFaultElapsed:      abi.ChainEpoch(-1),
 		PendingOwnerAddress:        nil,
+		Beneficiary:                owner,
+		BeneficiaryTerm: BeneficiaryTerm{
+			Quota:      big.Zero(),
+			UsedQuota:  big.Zero(),
+			Expiration: 0,
+		},
+		PendingBeneficiaryTerm: nil,
+		DebtRepaymentPolicy: DebtRepaymentPolicy{
+			Priority:     DebtRepaymentVestingFirst,
+			MaxRepayment: big.Zero(),
+		},
 	}, nil
 }
 
@@ -343,6 +440,59 @@ func (st *State) AllocateSectorNumbers(store adt.Store, sectorNos bitfield.BitFi
 	return nil
 }
 
+// Masks the sector numbers below the lowest sector number that still has an on-chain info, collapsing the allocated
+// sector numbers below it into a single run. None of those numbers can be in use by a sector.
+// Nothing is masked while the miner has pending pre-commitments, which may have been made with lower numbers
+// and could only be found by loading every pre-commitment.
+// Returns whether the allocated sector numbers were changed.
+func (st *State) CompactAllocatedSectors(store adt.Store) (bool, error) {
+	if !st.PreCommitDeposits.IsZero() {
+		return false, nil
+	}
+
+	sectors, err := LoadSectors(store, st.Sectors)
+	if err != nil {
+		return false, xerrors.Errorf("failed to load sectors: %w", err)
+	}
+	lowest := uint64(0)
+	found := false
+	stopErr := xerrors.New("stop error")
+	var sector SectorOnChainInfo
+	if err := sectors.ForEach(&sector, func(sectorNo int64) error {
+		lowest = uint64(sectorNo)
+		found = true
+		return stopErr
+	}); err != nil && err != stopErr {
+		return false, xerrors.Errorf("failed to find lowest sector number: %w", err)
+	}
+	if !found || lowest == 0 {
+		return false, nil
+	}
+
+	var allocated bitfield.BitField
+	if err := store.Get(store.Context(), st.AllocatedSectors, &allocated); err != nil {
+		return false, xc.ErrIllegalState.Wrapf("failed to load allocated sectors bitfield: %w", err)
+	}
+	mask, err := bitfield.NewFromIter(&rlepluslazy.RunSliceIterator{Runs: []rlepluslazy.Run{{Val: true, Len: lowest}}})
+	if err != nil {
+		return false, xerrors.Errorf("failed to construct sector number mask: %w", err)
+	}
+	unmasked, err := bitfield.SubtractBitField(mask, allocated)
+	if err != nil {
+		return false, xerrors.Errorf("failed to subtract allocated sector numbers from mask: %w", err)
+	}
+	if empty, err := unmasked.IsEmpty(); err != nil {
+		return false, xerrors.Errorf("failed to check unmasked sector numbers: %w", err)
+	} else if empty {
+		return false, nil
+	}
+
+	if err := st.AllocateSectorNumbers(store, mask, AllowCollisions); err != nil {
+		return false, xerrors.Errorf("failed to mask sector numbers: %w", err)
+	}
+	return true, nil
+}
+
 // Stores a pre-committed sector info, failing if the sector number is already present.
 func (st *State) PutPrecommittedSectors(store adt.Store, precommits ...*SectorPreCommitOnChainInfo) error {
 	precommitted, err := adt.AsMap(store, st.PreCommittedSectors, builtin.DefaultHamtBitwidth)
@@ -404,6 +554,28 @@ func (st *State) GetAllPrecommittedSectors(store adt.Store, sectorNos bitfield.B
 	return precommits, nil
 }
 
+// Overwrites existing pre-committed sectors.
+func (st *State) UpdatePrecommittedSectors(store adt.Store, precommits ...*SectorPreCommitOnChainInfo) error {
+	precommitted, err := adt.AsMap(store, st.PreCommittedSectors, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return err
+	}
+
+	for _, precommit := range precommits {
+		var existing SectorPreCommitOnChainInfo
+		if found, err := precommitted.Get(SectorKey(precommit.Info.SectorNumber), &existing); err != nil {
+			return xerrors.Errorf("failed to load pre-commitment for %v: %w", precommit.Info.SectorNumber, err)
+		} else if !found {
+			return xc.ErrNotFound.Wrapf("sector %v not pre-committed", precommit.Info.SectorNumber)
+		}
+		if err := precommitted.Put(SectorKey(precommit.Info.SectorNumber), precommit); err != nil {
+			return xerrors.Errorf("failed to store pre-commitment for %v: %w", precommit, err)
+		}
+	}
+	st.PreCommittedSectors, err = precommitted.Root()
+	return err
+}
+
 // This method gets and returns the requested pre-committed sectors, skipping
 // missing sectors.
 func (st *State) FindPrecommittedSectors(store adt.Store, sectorNos ...abi.SectorNumber) ([]*SectorPreCommitOnChainInfo, error) {
@@ -505,6 +677,50 @@ func (st *State) DeleteSectors(store adt.Store, sectorNos bitfield.BitField) err
 	return err
 }
 
+// Removes fully-terminated partitions from the end of a deadline and deletes the infos of their sectors.
+// The number of partitions inspected and sectors deleted is bounded by partitionLimit and sectorLimit.
+// Nothing is removed while the deadline has pending early terminations, which still require the sector infos.
+// The indices of the partitions that remain are unchanged, but the caller must ensure the deadline is available
+// for compaction.
+// Returns the number of partitions removed.
+func (st *State) CompactTerminatedPartitions(store adt.Store, dlIdx uint64, partitionLimit, sectorLimit uint64) (uint64, error) {
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return 0, err
+	}
+	deadline, err := deadlines.LoadDeadline(store, dlIdx)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to load deadline %d: %w", dlIdx, err)
+	}
+	if deadline.LiveSectors == deadline.TotalSectors {
+		// No terminated sectors to remove.
+		return 0, nil
+	}
+	if noEarlyTerminations, err := deadline.EarlyTerminations.IsEmpty(); err != nil {
+		return 0, xerrors.Errorf("failed to check for early terminations: %w", err)
+	} else if !noEarlyTerminations {
+		return 0, nil
+	}
+
+	dead, removed, err := deadline.PopTerminatedPartitions(store, partitionLimit, sectorLimit, st.QuantSpecForDeadline(dlIdx))
+	if err != nil {
+		return 0, xerrors.Errorf("failed to remove partitions from deadline %d: %w", dlIdx, err)
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	if err := st.DeleteSectors(store, dead); err != nil {
+		return 0, xerrors.Errorf("failed to delete terminated sectors: %w", err)
+	}
+	if err := deadlines.UpdateDeadline(store, dlIdx, deadline); err != nil {
+		return 0, xerrors.Errorf("failed to update deadline %d: %w", dlIdx, err)
+	}
+	if err := st.SaveDeadlines(store, deadlines); err != nil {
+		return 0, xerrors.Errorf("failed to save deadlines: %w", err)
+	}
+	return removed, nil
+}
+
 // Iterates sectors.
 // The pointer provided to the callback is not safe for re-use. Copy the pointed-to value in full to hold a reference.
 func (st *State) ForEachSector(store adt.Store, f func(*SectorOnChainInfo)) error {
@@ -528,12 +744,13 @@ func (st *State) FindSector(store adt.Store, sno abi.SectorNumber) (uint64, uint
 }
 
 // Assign new sectors to deadlines.
+// Returns the sectors assigned to each deadline and partition.
 func (st *State) AssignSectorsToDeadlines(
 	store adt.Store, currentEpoch abi.ChainEpoch, sectors []*SectorOnChainInfo, partitionSize uint64, sectorSize abi.SectorSize,
-) error {
+) (DeadlineSectorMap, error) {
 	deadlines, err := st.LoadDeadlines(store)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Sort sectors by number to get better runs in partition bitfields.
@@ -549,12 +766,13 @@ func (st *State) AssignSectorsToDeadlines(
 		}
 		return nil
 	}); err != nil {
-		return err
+		return nil, err
 	}
 
+	assigned := make(DeadlineSectorMap)
 	deadlineToSectors, err := assignDeadlines(MaxPartitionsPerDeadline, partitionSize, &deadlineArr, sectors)
 	if err != nil {
-		return xerrors.Errorf("failed to assign sectors to deadlines: %w", err)
+		return nil, xerrors.Errorf("failed to assign sectors to deadlines: %w", err)
 	}
 
 	for dlIdx, deadlineSectors := range deadlineToSectors {
@@ -567,19 +785,25 @@ func (st *State) AssignSectorsToDeadlines(
 
 		// The power returned from AddSectors is ignored because it's not activated (proven) yet.
 		proven := false
-		if _, err := dl.AddSectors(store, partitionSize, proven, deadlineSectors, sectorSize, quant); err != nil {
-			return err
+		_, changes, err := dl.AddSectors(store, partitionSize, proven, deadlineSectors, sectorSize, quant)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			if err := assigned.Add(uint64(dlIdx), change.Partition, change.Sectors); err != nil {
+				return nil, err
+			}
 		}
 
 		if err := deadlines.UpdateDeadline(store, uint64(dlIdx), dl); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if err := st.SaveDeadlines(store, deadlines); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return assigned, nil
 }
 
 // Pops up to max early terminated sectors from all deadlines.
@@ -821,28 +1045,52 @@ func (st *State) ApplyPenalty(penalty abi.TokenAmount) error {
 // current balance. If the fee debt exceeds the total amount available for repayment
 // the fee debt field is updated to track the remaining debt.  Otherwise it is set to zero.
 func (st *State) RepayPartialDebtInPriorityOrder(store adt.Store, currEpoch abi.ChainEpoch, currBalance abi.TokenAmount) (fromVesting abi.TokenAmount, fromBalance abi.TokenAmount, err error) {
+	return st.repayPartialDebt(store, currEpoch, currBalance, DebtRepaymentVestingFirst, st.FeeDebt)
+}
+
+// Repays up to the fee debt as directed by a repayment policy, drawing from the policy's
+// preferred source of funds first and repaying no more than the policy's maximum, if any.
+// Returns the amount unlocked from the vesting table and the amount taken from current balance.
+func (st *State) RepayPartialDebtByPolicy(store adt.Store, currEpoch abi.ChainEpoch, currBalance abi.TokenAmount, policy *DebtRepaymentPolicy) (fromVesting abi.TokenAmount, fromBalance abi.TokenAmount, err error) {
+	target := st.FeeDebt
+	if policy.MaxRepayment.GreaterThan(big.Zero()) {
+		target = big.Min(target, policy.MaxRepayment)
+	}
+	return st.repayPartialDebt(store, currEpoch, currBalance, policy.Priority, target)
+}
+
+func (st *State) repayPartialDebt(store adt.Store, currEpoch abi.ChainEpoch, currBalance abi.TokenAmount, priority DebtRepaymentPriority, target abi.TokenAmount) (fromVesting abi.TokenAmount, fromBalance abi.TokenAmount, err error) {
+	if target.GreaterThan(st.FeeDebt) {
+		return big.Zero(), big.Zero(), xerrors.Errorf("repayment %v exceeds fee debt %v", target, st.FeeDebt)
+	}
 	unlockedBalance, err := st.GetUnlockedBalance(currBalance)
 	if err != nil {
 		return big.Zero(), big.Zero(), err
 	}
 
-	// Pay fee debt with locked funds first
-	fromVesting, err = st.UnlockUnvestedFunds(store, currEpoch, st.FeeDebt)
+	fromBalance = big.Zero()
+	if priority == DebtRepaymentBalanceFirst {
+		fromBalance = big.Min(unlockedBalance, target)
+	}
+
+	// Pay the rest of the debt with locked funds
+	remaining := big.Sub(target, fromBalance)
+	fromVesting, err = st.UnlockUnvestedFunds(store, currEpoch, remaining)
 	if err != nil {
 		return abi.NewTokenAmount(0), abi.NewTokenAmount(0), err
 	}
 
 	// We should never unlock more than the debt we need to repay
-	if fromVesting.GreaterThan(st.FeeDebt) {
-		return big.Zero(), big.Zero(), xerrors.Errorf("unlocked more vesting funds %v than required for debt %v", fromVesting, st.FeeDebt)
+	if fromVesting.GreaterThan(remaining) {
+		return big.Zero(), big.Zero(), xerrors.Errorf("unlocked more vesting funds %v than required for debt %v", fromVesting, remaining)
 	}
-	st.FeeDebt = big.Sub(st.FeeDebt, fromVesting)
 
-	fromBalance = big.Min(unlockedBalance, st.FeeDebt)
-	st.FeeDebt = big.Sub(st.FeeDebt, fromBalance)
+	if priority == DebtRepaymentVestingFirst {
+		fromBalance = big.Min(unlockedBalance, big.Sub(remaining, fromVesting))
+	}
+	st.FeeDebt = big.Subtract(st.FeeDebt, fromVesting, fromBalance)
 
 	return fromVesting, fromBalance, nil
-
 }
 
 // Repays the full miner actor fee debt.  Returns the amount that must be
@@ -1022,6 +1270,128 @@ func (st *State) AddPreCommitCleanUps(store adt.Store, cleanUpEvents map[abi.Cha
 	return nil
 }
 
+// Removes pre-committed sectors from their scheduled clean up epochs.
+func (st *State) RemovePreCommitCleanUps(store adt.Store, cleanUpEvents map[abi.ChainEpoch][]uint64) error {
+	queue, err := LoadBitfieldQueue(store, st.PreCommittedSectorsCleanUp, st.QuantSpecEveryDeadline(), PrecommitCleanUpAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to load pre-commit clean up queue: %w", err)
+	}
+
+	epochs := make([]abi.ChainEpoch, 0, len(cleanUpEvents))
+	for epoch := range cleanUpEvents { // nolint:nomaprange // subsequently sorted
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+
+	for _, epoch := range epochs {
+		if err := queue.RemoveFromQueue(epoch, bitfield.NewFromSet(cleanUpEvents[epoch])); err != nil {
+			return xerrors.Errorf("failed to remove pre-commit sector clean up from queue: %w", err)
+		}
+	}
+
+	st.PreCommittedSectorsCleanUp, err = queue.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to save pre-commit sector queue: %w", err)
+	}
+	return nil
+}
+
+// Schedules sectors to be declared recovered ahead of the challenge window opening at an epoch.
+func (st *State) AddScheduledRecoveries(store adt.Store, openEpoch abi.ChainEpoch, sectorNos bitfield.BitField) error {
+	queue, err := LoadBitfieldQueue(store, st.ScheduledRecoveries, st.QuantSpecEveryDeadline(), ScheduledRecoveriesAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to load scheduled recoveries queue: %w", err)
+	}
+	if err = queue.AddToQueue(openEpoch, sectorNos); err != nil {
+		return xerrors.Errorf("failed to add scheduled recoveries: %w", err)
+	}
+	st.ScheduledRecoveries, err = queue.Root()
+	return err
+}
+
+// Removes and returns the sectors scheduled to be declared recovered ahead of challenge windows opening
+// at or before an epoch.
+func (st *State) PopScheduledRecoveries(store adt.Store, until abi.ChainEpoch) (bitfield.BitField, error) {
+	queue, err := LoadBitfieldQueue(store, st.ScheduledRecoveries, st.QuantSpecEveryDeadline(), ScheduledRecoveriesAmtBitwidth)
+	if err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to load scheduled recoveries queue: %w", err)
+	}
+	sectorNos, modified, err := queue.PopUntil(until)
+	if err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to pop scheduled recoveries: %w", err)
+	}
+	if modified {
+		if st.ScheduledRecoveries, err = queue.Root(); err != nil {
+			return bitfield.BitField{}, xerrors.Errorf("failed to save scheduled recoveries queue: %w", err)
+		}
+	}
+	return sectorNos, nil
+}
+
+// Declares recovered those of the given sectors that are faulty at a deadline.
+// Sectors that are not faulty or already recovering are ignored.
+// Returns the sectors that are no longer assigned to the deadline, having been moved to another deadline
+// or terminated and compacted away since their recovery was scheduled. These are also ignored.
+func (st *State) DeclareScheduledRecoveries(store adt.Store, dlIdx uint64, sectorNos bitfield.BitField, ssize abi.SectorSize) (bitfield.BitField, error) {
+	if empty, err := sectorNos.IsEmpty(); err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to check scheduled recoveries: %w", err)
+	} else if empty {
+		return bitfield.New(), nil
+	}
+
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return bitfield.BitField{}, err
+	}
+	deadline, err := deadlines.LoadDeadline(store, dlIdx)
+	if err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to load deadline %d: %w", dlIdx, err)
+	}
+	located := make(DeadlineSectorMap)
+	missing, err := findSectorsInDeadline(store, deadline, dlIdx, sectorNos, located)
+	if err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to find scheduled recoveries in deadline %d: %w", dlIdx, err)
+	}
+
+	recoveries := make(PartitionSectorMap)
+	err = located[dlIdx].ForEach(func(partIdx uint64, partSectors bitfield.BitField) error {
+		partition, err := deadline.LoadPartition(store, partIdx)
+		if err != nil {
+			return err
+		}
+		faulty, err := bitfield.IntersectBitField(partSectors, partition.Faults)
+		if err != nil {
+			return xerrors.Errorf("failed to intersect scheduled recoveries with faults of partition %d: %w", partIdx, err)
+		}
+		if empty, err := faulty.IsEmpty(); err != nil {
+			return err
+		} else if empty {
+			return nil
+		}
+		return recoveries.Add(partIdx, faulty)
+	})
+	if err != nil {
+		return bitfield.BitField{}, err
+	}
+	if len(recoveries) == 0 {
+		return missing, nil
+	}
+
+	sectors, err := LoadSectors(store, st.Sectors)
+	if err != nil {
+		return bitfield.BitField{}, err
+	}
+	if err = deadline.DeclareFaultsRecovered(store, sectors, ssize, recoveries); err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to declare recoveries for deadline %d: %w", dlIdx, err)
+	}
+	if err = deadlines.UpdateDeadline(store, dlIdx, deadline); err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to update deadline %d: %w", dlIdx, err)
+	}
+	return missing, st.SaveDeadlines(store, deadlines)
+}
+
 func (st *State) CleanUpExpiredPreCommits(store adt.Store, currEpoch abi.ChainEpoch) (depositToBurn abi.TokenAmount, err error) {
 	depositToBurn = abi.NewTokenAmount(0)
 
@@ -1089,6 +1459,9 @@ type AdvanceDeadlineResult struct {
 	TotalFaultyPower      PowerPair // Total faulty power after detecting faults (before expiring sectors)
 	// Note that failed recovery power is included in both PreviouslyFaultyPower and DetectedFaultyPower,
 	// so TotalFaultyPower is not simply their sum.
+	DetectedFaults []PartitionSectorChange // Sectors newly detected faulty in each partition
+	OnTimeExpired  []PartitionSectorChange // Sectors expired at the end of their committed life in each partition
+	EarlyExpired   []PartitionSectorChange // Sectors terminated for being faulty for too long in each partition
 }
 
 // AdvanceDeadline advances the deadline. It:
@@ -1101,6 +1474,7 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 
 	var totalFaultyPower PowerPair
 	detectedFaultyPower := NewPowerPairZero()
+	var detectedFaults, onTimeExpired, earlyExpired []PartitionSectorChange
 
 	// Note: Use dlInfo.Last() rather than rt.CurrEpoch unless certain
 	// of the desired semantics. In the past, this method would sometimes be
@@ -1124,6 +1498,9 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 			NewPowerPairZero(),
 			NewPowerPairZero(),
 			NewPowerPairZero(),
+			nil,
+			nil,
+			nil,
 		}, nil
 	}
 
@@ -1155,6 +1532,9 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 			previouslyFaultyPower,
 			detectedFaultyPower,
 			deadline.FaultyPower,
+			nil,
+			nil,
+			nil,
 		}, nil
 	}
 
@@ -1164,7 +1544,7 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 		faultExpiration := dlInfo.Last() + FaultMaxAge
 
 		// detectedFaultyPower is new faults and failed recoveries
-		powerDelta, detectedFaultyPower, err = deadline.ProcessDeadlineEnd(store, quant, faultExpiration, st.Sectors)
+		powerDelta, detectedFaultyPower, detectedFaults, err = deadline.ProcessDeadlineEnd(store, quant, faultExpiration, st.Sectors)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to process end of deadline %d: %w", dlInfo.Index, err)
 		}
@@ -1174,7 +1554,8 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 	}
 	{
 		// Expire sectors that are due, either for on-time expiration or "early" faulty-for-too-long.
-		expired, err := deadline.PopExpiredSectors(store, dlInfo.Last(), quant)
+		var expired *ExpirationSet
+		expired, onTimeExpired, earlyExpired, err = deadline.PopExpiredSectors(store, dlInfo.Last(), quant)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to load expired sectors: %w", err)
 		}
@@ -1222,6 +1603,9 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 		PreviouslyFaultyPower: previouslyFaultyPower,
 		DetectedFaultyPower:   detectedFaultyPower,
 		TotalFaultyPower:      totalFaultyPower,
+		DetectedFaults:        detectedFaults,
+		OnTimeExpired:         onTimeExpired,
+		EarlyExpired:          earlyExpired,
 	}, nil
 }
 
@@ -687,7 +687,7 @@ func TestSectorAssignment(t *testing.T) {
 	t.Run("assign sectors to deadlines", func(t *testing.T) {
 		harness := constructStateHarness(t, abi.ChainEpoch(0))
 
-		err := harness.s.AssignSectorsToDeadlines(harness.store, 0, sectorInfos,
+		_, err := harness.s.AssignSectorsToDeadlines(harness.store, 0, sectorInfos,
 			partitionSectors, sectorSize)
 		require.NoError(t, err)
 
@@ -835,6 +835,46 @@ func TestSectorNumberAllocation(t *testing.T) {
 		}
 		assert.True(t, limitReached)
 	})
+
+	t.Run("compaction masks numbers below the lowest sector", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		assert.NoError(t, allocate(harness, 1, 2, 5, 6, 9))
+		harness.putSector(testSector(100, 6, 1, 1, 1))
+		harness.putSector(testSector(100, 9, 1, 1, 1))
+
+		compacted, err := harness.s.CompactAllocatedSectors(harness.store)
+		require.NoError(t, err)
+		assert.True(t, compacted)
+		expect(harness, bf(0, 1, 2, 3, 4, 5, 6, 9))
+
+		// Nothing further to mask.
+		compacted, err = harness.s.CompactAllocatedSectors(harness.store)
+		require.NoError(t, err)
+		assert.False(t, compacted)
+		assert.NoError(t, allocate(harness, 7))
+	})
+
+	t.Run("compaction leaves numbers unmasked with pending pre-commitments", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		assert.NoError(t, allocate(harness, 1, 2, 5, 6))
+		harness.putSector(testSector(100, 6, 1, 1, 1))
+		harness.s.PreCommitDeposits = abi.NewTokenAmount(1)
+
+		compacted, err := harness.s.CompactAllocatedSectors(harness.store)
+		require.NoError(t, err)
+		assert.False(t, compacted)
+		expect(harness, bf(1, 2, 5, 6))
+	})
+
+	t.Run("compaction leaves numbers unmasked without sectors", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		assert.NoError(t, allocate(harness, 1, 2, 5, 6))
+
+		compacted, err := harness.s.CompactAllocatedSectors(harness.store)
+		require.NoError(t, err)
+		assert.False(t, compacted)
+		expect(harness, bf(1, 2, 5, 6))
+	})
 }
 
 func TestRepayDebtInPriorityOrder(t *testing.T) {
@@ -867,6 +907,55 @@ func TestRepayDebtInPriorityOrder(t *testing.T) {
 	assert.Equal(t, expectedDebt, harness.s.FeeDebt)
 }
 
+func TestRepayDebtByPolicy(t *testing.T) {
+	vspec := &miner.VestSpec{
+		InitialDelay: 0,
+		VestPeriod:   5,
+		StepDuration: 1,
+		Quantization: 1,
+	}
+	vestStart := abi.ChainEpoch(100)
+	fee := abi.NewTokenAmount(1000)
+
+	// Each case has 100 locked in vesting and 300 unlocked.
+	for _, tc := range []struct {
+		name                string
+		policy              miner.DebtRepaymentPolicy
+		expectedFromVesting abi.TokenAmount
+		expectedFromBalance abi.TokenAmount
+	}{
+		{"vesting first", miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentVestingFirst, MaxRepayment: big.Zero()},
+			abi.NewTokenAmount(100), abi.NewTokenAmount(300)},
+		{"vesting first with cap", miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentVestingFirst, MaxRepayment: abi.NewTokenAmount(150)},
+			abi.NewTokenAmount(100), abi.NewTokenAmount(50)},
+		{"balance first", miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst, MaxRepayment: big.Zero()},
+			abi.NewTokenAmount(100), abi.NewTokenAmount(300)},
+		{"balance first with cap", miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst, MaxRepayment: abi.NewTokenAmount(250)},
+			abi.NewTokenAmount(0), abi.NewTokenAmount(250)},
+		{"cap above debt", miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst, MaxRepayment: abi.NewTokenAmount(5000)},
+			abi.NewTokenAmount(100), abi.NewTokenAmount(300)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			harness := constructStateHarness(t, abi.ChainEpoch(0))
+			harness.addLockedFunds(vestStart, abi.NewTokenAmount(100), vspec)
+			require.NoError(t, harness.s.ApplyPenalty(fee))
+
+			fromVesting, fromBalance, err := harness.s.RepayPartialDebtByPolicy(harness.store, vestStart, abi.NewTokenAmount(400), &tc.policy)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFromVesting, fromVesting)
+			assert.Equal(t, tc.expectedFromBalance, fromBalance)
+			assert.Equal(t, big.Subtract(fee, fromVesting, fromBalance), harness.s.FeeDebt)
+			assert.Equal(t, big.Sub(abi.NewTokenAmount(100), fromVesting), harness.s.LockedFunds)
+		})
+	}
+
+	t.Run("invalid policies", func(t *testing.T) {
+		assert.Error(t, (&miner.DebtRepaymentPolicy{Priority: 2, MaxRepayment: big.Zero()}).Validate())
+		assert.Error(t, (&miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst, MaxRepayment: big.NewInt(-1)}).Validate())
+		assert.Error(t, (&miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst}).Validate())
+	})
+}
+
 type stateHarness struct {
 	t testing.TB
 
@@ -985,6 +1074,14 @@ func constructStateHarness(t *testing.T, periodBoundary abi.ChainEpoch) *stateHa
 		WindowPoStProofType:        testWindowPoStProofType,
 		SectorSize:                 sectorSize,
 		WindowPoStPartitionSectors: partitionSectors,
+		Beneficiary:                owner,
+		BeneficiaryTerm: miner.BeneficiaryTerm{
+			Quota:     big.Zero(),
+			UsedQuota: big.Zero(),
+		},
+		DebtRepaymentPolicy: miner.DebtRepaymentPolicy{
+			MaxRepayment: big.Zero(),
+		},
 	}
 	infoCid, err := store.Put(context.Background(), &info)
 	require.NoError(t, err)
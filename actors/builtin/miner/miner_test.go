@@ -457,6 +457,170 @@ func TestWindowPost(t *testing.T) {
 		actor.disputeWindowPoSt(rt, dlinfo, 0, []*miner.SectorOnChainInfo{sector}, result)
 	})
 
+	t.Run("aggregate PoSt and dispute", func(t *testing.T) {
+		actor := newHarness(t, periodOffset)
+		actor.setProofType(abi.RegisteredSealProof_StackedDrg2KiBV1_1)
+		builder := builderForHarness(actor).
+			WithEpoch(precommitEpoch).
+			WithBalance(bigBalance, big.Zero())
+
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		store := rt.AdtStore()
+
+		sectors := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		pwr := miner.PowerForSectors(actor.sectorSize, sectors)
+
+		// Both sectors fill a single partition.
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(store, sectors[0].SectorNumber)
+		require.NoError(t, err)
+		dlIdx1, pIdx1, err := st.FindSector(store, sectors[1].SectorNumber)
+		require.NoError(t, err)
+		require.Equal(t, dlIdx, dlIdx1)
+		require.Equal(t, pIdx, pIdx1)
+		dlinfo := advanceToDeadline(rt, actor, dlIdx)
+
+		// Submit a single aggregate proof for the partition.
+		partitions := []miner.PoStPartition{
+			{Index: pIdx, Skipped: bitfield.New()},
+		}
+		actor.submitWindowPoSt(rt, dlinfo, partitions, sectors, &poStConfig{
+			expectedPowerDelta: pwr,
+			aggregate:          true,
+		})
+
+		// Verify proof recorded as an aggregate.
+		deadline := actor.getDeadline(rt, dlIdx)
+		assertBitfieldEquals(t, deadline.PartitionsPoSted, pIdx)
+		posts, err := adt.AsArray(store, deadline.OptimisticPoStSubmissions, miner.DeadlineOptimisticPoStSubmissionsAmtBitwidth)
+		require.NoError(t, err)
+		require.EqualValues(t, posts.Length(), 1)
+		var post miner.WindowedPoSt
+		found, err := posts.Get(0, &post)
+		require.NoError(t, err)
+		require.True(t, found)
+		assertBitfieldEquals(t, post.Partitions, pIdx)
+		assert.True(t, post.Aggregated)
+		require.Len(t, post.Proofs, 1)
+
+		advanceDeadline(rt, actor, &cronConfig{})
+		actor.checkState(rt)
+
+		// Try a failed dispute, which re-verifies the aggregate.
+		var result *poStDisputeResult
+		actor.disputeWindowPoSt(rt, dlinfo, 0, sectors, result)
+
+		// Now a successful dispute, which faults every sector covered by the aggregate.
+		expectedFee := miner.PledgePenaltyForInvalidWindowPoSt(actor.epochRewardSmooth, actor.epochQAPowerSmooth, pwr.QA)
+		result = &poStDisputeResult{
+			expectedPowerDelta:  pwr.Neg(),
+			expectedPenalty:     expectedFee,
+			expectedReward:      miner.BaseRewardForDisputedWindowPoSt,
+			expectedPledgeDelta: big.Zero(),
+		}
+		actor.disputeWindowPoSt(rt, dlinfo, 0, sectors, result)
+	})
+
+	t.Run("invalid aggregate submissions", func(t *testing.T) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithEpoch(precommitEpoch).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		actor.constructAndVerify(rt)
+		store := rt.AdtStore()
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+
+		dlIdx, pIdx, err := getState(rt).FindSector(store, sector.SectorNumber)
+		require.NoError(t, err)
+		dlInfo := advanceToDeadline(rt, actor, dlIdx)
+
+		// Aggregate proof too large.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "expected proof to be smaller", func() {
+			proofs := makePoStProofs(actor.windowPostProofType)
+			proofs[0].ProofBytes = make([]byte, miner.MaxAggregatePoStProofSize(1)+1)
+			params := miner.SubmitWindowedPoStParams{
+				Deadline:         dlInfo.Index,
+				Partitions:       []miner.PoStPartition{{Index: pIdx, Skipped: bf()}},
+				Proofs:           proofs,
+				ChainCommitEpoch: dlInfo.Challenge,
+				ChainCommitRand:  abi.Randomness("chaincommitment"),
+			}
+			actor.submitWindowPoStRaw(rt, dlInfo, []*miner.SectorOnChainInfo{sector}, &params, &poStConfig{aggregate: true})
+		})
+		rt.Reset()
+
+		// Unexpected proof type.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "expected proof of type", func() {
+			params := miner.SubmitWindowedPoStParams{
+				Deadline:         dlInfo.Index,
+				Partitions:       []miner.PoStPartition{{Index: pIdx, Skipped: bf()}},
+				Proofs:           makePoStProofs(abi.RegisteredPoStProof_StackedDrgWindow64GiBV1),
+				ChainCommitEpoch: dlInfo.Challenge,
+				ChainCommitRand:  abi.Randomness("chaincommitment"),
+			}
+			actor.submitWindowPoStRaw(rt, dlInfo, []*miner.SectorOnChainInfo{sector}, &params, &poStConfig{aggregate: true})
+		})
+		rt.Reset()
+
+	})
+
+	t.Run("aggregate PoSt recovering power is verified immediately", func(t *testing.T) {
+		actor := newHarness(t, periodOffset)
+		actor.setProofType(abi.RegisteredSealProof_StackedDrg2KiBV1_1)
+		rt := builderForHarness(actor).
+			WithEpoch(precommitEpoch).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		actor.constructAndVerify(rt)
+		infos := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		pwr := miner.PowerForSectors(actor.sectorSize, infos)
+
+		advanceAndSubmitPoSts(rt, actor, infos[0])
+		advanceDeadline(rt, actor, &cronConfig{})
+		actor.declareFaults(rt, infos...)
+		advanceDeadline(rt, actor, &cronConfig{})
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), infos[0].SectorNumber)
+		require.NoError(t, err)
+		actor.declareRecoveries(rt, dlIdx, pIdx, bf(uint64(infos[0].SectorNumber)), big.Zero())
+
+		dlinfo := actor.deadline(rt)
+		for dlinfo.Index != dlIdx {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+		partitions := []miner.PoStPartition{
+			{Index: pIdx, Skipped: bitfield.New()},
+		}
+
+		// An invalid aggregate is rejected.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid aggregate PoSt", func() {
+			actor.submitWindowPoSt(rt, dlinfo, partitions, infos, &poStConfig{
+				aggregate:         true,
+				verificationError: fmt.Errorf("invalid aggregate"),
+			})
+		})
+		rt.Reset()
+
+		// A valid aggregate recovers power and is not recorded for optimistic verification.
+		ret := actor.submitWindowPoSt(rt, dlinfo, partitions, infos, &poStConfig{
+			expectedPowerDelta: pwr,
+			aggregate:          true,
+		})
+		assertBitfieldEquals(t, ret.RecoveredSectors, uint64(infos[0].SectorNumber))
+		assert.True(t, ret.RecoveredPower.Equals(pwr))
+
+		deadline := actor.getDeadline(rt, dlIdx)
+		assertBitfieldEquals(t, deadline.PartitionsPoSted, pIdx)
+		posts, err := adt.AsArray(rt.AdtStore(), deadline.OptimisticPoStSubmissions,
+			miner.DeadlineOptimisticPoStSubmissionsAmtBitwidth)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), posts.Length())
+		actor.checkState(rt)
+	})
+
 	t.Run("invalid submissions", func(t *testing.T) {
 		actor := newHarness(t, periodOffset)
 		rt := builderForHarness(actor).
@@ -873,7 +1037,10 @@ func TestWindowPost(t *testing.T) {
 		partitions := []miner.PoStPartition{
 			{Index: pIdx, Skipped: bitfield.New()},
 		}
-		actor.submitWindowPoSt(rt, dlinfo, partitions, infos, cfg)
+		ret := actor.submitWindowPoSt(rt, dlinfo, partitions, infos, cfg)
+		assertBitfieldEquals(t, ret.RecoveredSectors, uint64(infos[0].SectorNumber))
+		assert.True(t, ret.RecoveredPower.Equals(pwr))
+		assertEmptyBitfield(t, ret.NewFaultySectors)
 
 		// faulty power has been removed, partition no longer has faults or recoveries
 		deadline, partition := actor.findSector(rt, infos[0].SectorNumber)
@@ -928,7 +1095,10 @@ func TestWindowPost(t *testing.T) {
 		partitions := []miner.PoStPartition{
 			{Index: pIdx, Skipped: bf(uint64(infos[0].SectorNumber))},
 		}
-		actor.submitWindowPoSt(rt, dlinfo, partitions, infos, cfg)
+		ret := actor.submitWindowPoSt(rt, dlinfo, partitions, infos, cfg)
+		assertBitfieldEquals(t, ret.NewFaultySectors, uint64(infos[0].SectorNumber))
+		assert.True(t, ret.NewFaultyPower.Equals(miner.PowerForSectors(actor.sectorSize, infos[:1])))
+		assertEmptyBitfield(t, ret.RecoveredSectors)
 
 		// expect continued fault fee to be charged during cron
 		faultFee := actor.continuedFaultPenalty(infos[:1])
@@ -1041,7 +1211,11 @@ func TestWindowPost(t *testing.T) {
 		partitions := []miner.PoStPartition{
 			{Index: pIdx, Skipped: bf(uint64(infos[0].SectorNumber))},
 		}
-		actor.submitWindowPoSt(rt, dlinfo, partitions, infos, cfg)
+		ret := actor.submitWindowPoSt(rt, dlinfo, partitions, infos, cfg)
+		// The sector was already faulty, so is neither newly faulty nor recovered.
+		assertEmptyBitfield(t, ret.NewFaultySectors)
+		assertEmptyBitfield(t, ret.RecoveredSectors)
+		assert.True(t, ret.RetractedRecoveryPower.Equals(miner.PowerForSectors(actor.sectorSize, infos[:1])))
 
 		// sector will be charged ongoing fee at proving period cron
 		ongoingFee := actor.continuedFaultPenalty(infos[:1])
@@ -1689,6 +1863,148 @@ func TestDeclareFaults(t *testing.T) {
 	})
 }
 
+func TestDeclareFaultsWithRecoveryEpoch(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	assertRecovering := func(rt *mock.Runtime, dlIdx, pIdx uint64, expected bitfield.BitField) {
+		dl := actor.getDeadline(rt, dlIdx)
+		p, err := dl.LoadPartition(rt.AdtStore(), pIdx)
+		require.NoError(t, err)
+		assertBitfieldsEqual(t, expected, p.Recoveries)
+	}
+
+	t.Run("deadline cron declares scheduled recovery before challenge window", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		oneSector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		advanceAndSubmitPoSts(rt, actor, oneSector...)
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), oneSector[0].SectorNumber)
+		require.NoError(t, err)
+
+		actor.declareFaultsWithRecoveryEpoch(rt, rt.Epoch(), oneSector...)
+		assertRecovering(rt, dlIdx, pIdx, bf())
+
+		// The recovery is declared before the sector's deadline opens.
+		dlinfo := actor.deadline(rt)
+		for dlinfo.Index != dlIdx {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+		assertRecovering(rt, dlIdx, pIdx, bf(uint64(oneSector[0].SectorNumber)))
+
+		st = getState(rt)
+		scheduled, err := adt.AsArray(rt.AdtStore(), st.ScheduledRecoveries, miner.ScheduledRecoveriesAmtBitwidth)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), scheduled.Length())
+
+		// The recovered sector regains power when proven.
+		partitions := []miner.PoStPartition{{Index: pIdx, Skipped: bitfield.New()}}
+		pwr := miner.PowerForSectors(actor.sectorSize, oneSector)
+		actor.submitWindowPoSt(rt, dlinfo, partitions, oneSector, &poStConfig{
+			expectedPowerDelta: pwr,
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("recovery is scheduled for a later proving period", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		oneSector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		advanceAndSubmitPoSts(rt, actor, oneSector...)
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), oneSector[0].SectorNumber)
+		require.NoError(t, err)
+
+		actor.declareFaultsWithRecoveryEpoch(rt, rt.Epoch()+miner.WPoStProvingPeriod, oneSector...)
+
+		// The sector remains faulty through its next deadline.
+		dlinfo := actor.deadline(rt)
+		for dlinfo.Index != dlIdx {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+		assertRecovering(rt, dlIdx, pIdx, bf())
+		ongoingPwr := miner.PowerForSectors(actor.sectorSize, oneSector)
+		ongoingPenalty := miner.PledgePenaltyForContinuedFault(actor.epochRewardSmooth, actor.epochQAPowerSmooth, ongoingPwr.QA)
+		dlinfo = advanceDeadline(rt, actor, &cronConfig{
+			continuedFaultsPenalty: ongoingPenalty,
+		})
+
+		// The recovery is declared ahead of the following one.
+		for dlinfo.Index != dlIdx {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+		assertRecovering(rt, dlIdx, pIdx, bf(uint64(oneSector[0].SectorNumber)))
+		actor.checkState(rt)
+	})
+
+	t.Run("drops scheduled recovery of sector moved to another deadline", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		oneSector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		advanceAndSubmitPoSts(rt, actor, oneSector...)
+		sno := uint64(oneSector[0].SectorNumber)
+
+		st := getState(rt)
+		origIdx, _, err := st.FindSector(rt.AdtStore(), oneSector[0].SectorNumber)
+		require.NoError(t, err)
+		relative := func(offset uint64) uint64 {
+			return (origIdx + offset) % miner.WPoStPeriodDeadlines
+		}
+
+		// Schedule the recovery ahead of the next challenge window of the origin deadline, then move the
+		// sector's partition to a deadline challenged before it.
+		advanceToDeadline(rt, actor, relative(31))
+		actor.declareFaultsWithRecoveryEpoch(rt, rt.Epoch(), oneSector...)
+		destIdx := relative(40)
+		actor.movePartitions(rt, origIdx, destIdx, bf(0))
+
+		advanceToDeadline(rt, actor, destIdx)
+		ongoingPenalty := actor.continuedFaultPenalty(oneSector)
+		advanceDeadline(rt, actor, &cronConfig{continuedFaultsPenalty: ongoingPenalty})
+
+		// The cron that would have declared the recovery ignores the moved sector, which remains faulty.
+		advanceToDeadline(rt, actor, relative(46))
+		rt.ExpectLogsContain(fmt.Sprintf("dropped scheduled recoveries of 1 sectors no longer in deadline %d", origIdx))
+		assertRecovering(rt, destIdx, 0, bf())
+		dl := actor.getDeadline(rt, destIdx)
+		p, err := dl.LoadPartition(rt.AdtStore(), 0)
+		require.NoError(t, err)
+		assertBitfieldsEqual(t, bf(sno), p.Faults)
+
+		st = getState(rt)
+		scheduled, err := adt.AsArray(rt.AdtStore(), st.ScheduledRecoveries, miner.ScheduledRecoveriesAmtBitwidth)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), scheduled.Length())
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects recovery epoch after faulty sectors would be terminated", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		oneSector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, oneSector...)
+
+		st := getState(rt)
+		params := makeFaultParamsFromFaultingSectors(t, st, rt.AdtStore(), oneSector)
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "recovery epoch", func() {
+			rt.Call(actor.a.DeclareFaultsWithRecoveryEpoch, &miner.DeclareFaultsWithRecoveryEpochParams{
+				Faults:        params.Faults,
+				RecoveryEpoch: rt.Epoch() + miner.FaultMaxAge + 1,
+			})
+		})
+		rt.Reset()
+	})
+}
+
 func TestDeclareRecoveries(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
@@ -2026,7 +2342,7 @@ func TestExtendSectorExpiration(t *testing.T) {
 			// Half the sectors should expire on-time.
 			var onTimeTotal uint64
 			require.NoError(t, deadlines.ForEach(rt.AdtStore(), func(dlIdx uint64, dl *miner.Deadline) error {
-				expirationSet, err := dl.PopExpiredSectors(rt.AdtStore(), newExpiration-1, st.QuantSpecForDeadline(dlIdx))
+				expirationSet, _, _, err := dl.PopExpiredSectors(rt.AdtStore(), newExpiration-1, st.QuantSpecForDeadline(dlIdx))
 				require.NoError(t, err)
 
 				count, err := expirationSet.Count()
@@ -2039,7 +2355,7 @@ func TestExtendSectorExpiration(t *testing.T) {
 			// Half the sectors should expire late.
 			var extendedTotal uint64
 			require.NoError(t, deadlines.ForEach(rt.AdtStore(), func(dlIdx uint64, dl *miner.Deadline) error {
-				expirationSet, err := dl.PopExpiredSectors(rt.AdtStore(), newExpiration-1, st.QuantSpecForDeadline(dlIdx))
+				expirationSet, _, _, err := dl.PopExpiredSectors(rt.AdtStore(), newExpiration-1, st.QuantSpecForDeadline(dlIdx))
 				require.NoError(t, err)
 
 				count, err := expirationSet.Count()
@@ -2111,48 +2427,186 @@ func TestExtendSectorExpiration(t *testing.T) {
 	})
 }
 
-func TestTerminateSectors(t *testing.T) {
+func TestExtendSectorExpiration2(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
+	precommitEpoch := abi.ChainEpoch(1)
 	builder := builderForHarness(actor).
-		WithBalance(big.Mul(big.NewInt(1e18), big.NewInt(200000)), big.Zero())
+		WithEpoch(precommitEpoch).
+		WithBalance(bigBalance, big.Zero())
 
-	t.Run("removes sector with correct accounting", func(t *testing.T) {
-		rt := builder.Build(t)
+	// Commits and proves sectors, returning them grouped in a single extension declaration per partition.
+	commitSectors := func(t *testing.T, rt *mock.Runtime, n int) ([]*miner.SectorOnChainInfo, uint64, uint64) {
 		actor.constructAndVerify(rt)
-		rt.SetEpoch(abi.ChainEpoch(1))
-		sectorInfo := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
-		sector := sectorInfo[0]
-		advanceAndSubmitPoSts(rt, actor, sector)
+		sectors := actor.commitAndProveSectors(rt, n, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
 
-		// A miner will pay the minimum of termination fee and locked funds. Add some locked funds to ensure
-		// correct fee calculation is used.
-		actor.applyRewards(rt, bigRewards, big.Zero())
 		st := getState(rt)
-		initialLockedFunds := st.LockedFunds
-
-		sectorSize, err := sector.SealProof.SectorSize()
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sectors[0].SectorNumber)
 		require.NoError(t, err)
-		sectorPower := miner.QAPowerForSector(sectorSize, sector)
-		dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
-		twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
-		sectorAge := rt.Epoch() - sector.Activation
-		expectedFee := miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, actor.epochQAPowerSmooth, sectorPower, actor.epochRewardSmooth, big.Zero(), 0)
+		for _, s := range sectors[1:] {
+			d, p, err := st.FindSector(rt.AdtStore(), s.SectorNumber)
+			require.NoError(t, err)
+			require.Equal(t, dlIdx, d, "test error: sectors should share a deadline")
+			require.Equal(t, pIdx, p, "test error: sectors should share a partition")
+		}
+		return sectors, dlIdx, pIdx
+	}
 
-		sectors := bf(uint64(sector.SectorNumber))
-		actor.terminateSectors(rt, sectors, expectedFee)
+	t.Run("extends sectors in a partition to different expirations", func(t *testing.T) {
+		rt := builder.Build(t)
+		sectors, dlIdx, pIdx := commitSectors(t, rt, 3)
 
-		{
-			st := getState(rt)
+		newExpirations := []abi.ChainEpoch{
+			sectors[0].Expiration + 10*miner.WPoStProvingPeriod,
+			sectors[1].Expiration + 42*miner.WPoStProvingPeriod + miner.WPoStProvingPeriod/3,
+			sectors[2].Expiration, // unchanged
+		}
+		decl := miner.ExpirationExtension2{Deadline: dlIdx, Partition: pIdx}
+		for i, s := range sectors {
+			decl.Sectors = append(decl.Sectors, miner.SectorExpiration{SectorNumber: s.SectorNumber, NewExpiration: newExpirations[i]})
+		}
+		actor.extendSectors2(rt, &miner.ExtendSectorExpiration2Params{Extensions: []miner.ExpirationExtension2{decl}})
 
-			// expect sector to be marked as terminated and the early termination queue to be empty (having been fully processed)
-			_, partition := actor.findSector(rt, sector.SectorNumber)
-			terminated, err := partition.Terminated.IsSet(uint64(sector.SectorNumber))
-			require.NoError(t, err)
-			assert.True(t, terminated)
-			result, _, err := partition.PopEarlyTerminations(rt.AdtStore(), 1000)
-			require.NoError(t, err)
-			assert.True(t, result.IsEmpty())
+		st := getState(rt)
+		quant := st.QuantSpecForDeadline(dlIdx)
+		for i, s := range sectors {
+			assert.Equal(t, newExpirations[i], actor.getSector(rt, s.SectorNumber).Expiration)
+		}
+
+		// Each sector is scheduled to expire in the partition at its own new expiration.
+		_, partition := actor.getDeadlineAndPartition(rt, dlIdx, pIdx)
+		queue, err := miner.LoadExpirationQueue(rt.AdtStore(), partition.ExpirationsEpochs, quant, miner.PartitionExpirationAmtBitwidth)
+		require.NoError(t, err)
+		for _, i := range []int{2, 0, 1} { // in order of expiration
+			set, err := queue.PopUntil(quant.QuantizeUp(newExpirations[i]))
+			require.NoError(t, err)
+			assertBitfieldEquals(t, set.OnTimeSectors, uint64(sectors[i].SectorNumber))
+		}
+
+		// The deadline expects the partition at each new epoch.
+		deadline := actor.getDeadline(rt, dlIdx)
+		expirations := actor.collectDeadlineExpirations(rt, deadline)
+		for _, e := range newExpirations {
+			assert.Contains(t, expirations, quant.QuantizeUp(e))
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects sector declared more than once", func(t *testing.T) {
+		rt := builder.Build(t)
+		sectors, dlIdx, pIdx := commitSectors(t, rt, 1)
+
+		se := miner.SectorExpiration{SectorNumber: sectors[0].SectorNumber, NewExpiration: sectors[0].Expiration + miner.WPoStProvingPeriod}
+		params := &miner.ExtendSectorExpiration2Params{Extensions: []miner.ExpirationExtension2{
+			{Deadline: dlIdx, Partition: pIdx, Sectors: []miner.SectorExpiration{se}},
+			{Deadline: dlIdx, Partition: pIdx, Sectors: []miner.SectorExpiration{se}},
+		}}
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "extended more than once", func() {
+			rt.Call(actor.a.ExtendSectorExpiration2, params)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects invalid deadline", func(t *testing.T) {
+		rt := builder.Build(t)
+		sectors, _, pIdx := commitSectors(t, rt, 1)
+
+		params := &miner.ExtendSectorExpiration2Params{Extensions: []miner.ExpirationExtension2{{
+			Deadline:  miner.WPoStPeriodDeadlines,
+			Partition: pIdx,
+			Sectors:   []miner.SectorExpiration{{SectorNumber: sectors[0].SectorNumber, NewExpiration: sectors[0].Expiration + 1}},
+		}}}
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.ExtendSectorExpiration2, params)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects reduction of any sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		sectors, dlIdx, pIdx := commitSectors(t, rt, 2)
+
+		params := &miner.ExtendSectorExpiration2Params{Extensions: []miner.ExpirationExtension2{{
+			Deadline:  dlIdx,
+			Partition: pIdx,
+			Sectors: []miner.SectorExpiration{
+				{SectorNumber: sectors[0].SectorNumber, NewExpiration: sectors[0].Expiration + miner.WPoStProvingPeriod},
+				{SectorNumber: sectors[1].SectorNumber, NewExpiration: sectors[1].Expiration - miner.WPoStProvingPeriod},
+			},
+		}}}
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, fmt.Sprintf("cannot reduce sector %d's expiration", sectors[1].SectorNumber), func() {
+			rt.Call(actor.a.ExtendSectorExpiration2, params)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects extension too far in future", func(t *testing.T) {
+		rt := builder.Build(t)
+		sectors, dlIdx, pIdx := commitSectors(t, rt, 1)
+
+		newExpiration := rt.Epoch() + miner.MaxSectorExpirationExtension + miner.WPoStProvingPeriod
+		params := &miner.ExtendSectorExpiration2Params{Extensions: []miner.ExpirationExtension2{{
+			Deadline:  dlIdx,
+			Partition: pIdx,
+			Sectors:   []miner.SectorExpiration{{SectorNumber: sectors[0].SectorNumber, NewExpiration: newExpiration}},
+		}}}
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		expectedMessage := fmt.Sprintf("cannot be more than %d past current epoch", miner.MaxSectorExpirationExtension)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, expectedMessage, func() {
+			rt.Call(actor.a.ExtendSectorExpiration2, params)
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestTerminateSectors(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(big.Mul(big.NewInt(1e18), big.NewInt(200000)), big.Zero())
+
+	t.Run("removes sector with correct accounting", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectorInfo := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		sector := sectorInfo[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		// A miner will pay the minimum of termination fee and locked funds. Add some locked funds to ensure
+		// correct fee calculation is used.
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		st := getState(rt)
+		initialLockedFunds := st.LockedFunds
+
+		sectorSize, err := sector.SealProof.SectorSize()
+		require.NoError(t, err)
+		sectorPower := miner.QAPowerForSector(sectorSize, sector)
+		dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
+		twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
+		sectorAge := rt.Epoch() - sector.Activation
+		expectedFee := miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, actor.epochQAPowerSmooth, sectorPower, actor.epochRewardSmooth, big.Zero(), 0)
+
+		sectors := bf(uint64(sector.SectorNumber))
+		actor.terminateSectors(rt, sectors, expectedFee)
+
+		{
+			st := getState(rt)
+
+			// expect sector to be marked as terminated and the early termination queue to be empty (having been fully processed)
+			_, partition := actor.findSector(rt, sector.SectorNumber)
+			terminated, err := partition.Terminated.IsSet(uint64(sector.SectorNumber))
+			require.NoError(t, err)
+			assert.True(t, terminated)
+			result, _, err := partition.PopEarlyTerminations(rt.AdtStore(), 1000)
+			require.NoError(t, err)
+			assert.True(t, result.IsEmpty())
 
 			// expect fee to have been unlocked and burnt
 			assert.Equal(t, big.Sub(initialLockedFunds, expectedFee), st.LockedFunds)
@@ -2196,6 +2650,125 @@ func TestTerminateSectors(t *testing.T) {
 
 }
 
+func TestSectorEvents(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	t.Run("emits events through a sector's lifecycle", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		sno := uint64(sector.SectorNumber)
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+		pwr := miner.PowerForSectors(actor.sectorSize, []*miner.SectorOnChainInfo{sector})
+
+		// Power is gained at the first proof, not at activation.
+		assertSectorEvents(t, rt,
+			&miner.SectorEvent{Type: miner.SectorEventPreCommitted, Sectors: bf(sno), PowerDelta: miner.NewPowerPairZero()},
+			&miner.SectorEvent{Type: miner.SectorEventActivated, Deadline: dlIdx, Partition: pIdx, Sectors: bf(sno), PowerDelta: miner.NewPowerPairZero()},
+		)
+
+		// A proof with no faults or recoveries emits nothing.
+		advanceAndSubmitPoSts(rt, actor, sector)
+		assertSectorEvents(t, rt)
+
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		advanceDeadline(rt, actor, &cronConfig{})
+		actor.declareFaults(rt, sector)
+		assertSectorEvents(t, rt,
+			&miner.SectorEvent{Type: miner.SectorEventFaulted, Deadline: dlIdx, Partition: pIdx, Sectors: bf(sno), PowerDelta: pwr.Neg()},
+		)
+
+		advanceDeadline(rt, actor, &cronConfig{})
+		actor.declareRecoveries(rt, dlIdx, pIdx, bf(sno), big.Zero())
+		dlinfo := advanceToDeadline(rt, actor, dlIdx)
+		rt.ClearEvents()
+		actor.submitWindowPoSt(rt, dlinfo, []miner.PoStPartition{{Index: pIdx, Skipped: bitfield.New()}},
+			[]*miner.SectorOnChainInfo{sector}, &poStConfig{expectedPowerDelta: pwr})
+		assertSectorEvents(t, rt,
+			&miner.SectorEvent{Type: miner.SectorEventRecovered, Deadline: dlIdx, Partition: pIdx, Sectors: bf(sno), PowerDelta: pwr},
+		)
+
+		// Move past the sector's deadline so that it may be terminated.
+		advanceDeadline(rt, actor, &cronConfig{})
+		advanceDeadline(rt, actor, &cronConfig{})
+		fee := actor.previewTerminationFees(rt, bf(sno)).TotalFee
+		rt.ClearEvents()
+		actor.terminateSectors(rt, bf(sno), fee)
+		assertSectorEvents(t, rt,
+			&miner.SectorEvent{Type: miner.SectorEventTerminated, Deadline: dlIdx, Partition: pIdx, Sectors: bf(sno), PowerDelta: pwr.Neg()},
+		)
+		actor.checkState(rt)
+	})
+
+	t.Run("emits event for faults detected at deadline end", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		sno := uint64(sector.SectorNumber)
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+		advanceToDeadline(rt, actor, dlIdx)
+		rt.ClearEvents()
+
+		// The PoSt is missed.
+		pwrDelta := miner.PowerForSectors(actor.sectorSize, []*miner.SectorOnChainInfo{sector}).Neg()
+		advanceDeadline(rt, actor, &cronConfig{detectedFaultsPowerDelta: &pwrDelta})
+		assertSectorEvents(t, rt,
+			&miner.SectorEvent{Type: miner.SectorEventFaulted, Deadline: dlIdx, Partition: pIdx, Sectors: bf(sno), PowerDelta: pwrDelta},
+		)
+		actor.checkState(rt)
+	})
+
+	t.Run("emits event for sectors expiring at deadline end", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		sno := uint64(sector.SectorNumber)
+		advanceAndSubmitPoSts(rt, actor, sector)
+		pwr := miner.PowerForSectors(actor.sectorSize, []*miner.SectorOnChainInfo{sector})
+		initialPledge := getState(rt).InitialPledge
+
+		// Skip forward in state to the sector's expiration.
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+		expiration := st.QuantSpecForDeadline(dlIdx).QuantizeUp(sector.Expiration)
+		remainingPeriods := (expiration-st.ProvingPeriodStart)/miner.WPoStProvingPeriod + 1
+		st.ProvingPeriodStart += remainingPeriods * miner.WPoStProvingPeriod
+		st.CurrentDeadline = dlIdx
+		rt.ReplaceState(st)
+		rt.SetEpoch(expiration)
+		rt.ClearEvents()
+
+		// Having skipped its proofs, the sector is detected faulty before it expires.
+		powerDelta := pwr.Neg()
+		advanceDeadline(rt, actor, &cronConfig{
+			noEnrollment:              true,
+			expiredSectorsPowerDelta:  &powerDelta,
+			expiredSectorsPledgeDelta: initialPledge.Neg(),
+		})
+		assertSectorEvents(t, rt,
+			&miner.SectorEvent{Type: miner.SectorEventFaulted, Deadline: dlIdx, Partition: pIdx, Sectors: bf(sno), PowerDelta: pwr.Neg()},
+			&miner.SectorEvent{Type: miner.SectorEventExpired, Deadline: dlIdx, Partition: pIdx, Sectors: bf(sno), PowerDelta: miner.NewPowerPairZero()},
+		)
+		actor.checkState(rt)
+	})
+}
+
 func TestWithdrawBalance(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
@@ -2328,6 +2901,101 @@ func TestRepayDebts(t *testing.T) {
 	})
 }
 
+func TestChangeDebtRepaymentPolicy(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	t.Run("owner changes policy", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		info := actor.getInfo(rt)
+		assert.Equal(t, miner.DebtRepaymentVestingFirst, info.DebtRepaymentPolicy.Priority)
+		assert.Equal(t, big.Zero(), info.DebtRepaymentPolicy.MaxRepayment)
+
+		policy := miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst, MaxRepayment: big.NewInt(1e18)}
+		actor.changeDebtRepaymentPolicy(rt, actor.owner, &policy)
+		assert.Equal(t, policy, actor.getInfo(rt).DebtRepaymentPolicy)
+		actor.checkState(rt)
+	})
+
+	t.Run("only owner can change policy", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		policy := miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst, MaxRepayment: big.Zero()}
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			actor.changeDebtRepaymentPolicy(rt, actor.worker, &policy)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects invalid policy", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid debt repayment priority", func() {
+			actor.changeDebtRepaymentPolicy(rt, actor.owner, &miner.DebtRepaymentPolicy{Priority: 7, MaxRepayment: big.Zero()})
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid maximum debt repayment", func() {
+			actor.changeDebtRepaymentPolicy(rt, actor.owner, &miner.DebtRepaymentPolicy{Priority: miner.DebtRepaymentBalanceFirst, MaxRepayment: big.NewInt(-1)})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("cron repays no more than the policy maximum", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+
+		maxRepayment := big.NewInt(1e18)
+		actor.changeDebtRepaymentPolicy(rt, actor.owner, &miner.DebtRepaymentPolicy{
+			Priority:     miner.DebtRepaymentVestingFirst,
+			MaxRepayment: maxRepayment,
+		})
+
+		st := getState(rt)
+		feeDebt := big.Mul(big.NewInt(4), big.NewInt(1e18))
+		st.FeeDebt = feeDebt
+		rt.ReplaceState(st)
+
+		advanceDeadline(rt, actor, &cronConfig{repaidFeeDebt: maxRepayment})
+		assert.Equal(t, big.Sub(feeDebt, maxRepayment), getState(rt).FeeDebt)
+
+		advanceDeadline(rt, actor, &cronConfig{repaidFeeDebt: maxRepayment})
+		assert.Equal(t, big.Sub(feeDebt, big.Mul(big.NewInt(2), maxRepayment)), getState(rt).FeeDebt)
+		actor.checkState(rt)
+	})
+
+	t.Run("cron repays from balance before vesting funds", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		lockedFunds := actor.getLockedFunds(rt)
+		require.True(t, lockedFunds.GreaterThan(big.Zero()))
+
+		actor.changeDebtRepaymentPolicy(rt, actor.owner, &miner.DebtRepaymentPolicy{
+			Priority:     miner.DebtRepaymentBalanceFirst,
+			MaxRepayment: big.Zero(),
+		})
+
+		st := getState(rt)
+		feeDebt := big.NewInt(1e18)
+		st.FeeDebt = feeDebt
+		rt.ReplaceState(st)
+
+		// The debt is repaid in full from unlocked balance, leaving vesting funds in place.
+		advanceDeadline(rt, actor, &cronConfig{repaidFeeDebt: feeDebt})
+		assert.Equal(t, big.Zero(), getState(rt).FeeDebt)
+		assert.Equal(t, lockedFunds, actor.getLockedFunds(rt))
+		actor.checkState(rt)
+	})
+}
+
 func TestChangePeerID(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
@@ -2344,6 +3012,103 @@ func TestChangePeerID(t *testing.T) {
 	})
 }
 
+func TestCronCompaction(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	terminateSector := func(rt *mock.Runtime, sector *miner.SectorOnChainInfo) {
+		sectorSize, err := sector.SealProof.SectorSize()
+		require.NoError(t, err)
+		sectorPower := miner.QAPowerForSector(sectorSize, sector)
+		dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
+		twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
+		sectorAge := rt.Epoch() - sector.Activation
+		expectedFee := miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, actor.epochQAPowerSmooth,
+			sectorPower, actor.epochRewardSmooth, big.Zero(), 0)
+		actor.terminateSectors(rt, bf(uint64(sector.SectorNumber)), expectedFee)
+	}
+
+	t.Run("deadline cron removes fully terminated partitions", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(200)
+		info := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, [][]abi.DealID{{10}}, true)
+		advanceAndSubmitPoSts(rt, actor, info...)
+		dlIdx, _, err := getState(rt).FindSector(rt.AdtStore(), info[0].SectorNumber)
+		require.NoError(t, err)
+
+		rt.SetEpoch(rt.Epoch() + 100)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		terminateSector(rt, info[0])
+
+		// The terminated sector remains in state until its deadline is compacted.
+		st := getState(rt)
+		_, found, err := st.GetSector(rt.AdtStore(), info[0].SectorNumber)
+		require.NoError(t, err)
+		assert.True(t, found)
+
+		// Every deadline is compacted within a proving period after the dispute window has passed.
+		advanceToEpochWithCron(rt, actor, rt.Epoch()+miner.WPoStDisputeWindow+miner.WPoStProvingPeriod)
+
+		st = getState(rt)
+		_, found, err = st.GetSector(rt.AdtStore(), info[0].SectorNumber)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		deadline := actor.getDeadline(rt, dlIdx)
+		partitions, err := deadline.PartitionsArray(rt.AdtStore())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), partitions.Length())
+		assert.Equal(t, uint64(0), deadline.TotalSectors)
+
+		// With no sectors remaining, allocated sector numbers are left unchanged.
+		var allocated bitfield.BitField
+		require.NoError(t, rt.AdtStore().Get(rt.Context(), st.AllocatedSectors, &allocated))
+		assertBitfieldEquals(t, allocated, uint64(info[0].SectorNumber))
+		actor.checkState(rt)
+	})
+
+	t.Run("deadline cron retains partitions with live sectors", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(200)
+		info := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, [][]abi.DealID{{10}, {20}}, true)
+		advanceAndSubmitPoSts(rt, actor, info...)
+		dlIdx, _, err := getState(rt).FindSector(rt.AdtStore(), info[0].SectorNumber)
+		require.NoError(t, err)
+
+		rt.SetEpoch(rt.Epoch() + 100)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		terminateSector(rt, info[0])
+
+		target := rt.Epoch() + miner.WPoStDisputeWindow + miner.WPoStProvingPeriod
+		for rt.Epoch() < target {
+			advanceAndSubmitPoSts(rt, actor, info[1])
+		}
+
+		// Both sectors remain, as the partition is not fully terminated.
+		st := getState(rt)
+		for _, sector := range info {
+			_, found, err := st.GetSector(rt.AdtStore(), sector.SectorNumber)
+			require.NoError(t, err)
+			assert.True(t, found)
+		}
+		deadline := actor.getDeadline(rt, dlIdx)
+		assert.Equal(t, uint64(2), deadline.TotalSectors)
+		assert.Equal(t, uint64(1), deadline.LiveSectors)
+
+		// Unused sector numbers below the lowest sector are masked.
+		var allocated bitfield.BitField
+		require.NoError(t, rt.AdtStore().Get(rt.Context(), st.AllocatedSectors, &allocated))
+		assertBitfieldsEqual(t, seq(t, 0, uint64(info[1].SectorNumber)+1), allocated)
+		actor.checkState(rt)
+	})
+}
+
 func TestCompactPartitions(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
@@ -2484,74 +3249,335 @@ func TestCompactPartitions(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
 
-		rt.SetEpoch(periodOffset)
-		rt.ExpectAbort(exitcode.ErrForbidden, func() {
-			actor.compactPartitions(rt, 0, bitfield.New())
-		})
+		rt.SetEpoch(periodOffset)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.compactPartitions(rt, 0, bitfield.New())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if deadline is next up to be challenged", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(periodOffset)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.compactPartitions(rt, 1, bitfield.New())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("the deadline after the next deadline should still be open for compaction", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(periodOffset)
+		actor.compactPartitions(rt, 3, bitfield.New())
+		actor.checkState(rt)
+	})
+
+	t.Run("deadlines challenged last proving period should still be in the dispute window", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(periodOffset)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.compactPartitions(rt, 47, bitfield.New())
+		})
+		actor.checkState(rt)
+	})
+
+	disputeEnd := periodOffset + miner.WPoStChallengeWindow + miner.WPoStDisputeWindow - 1
+	t.Run("compaction should be forbidden during the dispute window", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(disputeEnd)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.compactPartitions(rt, 0, bitfield.New())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("compaction should be allowed following the dispute window", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(disputeEnd + 1)
+		actor.compactPartitions(rt, 0, bitfield.New())
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if partition count is above limit", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		// partition limit is 4 for the default construction
+		bf := bitfield.NewFromSet([]uint64{1, 2, 3, 4, 5})
+
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.compactPartitions(rt, 1, bf)
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestPreviewTerminationFees(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(big.Mul(big.NewInt(1e18), big.NewInt(200000)), big.Zero())
+
+	t.Run("preview matches the fee charged on termination", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectorInfo := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectorInfo...)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		rt.SetEpoch(rt.Epoch() + 100)
+
+		sectors := bf(uint64(sectorInfo[0].SectorNumber), uint64(sectorInfo[1].SectorNumber))
+		preview := actor.previewTerminationFees(rt, sectors)
+		require.Len(t, preview.Sectors, 2)
+
+		expectedTotal := big.Zero()
+		for i, sector := range sectorInfo {
+			expectedFee := miner.TerminationPenaltyForSector(actor.sectorSize, rt.Epoch(), actor.epochRewardSmooth, actor.epochQAPowerSmooth, sector)
+			assert.True(t, expectedFee.GreaterThan(big.Zero()))
+			assert.Equal(t, sector.SectorNumber, preview.Sectors[i].SectorNumber)
+			assert.Equal(t, expectedFee, preview.Sectors[i].Fee)
+			assert.Equal(t, sector.InitialPledge, preview.Sectors[i].InitialPledge)
+			expectedTotal = big.Add(expectedTotal, expectedFee)
+		}
+		assert.Equal(t, expectedTotal, preview.TotalFee)
+		assert.Equal(t, big.Add(sectorInfo[0].InitialPledge, sectorInfo[1].InitialPledge), preview.PledgeReleased)
+
+		// Previewing does not modify state.
+		st := getState(rt)
+		assert.Equal(t, preview.PledgeReleased, st.InitialPledge)
+
+		// Terminating at the same epoch burns exactly the previewed fee.
+		actor.terminateSectors(rt, sectors, preview.TotalFee)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for unknown sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectValidateCallerAny()
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "failed to compute termination fees", func() {
+			rt.Call(actor.a.PreviewTerminationFees, &miner.PreviewTerminationFeesParams{Sectors: bf(100)})
+		})
+		rt.Reset()
+	})
+
+	t.Run("fails for already terminated sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectorInfo := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectorInfo...)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		rt.SetEpoch(rt.Epoch() + 100)
+
+		terminated := bf(uint64(sectorInfo[0].SectorNumber))
+		actor.terminateSectors(rt, terminated, actor.previewTerminationFees(rt, terminated).TotalFee)
+
+		// The terminated sector remains in state until compacted, but is no longer previewed.
+		_, found, err := getState(rt).GetSector(rt.AdtStore(), sectorInfo[0].SectorNumber)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		rt.ExpectValidateCallerAny()
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "already terminated", func() {
+			rt.Call(actor.a.PreviewTerminationFees, &miner.PreviewTerminationFeesParams{
+				Sectors: bf(uint64(sectorInfo[0].SectorNumber), uint64(sectorInfo[1].SectorNumber)),
+			})
+		})
+		rt.Reset()
+
+		// The live sector may still be previewed.
+		preview := actor.previewTerminationFees(rt, bf(uint64(sectorInfo[1].SectorNumber)))
+		assert.Len(t, preview.Sectors, 1)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for too many sectors", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		sectorNos := make([]uint64, miner.AddressedSectorsMax+1)
+		for i := range sectorNos {
+			sectorNos[i] = uint64(i)
+		}
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many sectors", func() {
+			rt.Call(actor.a.PreviewTerminationFees, &miner.PreviewTerminationFeesParams{
+				Sectors: bitfield.NewFromSet(sectorNos),
+			})
+		})
+		rt.Reset()
+	})
+}
+
+func TestMovePartitions(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	// Commits and proves sectors, returning them and the deadline to which they were assigned.
+	setup := func(t *testing.T, rt *mock.Runtime, n int) ([]*miner.SectorOnChainInfo, uint64) {
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(200)
+		sectors := actor.commitAndProveSectors(rt, n, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+
+		st := getState(rt)
+		dlIdx, _, err := st.FindSector(rt.AdtStore(), sectors[0].SectorNumber)
+		require.NoError(t, err)
+		return sectors, dlIdx
+	}
+	relative := func(dlIdx, offset uint64) uint64 {
+		return (dlIdx + offset) % miner.WPoStPeriodDeadlines
+	}
+
+	t.Run("moves partition to an earlier deadline", func(t *testing.T) {
+		rt := builder.Build(t)
+		sectors, origIdx := setup(t, rt, 4)
+
+		// Wait for the dispute window of the origin deadline to pass.
+		advanceToDeadline(rt, actor, relative(origIdx, 31))
+		destIdx := relative(origIdx, 40)
+
+		origBefore := actor.getDeadline(rt, origIdx)
+		actor.movePartitions(rt, origIdx, destIdx, bf(0))
+
+		st := getState(rt)
+		for _, sector := range sectors {
+			dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+			require.NoError(t, err)
+			assert.Equal(t, destIdx, dlIdx)
+			assert.Equal(t, uint64(0), pIdx)
+		}
+
+		orig := actor.getDeadline(rt, origIdx)
+		dest := actor.getDeadline(rt, destIdx)
+		assert.Equal(t, uint64(0), orig.LiveSectors)
+		assert.Equal(t, origBefore.LiveSectors, dest.LiveSectors)
+		assert.Empty(t, actor.collectDeadlineExpirations(rt, orig))
+		assert.NotEmpty(t, actor.collectDeadlineExpirations(rt, dest))
+		actor.checkState(rt)
+
+		// The sectors must now be proven at the destination deadline, and remain active.
+		advanceAndSubmitPoSts(rt, actor, sectors...)
 		actor.checkState(rt)
 	})
 
-	t.Run("fails if deadline is next up to be challenged", func(t *testing.T) {
+	t.Run("fails to move to a deadline challenged after the origin", func(t *testing.T) {
 		rt := builder.Build(t)
-		actor.constructAndVerify(rt)
+		_, origIdx := setup(t, rt, 1)
 
-		rt.SetEpoch(periodOffset)
-		rt.ExpectAbort(exitcode.ErrForbidden, func() {
-			actor.compactPartitions(rt, 1, bitfield.New())
+		advanceToDeadline(rt, actor, relative(origIdx, 45))
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "next challenged later", func() {
+			actor.movePartitions(rt, origIdx, relative(origIdx, 5), bf(0))
 		})
 		actor.checkState(rt)
 	})
 
-	t.Run("the deadline after the next deadline should still be open for compaction", func(t *testing.T) {
+	t.Run("fails to move from a deadline in its dispute window", func(t *testing.T) {
 		rt := builder.Build(t)
-		actor.constructAndVerify(rt)
+		_, origIdx := setup(t, rt, 1)
 
-		rt.SetEpoch(periodOffset)
-		actor.compactPartitions(rt, 3, bitfield.New())
+		advanceToDeadline(rt, actor, relative(origIdx, 20))
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, fmt.Sprintf("deadline %d", origIdx), func() {
+			actor.movePartitions(rt, origIdx, relative(origIdx, 40), bf(0))
+		})
 		actor.checkState(rt)
 	})
 
-	t.Run("deadlines challenged last proving period should still be in the dispute window", func(t *testing.T) {
+	t.Run("moves partition with faulty sectors", func(t *testing.T) {
 		rt := builder.Build(t)
-		actor.constructAndVerify(rt)
+		sectors, origIdx := setup(t, rt, 2)
+
+		actor.declareFaults(rt, sectors[0])
+		faultyPower := actor.powerPairForSectors(sectors[:1])
+		advanceToDeadline(rt, actor, relative(origIdx, 31))
+		destIdx := relative(origIdx, 40)
+		actor.movePartitions(rt, origIdx, destIdx, bf(0))
+
+		orig := actor.getDeadline(rt, origIdx)
+		dest := actor.getDeadline(rt, destIdx)
+		assert.True(t, orig.FaultyPower.IsZero())
+		assert.Equal(t, faultyPower, dest.FaultyPower)
+		partition := actor.getPartition(rt, dest, 0)
+		assertBitfieldEquals(t, partition.Faults, uint64(sectors[0].SectorNumber))
+		assert.Equal(t, faultyPower, partition.FaultyPower)
+		actor.checkState(rt)
 
-		rt.SetEpoch(periodOffset)
-		rt.ExpectAbort(exitcode.ErrForbidden, func() {
-			actor.compactPartitions(rt, 47, bitfield.New())
+		// The healthy sector is proven at the destination deadline, which charges the fee for the ongoing fault.
+		dlinfo := advanceToDeadline(rt, actor, destIdx)
+		partitions := []miner.PoStPartition{{Index: 0, Skipped: bitfield.New()}}
+		actor.submitWindowPoSt(rt, dlinfo, partitions, sectors[1:], &poStConfig{
+			expectedPowerDelta: miner.NewPowerPairZero(),
 		})
+		ongoingPenalty := miner.PledgePenaltyForContinuedFault(actor.epochRewardSmooth, actor.epochQAPowerSmooth, faultyPower.QA)
+		advanceDeadline(rt, actor, &cronConfig{continuedFaultsPenalty: ongoingPenalty, penaltyFromUnlocked: ongoingPenalty})
+
+		// The origin deadline no longer charges for the fault.
+		advanceToDeadline(rt, actor, origIdx)
+		advanceDeadline(rt, actor, &cronConfig{})
 		actor.checkState(rt)
 	})
 
-	disputeEnd := periodOffset + miner.WPoStChallengeWindow + miner.WPoStDisputeWindow - 1
-	t.Run("compaction should be forbidden during the dispute window", func(t *testing.T) {
+	t.Run("moves partition with recovering sectors", func(t *testing.T) {
 		rt := builder.Build(t)
-		actor.constructAndVerify(rt)
-
-		rt.SetEpoch(disputeEnd)
-		rt.ExpectAbort(exitcode.ErrForbidden, func() {
-			actor.compactPartitions(rt, 0, bitfield.New())
-		})
+		sectors, origIdx := setup(t, rt, 2)
+
+		actor.declareFaults(rt, sectors[0])
+		actor.declareRecoveries(rt, origIdx, 0, bf(uint64(sectors[0].SectorNumber)), big.Zero())
+		recoveringPower := actor.powerPairForSectors(sectors[:1])
+		advanceToDeadline(rt, actor, relative(origIdx, 31))
+		destIdx := relative(origIdx, 40)
+		actor.movePartitions(rt, origIdx, destIdx, bf(0))
+
+		dest := actor.getDeadline(rt, destIdx)
+		assert.Equal(t, recoveringPower, dest.FaultyPower)
+		partition := actor.getPartition(rt, dest, 0)
+		assertBitfieldEquals(t, partition.Recoveries, uint64(sectors[0].SectorNumber))
+		assert.Equal(t, recoveringPower, partition.RecoveringPower)
 		actor.checkState(rt)
-	})
 
-	t.Run("compaction should be allowed following the dispute window", func(t *testing.T) {
-		rt := builder.Build(t)
-		actor.constructAndVerify(rt)
+		// The recovering sector is proven at the destination deadline, restoring its power.
+		dlinfo := advanceToDeadline(rt, actor, destIdx)
+		partitions := []miner.PoStPartition{{Index: 0, Skipped: bitfield.New()}}
+		actor.submitWindowPoSt(rt, dlinfo, partitions, sectors, &poStConfig{
+			expectedPowerDelta: recoveringPower,
+		})
+		advanceDeadline(rt, actor, &cronConfig{})
 
-		rt.SetEpoch(disputeEnd + 1)
-		actor.compactPartitions(rt, 0, bitfield.New())
+		dest = actor.getDeadline(rt, destIdx)
+		assert.True(t, dest.FaultyPower.IsZero())
+		partition = actor.getPartition(rt, dest, 0)
+		assertEmptyBitfield(t, partition.Faults)
+		assertEmptyBitfield(t, partition.Recoveries)
 		actor.checkState(rt)
 	})
 
-	t.Run("fails if partition count is above limit", func(t *testing.T) {
+	t.Run("fails if deadlines are the same or out of range", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
 
-		// partition limit is 4 for the default construction
-		bf := bitfield.NewFromSet([]uint64{1, 2, 3, 4, 5})
-
 		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
-			actor.compactPartitions(rt, 1, bf)
+			actor.movePartitions(rt, 2, 2, bf(0))
+		})
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.movePartitions(rt, 2, miner.WPoStPeriodDeadlines, bf(0))
 		})
 		actor.checkState(rt)
 	})
@@ -2587,6 +3613,95 @@ func TestCheckSectorProven(t *testing.T) {
 	})
 }
 
+func TestQueryMethods(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	t.Run("get sector info", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		sectors := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		info := actor.getSectorInfo(rt, sectors[0].SectorNumber)
+		assert.Equal(t, sectors[0], info)
+
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			actor.getSectorInfo(rt, sectors[0].SectorNumber+1)
+		})
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.getSectorInfo(rt, abi.MaxSectorNumber+1)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("sector is active only once proven and while not faulty", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		assert.False(t, actor.isSectorActive(rt, 100))
+
+		sectors := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		sno := sectors[0].SectorNumber
+		assert.False(t, actor.isSectorActive(rt, sno))
+
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+		assert.True(t, actor.isSectorActive(rt, sno))
+
+		actor.declareFaults(rt, sectors...)
+		assert.False(t, actor.isSectorActive(rt, sno))
+		actor.checkState(rt)
+	})
+
+	t.Run("available balance includes vested funds and excludes fee debt", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		assert.Equal(t, bigBalance, actor.getAvailableBalance(rt))
+
+		rwd := abi.NewTokenAmount(1_000_000)
+		rt.SetBalance(big.Add(rt.Balance(), rwd))
+		actor.applyRewards(rt, rwd, big.Zero())
+
+		vesting := actor.getVestingFunds(rt)
+		st := getState(rt)
+		stored, err := st.LoadVestingFunds(rt.AdtStore())
+		require.NoError(t, err)
+		assert.Equal(t, stored, vesting)
+		require.NotEmpty(t, vesting.Funds)
+
+		unlocked := big.Sub(rt.Balance(), st.LockedFunds)
+		assert.Equal(t, unlocked, actor.getAvailableBalance(rt))
+
+		// Funds which have vested are available even before they are unlocked.
+		rt.SetEpoch(vesting.Funds[0].Epoch + 1)
+		assert.Equal(t, big.Add(unlocked, vesting.Funds[0].Amount), actor.getAvailableBalance(rt))
+
+		st = getState(rt)
+		st.FeeDebt = abi.NewTokenAmount(10)
+		rt.ReplaceState(st)
+		assert.Equal(t, big.Sum(unlocked, vesting.Funds[0].Amount, big.NewInt(-10)), actor.getAvailableBalance(rt))
+	})
+
+	t.Run("get deadline info", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		for i := 0; i < 3; i++ {
+			expected := actor.currentDeadline(rt)
+			ret := actor.getDeadlineInfo(rt)
+			assert.Equal(t, rt.Epoch(), ret.CurrentEpoch)
+			assert.Equal(t, expected.PeriodStart, ret.PeriodStart)
+			assert.Equal(t, expected.Index, ret.Index)
+			assert.Equal(t, expected.Open, ret.Open)
+			assert.Equal(t, expected.Close, ret.Close)
+			assert.Equal(t, expected.Challenge, ret.Challenge)
+			assert.Equal(t, expected.FaultCutoff, ret.FaultCutoff)
+			rt.SetEpoch(ret.Close)
+		}
+	})
+}
+
 func TestChangeMultiAddrs(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 
@@ -2905,6 +4020,7 @@ func TestConfirmUpdateWorkerKey(t *testing.T) {
 
 		// confirm at effective epoch
 		rt.SetEpoch(effectiveEpoch)
+		rt.ClearEvents()
 		actor.confirmUpdateWorkerKey(rt)
 
 		st := getState(rt)
@@ -2912,6 +4028,10 @@ func TestConfirmUpdateWorkerKey(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, info.Worker, newWorker)
 		require.Nil(t, info.PendingWorkerKey)
+
+		// The change is recorded in an event.
+		require.Len(t, rt.Events(), 1)
+		assert.Equal(t, &miner.WorkerKeyChangedEvent{OldWorker: actor.worker, NewWorker: newWorker}, rt.Events()[0])
 		actor.checkState(rt)
 	})
 
@@ -2925,6 +4045,7 @@ func TestConfirmUpdateWorkerKey(t *testing.T) {
 
 		// confirm right before the effective epoch
 		rt.SetEpoch(effectiveEpoch - 1)
+		rt.ClearEvents()
 		actor.confirmUpdateWorkerKey(rt)
 
 		st := getState(rt)
@@ -2932,6 +4053,28 @@ func TestConfirmUpdateWorkerKey(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, actor.worker, info.Worker)
 		require.NotNil(t, info.PendingWorkerKey)
+		assert.Empty(t, rt.Events())
+		actor.checkState(rt)
+	})
+
+	t.Run("deadline cron changes the worker address", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetEpoch(currentEpoch)
+		actor.constructAndVerify(rt)
+		sectors := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+
+		effectiveEpoch := rt.Epoch() + miner.WorkerKeyChangeDelay
+		actor.changeWorkerAddress(rt, newWorker, effectiveEpoch, actor.controlAddrs)
+
+		// The change takes effect at the end of the deadline containing the effective epoch,
+		// which is before the sector's next deadline.
+		advanceToEpochWithCron(rt, actor, effectiveEpoch)
+		rt.ClearEvents()
+		advanceDeadline(rt, actor, &cronConfig{})
+		assert.Equal(t, newWorker, actor.getInfo(rt).Worker)
+		require.Len(t, rt.Events(), 1)
+		assert.Equal(t, &miner.WorkerKeyChangedEvent{OldWorker: actor.worker, NewWorker: newWorker}, rt.Events()[0])
 		actor.checkState(rt)
 	})
 
@@ -2951,6 +4094,298 @@ func TestConfirmUpdateWorkerKey(t *testing.T) {
 	})
 }
 
+func TestCancelWorkerKeyChange(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	newWorker := tutil.NewIDAddr(t, 999)
+	otherWorker := tutil.NewIDAddr(t, 1000)
+	currentEpoch := abi.ChainEpoch(5)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	t.Run("cancels a pending change", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetEpoch(currentEpoch)
+		actor.constructAndVerify(rt)
+
+		effectiveEpoch := currentEpoch + miner.WorkerKeyChangeDelay
+		actor.changeWorkerAddress(rt, newWorker, effectiveEpoch, actor.controlAddrs)
+
+		ret := actor.cancelWorkerKeyChange(rt)
+		assert.Equal(t, newWorker, ret.CancelledWorker)
+		assert.Equal(t, effectiveEpoch, ret.EffectiveAt)
+		assert.Nil(t, actor.getInfo(rt).PendingWorkerKey)
+
+		// Confirming after the effective epoch leaves the worker unchanged.
+		rt.SetEpoch(effectiveEpoch)
+		actor.confirmUpdateWorkerKey(rt)
+		assert.Equal(t, actor.worker, actor.getInfo(rt).Worker)
+		actor.checkState(rt)
+	})
+
+	t.Run("a different worker can be scheduled after cancelling", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetEpoch(currentEpoch)
+		actor.constructAndVerify(rt)
+
+		actor.changeWorkerAddress(rt, newWorker, currentEpoch+miner.WorkerKeyChangeDelay, actor.controlAddrs)
+		actor.cancelWorkerKeyChange(rt)
+
+		rt.SetEpoch(currentEpoch + 10)
+		effectiveEpoch := rt.Epoch() + miner.WorkerKeyChangeDelay
+		actor.changeWorkerAddress(rt, otherWorker, effectiveEpoch, actor.controlAddrs)
+
+		rt.SetEpoch(effectiveEpoch)
+		actor.confirmUpdateWorkerKey(rt)
+		assert.Equal(t, otherWorker, actor.getInfo(rt).Worker)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when no change is pending", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetEpoch(currentEpoch)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no pending worker key change", func() {
+			actor.cancelWorkerKeyChange(rt)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("only owner can cancel", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetEpoch(currentEpoch)
+		actor.constructAndVerify(rt)
+		actor.changeWorkerAddress(rt, newWorker, currentEpoch+miner.WorkerKeyChangeDelay, actor.controlAddrs)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.owner)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.a.CancelWorkerKeyChange, nil)
+		})
+		require.NotNil(t, actor.getInfo(rt).PendingWorkerKey)
+		actor.checkState(rt)
+	})
+}
+
+func TestChangeBeneficiary(t *testing.T) {
+	actor := newHarness(t, 0)
+	beneficiary := tutil.NewIDAddr(t, 1001)
+	other := tutil.NewIDAddr(t, 1002)
+	builder := builderForHarness(actor).
+		WithActorType(beneficiary, builtin.AccountActorCodeID).
+		WithActorType(other, builtin.MultisigActorCodeID).
+		WithBalance(bigBalance, big.Zero())
+	quota := abi.NewTokenAmount(100)
+	expiration := abi.ChainEpoch(1000)
+
+	setBeneficiary := func(rt *mock.Runtime, nominee addr.Address, quota abi.TokenAmount, expiration abi.ChainEpoch) {
+		params := &miner.ChangeBeneficiaryParams{NewBeneficiary: nominee, NewQuota: quota, NewExpiration: expiration}
+		actor.changeBeneficiary(rt, actor.owner, params)
+		actor.changeBeneficiary(rt, nominee, params)
+	}
+
+	t.Run("beneficiary defaults to owner", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		ret := actor.getBeneficiary(rt)
+		assert.Equal(t, actor.owner, ret.Active.Beneficiary)
+		assert.Equal(t, big.Zero(), ret.Active.Term.Quota)
+		assert.Equal(t, abi.ChainEpoch(0), ret.Active.Term.Expiration)
+		assert.Nil(t, ret.Proposed)
+	})
+
+	t.Run("owner proposes and nominee confirms", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		params := &miner.ChangeBeneficiaryParams{NewBeneficiary: beneficiary, NewQuota: quota, NewExpiration: expiration}
+		actor.changeBeneficiary(rt, actor.owner, params)
+
+		ret := actor.getBeneficiary(rt)
+		assert.Equal(t, actor.owner, ret.Active.Beneficiary)
+		require.NotNil(t, ret.Proposed)
+		assert.Equal(t, beneficiary, ret.Proposed.NewBeneficiary)
+		assert.Equal(t, quota, ret.Proposed.NewQuota)
+		assert.Equal(t, expiration, ret.Proposed.NewExpiration)
+		assert.True(t, ret.Proposed.ApprovedByBeneficiary) // The owner's term has nothing available.
+		assert.False(t, ret.Proposed.ApprovedByNominee)
+
+		actor.changeBeneficiary(rt, beneficiary, params)
+		ret = actor.getBeneficiary(rt)
+		assert.Equal(t, beneficiary, ret.Active.Beneficiary)
+		assert.Equal(t, quota, ret.Active.Term.Quota)
+		assert.Equal(t, big.Zero(), ret.Active.Term.UsedQuota)
+		assert.Equal(t, expiration, ret.Active.Term.Expiration)
+		assert.Nil(t, ret.Proposed)
+		actor.checkState(rt)
+	})
+
+	t.Run("beneficiary withdraws up to quota", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		setBeneficiary(rt, beneficiary, quota, expiration)
+
+		actor.withdrawFundsAs(rt, beneficiary, abi.NewTokenAmount(40), abi.NewTokenAmount(40), big.Zero())
+		// The owner's withdrawal is also paid to the beneficiary and limited by the quota.
+		actor.withdrawFundsAs(rt, actor.owner, abi.NewTokenAmount(200), abi.NewTokenAmount(60), big.Zero())
+		assert.Equal(t, quota, actor.getInfo(rt).BeneficiaryTerm.UsedQuota)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "quota", func() {
+			actor.withdrawFundsAs(rt, beneficiary, abi.NewTokenAmount(1), big.Zero(), big.Zero())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("beneficiary cannot withdraw after expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		setBeneficiary(rt, beneficiary, quota, expiration)
+
+		rt.SetEpoch(expiration)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "expired", func() {
+			actor.withdrawFundsAs(rt, beneficiary, abi.NewTokenAmount(1), big.Zero(), big.Zero())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("active beneficiary must approve change", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		setBeneficiary(rt, beneficiary, quota, expiration)
+		actor.withdrawFundsAs(rt, beneficiary, abi.NewTokenAmount(40), abi.NewTokenAmount(40), big.Zero())
+
+		params := &miner.ChangeBeneficiaryParams{NewBeneficiary: other, NewQuota: quota, NewExpiration: expiration}
+		actor.changeBeneficiary(rt, actor.owner, params)
+		ret := actor.getBeneficiary(rt)
+		require.NotNil(t, ret.Proposed)
+		assert.False(t, ret.Proposed.ApprovedByBeneficiary)
+
+		actor.changeBeneficiary(rt, other, params)
+		ret = actor.getBeneficiary(rt)
+		assert.Equal(t, beneficiary, ret.Active.Beneficiary)
+		require.NotNil(t, ret.Proposed)
+		assert.True(t, ret.Proposed.ApprovedByNominee)
+
+		actor.changeBeneficiary(rt, beneficiary, params)
+		ret = actor.getBeneficiary(rt)
+		assert.Equal(t, other, ret.Active.Beneficiary)
+		assert.Equal(t, big.Zero(), ret.Active.Term.UsedQuota) // Reset for the new beneficiary
+		assert.Nil(t, ret.Proposed)
+		actor.checkState(rt)
+	})
+
+	t.Run("same beneficiary keeps used quota on new term", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		setBeneficiary(rt, beneficiary, quota, expiration)
+		actor.withdrawFundsAs(rt, beneficiary, abi.NewTokenAmount(40), abi.NewTokenAmount(40), big.Zero())
+
+		newQuota := abi.NewTokenAmount(500)
+		setBeneficiary(rt, beneficiary, newQuota, expiration+1000)
+		term := actor.getInfo(rt).BeneficiaryTerm
+		assert.Equal(t, newQuota, term.Quota)
+		assert.Equal(t, abi.NewTokenAmount(40), term.UsedQuota)
+		assert.Equal(t, expiration+1000, term.Expiration)
+		actor.checkState(rt)
+	})
+
+	t.Run("owner reclaims beneficiary once term expires", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		setBeneficiary(rt, beneficiary, quota, expiration)
+
+		rt.SetEpoch(expiration)
+		actor.changeBeneficiary(rt, actor.owner, &miner.ChangeBeneficiaryParams{NewBeneficiary: actor.owner, NewQuota: big.Zero()})
+		ret := actor.getBeneficiary(rt)
+		assert.Equal(t, actor.owner, ret.Active.Beneficiary)
+		assert.Nil(t, ret.Proposed)
+
+		actor.withdrawFunds(rt, abi.NewTokenAmount(500), abi.NewTokenAmount(500), big.Zero())
+		actor.checkState(rt)
+	})
+
+	t.Run("invalid proposals rejected", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		for _, p := range []*miner.ChangeBeneficiaryParams{
+			{NewBeneficiary: beneficiary, NewQuota: big.Zero(), NewExpiration: expiration},
+			{NewBeneficiary: beneficiary, NewQuota: abi.NewTokenAmount(-1), NewExpiration: expiration},
+			{NewBeneficiary: actor.owner, NewQuota: quota, NewExpiration: 0},
+			{NewBeneficiary: actor.owner, NewQuota: big.Zero(), NewExpiration: expiration},
+		} {
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				actor.changeBeneficiary(rt, actor.owner, p)
+			})
+		}
+		rt.SetCaller(actor.owner, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.ChangeBeneficiary, &miner.ChangeBeneficiaryParams{NewBeneficiary: tutil.NewIDAddr(t, 1234), NewQuota: quota})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("expiration must be after current epoch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetEpoch(expiration)
+		for _, e := range []abi.ChainEpoch{0, expiration - 1, expiration} {
+			rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must be after current epoch", func() {
+				actor.changeBeneficiary(rt, actor.owner, &miner.ChangeBeneficiaryParams{NewBeneficiary: beneficiary, NewQuota: quota, NewExpiration: e})
+			})
+		}
+		assert.Nil(t, actor.getBeneficiary(rt).Proposed)
+
+		actor.changeBeneficiary(rt, actor.owner, &miner.ChangeBeneficiaryParams{NewBeneficiary: beneficiary, NewQuota: quota, NewExpiration: expiration + 1})
+		require.NotNil(t, actor.getBeneficiary(rt).Proposed)
+		actor.checkState(rt)
+	})
+
+	t.Run("approval must match proposal", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		// Nothing to approve.
+		rt.SetCaller(beneficiary, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.a.ChangeBeneficiary, &miner.ChangeBeneficiaryParams{NewBeneficiary: beneficiary, NewQuota: quota, NewExpiration: expiration})
+		})
+
+		actor.changeBeneficiary(rt, actor.owner, &miner.ChangeBeneficiaryParams{NewBeneficiary: beneficiary, NewQuota: quota, NewExpiration: expiration})
+		for _, p := range []*miner.ChangeBeneficiaryParams{
+			{NewBeneficiary: other, NewQuota: quota, NewExpiration: expiration},
+			{NewBeneficiary: beneficiary, NewQuota: big.Add(quota, big.NewInt(1)), NewExpiration: expiration},
+			{NewBeneficiary: beneficiary, NewQuota: quota, NewExpiration: expiration + 1},
+		} {
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				actor.changeBeneficiary(rt, beneficiary, p)
+			})
+		}
+
+		// Only the current beneficiary and the nominee may approve.
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			actor.changeBeneficiary(rt, actor.worker, &miner.ChangeBeneficiaryParams{NewBeneficiary: beneficiary, NewQuota: quota, NewExpiration: expiration})
+		})
+		assert.Equal(t, actor.owner, actor.getInfo(rt).Beneficiary)
+		actor.checkState(rt)
+	})
+
+	t.Run("only owner and beneficiary can withdraw", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		setBeneficiary(rt, beneficiary, quota, expiration)
+
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			actor.withdrawFundsAs(rt, actor.worker, abi.NewTokenAmount(1), abi.NewTokenAmount(1), big.Zero())
+		})
+		rt.Reset()
+		actor.checkState(rt)
+	})
+}
+
 func TestChangeOwnerAddress(t *testing.T) {
 	actor := newHarness(t, 0)
 	builder := builderForHarness(actor).
@@ -2975,6 +4410,9 @@ func TestChangeOwnerAddress(t *testing.T) {
 		info = actor.getInfo(rt)
 		assert.Equal(t, newAddr, info.Owner)
 		assert.Nil(t, info.PendingOwnerAddress)
+		// The beneficiary defaults to the owner and follows it.
+		assert.Equal(t, newAddr, info.Beneficiary)
+		actor.checkState(rt)
 	})
 
 	t.Run("proposed must be valid", func(t *testing.T) {
@@ -3151,6 +4589,32 @@ func TestReportConsensusFault(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("report forwarded by the power actor pays reward to the power actor", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+
+		actor.reportConsensusFault(rt, builtin.StoragePowerActorAddr, &runtime.ConsensusFault{
+			Target: actor.receiver,
+			Epoch:  rt.Epoch() - 1,
+			Type:   runtime.ConsensusFaultDoubleForkMining,
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("report from a non-signable actor other than the power actor rejected", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.a.ReportConsensusFault, &miner.ReportConsensusFaultParams{})
+		})
+		actor.checkState(rt)
+	})
+
 	t.Run("Report consensus fault updates consensus fault reported field", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -3831,6 +5295,33 @@ func (h *actorHarness) confirmUpdateWorkerKey(rt *mock.Runtime) {
 	rt.Verify()
 }
 
+func (h *actorHarness) cancelWorkerKeyChange(rt *mock.Runtime) *miner.CancelWorkerKeyChangeReturn {
+	rt.ExpectValidateCallerAddr(h.owner)
+	rt.SetCaller(h.owner, builtin.AccountActorCodeID)
+	ret := rt.Call(h.a.CancelWorkerKeyChange, nil).(*miner.CancelWorkerKeyChangeReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) changeBeneficiary(rt *mock.Runtime, caller addr.Address, params *miner.ChangeBeneficiaryParams) {
+	info := h.getInfo(rt)
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	if caller == info.Owner {
+		rt.ExpectValidateCallerAddr(info.Owner)
+	} else if info.PendingBeneficiaryTerm != nil {
+		rt.ExpectValidateCallerAddr(info.Beneficiary, info.PendingBeneficiaryTerm.NewBeneficiary)
+	}
+	rt.Call(h.a.ChangeBeneficiary, params)
+	rt.Verify()
+}
+
+func (h *actorHarness) getBeneficiary(rt *mock.Runtime) *miner.GetBeneficiaryReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.GetBeneficiary, nil).(*miner.GetBeneficiaryReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *actorHarness) changeOwnerAddress(rt *mock.Runtime, newAddr addr.Address) {
 	if rt.Caller() == h.owner {
 		rt.ExpectValidateCallerAddr(h.owner)
@@ -3855,6 +5346,49 @@ func (h *actorHarness) checkSectorProven(rt *mock.Runtime, sectorNum abi.SectorN
 	rt.Verify()
 }
 
+func (h *actorHarness) getSectorInfo(rt *mock.Runtime, sectorNum abi.SectorNumber) *miner.SectorOnChainInfo {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.GetSectorInfo, &miner.GetSectorInfoParams{SectorNumber: sectorNum}).(*miner.SectorOnChainInfo)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) getAvailableBalance(rt *mock.Runtime) abi.TokenAmount {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.GetAvailableBalance, nil).(*abi.TokenAmount)
+	rt.Verify()
+	return *ret
+}
+
+func (h *actorHarness) getVestingFunds(rt *mock.Runtime) *miner.VestingFunds {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.GetVestingFunds, nil).(*miner.VestingFunds)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) getDeadlineInfo(rt *mock.Runtime) *miner.GetDeadlineInfoReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.GetDeadlineInfo, nil).(*miner.GetDeadlineInfoReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) isSectorActive(rt *mock.Runtime, sectorNum abi.SectorNumber) bool {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.IsSectorActive, &miner.IsSectorActiveParams{SectorNumber: sectorNum}).(*miner.IsSectorActiveReturn)
+	rt.Verify()
+	return ret.Active
+}
+
+func (h *actorHarness) previewTerminationFees(rt *mock.Runtime, sectors bitfield.BitField) *miner.TerminationFeePreview {
+	rt.ExpectValidateCallerAny()
+	expectQueryNetworkInfo(rt, h)
+	ret := rt.Call(h.a.PreviewTerminationFees, &miner.PreviewTerminationFeesParams{Sectors: sectors}).(*miner.TerminationFeePreview)
+	rt.Verify()
+	return ret
+}
+
 func (h *actorHarness) changeMultiAddrs(rt *mock.Runtime, newAddrs []abi.Multiaddrs) {
 	param := &miner.ChangeMultiaddrsParams{NewMultiaddrs: newAddrs}
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -3950,6 +5484,23 @@ func (h *actorHarness) preCommitSector(rt *mock.Runtime, params *miner.PreCommit
 	return h.getPreCommit(rt, params.SectorNumber)
 }
 
+func (h *actorHarness) extendPreCommits(rt *mock.Runtime, sectors bitfield.BitField, extension abi.ChainEpoch) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+	expectQueryNetworkInfo(rt, h)
+
+	st := getState(rt)
+	if st.FeeDebt.GreaterThan(big.Zero()) {
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, st.FeeDebt, nil, exitcode.Ok)
+	}
+
+	rt.Call(h.a.ExtendPreCommits, &miner.ExtendPreCommitsParams{
+		Sectors:   sectors,
+		Extension: extension,
+	})
+	rt.Verify()
+}
+
 type preCommitBatchConf struct {
 	// Weights to be returned from the market actor for sectors 0..len(sectorWeights).
 	// Any remaining sectors are taken to have zero deal weight.
@@ -4024,7 +5575,8 @@ func (h *actorHarness) preCommitSectorBatch(rt *mock.Runtime, params *miner.PreC
 // Options for proveCommitSector behaviour.
 // Default zero values should let everything be ok.
 type proveCommitConf struct {
-	verifyDealsExit map[abi.SectorNumber]exitcode.ExitCode
+	// Sectors whose deals fail to activate, expected to be dropped.
+	failedDealActivations map[abi.SectorNumber]bool
 }
 
 func (h *actorHarness) proveCommitSector(rt *mock.Runtime, precommit *miner.SectorPreCommitOnChainInfo, params *miner.ProveCommitSectorParams) {
@@ -4159,25 +5711,148 @@ func (h *actorHarness) proveCommitAggregateSector(rt *mock.Runtime, conf proveCo
 	rt.Verify()
 }
 
+// Options for proveCommitSectorsNI behaviour.
+// Default zero values should let everything be ok.
+type proveCommitNIConf struct {
+	// Weights to be returned from the market actor for sectors 0..len(sectorWeights).
+	// Any remaining sectors are taken to have zero deal weight.
+	sectorWeights []market.SectorWeights
+	// Sectors whose deals fail to activate, expected to be dropped.
+	failedDealActivations map[abi.SectorNumber]bool
+	// Set if this is the first commitment by this miner, hence should expect scheduling end-of-deadline cron.
+	firstForMiner bool
+}
+
+func (h *actorHarness) proveCommitSectorsNI(rt *mock.Runtime, params *miner.ProveCommitSectorsNIParams, conf proveCommitNIConf, baseFee abi.TokenAmount) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	// Receive call to VerifyDealsForActivation
+	sectorDeals := make([]market.SectorDeals, len(params.Sectors))
+	sectorWeights := make([]market.SectorWeights, len(params.Sectors))
+	anyDeals := false
+	for i, sector := range params.Sectors {
+		sectorDeals[i] = market.SectorDeals{
+			SectorExpiry: sector.Expiration,
+			DealIDs:      sector.DealIDs,
+		}
+		if len(conf.sectorWeights) > i {
+			sectorWeights[i] = conf.sectorWeights[i]
+		} else {
+			sectorWeights[i] = market.SectorWeights{
+				DealSpace:          0,
+				DealWeight:         big.Zero(),
+				VerifiedDealWeight: big.Zero(),
+			}
+		}
+		anyDeals = anyDeals || len(sector.DealIDs) > 0
+	}
+	if anyDeals {
+		vdParams := market.VerifyDealsForActivationParams{Sectors: sectorDeals}
+		vdReturn := market.VerifyDealsForActivationReturn{Sectors: sectorWeights}
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.VerifyDealsForActivation, &vdParams, big.Zero(), &vdReturn, exitcode.Ok)
+	}
+
+	// Receive call to ComputeDataCommitment
+	commDs := make([]cbg.CborCid, len(params.Sectors))
+	{
+		cdcInputs := make([]*market.SectorDataSpec, len(params.Sectors))
+		for i, sector := range params.Sectors {
+			cdcInputs[i] = &market.SectorDataSpec{
+				DealIDs:    sector.DealIDs,
+				SectorType: params.SealProof,
+			}
+			commDs[i] = cbg.CborCid(tutil.MakeCID(fmt.Sprintf("commd-%d", i), &market.PieceCIDPrefix))
+		}
+		cdcParams := market.ComputeDataCommitmentParams{Inputs: cdcInputs}
+		cdcRet := market.ComputeDataCommitmentReturn{CommDs: commDs}
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ComputeDataCommitment, &cdcParams, big.Zero(), &cdcRet, exitcode.Ok)
+	}
+
+	// Expect randomness queries and the verify syscall, with no interactive randomness
+	{
+		var buf bytes.Buffer
+		receiver := rt.Receiver()
+		require.NoError(h.t, receiver.MarshalCBOR(&buf))
+		svis := make([]proof.NonInteractiveSealVerifyInfo, len(params.Sectors))
+		for i, sector := range params.Sectors {
+			sealRand := abi.SealRandomness([]byte{1, 2, 3, byte(i)})
+			rt.ExpectGetRandomnessTickets(crypto.DomainSeparationTag_SealRandomness, sector.SealRandEpoch, buf.Bytes(), abi.Randomness(sealRand))
+			svis[i] = proof.NonInteractiveSealVerifyInfo{
+				Number:      sector.SectorNumber,
+				Randomness:  sealRand,
+				SealedCID:   sector.SealedCID,
+				UnsealedCID: cid.Cid(commDs[i]),
+			}
+		}
+		actorId, err := addr.IDFromAddress(h.receiver)
+		require.NoError(h.t, err)
+		rt.ExpectAggregateVerifyNonInteractiveSeals(proof.AggregateNonInteractiveSealVerifyProofAndInfos{
+			Miner:          abi.ActorID(actorId),
+			SealProof:      params.SealProof,
+			AggregateProof: abi.RegisteredAggregationProof_SnarkPackV1,
+			Proof:          params.AggregateProof,
+			Infos:          svis,
+		}, nil)
+	}
+	expectQueryNetworkInfo(rt, h)
+
+	// Activate deals and expect pledge for sectors with valid deals
+	var dealSectorNos []abi.SectorNumber
+	var sectorsDeals []market.SectorDeals
+	for _, sector := range params.Sectors {
+		if len(sector.DealIDs) > 0 {
+			dealSectorNos = append(dealSectorNos, sector.SectorNumber)
+			sectorsDeals = append(sectorsDeals, market.SectorDeals{DealIDs: sector.DealIDs, SectorExpiry: sector.Expiration})
+		}
+	}
+	expectActivateDealsBatch(rt, dealSectorNos, sectorsDeals, conf.failedDealActivations)
+	expectPledge := big.Zero()
+	for i, sector := range params.Sectors {
+		if conf.failedDealActivations[sector.SectorNumber] {
+			continue
+		}
+		qaPower := miner.QAPowerForWeight(h.sectorSize, sector.Expiration-rt.Epoch(), sectorWeights[i].DealWeight, sectorWeights[i].VerifiedDealWeight)
+		pledge := miner.InitialPledgeForPower(qaPower, h.baselinePower, h.epochRewardSmooth, h.epochQAPowerSmooth, rt.TotalFilCircSupply())
+		expectPledge = big.Add(expectPledge, pledge)
+	}
+	if !expectPledge.IsZero() {
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &expectPledge, big.Zero(), nil, exitcode.Ok)
+	}
+
+	// burn network fee for activated sectors along with any fee debt
+	st := getState(rt)
+	activatedCount := len(params.Sectors) - len(conf.failedDealActivations)
+	expectedBurn := big.Add(miner.AggregateProveCommitNetworkFee(activatedCount, baseFee), st.FeeDebt)
+	if expectedBurn.GreaterThan(big.Zero()) {
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedBurn, nil, exitcode.Ok)
+	}
+
+	if conf.firstForMiner {
+		dlInfo := miner.NewDeadlineInfoFromOffsetAndEpoch(st.ProvingPeriodStart, rt.Epoch())
+		cronParams := makeDeadlineCronEventParams(h.t, dlInfo.Last())
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.EnrollCronEvent, cronParams, big.Zero(), nil, exitcode.Ok)
+	}
+
+	rt.Call(h.a.ProveCommitSectorsNI, params)
+	rt.Verify()
+}
+
 func (h *actorHarness) confirmSectorProofsValidInternal(rt *mock.Runtime, conf proveCommitConf, precommits ...*miner.SectorPreCommitOnChainInfo) {
 	// Prepare for and receive call to ConfirmSectorProofsValid.
 	var validPrecommits []*miner.SectorPreCommitOnChainInfo
+	var dealSectorNos []abi.SectorNumber
+	var sectorsDeals []market.SectorDeals
 	for _, precommit := range precommits {
-		validPrecommits = append(validPrecommits, precommit)
 		if len(precommit.Info.DealIDs) > 0 {
-			vdParams := market.ActivateDealsParams{
-				DealIDs:      precommit.Info.DealIDs,
-				SectorExpiry: precommit.Info.Expiration,
-			}
-			exit, found := conf.verifyDealsExit[precommit.Info.SectorNumber]
-			if found {
-				validPrecommits = validPrecommits[:len(validPrecommits)-1] // pop
-			} else {
-				exit = exitcode.Ok
-			}
-			rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ActivateDeals, &vdParams, big.Zero(), nil, exit)
+			dealSectorNos = append(dealSectorNos, precommit.Info.SectorNumber)
+			sectorsDeals = append(sectorsDeals, market.SectorDeals{DealIDs: precommit.Info.DealIDs, SectorExpiry: precommit.Info.Expiration})
+		}
+		if !conf.failedDealActivations[precommit.Info.SectorNumber] {
+			validPrecommits = append(validPrecommits, precommit)
 		}
 	}
+	expectActivateDealsBatch(rt, dealSectorNos, sectorsDeals, conf.failedDealActivations)
 
 	// expected pledge is the sum of initial pledges
 	if len(validPrecommits) > 0 {
@@ -4212,6 +5887,35 @@ func (h *actorHarness) confirmSectorProofsValidInternal(rt *mock.Runtime, conf p
 	}
 }
 
+// Expects a single request to activate the deals of the given sectors, in which the deals of the failed sectors
+// are not activated.
+func expectActivateDealsBatch(rt *mock.Runtime, sectorNos []abi.SectorNumber, sectors []market.SectorDeals, failed map[abi.SectorNumber]bool) {
+	if len(sectors) == 0 {
+		return
+	}
+	ret := market.ActivateDealsBatchReturn{Sectors: make([]market.SectorDealActivation, len(sectors))}
+	for i, sector := range sectors {
+		activation := market.SectorDealActivation{
+			Activated:          !failed[sectorNos[i]],
+			DealWeight:         big.Zero(),
+			VerifiedDealWeight: big.Zero(),
+		}
+		for j, dealID := range sector.DealIDs {
+			outcome := market.DealActivated
+			if !activation.Activated {
+				outcome = market.DealActivationSkipped
+				if j == 0 {
+					outcome = market.DealActivationInvalid
+				}
+			}
+			activation.Deals = append(activation.Deals, market.DealActivationResult{DealID: dealID, Outcome: outcome})
+		}
+		ret.Sectors[i] = activation
+	}
+	rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ActivateDealsBatch,
+		&market.ActivateDealsBatchParams{Sectors: sectors}, big.Zero(), &ret, exitcode.Ok)
+}
+
 func (h *actorHarness) confirmSectorProofsValid(rt *mock.Runtime, conf proveCommitConf, precommits ...*miner.SectorPreCommitOnChainInfo) {
 	h.confirmSectorProofsValidInternal(rt, conf, precommits...)
 	var allSectorNumbers []abi.SectorNumber
@@ -4390,18 +6094,28 @@ func (h *actorHarness) disputeWindowPoSt(rt *mock.Runtime, deadline *dline.Info,
 		}
 	}
 
-	vi := proof.WindowPoStVerifyInfo{
-		Randomness:        abi.PoStRandomness(challengeRand),
-		Proofs:            post.Proofs,
-		ChallengedSectors: proofInfos,
-		Prover:            abi.ActorID(actorId),
-	}
 	var verifResult error
 	if expectSuccess != nil {
 		// if we succeed at challenging, proof verification needs to fail.
 		verifResult = fmt.Errorf("invalid post")
 	}
-	rt.ExpectVerifyPoSt(vi, verifResult)
+	if post.Aggregated {
+		require.Len(h.t, post.Proofs, 1)
+		rt.ExpectVerifyAggregatePoSt(proof.AggregateWindowPoStVerifyInfo{
+			Randomness:        abi.PoStRandomness(challengeRand),
+			AggregateProof:    abi.RegisteredAggregationProof_SnarkPackV1,
+			Proof:             post.Proofs[0],
+			ChallengedSectors: proofInfos,
+			Prover:            abi.ActorID(actorId),
+		}, verifResult)
+	} else {
+		rt.ExpectVerifyPoSt(proof.WindowPoStVerifyInfo{
+			Randomness:        abi.PoStRandomness(challengeRand),
+			Proofs:            post.Proofs,
+			ChallengedSectors: proofInfos,
+			Prover:            abi.ActorID(actorId),
+		}, verifResult)
+	}
 
 	if expectSuccess != nil {
 		// expect power update
@@ -4446,9 +6160,11 @@ type poStConfig struct {
 	chainRandomness    abi.Randomness
 	expectedPowerDelta miner.PowerPair
 	verificationError  error
+	// Submit the proof as an aggregate with SubmitWindowedPoStAggregate.
+	aggregate bool
 }
 
-func (h *actorHarness) submitWindowPoSt(rt *mock.Runtime, deadline *dline.Info, partitions []miner.PoStPartition, infos []*miner.SectorOnChainInfo, poStCfg *poStConfig) {
+func (h *actorHarness) submitWindowPoSt(rt *mock.Runtime, deadline *dline.Info, partitions []miner.PoStPartition, infos []*miner.SectorOnChainInfo, poStCfg *poStConfig) *miner.SubmitWindowedPoStReturn {
 	params := miner.SubmitWindowedPoStParams{
 		Deadline:         deadline.Index,
 		Partitions:       partitions,
@@ -4456,11 +6172,11 @@ func (h *actorHarness) submitWindowPoSt(rt *mock.Runtime, deadline *dline.Info,
 		ChainCommitEpoch: deadline.Challenge,
 		ChainCommitRand:  abi.Randomness("chaincommitment"),
 	}
-	h.submitWindowPoStRaw(rt, deadline, infos, &params, poStCfg)
+	return h.submitWindowPoStRaw(rt, deadline, infos, &params, poStCfg)
 }
 
 func (h *actorHarness) submitWindowPoStRaw(rt *mock.Runtime, deadline *dline.Info,
-	infos []*miner.SectorOnChainInfo, params *miner.SubmitWindowedPoStParams, poStCfg *poStConfig) {
+	infos []*miner.SectorOnChainInfo, params *miner.SubmitWindowedPoStParams, poStCfg *poStConfig) *miner.SubmitWindowedPoStReturn {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	chainCommitRand := params.ChainCommitRand
 	if poStCfg != nil && len(poStCfg.chainRandomness) > 0 {
@@ -4530,17 +6246,26 @@ func (h *actorHarness) submitWindowPoStRaw(rt *mock.Runtime, deadline *dline.Inf
 			}
 		}
 
-		vi := proof.WindowPoStVerifyInfo{
-			Randomness:        abi.PoStRandomness(challengeRand),
-			Proofs:            params.Proofs,
-			ChallengedSectors: proofInfos,
-			Prover:            abi.ActorID(actorId),
-		}
 		var verifResult error
 		if poStCfg != nil {
 			verifResult = poStCfg.verificationError
 		}
-		rt.ExpectVerifyPoSt(vi, verifResult)
+		if poStCfg != nil && poStCfg.aggregate {
+			rt.ExpectVerifyAggregatePoSt(proof.AggregateWindowPoStVerifyInfo{
+				Randomness:        abi.PoStRandomness(challengeRand),
+				AggregateProof:    abi.RegisteredAggregationProof_SnarkPackV1,
+				Proof:             params.Proofs[0],
+				ChallengedSectors: proofInfos,
+				Prover:            abi.ActorID(actorId),
+			}, verifResult)
+		} else {
+			rt.ExpectVerifyPoSt(proof.WindowPoStVerifyInfo{
+				Randomness:        abi.PoStRandomness(challengeRand),
+				Proofs:            params.Proofs,
+				ChallengedSectors: proofInfos,
+				Prover:            abi.ActorID(actorId),
+			}, verifResult)
+		}
 	}
 
 	if poStCfg != nil {
@@ -4555,8 +6280,25 @@ func (h *actorHarness) submitWindowPoStRaw(rt *mock.Runtime, deadline *dline.Inf
 		}
 	}
 
-	rt.Call(h.a.SubmitWindowedPoSt, params)
+	var ret *miner.SubmitWindowedPoStReturn
+	if poStCfg != nil && poStCfg.aggregate {
+		ret = rt.Call(h.a.SubmitWindowedPoStAggregate, &miner.SubmitWindowedPoStAggregateParams{
+			Deadline:         params.Deadline,
+			Partitions:       params.Partitions,
+			AggregateProof:   params.Proofs[0],
+			ChainCommitEpoch: params.ChainCommitEpoch,
+			ChainCommitRand:  params.ChainCommitRand,
+		}).(*miner.SubmitWindowedPoStReturn)
+	} else {
+		ret = rt.Call(h.a.SubmitWindowedPoSt, params).(*miner.SubmitWindowedPoStReturn)
+	}
 	rt.Verify()
+
+	if poStCfg != nil && !poStCfg.expectedPowerDelta.Raw.Nil() {
+		assert.True(h.t, poStCfg.expectedPowerDelta.Equals(ret.PowerDelta),
+			"returned power delta %v, expected %v", ret.PowerDelta, poStCfg.expectedPowerDelta)
+	}
+	return ret
 }
 
 func (h *actorHarness) declareFaults(rt *mock.Runtime, faultSectorInfos ...*miner.SectorOnChainInfo) miner.PowerPair {
@@ -4592,6 +6334,28 @@ func (h *actorHarness) declareFaults(rt *mock.Runtime, faultSectorInfos ...*mine
 	return miner.NewPowerPair(claim.RawByteDelta, claim.QualityAdjustedDelta)
 }
 
+func (h *actorHarness) declareFaultsWithRecoveryEpoch(rt *mock.Runtime, recoveryEpoch abi.ChainEpoch, faultSectorInfos ...*miner.SectorOnChainInfo) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	ss, err := faultSectorInfos[0].SealProof.SectorSize()
+	require.NoError(h.t, err)
+	expectedRawDelta, expectedQADelta := powerForSectors(ss, faultSectorInfos)
+	claim := &power.UpdateClaimedPowerParams{
+		RawByteDelta:         expectedRawDelta.Neg(),
+		QualityAdjustedDelta: expectedQADelta.Neg(),
+	}
+	rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdateClaimedPower, claim, abi.NewTokenAmount(0), nil, exitcode.Ok)
+
+	st := getState(rt)
+	faultParams := makeFaultParamsFromFaultingSectors(h.t, st, rt.AdtStore(), faultSectorInfos)
+	rt.Call(h.a.DeclareFaultsWithRecoveryEpoch, &miner.DeclareFaultsWithRecoveryEpochParams{
+		Faults:        faultParams.Faults,
+		RecoveryEpoch: recoveryEpoch,
+	})
+	rt.Verify()
+}
+
 func (h *actorHarness) declareRecoveries(rt *mock.Runtime, deadlineIdx uint64, partitionIdx uint64, recoverySectors bitfield.BitField, expectedDebtRepaid abi.TokenAmount) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4645,6 +6409,38 @@ func (h *actorHarness) extendSectors(rt *mock.Runtime, params *miner.ExtendSecto
 	rt.Verify()
 }
 
+func (h *actorHarness) extendSectors2(rt *mock.Runtime, params *miner.ExtendSectorExpiration2Params) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	qaDelta := big.Zero()
+	for _, extension := range params.Extensions {
+		for _, se := range extension.Sectors {
+			sector := h.getSector(rt, se.SectorNumber)
+			newSector := *sector
+			newSector.Expiration = se.NewExpiration
+			qaDelta = big.Sum(qaDelta,
+				miner.QAPowerForSector(h.sectorSize, &newSector),
+				miner.QAPowerForSector(h.sectorSize, sector).Neg(),
+			)
+		}
+	}
+	if !qaDelta.IsZero() {
+		rt.ExpectSend(builtin.StoragePowerActorAddr,
+			builtin.MethodsPower.UpdateClaimedPower,
+			&power.UpdateClaimedPowerParams{
+				RawByteDelta:         big.Zero(),
+				QualityAdjustedDelta: qaDelta,
+			},
+			abi.NewTokenAmount(0),
+			nil,
+			exitcode.Ok,
+		)
+	}
+	rt.Call(h.a.ExtendSectorExpiration2, params)
+	rt.Verify()
+}
+
 func (h *actorHarness) terminateSectors(rt *mock.Runtime, sectors bitfield.BitField, expectedFee abi.TokenAmount) (miner.PowerPair, abi.TokenAmount) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4723,8 +6519,13 @@ func (h *actorHarness) terminateSectors(rt *mock.Runtime, sectors bitfield.BitFi
 }
 
 func (h *actorHarness) reportConsensusFault(rt *mock.Runtime, from addr.Address, fault *runtime.ConsensusFault) {
-	rt.SetCaller(from, builtin.AccountActorCodeID)
-	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	if from == builtin.StoragePowerActorAddr {
+		rt.SetCaller(from, builtin.StoragePowerActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StoragePowerActorAddr)
+	} else {
+		rt.SetCaller(from, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	}
 	params := &miner.ReportConsensusFaultParams{
 		BlockHeader1:     nil,
 		BlockHeader2:     nil,
@@ -4880,10 +6681,17 @@ func (h *actorHarness) onDeadlineCron(rt *mock.Runtime, config *cronConfig) {
 }
 
 func (h *actorHarness) withdrawFunds(rt *mock.Runtime, amountRequested, expectedWithdrawn, expectedDebtRepaid abi.TokenAmount) {
-	rt.SetCaller(h.owner, builtin.AccountActorCodeID)
-	rt.ExpectValidateCallerAddr(h.owner)
+	h.withdrawFundsAs(rt, h.owner, amountRequested, expectedWithdrawn, expectedDebtRepaid)
+}
+
+func (h *actorHarness) withdrawFundsAs(rt *mock.Runtime, caller addr.Address, amountRequested, expectedWithdrawn, expectedDebtRepaid abi.TokenAmount) {
+	info := h.getInfo(rt)
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.owner, info.Beneficiary)
 
-	rt.ExpectSend(h.owner, builtin.MethodSend, nil, expectedWithdrawn, nil, exitcode.Ok)
+	if expectedWithdrawn.GreaterThan(big.Zero()) {
+		rt.ExpectSend(info.Beneficiary, builtin.MethodSend, nil, expectedWithdrawn, nil, exitcode.Ok)
+	}
 	if expectedDebtRepaid.GreaterThan(big.Zero()) {
 		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedDebtRepaid, nil, exitcode.Ok)
 	}
@@ -4899,6 +6707,14 @@ func (h *actorHarness) withdrawFunds(rt *mock.Runtime, amountRequested, expected
 	assert.Equal(h.t, expectedWithdrawn, *withdrawn, "return value indicates %s withdrawn but expected %s", *withdrawn, expectedWithdrawn)
 }
 
+func (h *actorHarness) changeDebtRepaymentPolicy(rt *mock.Runtime, caller addr.Address, policy *miner.DebtRepaymentPolicy) {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.owner)
+	ret := rt.Call(h.a.ChangeDebtRepaymentPolicy, policy)
+	assert.Nil(h.t, ret)
+	rt.Verify()
+}
+
 func (h *actorHarness) repayDebt(rt *mock.Runtime, value, expectedRepayedFromVest, expectedRepaidFromBalance abi.TokenAmount) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4929,6 +6745,16 @@ func (h *actorHarness) compactPartitions(rt *mock.Runtime, deadline uint64, part
 	rt.Verify()
 }
 
+func (h *actorHarness) movePartitions(rt *mock.Runtime, origDeadline, destDeadline uint64, partitions bitfield.BitField) {
+	param := miner.MovePartitionsParams{OrigDeadline: origDeadline, DestDeadline: destDeadline, Partitions: partitions}
+
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+
+	rt.Call(h.a.MovePartitions, &param)
+	rt.Verify()
+}
+
 func (h *actorHarness) continuedFaultPenalty(sectors []*miner.SectorOnChainInfo) abi.TokenAmount {
 	_, qa := powerForSectors(h.sectorSize, sectors)
 	return miner.PledgePenaltyForContinuedFault(h.epochRewardSmooth, h.epochQAPowerSmooth, qa)
@@ -5184,6 +7010,23 @@ func makeProveCommitAggregate(sectorNos bitfield.BitField) *miner.ProveCommitAgg
 	}
 }
 
+func (h *actorHarness) makeProveCommitSectorsNI(firstSectorNo abi.SectorNumber, count int, challenge, expiration abi.ChainEpoch) *miner.ProveCommitSectorsNIParams {
+	sectors := make([]miner.SectorNIActivationInfo, count)
+	for i := range sectors {
+		sectors[i] = miner.SectorNIActivationInfo{
+			SectorNumber:  firstSectorNo + abi.SectorNumber(i),
+			SealedCID:     tutil.MakeCID(fmt.Sprintf("commr-%d", i), &miner.SealedCIDPrefix),
+			SealRandEpoch: challenge,
+			Expiration:    expiration,
+		}
+	}
+	return &miner.ProveCommitSectorsNIParams{
+		Sectors:        sectors,
+		SealProof:      h.sealProofType,
+		AggregateProof: make([]byte, 1024),
+	}
+}
+
 func makePoStProofs(registeredPoStProof abi.RegisteredPoStProof) []proof.PoStProof {
 	proofs := make([]proof.PoStProof, 1) // Number of proofs doesn't depend on partition count
 	for i := range proofs {
@@ -5292,3 +7135,19 @@ func expectQueryNetworkInfo(rt *mock.Runtime, h *actorHarness) {
 		exitcode.Ok,
 	)
 }
+
+// Checks the sector events emitted since the last check, and clears them.
+func assertSectorEvents(t *testing.T, rt *mock.Runtime, expected ...*miner.SectorEvent) {
+	events := rt.Events()
+	require.Len(t, events, len(expected))
+	for i, e := range expected {
+		actual, ok := events[i].(*miner.SectorEvent)
+		require.True(t, ok, "event %d is not a sector event", i)
+		assert.Equal(t, e.Type, actual.Type, "event %d type", i)
+		assert.Equal(t, e.Deadline, actual.Deadline, "event %d deadline", i)
+		assert.Equal(t, e.Partition, actual.Partition, "event %d partition", i)
+		assertBitfieldsEqual(t, e.Sectors, actual.Sectors)
+		assert.True(t, e.PowerDelta.Equals(actual.PowerDelta), "event %d power delta %v, expected %v", i, actual.PowerDelta, e.PowerDelta)
+	}
+	rt.ClearEvents()
+}
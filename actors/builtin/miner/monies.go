@@ -139,6 +139,15 @@ func PledgePenaltyForTermination(dayReward abi.TokenAmount, sectorAge abi.ChainE
 				big.Mul(big.NewInt(builtin.EpochsInDay), TerminationRewardFactor.Denominator)))) // (epochs*AttoFIL/day -> AttoFIL)
 }
 
+// The termination penalty for a sector terminated at the given epoch, computed from its on-chain info.
+// This is the amount charged for each sector when early terminations are processed.
+func TerminationPenaltyForSector(sectorSize abi.SectorSize, terminationEpoch abi.ChainEpoch,
+	rewardEstimate, networkQAPowerEstimate smoothing.FilterEstimate, sector *SectorOnChainInfo) abi.TokenAmount {
+	sectorPower := QAPowerForSector(sectorSize, sector)
+	return PledgePenaltyForTermination(sector.ExpectedDayReward, terminationEpoch-sector.Activation, sector.ExpectedStoragePledge,
+		networkQAPowerEstimate, sectorPower, rewardEstimate, sector.ReplacedDayReward, sector.ReplacedSectorAge)
+}
+
 // The penalty for optimistically proving a sector with an invalid window PoSt.
 func PledgePenaltyForInvalidWindowPoSt(rewardEstimate, networkQAPowerEstimate smoothing.FilterEstimate, qaSectorPower abi.StoragePower) abi.TokenAmount {
 	return big.Add(
@@ -153,6 +162,15 @@ func PreCommitDepositForPower(rewardEstimate, networkQAPowerEstimate smoothing.F
 	return ExpectedRewardForPowerClampedAtAttoFIL(rewardEstimate, networkQAPowerEstimate, qaSectorPower, PreCommitDepositProjectionPeriod)
 }
 
+// Computes the additional deposit required to extend a pre-commitment's prove-commit deadline.
+// The pre-commit deposit for the sector under current network conditions is charged in proportion
+// to the length of the extension relative to the maximum prove-commit duration.
+func PreCommitExtensionDeposit(rewardEstimate, networkQAPowerEstimate smoothing.FilterEstimate, qaSectorPower abi.StoragePower,
+	extension, maxProveCommitDuration abi.ChainEpoch) abi.TokenAmount {
+	deposit := PreCommitDepositForPower(rewardEstimate, networkQAPowerEstimate, qaSectorPower)
+	return big.Div(big.Mul(deposit, big.NewInt(int64(extension))), big.NewInt(int64(maxProveCommitDuration)))
+}
+
 // Computes the pledge requirement for committing new quality-adjusted power to the network, given the current
 // network total and baseline power, per-epoch  reward, and circulating token supply.
 // The pledge comprises two parts:
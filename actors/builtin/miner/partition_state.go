@@ -464,6 +464,121 @@ func (p *Partition) ReplaceSectors(store adt.Store, oldSectors, newSectors []*Se
 	return powerDelta, pledgeDelta, nil
 }
 
+// Re-quantizes the partition's expiration queue for a move to another deadline, returning the (new) epochs
+// at which sectors are queued to expire.
+// On-time expirations are re-scheduled from the sectors' committed expirations. Faulty sectors due to expire early
+// are re-scheduled at their fault expiration, quantized upwards, so a fault may last up to one proving period longer.
+// Faults, recoveries, unproven sectors, early terminations and power are unchanged.
+func (p *Partition) requantizeExpirations(store adt.Store, sectors Sectors, ssize abi.SectorSize,
+	origQuant, destQuant builtin.QuantSpec) ([]abi.ChainEpoch, error) {
+	origExpirations, err := LoadExpirationQueue(store, p.ExpirationsEpochs, origQuant, PartitionExpirationAmtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load sector expirations: %w", err)
+	}
+	type earlyExpiration struct {
+		epoch   abi.ChainEpoch
+		sectors bitfield.BitField
+	}
+	var earlyExpirations []earlyExpiration
+	if err = origExpirations.traverse(func(epoch abi.ChainEpoch, es *ExpirationSet) (bool, error) {
+		if empty, err := es.EarlySectors.IsEmpty(); err != nil {
+			return false, err
+		} else if !empty {
+			earlyExpirations = append(earlyExpirations, earlyExpiration{epoch, es.EarlySectors})
+		}
+		return true, nil
+	}); err != nil {
+		return nil, xerrors.Errorf("failed to traverse sector expirations: %w", err)
+	}
+
+	liveSectors, err := p.LiveSectors()
+	if err != nil {
+		return nil, err
+	}
+	liveInfos, err := sectors.Load(liveSectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load live sectors: %w", err)
+	}
+	infosByNumber := make(map[uint64]*SectorOnChainInfo, len(liveInfos))
+	for _, info := range liveInfos {
+		infosByNumber[uint64(info.SectorNumber)] = info
+	}
+	selectInfos := func(sectorNos bitfield.BitField) ([]*SectorOnChainInfo, error) {
+		var infos []*SectorOnChainInfo
+		err := sectorNos.ForEach(func(sno uint64) error {
+			info, ok := infosByNumber[sno]
+			if !ok {
+				return xerrors.Errorf("sector %d is not live", sno)
+			}
+			infos = append(infos, info)
+			return nil
+		})
+		return infos, err
+	}
+
+	emptyRoot, err := adt.StoreEmptyArray(store, PartitionExpirationAmtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty expiration queue: %w", err)
+	}
+	expirations, err := LoadExpirationQueue(store, emptyRoot, destQuant, PartitionExpirationAmtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load sector expirations: %w", err)
+	}
+
+	// Schedule all live sectors as active, then re-schedule faulty sectors as they were.
+	if _, _, _, err = expirations.AddActiveSectors(liveInfos, ssize); err != nil {
+		return nil, xerrors.Errorf("failed to add sector expirations: %w", err)
+	}
+	earlyFaults := make([]bitfield.BitField, 0, len(earlyExpirations))
+	for _, early := range earlyExpirations {
+		infos, err := selectInfos(early.sectors)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = expirations.RescheduleAsFaults(early.epoch, infos, ssize); err != nil {
+			return nil, xerrors.Errorf("failed to reschedule early sector expirations: %w", err)
+		}
+		earlyFaults = append(earlyFaults, early.sectors)
+	}
+	// Faulty sectors due to expire on-time before their fault expiration remain on-time.
+	allEarlyFaults, err := bitfield.MultiMerge(earlyFaults...)
+	if err != nil {
+		return nil, err
+	}
+	onTimeFaults, err := bitfield.SubtractBitField(p.Faults, allEarlyFaults)
+	if err != nil {
+		return nil, err
+	}
+	onTimeFaultInfos, err := selectInfos(onTimeFaults)
+	if err != nil {
+		return nil, err
+	}
+	if len(onTimeFaultInfos) > 0 {
+		lastExpiration := abi.ChainEpoch(0)
+		for _, info := range onTimeFaultInfos {
+			if info.Expiration > lastExpiration {
+				lastExpiration = info.Expiration
+			}
+		}
+		if _, err = expirations.RescheduleAsFaults(lastExpiration, onTimeFaultInfos, ssize); err != nil {
+			return nil, xerrors.Errorf("failed to reschedule on-time faulty sector expirations: %w", err)
+		}
+	}
+
+	if p.ExpirationsEpochs, err = expirations.Root(); err != nil {
+		return nil, xerrors.Errorf("failed to save sector expirations: %w", err)
+	}
+
+	var epochs []abi.ChainEpoch
+	if err = expirations.traverse(func(epoch abi.ChainEpoch, _ *ExpirationSet) (bool, error) {
+		epochs = append(epochs, epoch)
+		return true, nil
+	}); err != nil {
+		return nil, xerrors.Errorf("failed to traverse sector expirations: %w", err)
+	}
+	return epochs, nil
+}
+
 // Record the epoch of any sectors expiring early, for termination fee calculation later.
 func (p *Partition) recordEarlyTermination(store adt.Store, epoch abi.ChainEpoch, sectors bitfield.BitField) error {
 	etQueue, err := LoadBitfieldQueue(store, p.EarlyTerminated, builtin.NoQuantization, PartitionEarlyTerminationArrayAmtBitwidth)
@@ -2,6 +2,7 @@ package miner
 
 import (
 	"fmt"
+	"math/bits"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
@@ -83,6 +84,14 @@ const DeclarationsMax = AddressedPartitionsMax
 // This limits the amount of state to be read in a single message execution.
 const AddressedSectorsMax = 25_000 // PARAM_SPEC
 
+// The maximum number of trailing partitions of a deadline inspected, and so removed if fully terminated,
+// by the background compaction performed at the end of each deadline.
+const CronCompactionPartitionsMax = 4 // PARAM_SPEC
+
+// The maximum number of terminated sector infos deleted by the background compaction performed at the end
+// of each deadline.
+const CronCompactionSectorsMax = 10_000 // PARAM_SPEC
+
 // Libp2p peer info limits.
 const (
 	// MaxPeerIDLength is the maximum length allowed for any on-chain peer ID.
@@ -167,6 +176,9 @@ var MaxProveCommitDuration = map[abi.RegisteredSealProof]abi.ChainEpoch{
 // 32 sectors per epoch would support a single miner onboarding 1EiB of 32GiB sectors in 1 year.
 const PreCommitSectorBatchMaxSize = 256
 
+// The maximum total by which a pre-commitment's prove-commit deadline may be extended.
+const MaxPreCommitExtension = 30 * builtin.EpochsInDay // PARAM_SPEC
+
 // The maximum number of sector replica updates in a single batch.
 // Same as PreCommitSectorBatchMaxSize for consistency
 const ProveReplicaUpdatesMaxSize = PreCommitSectorBatchMaxSize
@@ -304,6 +316,22 @@ const MaxAggregatedSectors = 819
 const MinAggregatedSectors = 4
 const MaxAggregateProofSize = 81960
 
+// A SnarkPack aggregate proof comprises a fixed part and one round of commitments for each halving of the
+// number of proofs aggregated, padded to a power of two. Each round holds ten target group elements of 576
+// bytes and two compressed G1 points of 48 bytes. The fixed part is sized so that an aggregate of the
+// MaxAggregatedSectors seal proofs, padded to 1024, is bounded by MaxAggregateProofSize.
+const aggregateProofRoundSize = 10*576 + 2*48
+const aggregateProofFixedSize = MaxAggregateProofSize - 10*aggregateProofRoundSize
+
+// The maximum size of an aggregate of the Window PoSt proofs of a number of partitions.
+func MaxAggregatePoStProofSize(partitions uint64) uint64 {
+	rounds := uint64(1) // Even a single proof is aggregated in one round.
+	if partitions > 2 {
+		rounds = uint64(bits.Len64(partitions - 1))
+	}
+	return aggregateProofFixedSize + rounds*aggregateProofRoundSize
+}
+
 // The delay between pre commit expiration and clean up from state. This enforces that expired pre-commits
 // stay in state for a period of time creating a grace period during which a late-running aggregated prove-commit
 // can still prove its non-expired precommits without resubmitting a message
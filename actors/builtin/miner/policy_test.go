@@ -134,6 +134,22 @@ func TestPower(t *testing.T) {
 	})
 }
 
+func TestMaxAggregatePoStProofSize(t *testing.T) {
+	t.Run("bound matches seal aggregate bound for the same number of proofs", func(t *testing.T) {
+		assert.Equal(t, uint64(miner.MaxAggregateProofSize), miner.MaxAggregatePoStProofSize(miner.MaxAggregatedSectors))
+		assert.Equal(t, uint64(miner.MaxAggregateProofSize), miner.MaxAggregatePoStProofSize(1024))
+	})
+
+	t.Run("bound grows with each doubling of partitions", func(t *testing.T) {
+		round := miner.MaxAggregatePoStProofSize(4) - miner.MaxAggregatePoStProofSize(2)
+		assert.Equal(t, uint64(5856), round)
+		assert.Equal(t, miner.MaxAggregatePoStProofSize(1), miner.MaxAggregatePoStProofSize(2))
+		assert.Equal(t, miner.MaxAggregatePoStProofSize(3), miner.MaxAggregatePoStProofSize(4))
+		assert.Equal(t, miner.MaxAggregatePoStProofSize(4)+round, miner.MaxAggregatePoStProofSize(5))
+		assert.Equal(t, miner.MaxAggregateProofSize-9*round, miner.MaxAggregatePoStProofSize(2))
+	})
+}
+
 func weight(size abi.SectorSize, duration abi.ChainEpoch) big.Int {
 	return big.Mul(big.NewIntUnsigned(uint64(size)), big.NewInt(int64(duration)))
 }
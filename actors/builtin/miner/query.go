@@ -0,0 +1,383 @@
+package miner
+
+import (
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	xc "github.com/filecoin-project/go-state-types/exitcode"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/smoothing"
+)
+
+//
+// Read-only views of miner state.
+// These methods never mutate the state and may be used against any store, e.g. by tooling
+// inspecting a state tree outside of a state transaction.
+//
+
+// SectorStatus describes the state of a sector with respect to the partition that holds it.
+type SectorStatus uint64
+
+const (
+	// The sector is proven, not faulty and contributes power.
+	SectorStatusActive SectorStatus = iota
+	// The sector is faulty and has not been declared recovered.
+	SectorStatusFaulty
+	// The sector is faulty but has been declared recovered, pending a successful Window PoSt.
+	SectorStatusRecovering
+	// The sector has been terminated (early or on expiration) but not yet compacted away.
+	SectorStatusTerminated
+	// The sector has been activated but is yet to be proven in a Window PoSt.
+	SectorStatusUnproven
+)
+
+func (s SectorStatus) String() string {
+	switch s {
+	case SectorStatusActive:
+		return "active"
+	case SectorStatusFaulty:
+		return "faulty"
+	case SectorStatusRecovering:
+		return "recovering"
+	case SectorStatusTerminated:
+		return "terminated"
+	case SectorStatusUnproven:
+		return "unproven"
+	default:
+		return "unknown"
+	}
+}
+
+// SectorStatusInfo locates a sector and describes its status.
+type SectorStatusInfo struct {
+	SectorNumber abi.SectorNumber
+	Status       SectorStatus
+	Deadline     uint64
+	Partition    uint64
+	// The sector's on-chain info. A terminated sector's info remains until its partition is compacted,
+	// so this is nil only once that has happened.
+	Info *SectorOnChainInfo
+}
+
+// DeadlineSummary aggregates sector counts and power for a single deadline.
+type DeadlineSummary struct {
+	Index          uint64
+	PartitionCount uint64
+	// Number of partitions which have been proven in the current challenge window.
+	PartitionsPoSted uint64
+	// Whether any partition has early terminations pending processing.
+	HasEarlyTerminations bool
+
+	TotalSectors      uint64 // Including terminated sectors not yet compacted.
+	LiveSectors       uint64 // Not terminated, including faulty and unproven.
+	ActiveSectors     uint64 // Neither terminated, faulty nor unproven.
+	FaultySectors     uint64 // Including recovering sectors.
+	RecoveringSectors uint64
+	UnprovenSectors   uint64
+	TerminatedSectors uint64
+
+	LivePower       PowerPair
+	ActivePower     PowerPair
+	FaultyPower     PowerPair
+	RecoveringPower PowerPair
+	UnprovenPower   PowerPair
+}
+
+// BalanceSummary breaks down a miner's balance into its locked and available components.
+type BalanceSummary struct {
+	// The actor's total balance, as provided by the caller.
+	Balance abi.TokenAmount
+	// Locked rewards, as scheduled in VestingSchedule.
+	LockedFunds       abi.TokenAmount
+	VestingSchedule   []VestingFund
+	PreCommitDeposits abi.TokenAmount
+	InitialPledge     abi.TokenAmount
+	FeeDebt           abi.TokenAmount
+	// Balance less locked funds, pre-commit deposits and initial pledge.
+	Unlocked abi.TokenAmount
+	// Unlocked balance less fee debt. May be negative if the miner is in debt.
+	Available abi.TokenAmount
+}
+
+// TerminationFeePreview describes the outcome of terminating a set of sectors at some epoch.
+type TerminationFeePreview struct {
+	// Per-sector fees, in sector number order.
+	Sectors []SectorTerminationFee
+	// Sum of the per-sector fees.
+	TotalFee abi.TokenAmount
+	// Initial pledge that would no longer be required of the miner.
+	PledgeReleased abi.TokenAmount
+}
+
+type SectorTerminationFee struct {
+	SectorNumber  abi.SectorNumber
+	Fee           abi.TokenAmount
+	InitialPledge abi.TokenAmount
+}
+
+// LoadSectorStatus finds the deadline and partition holding a sector and determines its status.
+// Returns a not-found error if the sector is not assigned to any deadline.
+func (st *State) LoadSectorStatus(store adt.Store, sno abi.SectorNumber) (*SectorStatusInfo, error) {
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return nil, err
+	}
+	dlIdx, pIdx, err := FindSector(store, deadlines, sno)
+	if err != nil {
+		return nil, xc.ErrNotFound.Wrapf("failed to find sector %d: %w", sno, err)
+	}
+	dl, err := deadlines.LoadDeadline(store, dlIdx)
+	if err != nil {
+		return nil, err
+	}
+	partition, err := dl.LoadPartition(store, pIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := partition.sectorStatus(sno)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to determine status of sector %d (deadline %d, partition %d): %w", sno, dlIdx, pIdx, err)
+	}
+
+	info, found, err := st.GetSector(store, sno)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load sector %d: %w", sno, err)
+	} else if !found {
+		info = nil
+	}
+
+	return &SectorStatusInfo{
+		SectorNumber: sno,
+		Status:       status,
+		Deadline:     dlIdx,
+		Partition:    pIdx,
+		Info:         info,
+	}, nil
+}
+
+// LoadDeadlineSummaries summarizes every deadline, in index order.
+func (st *State) LoadDeadlineSummaries(store adt.Store) ([]DeadlineSummary, error) {
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]DeadlineSummary, 0, WPoStPeriodDeadlines)
+	if err := deadlines.ForEach(store, func(dlIdx uint64, dl *Deadline) error {
+		summary, err := dl.summarize(store, dlIdx)
+		if err != nil {
+			return xerrors.Errorf("failed to summarize deadline %d: %w", dlIdx, err)
+		}
+		summaries = append(summaries, *summary)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// LoadDeadlineSummary summarizes a single deadline.
+func (st *State) LoadDeadlineSummary(store adt.Store, dlIdx uint64) (*DeadlineSummary, error) {
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return nil, err
+	}
+	dl, err := deadlines.LoadDeadline(store, dlIdx)
+	if err != nil {
+		return nil, err
+	}
+	return dl.summarize(store, dlIdx)
+}
+
+// LoadBalanceSummary breaks down the actor balance into locked, vesting and available funds.
+func (st *State) LoadBalanceSummary(store adt.Store, actorBalance abi.TokenAmount) (*BalanceSummary, error) {
+	vesting, err := st.LoadVestingFunds(store)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := st.GetUnlockedBalance(actorBalance)
+	if err != nil {
+		return nil, err
+	}
+	schedule := make([]VestingFund, len(vesting.Funds))
+	copy(schedule, vesting.Funds)
+
+	return &BalanceSummary{
+		Balance:           actorBalance,
+		LockedFunds:       st.LockedFunds,
+		VestingSchedule:   schedule,
+		PreCommitDeposits: st.PreCommitDeposits,
+		InitialPledge:     st.InitialPledge,
+		FeeDebt:           st.FeeDebt,
+		Unlocked:          unlocked,
+		Available:         big.Sub(unlocked, st.FeeDebt),
+	}, nil
+}
+
+// PreviewTerminationFees computes the fees that would be charged for terminating the given sectors at
+// the given epoch, and the pledge that would be released.
+// The fees are computed exactly as when early terminations are processed.
+// Returns an error if any sector is not found or has already been terminated.
+// Partitions are searched for the sectors only until all have been found, and only the partitions holding them
+// are checked for terminations.
+func (st *State) PreviewTerminationFees(store adt.Store, sectorNos bitfield.BitField, epoch abi.ChainEpoch,
+	rewardEstimate, networkQAPowerEstimate smoothing.FilterEstimate) (*TerminationFeePreview, error) {
+	info, err := st.GetInfo(store)
+	if err != nil {
+		return nil, xc.ErrIllegalState.Wrapf("failed to load miner info: %w", err)
+	}
+	sectorsArr, err := LoadSectors(store, st.Sectors)
+	if err != nil {
+		return nil, xc.ErrIllegalState.Wrapf("failed to load sectors array: %w", err)
+	}
+	sectors, err := sectorsArr.Load(sectorNos)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load sectors: %w", err)
+	}
+
+	// Terminated sectors remain in the sectors array until their partition is compacted,
+	// so check them against the terminated sectors of the partitions holding them.
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return nil, err
+	}
+	located, err := FindSectors(store, deadlines, sectorNos)
+	if err != nil {
+		return nil, xc.ErrNotFound.Wrapf("failed to find sectors: %w", err)
+	}
+	if err := located.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+		dl, err := deadlines.LoadDeadline(store, dlIdx)
+		if err != nil {
+			return err
+		}
+		return pm.ForEach(func(partIdx uint64, partSectors bitfield.BitField) error {
+			partition, err := dl.LoadPartition(store, partIdx)
+			if err != nil {
+				return xc.ErrIllegalState.Wrapf("failed to load partition %d of deadline %d: %w", partIdx, dlIdx, err)
+			}
+			terminated, err := bitfield.IntersectBitField(partSectors, partition.Terminated)
+			if err != nil {
+				return xc.ErrIllegalState.Wrapf("failed to intersect terminated sectors: %w", err)
+			}
+			if empty, err := terminated.IsEmpty(); err != nil {
+				return xc.ErrIllegalState.Wrapf("failed to check terminated sectors: %w", err)
+			} else if !empty {
+				first, err := terminated.First()
+				if err != nil {
+					return xc.ErrIllegalState.Wrapf("failed to read terminated sectors: %w", err)
+				}
+				return xc.ErrIllegalArgument.Wrapf("sector %d already terminated", first)
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, xc.Unwrap(err, xc.ErrIllegalState).Wrapf("failed to check for terminated sectors: %w", err)
+	}
+
+	preview := TerminationFeePreview{
+		Sectors:        make([]SectorTerminationFee, 0, len(sectors)),
+		TotalFee:       big.Zero(),
+		PledgeReleased: big.Zero(),
+	}
+	for _, sector := range sectors {
+		fee := TerminationPenaltyForSector(info.SectorSize, epoch, rewardEstimate, networkQAPowerEstimate, sector)
+		preview.Sectors = append(preview.Sectors, SectorTerminationFee{
+			SectorNumber:  sector.SectorNumber,
+			Fee:           fee,
+			InitialPledge: sector.InitialPledge,
+		})
+		preview.TotalFee = big.Add(preview.TotalFee, fee)
+		preview.PledgeReleased = big.Add(preview.PledgeReleased, sector.InitialPledge)
+	}
+	return &preview, nil
+}
+
+// Determines the status of a sector known to be a member of this partition.
+func (p *Partition) sectorStatus(sno abi.SectorNumber) (SectorStatus, error) {
+	if terminated, err := p.Terminated.IsSet(uint64(sno)); err != nil {
+		return 0, xerrors.Errorf("failed to decode terminated bitfield: %w", err)
+	} else if terminated {
+		return SectorStatusTerminated, nil
+	}
+	if faulty, err := p.Faults.IsSet(uint64(sno)); err != nil {
+		return 0, xerrors.Errorf("failed to decode faults bitfield: %w", err)
+	} else if faulty {
+		if recovering, err := p.Recoveries.IsSet(uint64(sno)); err != nil {
+			return 0, xerrors.Errorf("failed to decode recoveries bitfield: %w", err)
+		} else if recovering {
+			return SectorStatusRecovering, nil
+		}
+		return SectorStatusFaulty, nil
+	}
+	if unproven, err := p.Unproven.IsSet(uint64(sno)); err != nil {
+		return 0, xerrors.Errorf("failed to decode unproven bitfield: %w", err)
+	} else if unproven {
+		return SectorStatusUnproven, nil
+	}
+	return SectorStatusActive, nil
+}
+
+func (dl *Deadline) summarize(store adt.Store, dlIdx uint64) (*DeadlineSummary, error) {
+	partitions, err := dl.PartitionsArray(store)
+	if err != nil {
+		return nil, err
+	}
+	posted, err := dl.PartitionsPoSted.Count()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to count posted partitions: %w", err)
+	}
+	earlyTerminations, err := dl.EarlyTerminations.IsEmpty()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode early terminations: %w", err)
+	}
+
+	summary := DeadlineSummary{
+		Index:                dlIdx,
+		PartitionCount:       partitions.Length(),
+		PartitionsPoSted:     posted,
+		HasEarlyTerminations: !earlyTerminations,
+		TotalSectors:         dl.TotalSectors,
+		LiveSectors:          dl.LiveSectors,
+		LivePower:            NewPowerPairZero(),
+		ActivePower:          NewPowerPairZero(),
+		FaultyPower:          NewPowerPairZero(),
+		RecoveringPower:      NewPowerPairZero(),
+		UnprovenPower:        NewPowerPairZero(),
+	}
+
+	var partition Partition
+	if err := partitions.ForEach(&partition, func(pIdx int64) error {
+		active, err := partition.ActiveSectors()
+		if err != nil {
+			return err
+		}
+		for _, c := range []struct {
+			bf  bitfield.BitField
+			dst *uint64
+		}{
+			{active, &summary.ActiveSectors},
+			{partition.Faults, &summary.FaultySectors},
+			{partition.Recoveries, &summary.RecoveringSectors},
+			{partition.Unproven, &summary.UnprovenSectors},
+			{partition.Terminated, &summary.TerminatedSectors},
+		} {
+			n, err := c.bf.Count()
+			if err != nil {
+				return xerrors.Errorf("failed to count sectors in partition %d: %w", pIdx, err)
+			}
+			*c.dst += n
+		}
+
+		summary.LivePower = summary.LivePower.Add(partition.LivePower)
+		summary.ActivePower = summary.ActivePower.Add(partition.ActivePower())
+		summary.FaultyPower = summary.FaultyPower.Add(partition.FaultyPower)
+		summary.RecoveringPower = summary.RecoveringPower.Add(partition.RecoveringPower)
+		summary.UnprovenPower = summary.UnprovenPower.Add(partition.UnprovenPower)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
@@ -0,0 +1,273 @@
+package miner_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/smoothing"
+)
+
+func TestQuerySectorStatus(t *testing.T) {
+	const dlIdx = 3
+	sectorSize := abi.SectorSize(32 << 30)
+	sectors := []*miner.SectorOnChainInfo{
+		testSector(1000, 1, 0, 0, 10),
+		testSector(1000, 2, 0, 0, 10),
+		testSector(1000, 3, 0, 0, 10),
+		testSector(1000, 4, 0, 0, 10),
+		testSector(1000, 5, 0, 0, 10),
+	}
+
+	// Builds a state with one deadline holding a single partition in which sector 1 is active,
+	// 2 is faulty, 3 is recovering, 4 is terminated and 5 is unproven.
+	setup := func(t *testing.T) *stateHarness {
+		h := constructStateHarness(t, abi.ChainEpoch(0))
+		sectorArr := sectorsArr(t, h.store, sectors)
+		quant := h.s.QuantSpecForDeadline(dlIdx)
+
+		deadlines, err := h.s.LoadDeadlines(h.store)
+		require.NoError(t, err)
+		dl, err := deadlines.LoadDeadline(h.store, dlIdx)
+		require.NoError(t, err)
+
+		_, _, err = dl.AddSectors(h.store, 8, true, sectors[:4], sectorSize, quant)
+		require.NoError(t, err)
+		_, _, err = dl.RecordFaults(h.store, sectorArr, sectorSize, quant, 500, miner.PartitionSectorMap{0: bf(2, 3)})
+		require.NoError(t, err)
+		require.NoError(t, dl.DeclareFaultsRecovered(h.store, sectorArr, sectorSize, miner.PartitionSectorMap{0: bf(3)}))
+		_, _, err = dl.TerminateSectors(h.store, sectorArr, 100, miner.PartitionSectorMap{0: bf(4)}, sectorSize, quant)
+		require.NoError(t, err)
+		_, _, err = dl.AddSectors(h.store, 8, false, sectors[4:], sectorSize, quant)
+		require.NoError(t, err)
+
+		require.NoError(t, deadlines.UpdateDeadline(h.store, dlIdx, dl))
+		require.NoError(t, h.s.SaveDeadlines(h.store, deadlines))
+		require.NoError(t, h.s.PutSectors(h.store, sectors[0], sectors[1], sectors[2], sectors[4]))
+		return h
+	}
+
+	t.Run("reports status and location of each sector", func(t *testing.T) {
+		h := setup(t)
+		expected := map[abi.SectorNumber]miner.SectorStatus{
+			1: miner.SectorStatusActive,
+			2: miner.SectorStatusFaulty,
+			3: miner.SectorStatusRecovering,
+			4: miner.SectorStatusTerminated,
+			5: miner.SectorStatusUnproven,
+		}
+		for _, s := range sectors {
+			status, err := h.s.LoadSectorStatus(h.store, s.SectorNumber)
+			require.NoError(t, err)
+			assert.Equal(t, s.SectorNumber, status.SectorNumber)
+			assert.Equal(t, expected[s.SectorNumber], status.Status, "sector %d is %s", s.SectorNumber, status.Status)
+			assert.Equal(t, uint64(dlIdx), status.Deadline)
+			assert.Equal(t, uint64(0), status.Partition)
+			if status.Status == miner.SectorStatusTerminated {
+				assert.Nil(t, status.Info)
+			} else {
+				require.NotNil(t, status.Info)
+				assert.Equal(t, s.SectorNumber, status.Info.SectorNumber)
+				assert.Equal(t, s.InitialPledge, status.Info.InitialPledge)
+			}
+		}
+	})
+
+	t.Run("unknown sector is not found", func(t *testing.T) {
+		h := setup(t)
+		_, err := h.s.LoadSectorStatus(h.store, 6)
+		assert.Equal(t, exitcode.ErrNotFound, exitcode.Unwrap(err, exitcode.Ok))
+	})
+
+	t.Run("summarizes deadlines", func(t *testing.T) {
+		h := setup(t)
+		summaries, err := h.s.LoadDeadlineSummaries(h.store)
+		require.NoError(t, err)
+		require.Len(t, summaries, int(miner.WPoStPeriodDeadlines))
+
+		for i, summary := range summaries {
+			assert.Equal(t, uint64(i), summary.Index)
+			if i != dlIdx {
+				assert.Equal(t, uint64(0), summary.PartitionCount)
+				assert.Equal(t, uint64(0), summary.TotalSectors)
+				assert.True(t, summary.LivePower.IsZero())
+			}
+		}
+
+		summary, err := h.s.LoadDeadlineSummary(h.store, dlIdx)
+		require.NoError(t, err)
+		assert.Equal(t, summaries[dlIdx], *summary)
+
+		assert.Equal(t, uint64(1), summary.PartitionCount)
+		assert.True(t, summary.HasEarlyTerminations)
+		assert.Equal(t, uint64(5), summary.TotalSectors)
+		assert.Equal(t, uint64(4), summary.LiveSectors)
+		assert.Equal(t, uint64(1), summary.ActiveSectors)
+		assert.Equal(t, uint64(2), summary.FaultySectors)
+		assert.Equal(t, uint64(1), summary.RecoveringSectors)
+		assert.Equal(t, uint64(1), summary.UnprovenSectors)
+		assert.Equal(t, uint64(1), summary.TerminatedSectors)
+
+		powerOf := func(infos ...*miner.SectorOnChainInfo) miner.PowerPair {
+			return miner.PowerForSectors(sectorSize, infos)
+		}
+		assert.True(t, summary.LivePower.Equals(powerOf(sectors[0], sectors[1], sectors[2], sectors[4])))
+		assert.True(t, summary.ActivePower.Equals(powerOf(sectors[0])))
+		assert.True(t, summary.FaultyPower.Equals(powerOf(sectors[1], sectors[2])))
+		assert.True(t, summary.RecoveringPower.Equals(powerOf(sectors[2])))
+		assert.True(t, summary.UnprovenPower.Equals(powerOf(sectors[4])))
+	})
+}
+
+func TestQueryBalanceSummary(t *testing.T) {
+	h := constructStateHarness(t, abi.ChainEpoch(0))
+	vestSpec := &miner.VestSpec{InitialDelay: 0, VestPeriod: 10, StepDuration: 5, Quantization: 1}
+	h.addLockedFunds(0, abi.NewTokenAmount(100), vestSpec)
+	require.NoError(t, h.s.AddPreCommitDeposit(abi.NewTokenAmount(20)))
+	require.NoError(t, h.s.AddInitialPledge(abi.NewTokenAmount(30)))
+	require.NoError(t, h.s.ApplyPenalty(abi.NewTokenAmount(40)))
+
+	balance := abi.NewTokenAmount(170)
+	summary, err := h.s.LoadBalanceSummary(h.store, balance)
+	require.NoError(t, err)
+
+	assert.Equal(t, balance, summary.Balance)
+	assert.Equal(t, abi.NewTokenAmount(100), summary.LockedFunds)
+	assert.Equal(t, abi.NewTokenAmount(20), summary.PreCommitDeposits)
+	assert.Equal(t, abi.NewTokenAmount(30), summary.InitialPledge)
+	assert.Equal(t, abi.NewTokenAmount(40), summary.FeeDebt)
+	assert.Equal(t, abi.NewTokenAmount(20), summary.Unlocked)
+	assert.Equal(t, abi.NewTokenAmount(-20), summary.Available)
+
+	vesting := big.Zero()
+	for _, vf := range summary.VestingSchedule {
+		vesting = big.Add(vesting, vf.Amount)
+	}
+	assert.Equal(t, summary.LockedFunds, vesting)
+	assert.Len(t, summary.VestingSchedule, 2)
+
+	_, err = h.s.LoadBalanceSummary(h.store, abi.NewTokenAmount(100))
+	assert.Error(t, err)
+}
+
+func TestQueryTerminationFees(t *testing.T) {
+	const dlIdx = 3
+	sectorSize := abi.SectorSize(32 << 30)
+	sectors := []*miner.SectorOnChainInfo{
+		testSector(1000, 1, 0, 0, 1000),
+		testSector(2000, 2, 0, 0, 2000),
+		testSector(2000, 3, 0, 0, 3000),
+	}
+	// Half a day's reward per epoch of age is charged on top of the storage pledge.
+	// The day rewards are chosen so that this is 1 and 2 per epoch respectively.
+	for i, sector := range sectors {
+		sector.Activation = 100
+		sector.ExpectedDayReward = abi.NewTokenAmount(int64(i+1) * 2 * builtin.EpochsInDay)
+		sector.ExpectedStoragePledge = abi.NewTokenAmount(int64(i+1) * 500)
+		sector.ReplacedDayReward = big.Zero()
+	}
+	// Estimates for which the termination penalty lower bound is negligible.
+	rewardEstimate := smoothing.TestingConstantEstimate(big.NewInt(1))
+	powerEstimate := smoothing.TestingConstantEstimate(big.NewInt(1 << 60))
+	epoch := abi.ChainEpoch(500)
+
+	// Sector 3 is terminated, while remaining in the sectors array until compaction.
+	setup := func(t *testing.T) *stateHarness {
+		h := constructStateHarness(t, abi.ChainEpoch(0))
+		require.NoError(t, h.s.PutSectors(h.store, sectors...))
+		quant := h.s.QuantSpecForDeadline(dlIdx)
+
+		deadlines, err := h.s.LoadDeadlines(h.store)
+		require.NoError(t, err)
+		dl, err := deadlines.LoadDeadline(h.store, dlIdx)
+		require.NoError(t, err)
+		_, _, err = dl.AddSectors(h.store, 8, true, sectors, sectorSize, quant)
+		require.NoError(t, err)
+		_, _, err = dl.TerminateSectors(h.store, sectorsArr(t, h.store, sectors), 200, miner.PartitionSectorMap{0: bf(3)}, sectorSize, quant)
+		require.NoError(t, err)
+		require.NoError(t, deadlines.UpdateDeadline(h.store, dlIdx, dl))
+		require.NoError(t, h.s.SaveDeadlines(h.store, deadlines))
+		return h
+	}
+
+	t.Run("previews fees and pledge of live sectors", func(t *testing.T) {
+		h := setup(t)
+		preview, err := h.s.PreviewTerminationFees(h.store, bf(1, 2), epoch, rewardEstimate, powerEstimate)
+		require.NoError(t, err)
+
+		// Each sector is 400 epochs old.
+		assert.Equal(t, []miner.SectorTerminationFee{
+			{SectorNumber: 1, Fee: abi.NewTokenAmount(500 + 400), InitialPledge: abi.NewTokenAmount(1000)},
+			{SectorNumber: 2, Fee: abi.NewTokenAmount(1000 + 800), InitialPledge: abi.NewTokenAmount(2000)},
+		}, preview.Sectors)
+		assert.Equal(t, abi.NewTokenAmount(2700), preview.TotalFee)
+		assert.Equal(t, abi.NewTokenAmount(3000), preview.PledgeReleased)
+	})
+
+	t.Run("fails for missing sector", func(t *testing.T) {
+		h := setup(t)
+		_, err := h.s.PreviewTerminationFees(h.store, bf(1, 4), epoch, rewardEstimate, powerEstimate)
+		require.Error(t, err)
+		assert.Equal(t, exitcode.ErrNotFound, exitcode.Unwrap(err, exitcode.Ok))
+	})
+
+	t.Run("fails for already terminated sector", func(t *testing.T) {
+		h := setup(t)
+		_, err := h.s.PreviewTerminationFees(h.store, bf(1, 3), epoch, rewardEstimate, powerEstimate)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sector 3 already terminated")
+		assert.Equal(t, exitcode.ErrIllegalArgument, exitcode.Unwrap(err, exitcode.Ok))
+	})
+
+	t.Run("checks sectors spread over many deadlines and partitions", func(t *testing.T) {
+		h := constructStateHarness(t, abi.ChainEpoch(0))
+		deadlines, err := h.s.LoadDeadlines(h.store)
+		require.NoError(t, err)
+
+		// Four sectors at each of six deadlines, in partitions of two.
+		var all []*miner.SectorOnChainInfo
+		for dlIdx := uint64(0); dlIdx < 6; dlIdx++ {
+			var dlSectors []*miner.SectorOnChainInfo
+			for i := uint64(0); i < 4; i++ {
+				sector := testSector(2000, int64(dlIdx*4+i+1), 0, 0, 1000)
+				sector.Activation = 100
+				sector.ExpectedDayReward = abi.NewTokenAmount(2 * builtin.EpochsInDay)
+				sector.ExpectedStoragePledge = abi.NewTokenAmount(500)
+				sector.ReplacedDayReward = big.Zero()
+				dlSectors = append(dlSectors, sector)
+			}
+			require.NoError(t, h.s.PutSectors(h.store, dlSectors...))
+			all = append(all, dlSectors...)
+
+			quant := h.s.QuantSpecForDeadline(dlIdx)
+			dl, err := deadlines.LoadDeadline(h.store, dlIdx)
+			require.NoError(t, err)
+			_, _, err = dl.AddSectors(h.store, 2, true, dlSectors, sectorSize, quant)
+			require.NoError(t, err)
+			if dlIdx == 5 {
+				// Terminate the first sector of the last partition.
+				_, _, err = dl.TerminateSectors(h.store, sectorsArr(t, h.store, all), 200, miner.PartitionSectorMap{1: bf(23)}, sectorSize, quant)
+				require.NoError(t, err)
+			}
+			require.NoError(t, deadlines.UpdateDeadline(h.store, dlIdx, dl))
+		}
+		require.NoError(t, h.s.SaveDeadlines(h.store, deadlines))
+
+		preview, err := h.s.PreviewTerminationFees(h.store, bf(1, 6, 11, 24), epoch, rewardEstimate, powerEstimate)
+		require.NoError(t, err)
+		require.Len(t, preview.Sectors, 4)
+		assert.Equal(t, abi.NewTokenAmount(4*900), preview.TotalFee)
+		assert.Equal(t, abi.NewTokenAmount(4*1000), preview.PledgeReleased)
+
+		_, err = h.s.PreviewTerminationFees(h.store, bf(1, 23), epoch, rewardEstimate, powerEstimate)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sector 23 already terminated")
+		assert.Equal(t, exitcode.ErrIllegalArgument, exitcode.Unwrap(err, exitcode.Ok))
+	})
+}
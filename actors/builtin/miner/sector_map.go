@@ -148,3 +148,12 @@ func (pm PartitionSectorMap) ForEach(cb func(partIdx uint64, sectorNos bitfield.
 	}
 	return nil
 }
+
+// Sectors returns the union of the sectors of all partitions in the map.
+func (pm PartitionSectorMap) Sectors() (bitfield.BitField, error) {
+	all := make([]bitfield.BitField, 0, len(pm))
+	for _, partIdx := range pm.Partitions() {
+		all = append(all, pm[partIdx])
+	}
+	return bitfield.MultiMerge(all...)
+}
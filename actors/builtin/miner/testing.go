@@ -50,19 +50,18 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 
 	CheckMinerBalances(st, store, balance, acc)
 
-	var allocatedSectors bitfield.BitField
-	var allocatedSectorsMap map[uint64]bool
-	if err := store.Get(store.Context(), st.AllocatedSectors, &allocatedSectors); err != nil {
+	// Allocated sector numbers are checked against the bitfield rather than an expansion of it,
+	// which may be too large after sector numbers have been masked.
+	var allocatedSectors *bitfield.BitField
+	var allocated bitfield.BitField
+	if err := store.Get(store.Context(), st.AllocatedSectors, &allocated); err != nil {
 		acc.Addf("error loading allocated sector bitfield: %v", err)
 	} else {
-		allocatedSectorsMap, err = allocatedSectors.AllMap(1 << 30)
-		if err != nil {
-			acc.Addf("error expanding allocated sector bitfield: %v", err)
-			allocatedSectorsMap = nil
-		}
+		allocatedSectors = &allocated
 	}
 
-	CheckPreCommits(st, store, allocatedSectorsMap, acc)
+	CheckPreCommits(st, store, allocatedSectors, acc)
+	CheckScheduledRecoveries(st, store, allocatedSectors, acc)
 
 	minerSummary.Deals = map[abi.DealID]DealSummary{}
 	var allSectors map[abi.SectorNumber]*SectorOnChainInfo
@@ -74,7 +73,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 		err = sectorsArr.ForEach(&sector, func(sno int64) error {
 			cpy := sector
 			allSectors[abi.SectorNumber(sno)] = &cpy
-			acc.Require(allocatedSectorsMap == nil || allocatedSectorsMap[uint64(sno)],
+			acc.Require(isAllocated(allocatedSectors, uint64(sno), acc),
 				"on chain sector's sector number has not been allocated %d", sno)
 
 			for _, dealID := range sector.DealIDs {
@@ -245,6 +244,7 @@ func CheckDeadlineStateInvariants(deadline *Deadline, store adt.Store, quant bui
 	acc.RequireNoError(err, "error loading proofs snapshot")
 	var proof WindowedPoSt
 	err = proofsSnapshot.ForEach(&proof, func(_ int64) error {
+		acc.Require(!proof.Aggregated || len(proof.Proofs) == 1, "aggregated proof has %d proofs, expected one", len(proof.Proofs))
 		err = proof.Partitions.ForEach(func(i uint64) error {
 			found, err := partitionsSnapshot.Get(i, &partition)
 			acc.RequireNoError(err, "error loading partition snapshot")
@@ -703,6 +703,22 @@ func CheckMinerInfo(info *MinerInfo, acc *builtin.MessageAccumulator) {
 			"pending owner address %v is same as existing owner %v", info.PendingOwnerAddress, info.Owner)
 	}
 
+	acc.Require(info.Beneficiary.Protocol() == addr.ID, "beneficiary address %v is not an ID address", info.Beneficiary)
+	acc.Require(info.BeneficiaryTerm.Quota.GreaterThanEqual(big.Zero()),
+		"beneficiary quota %v is negative", info.BeneficiaryTerm.Quota)
+	acc.Require(info.BeneficiaryTerm.UsedQuota.GreaterThanEqual(big.Zero()),
+		"beneficiary used quota %v is negative", info.BeneficiaryTerm.UsedQuota)
+
+	if info.PendingBeneficiaryTerm != nil {
+		pending := info.PendingBeneficiaryTerm
+		acc.Require(pending.NewBeneficiary.Protocol() == addr.ID,
+			"pending beneficiary address %v is not an ID address", pending.NewBeneficiary)
+		acc.Require(pending.NewQuota.GreaterThanEqual(big.Zero()),
+			"pending beneficiary quota %v is negative", pending.NewQuota)
+		acc.Require(!(pending.ApprovedByBeneficiary && pending.ApprovedByNominee),
+			"pending beneficiary change to %v is approved by both parties but not applied", pending.NewBeneficiary)
+	}
+
 	windowPoStProofInfo, found := abi.PoStProofInfos[info.WindowPoStProofType]
 	acc.Require(found, "miner has unrecognized Window PoSt proof type %d", info.WindowPoStProofType)
 	if found {
@@ -726,6 +742,12 @@ func CheckMinerBalances(st *State, store adt.Store, balance abi.TokenAmount, acc
 	acc.Require(st.InitialPledge.GreaterThanEqual(big.Zero()), "miner initial pledge is less than zero: %v", st.InitialPledge)
 	acc.Require(st.FeeDebt.GreaterThanEqual(big.Zero()), "miner fee debt is less than zero: %v", st.FeeDebt)
 
+	if info, err := st.GetInfo(store); err != nil {
+		acc.Addf("error loading miner info: %v", err)
+	} else {
+		acc.RequireNoError(info.DebtRepaymentPolicy.Validate(), "invalid debt repayment policy")
+	}
+
 	acc.Require(big.Subtract(balance, st.LockedFunds, st.PreCommitDeposits, st.InitialPledge).GreaterThanEqual(big.Zero()),
 		"miner balance (%v) is less than sum of locked funds (%v), precommit deposit (%v), and initial pledge (%v)",
 		balance, st.LockedFunds, st.PreCommitDeposits, st.InitialPledge)
@@ -754,7 +776,38 @@ func CheckMinerBalances(st *State, store adt.Store, balance abi.TokenAmount, acc
 	}
 }
 
-func CheckPreCommits(st *State, store adt.Store, allocatedSectors map[uint64]bool, acc *builtin.MessageAccumulator) {
+// Reports whether a sector number is in the allocated sector numbers, or true if they could not be loaded.
+func isAllocated(allocatedSectors *bitfield.BitField, sno uint64, acc *builtin.MessageAccumulator) bool {
+	if allocatedSectors == nil {
+		return true
+	}
+	set, err := allocatedSectors.IsSet(sno)
+	if err != nil {
+		acc.Addf("error checking allocation of sector number %d: %v", sno, err)
+		return true
+	}
+	return set
+}
+
+// Checks that scheduled recoveries are keyed by challenge window opening epochs and name only allocated sectors.
+func CheckScheduledRecoveries(st *State, store adt.Store, allocatedSectors *bitfield.BitField, acc *builtin.MessageAccumulator) {
+	quant := st.QuantSpecEveryDeadline()
+	queue, err := LoadBitfieldQueue(store, st.ScheduledRecoveries, quant, ScheduledRecoveriesAmtBitwidth)
+	if err != nil {
+		acc.Addf("error loading scheduled recoveries queue: %v", err)
+		return
+	}
+	err = queue.ForEach(func(epoch abi.ChainEpoch, sectorNos bitfield.BitField) error {
+		acc.Require(quant.QuantizeUp(epoch) == epoch, "scheduled recovery epoch %d is not the opening of a challenge window", epoch)
+		return sectorNos.ForEach(func(sno uint64) error {
+			acc.Require(isAllocated(allocatedSectors, sno, acc), "scheduled recovery of unallocated sector %d", sno)
+			return nil
+		})
+	})
+	acc.RequireNoError(err, "error iterating scheduled recoveries")
+}
+
+func CheckPreCommits(st *State, store adt.Store, allocatedSectors *bitfield.BitField, acc *builtin.MessageAccumulator) {
 	quant := st.QuantSpecEveryDeadline()
 
 	// invert pre-commit clean up queue into a lookup by sector number
@@ -788,11 +841,18 @@ func CheckPreCommits(st *State, store adt.Store, allocatedSectors map[uint64]boo
 				return nil
 			}
 
-			acc.Require(allocatedSectors[secNum], "pre-committed sector number has not been allocated %d", secNum)
+			acc.Require(allocatedSectors != nil && isAllocated(allocatedSectors, secNum, acc), "pre-committed sector number has not been allocated %d", secNum)
 
-			_, found := cleanUpEpochs[secNum]
+			cleanUpEpoch, found := cleanUpEpochs[secNum]
 			acc.Require(found, "no clean up epoch for pre-commit at %d", precommit.PreCommitEpoch)
 
+			acc.Require(precommit.ProveCommitExtension >= 0 && precommit.ProveCommitExtension <= MaxPreCommitExtension,
+				"pre-commit %d has invalid prove-commit extension %d", secNum, precommit.ProveCommitExtension)
+			if msd, ok := MaxProveCommitDuration[precommit.Info.SealProof]; found && ok {
+				expected := quant.QuantizeUp(precommit.PreCommitEpoch + msd + precommit.ProveCommitExtension + ExpiredPreCommitCleanUpDelay)
+				acc.Require(cleanUpEpoch == expected, "pre-commit %d clean up epoch %d, expected %d", secNum, cleanUpEpoch, expected)
+			}
+
 			precommitTotal = big.Add(precommitTotal, precommit.PreCommitDeposit)
 			return nil
 		})
@@ -252,3 +252,188 @@ func (t *LaneState) UnmarshalCBOR(r io.Reader) error {
 	}
 	return nil
 }
+
+var lengthBufGetLaneParams = []byte{129}
+
+func (t *GetLaneParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetLaneParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Lane (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Lane)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *GetLaneParams) UnmarshalCBOR(r io.Reader) error {
+	*t = GetLaneParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Lane (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Lane = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufGetLaneReturn = []byte{133}
+
+func (t *GetLaneReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufGetLaneReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.From (address.Address) (struct)
+	if err := t.From.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.To (address.Address) (struct)
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Available (big.Int) (struct)
+	if err := t.Available.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.SettlingAt (abi.ChainEpoch) (int64)
+	if t.SettlingAt >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SettlingAt)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.SettlingAt-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Lane (paych.LaneState) (struct)
+	if err := t.Lane.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *GetLaneReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = GetLaneReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.From (address.Address) (struct)
+
+	{
+
+		if err := t.From.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.From: %w", err)
+		}
+
+	}
+	// t.To (address.Address) (struct)
+
+	{
+
+		if err := t.To.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.To: %w", err)
+		}
+
+	}
+	// t.Available (big.Int) (struct)
+
+	{
+
+		if err := t.Available.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Available: %w", err)
+		}
+
+	}
+	// t.SettlingAt (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.SettlingAt = abi.ChainEpoch(extraI)
+	}
+	// t.Lane (paych.LaneState) (struct)
+
+	{
+
+		if err := t.Lane.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Lane: %w", err)
+		}
+
+	}
+	return nil
+}
@@ -30,6 +30,7 @@ func (a Actor) Exports() []interface{} {
 		2:                         a.UpdateChannelState,
 		3:                         a.Settle,
 		4:                         a.Collect,
+		5:                         a.GetLane,
 	}
 }
 
@@ -330,6 +331,44 @@ func (pca Actor) Collect(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue
 	return nil
 }
 
+type GetLaneParams struct {
+	Lane uint64
+}
+
+type GetLaneReturn struct {
+	From addr.Address
+	To   addr.Address
+	// Funds in the channel not yet redeemed by vouchers.
+	Available abi.TokenAmount
+	// Epoch at which the channel settles, or zero if not settling.
+	SettlingAt abi.ChainEpoch
+	// State of the requested lane, which is zero-valued if no voucher has been redeemed on it.
+	Lane LaneState
+}
+
+// Returns the parties and unredeemed funds of the channel, and the state of one of its lanes, so that another
+// actor may check whether a voucher on the lane can be redeemed.
+func (pca Actor) GetLane(rt runtime.Runtime, params *GetLaneParams) *GetLaneReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+
+	lstates, err := adt.AsArray(adt.AsStore(rt), st.LaneStates, LaneStatesAmtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
+	lane := LaneState{Redeemed: big.Zero()}
+	if ls := findLane(rt, lstates, params.Lane); ls != nil {
+		lane = *ls
+	}
+	return &GetLaneReturn{
+		From:       st.From,
+		To:         st.To,
+		Available:  big.Sub(rt.CurrentBalance(), st.ToSend),
+		SettlingAt: st.SettlingAt,
+		Lane:       lane,
+	}
+}
+
 // Returns the insertion index for a lane ID, with the matching lane state if found, or nil.
 func findLane(rt runtime.Runtime, ls *adt.Array, id uint64) *LaneState {
 	if id > MaxLane {
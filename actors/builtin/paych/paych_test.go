@@ -793,6 +793,51 @@ func TestActor_Settle(t *testing.T) {
 	})
 }
 
+func TestActor_GetLane(t *testing.T) {
+	getLane := func(rt *mock.Runtime, actor *pcActorHarness, lane uint64) *GetLaneReturn {
+		rt.SetCaller(tutil.NewIDAddr(t, 104), builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerAny()
+		ret := rt.Call(actor.GetLane, &GetLaneParams{Lane: lane}).(*GetLaneReturn)
+		rt.Verify()
+		return ret
+	}
+
+	t.Run("returns parties, unredeemed funds and lane state", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, 2)
+
+		ret := getLane(rt, actor, 1)
+		assert.Equal(t, actor.payer, ret.From)
+		assert.Equal(t, actor.payee, ret.To)
+		// Vouchers for 1 and 2 have been redeemed on lanes 0 and 1.
+		assert.Equal(t, abi.NewTokenAmount(100000-3), ret.Available)
+		assert.Equal(t, abi.ChainEpoch(0), ret.SettlingAt)
+		assert.Equal(t, LaneState{Redeemed: big.NewInt(2), Nonce: 2}, ret.Lane)
+		actor.checkState(rt)
+	})
+
+	t.Run("returns zero lane state for unused lane", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, 1)
+
+		ret := getLane(rt, actor, 5)
+		assert.Equal(t, LaneState{Redeemed: big.Zero()}, ret.Lane)
+		assert.Equal(t, abi.NewTokenAmount(100000-1), ret.Available)
+		actor.checkState(rt)
+	})
+
+	t.Run("returns settling epoch", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, 1)
+		rt.SetEpoch(10)
+		rt.SetCaller(actor.payer, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.Call(actor.Settle, nil)
+		rt.Verify()
+
+		ret := getLane(rt, actor, 0)
+		assert.Equal(t, abi.ChainEpoch(10)+SettleDelay, ret.SettlingAt)
+		actor.checkState(rt)
+	})
+}
+
 func TestActor_Collect(t *testing.T) {
 	t.Run("Happy path", func(t *testing.T) {
 		rt, actor, _ := requireCreateChannelWithLanes(t, 1)
@@ -537,3 +537,241 @@ func (t *CronEvent) UnmarshalCBOR(r io.Reader) error {
 	}
 	return nil
 }
+
+var lengthBufConsensusFaultReport = []byte{130}
+
+func (t *ConsensusFaultReport) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufConsensusFaultReport); err != nil {
+		return err
+	}
+
+	// t.Miner (address.Address) (struct)
+	if err := t.Miner.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Fault (miner.ReportConsensusFaultParams) (struct)
+	if err := t.Fault.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ConsensusFaultReport) UnmarshalCBOR(r io.Reader) error {
+	*t = ConsensusFaultReport{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Miner (address.Address) (struct)
+
+	{
+
+		if err := t.Miner.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Miner: %w", err)
+		}
+
+	}
+	// t.Fault (miner.ReportConsensusFaultParams) (struct)
+
+	{
+
+		if err := t.Fault.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Fault: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufReportConsensusFaultsParams = []byte{129}
+
+func (t *ReportConsensusFaultsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufReportConsensusFaultsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Reports ([]power.ConsensusFaultReport) (slice)
+	if len(t.Reports) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Reports was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Reports))); err != nil {
+		return err
+	}
+	for _, v := range t.Reports {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ReportConsensusFaultsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ReportConsensusFaultsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Reports ([]power.ConsensusFaultReport) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Reports: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Reports = make([]ConsensusFaultReport, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ConsensusFaultReport
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Reports[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufReportConsensusFaultsReturn = []byte{130}
+
+func (t *ReportConsensusFaultsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufReportConsensusFaultsReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Outcomes ([]power.ConsensusFaultReportOutcome) (slice)
+	if len(t.Outcomes) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Outcomes was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Outcomes))); err != nil {
+		return err
+	}
+	for _, v := range t.Outcomes {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+
+	// t.Reward (big.Int) (struct)
+	if err := t.Reward.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ReportConsensusFaultsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = ReportConsensusFaultsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Outcomes ([]power.ConsensusFaultReportOutcome) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Outcomes: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Outcomes = make([]ConsensusFaultReportOutcome, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.Outcomes slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.Outcomes was not a uint, instead got %d", maj)
+		}
+
+		t.Outcomes[i] = ConsensusFaultReportOutcome(val)
+	}
+
+	// t.Reward (big.Int) (struct)
+
+	{
+
+		if err := t.Reward.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Reward: %w", err)
+		}
+
+	}
+	return nil
+}
@@ -10,3 +10,8 @@ const ConsensusMinerMinMiners = 4 // PARAM_SPEC
 // This limits the number of proof partitions we may need to load in the cron call path.
 // Onboarding 1EiB/year requires at least 32 prove-commits per epoch.
 const MaxMinerProveCommitsPerEpoch = 200 // PARAM_SPEC
+
+// Maximum number of consensus faults that may be reported in a single batch.
+//
+// This bounds the number of miner actors invoked by one message.
+const MaxConsensusFaultReports = 32
@@ -11,6 +11,7 @@ import (
 	rtt "github.com/filecoin-project/go-state-types/rt"
 	xerrors "golang.org/x/xerrors"
 
+	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	power0 "github.com/filecoin-project/specs-actors/actors/builtin/power"
 	power3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/power"
 	power6 "github.com/filecoin-project/specs-actors/v6/actors/builtin/power"
@@ -45,6 +46,7 @@ func (a Actor) Exports() []interface{} {
 		7:                         nil, // deprecated
 		8:                         a.SubmitPoRepForBulkVerify,
 		9:                         a.CurrentTotalPower,
+		10:                        a.ReportConsensusFaults,
 	}
 }
 
@@ -335,6 +337,112 @@ func (a Actor) CurrentTotalPower(rt Runtime, _ *abi.EmptyValue) *CurrentTotalPow
 	}
 }
 
+// The consensus fault report params accepted by the miner actor.
+//type ReportConsensusFaultParams struct {
+//	BlockHeader1     []byte
+//	BlockHeader2     []byte
+//	BlockHeaderExtra []byte
+//}
+type ReportConsensusFaultParams = miner0.ReportConsensusFaultParams
+
+type ConsensusFaultReport struct {
+	Miner addr.Address
+	Fault ReportConsensusFaultParams
+}
+
+type ReportConsensusFaultsParams struct {
+	Reports []ConsensusFaultReport
+}
+
+type ConsensusFaultReportOutcome uint64
+
+const (
+	// The fault was verified and the miner penalized.
+	ConsensusFaultAccepted ConsensusFaultReportOutcome = iota
+	// The miner was already penalized by an earlier report in the same batch.
+	ConsensusFaultDuplicate
+	// The fault occurred before the end of the miner's exclusion period for a previously reported fault.
+	ConsensusFaultExpired
+	// The report does not target a miner with a power claim, or the fault could not be verified for the miner.
+	ConsensusFaultInvalid
+)
+
+type ReportConsensusFaultsReturn struct {
+	// One outcome for each report, in the order of the reports.
+	Outcomes []ConsensusFaultReportOutcome
+	// The total reward paid to the reporter.
+	Reward abi.TokenAmount
+}
+
+// Reports a batch of consensus faults, each targeting a miner.
+// Each report is forwarded to its miner, which verifies the fault and applies the penalty as if the
+// fault were reported directly. A report that fails does not revert the others.
+// The rewards paid by the miners for accepted reports are passed on to the reporter in a single transfer.
+func (a Actor) ReportConsensusFaults(rt Runtime, params *ReportConsensusFaultsParams) *ReportConsensusFaultsReturn {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	reporter := rt.Caller()
+
+	if len(params.Reports) == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no consensus faults reported")
+	}
+	if len(params.Reports) > MaxConsensusFaultReports {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many consensus faults reported %d, max %d", len(params.Reports), MaxConsensusFaultReports)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	claims, err := adt.AsMap(adt.AsStore(rt), st.Claims, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
+
+	balanceBefore := rt.CurrentBalance()
+	outcomes := make([]ConsensusFaultReportOutcome, len(params.Reports))
+	penalized := map[addr.Address]bool{}
+	for i, report := range params.Reports {
+		minerAddr, ok := rt.ResolveAddress(report.Miner)
+		if !ok {
+			rt.Log(rtt.INFO, "consensus fault report %d targets unknown address %s", i, report.Miner)
+			outcomes[i] = ConsensusFaultInvalid
+			continue
+		}
+		found, err := claims.Has(abi.AddrKey(minerAddr))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to look up claim")
+		if !found {
+			rt.Log(rtt.INFO, "consensus fault report %d targets %s, which is not a miner", i, minerAddr)
+			outcomes[i] = ConsensusFaultInvalid
+			continue
+		}
+		if penalized[minerAddr] {
+			outcomes[i] = ConsensusFaultDuplicate
+			continue
+		}
+
+		fault := report.Fault
+		code := rt.Send(minerAddr, builtin.MethodsMiner.ReportConsensusFault, &fault, big.Zero(), &builtin.Discard{})
+		switch {
+		case code.IsSuccess():
+			outcomes[i] = ConsensusFaultAccepted
+			penalized[minerAddr] = true
+		case code == exitcode.ErrForbidden:
+			outcomes[i] = ConsensusFaultExpired
+		default:
+			rt.Log(rtt.INFO, "consensus fault report %d rejected by miner %s: %s", i, minerAddr, code)
+			outcomes[i] = ConsensusFaultInvalid
+		}
+	}
+
+	// Only the rewards received from the miners above are passed on.
+	reward := big.Sub(rt.CurrentBalance(), balanceBefore)
+	if reward.GreaterThan(big.Zero()) {
+		code := rt.Send(reporter, builtin.MethodSend, nil, reward, &builtin.Discard{})
+		builtin.RequireSuccess(rt, code, "failed to send reward to reporter")
+	}
+
+	return &ReportConsensusFaultsReturn{
+		Outcomes: outcomes,
+		Reward:   reward,
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Method utility functions
 ////////////////////////////////////////////////////////////////////////////////
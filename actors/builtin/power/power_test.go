@@ -913,6 +913,105 @@ func TestSubmitPoRepForBulkVerify(t *testing.T) {
 	})
 }
 
+func TestReportConsensusFaults(t *testing.T) {
+	actor := newHarness(t)
+	owner := tutil.NewIDAddr(t, 101)
+	miner1 := tutil.NewIDAddr(t, 111)
+	miner2 := tutil.NewIDAddr(t, 112)
+	reporter := tutil.NewIDAddr(t, 121)
+	builder := mock.NewBuilder(builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	fault := power.ReportConsensusFaultParams{
+		BlockHeader1: []byte("header1"),
+		BlockHeader2: []byte("header2"),
+	}
+
+	t.Run("classifies each report by the miner's response", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.createMinerBasic(rt, owner, owner, miner2)
+
+		params := &power.ReportConsensusFaultsParams{Reports: []power.ConsensusFaultReport{
+			{Miner: miner1, Fault: fault},
+			{Miner: miner1, Fault: fault},
+			{Miner: owner, Fault: fault},
+			{Miner: tutil.NewBLSAddr(t, 1), Fault: fault},
+			{Miner: miner2, Fault: fault},
+		}}
+		rt.ExpectSend(miner1, builtin.MethodsMiner.ReportConsensusFault, &fault, big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(miner2, builtin.MethodsMiner.ReportConsensusFault, &fault, big.Zero(), nil, exitcode.ErrForbidden)
+		ret := actor.reportConsensusFaults(rt, reporter, params)
+
+		assert.Equal(t, []power.ConsensusFaultReportOutcome{
+			power.ConsensusFaultAccepted,
+			power.ConsensusFaultDuplicate,
+			power.ConsensusFaultInvalid,
+			power.ConsensusFaultInvalid,
+			power.ConsensusFaultExpired,
+		}, ret.Outcomes)
+		assert.Equal(t, big.Zero(), ret.Reward)
+		actor.checkState(rt)
+	})
+
+	t.Run("a rejected report does not prevent a later report for the same miner", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMinerBasic(rt, owner, owner, miner1)
+
+		badFault := power.ReportConsensusFaultParams{BlockHeader1: []byte("bad")}
+		params := &power.ReportConsensusFaultsParams{Reports: []power.ConsensusFaultReport{
+			{Miner: miner1, Fault: badFault},
+			{Miner: miner1, Fault: fault},
+		}}
+		rt.ExpectSend(miner1, builtin.MethodsMiner.ReportConsensusFault, &badFault, big.Zero(), nil, exitcode.ErrIllegalArgument)
+		rt.ExpectSend(miner1, builtin.MethodsMiner.ReportConsensusFault, &fault, big.Zero(), nil, exitcode.Ok)
+		ret := actor.reportConsensusFaults(rt, reporter, params)
+
+		assert.Equal(t, []power.ConsensusFaultReportOutcome{
+			power.ConsensusFaultInvalid,
+			power.ConsensusFaultAccepted,
+		}, ret.Outcomes)
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects an empty batch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no consensus faults", func() {
+			actor.reportConsensusFaults(rt, reporter, &power.ReportConsensusFaultsParams{})
+		})
+		rt.Reset()
+	})
+
+	t.Run("rejects too many reports", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		reports := make([]power.ConsensusFaultReport, power.MaxConsensusFaultReports+1)
+		for i := range reports {
+			reports[i] = power.ConsensusFaultReport{Miner: miner1, Fault: fault}
+		}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many consensus faults", func() {
+			actor.reportConsensusFaults(rt, reporter, &power.ReportConsensusFaultsParams{Reports: reports})
+		})
+		rt.Reset()
+	})
+
+	t.Run("rejects a caller that is not signable", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.ReportConsensusFaults, &power.ReportConsensusFaultsParams{})
+		})
+		rt.Reset()
+	})
+}
+
 func TestCronBatchProofVerifies(t *testing.T) {
 	sealInfo := func(i int) *proof.SealVerifyInfo {
 		var sealInfo proof.SealVerifyInfo
@@ -1395,6 +1494,14 @@ func (h *spActorHarness) submitPoRepForBulkVerify(rt *mock.Runtime, minerAddr ad
 	rt.Verify()
 }
 
+func (h *spActorHarness) reportConsensusFaults(rt *mock.Runtime, reporter addr.Address, params *power.ReportConsensusFaultsParams) *power.ReportConsensusFaultsReturn {
+	rt.SetCaller(reporter, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	ret := rt.Call(h.ReportConsensusFaults, params).(*power.ReportConsensusFaultsReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *spActorHarness) expectTotalPowerEager(rt *mock.Runtime, expectedRaw, expectedQA abi.StoragePower) {
 	st := getState(rt)
 
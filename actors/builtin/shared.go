@@ -10,8 +10,10 @@ import (
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	builtin0 "github.com/filecoin-project/specs-actors/actors/builtin"
+	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
 	builtin6 "github.com/filecoin-project/specs-actors/v6/actors/builtin"
+	miner7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
 )
 
@@ -97,6 +99,54 @@ func RequestMinerControlAddrs(rt runtime.Runtime, minerAddr addr.Address) (owner
 //}
 type MinerAddrs = builtin0.MinerAddrs
 
+// Returns a miner's on-chain info for a sector, or false if the miner has no such sector.
+func RequestMinerSectorInfo(rt runtime.Runtime, minerAddr addr.Address, sectorNo abi.SectorNumber) (*MinerSectorInfo, bool) {
+	var info MinerSectorInfo
+	code := rt.Send(minerAddr, MethodsMiner.GetSectorInfo, &MinerSectorParams{SectorNumber: sectorNo}, abi.NewTokenAmount(0), &info)
+	if code == exitcode.ErrNotFound {
+		return nil, false
+	}
+	RequireSuccess(rt, code, "failed fetching info for sector %d", sectorNo)
+	return &info, true
+}
+
+// Returns whether a miner's sector is active, i.e. proven, neither faulty nor terminated.
+func RequestMinerSectorActive(rt runtime.Runtime, minerAddr addr.Address, sectorNo abi.SectorNumber) bool {
+	var ret MinerSectorActiveReturn
+	code := rt.Send(minerAddr, MethodsMiner.IsSectorActive, &MinerSectorParams{SectorNumber: sectorNo}, abi.NewTokenAmount(0), &ret)
+	RequireSuccess(rt, code, "failed checking whether sector %d is active", sectorNo)
+	return ret.Active
+}
+
+// These types duplicate the Miner.GetSectorInfo parameter and return types, to work around a circular dependency between actors.
+//type MinerSectorParams struct {
+//	SectorNumber abi.SectorNumber
+//}
+type MinerSectorParams = miner0.CheckSectorProvenParams
+
+//type MinerSectorInfo struct {
+//	SectorNumber          abi.SectorNumber
+//	SealProof             abi.RegisteredSealProof
+//	SealedCID             cid.Cid
+//	DealIDs               []abi.DealID
+//	Activation            abi.ChainEpoch
+//	Expiration            abi.ChainEpoch
+//	DealWeight            abi.DealWeight
+//	VerifiedDealWeight    abi.DealWeight
+//	InitialPledge         abi.TokenAmount
+//	ExpectedDayReward     abi.TokenAmount
+//	ExpectedStoragePledge abi.TokenAmount
+//	ReplacedSectorAge     abi.ChainEpoch
+//	ReplacedDayReward     abi.TokenAmount
+//	SectorKeyCID          *cid.Cid
+//}
+type MinerSectorInfo = miner7.SectorOnChainInfo
+
+// This type duplicates the Miner.IsSectorActive return type, to work around a circular dependency between actors.
+type MinerSectorActiveReturn struct {
+	Active bool
+}
+
 //type DeferredCronEventParams struct {
 //	EventPayload            []byte
 //	RewardSmoothed          smoothing.FilterEstimate
@@ -1,11 +1,15 @@
 package nv16
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"unicode/utf8"
 
+	addr "github.com/filecoin-project/go-address"
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/go-state-types/abi"
 
@@ -41,6 +45,21 @@ func (m marketMigrator) migrateState(ctx context.Context, store cbor.IpldStore,
 		return nil, err
 	}
 
+	providerDealsCidOut, clientDealsCidOut, err := BuildDealIndexes(ctx, wrappedStore, proposalsCidOut)
+	if err != nil {
+		return nil, err
+	}
+
+	dealOpsCidOut, err := RescheduleDealExpiries(ctx, wrappedStore, proposalsCidOut, inState.States, inState.DealOpsByEpoch, in.priorEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	dealPaymentsCidOut, err := adt.StoreEmptyMap(wrappedStore, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
 	outState := market.State{
 		Proposals:                     proposalsCidOut,
 		States:                        inState.States,
@@ -48,11 +67,14 @@ func (m marketMigrator) migrateState(ctx context.Context, store cbor.IpldStore,
 		EscrowTable:                   inState.EscrowTable,
 		LockedTable:                   inState.LockedTable,
 		NextID:                        inState.NextID,
-		DealOpsByEpoch:                inState.DealOpsByEpoch,
+		DealOpsByEpoch:                dealOpsCidOut,
 		LastCron:                      inState.LastCron,
 		TotalClientLockedCollateral:   inState.TotalClientLockedCollateral,
 		TotalProviderLockedCollateral: inState.TotalProviderLockedCollateral,
 		TotalClientStorageFee:         inState.TotalClientStorageFee,
+		ProviderDeals:                 providerDealsCidOut,
+		ClientDeals:                   clientDealsCidOut,
+		DealPayments:                  dealPaymentsCidOut,
 	}
 
 	newHead, err := store.Put(ctx, &outState)
@@ -169,6 +191,114 @@ func UpdatePendingProposals(ctx context.Context, store adt.Store, changedProposa
 	return pendingProposalsCid, nil
 }
 
+// BuildDealIndexes indexes every deal proposal by its provider and by its client, returning the roots of
+// the provider and client indexes.
+func BuildDealIndexes(ctx context.Context, store adt.Store, proposalsRoot cid.Cid) (cid.Cid, cid.Cid, error) {
+	proposals, err := market.AsDealProposalArray(store, proposalsRoot)
+	if err != nil {
+		return cid.Undef, cid.Undef, err
+	}
+	providerDeals, err := market.MakeEmptyDealIndex(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, cid.Undef, err
+	}
+	clientDeals, err := market.MakeEmptyDealIndex(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, cid.Undef, err
+	}
+
+	// Group deals by party so that each party's set is written once.
+	byProvider := make(map[addr.Address][]abi.DealID)
+	byClient := make(map[addr.Address][]abi.DealID)
+	var proposal market.DealProposal
+	err = proposals.ForEach(&proposal, func(key int64) error {
+		byProvider[proposal.Provider] = append(byProvider[proposal.Provider], abi.DealID(key))
+		byClient[proposal.Client] = append(byClient[proposal.Client], abi.DealID(key))
+		return nil
+	})
+	if err != nil {
+		return cid.Undef, cid.Undef, err
+	}
+	if err := putDealIndex(providerDeals, byProvider); err != nil {
+		return cid.Undef, cid.Undef, xerrors.Errorf("failed to index deals by provider: %w", err)
+	}
+	if err := putDealIndex(clientDeals, byClient); err != nil {
+		return cid.Undef, cid.Undef, xerrors.Errorf("failed to index deals by client: %w", err)
+	}
+
+	providerDealsCid, err := providerDeals.Root()
+	if err != nil {
+		return cid.Undef, cid.Undef, err
+	}
+	clientDealsCid, err := clientDeals.Root()
+	if err != nil {
+		return cid.Undef, cid.Undef, err
+	}
+	return providerDealsCid, clientDealsCid, nil
+}
+
+// Writes the deals of each party to an index, in order of address.
+func putDealIndex(index *market.DealIndex, deals map[addr.Address][]abi.DealID) error {
+	parties := make([]addr.Address, 0, len(deals))
+	for a := range deals { //nolint:nomaprange
+		parties = append(parties, a)
+	}
+	sort.Slice(parties, func(i, j int) bool {
+		return bytes.Compare(parties[i].Bytes(), parties[j].Bytes()) < 0
+	})
+	for _, a := range parties {
+		if err := index.PutMany(a, deals[a]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RescheduleDealExpiries moves the next processing of each deal which has been processed since activation, has not
+// been terminated and has not yet ended, from one update interval after it was last processed to its expiry.
+// Deal payments are otherwise settled on demand, so cron next processes such deals when they expire.
+// Other deals are left where they are: cron processes them in the same way wherever they are scheduled.
+func RescheduleDealExpiries(ctx context.Context, store adt.Store, proposalsRoot, statesRoot, dealOpsRoot cid.Cid, priorEpoch abi.ChainEpoch) (cid.Cid, error) {
+	proposals, err := market.AsDealProposalArray(store, proposalsRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	states, err := market.AsDealStateArray(store, statesRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	dealOps, err := market.AsSetMultimap(store, dealOpsRoot, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	var state market.DealState
+	err = states.ForEach(&state, func(key int64) error {
+		if state.LastUpdatedEpoch == market.EpochUndefined || state.SlashEpoch != market.EpochUndefined {
+			return nil
+		}
+		dealID := abi.DealID(key)
+		proposal, found, err := proposals.Get(dealID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return xerrors.Errorf("no proposal for deal state %d", dealID)
+		}
+		if proposal.EndEpoch <= priorEpoch {
+			return nil
+		}
+		if err := dealOps.Remove(state.LastUpdatedEpoch+market.DealUpdatesInterval, dealID); err != nil {
+			return err
+		}
+		return dealOps.Put(market.GenRandNextEpoch(proposal.EndEpoch, dealID), dealID)
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+	return dealOps.Root()
+}
+
 // An adt.Map key that just preserves the underlying string.
 type StringKey string
 
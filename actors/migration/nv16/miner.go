@@ -0,0 +1,213 @@
+package nv16
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-state-types/big"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+
+	miner7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// Miner Actor migrator
+// Migrates the miner info to set the owner as the beneficiary, with an empty beneficiary term,
+// and the default debt repayment policy, marks each optimistically accepted Window PoSt as not aggregated,
+// records pre-committed sectors as having no prove-commit extension, and adds an empty queue of scheduled recoveries.
+// All other state is unchanged.
+type minerMigrator struct {
+	OutCodeCID               cid.Cid
+	EmptyScheduledRecoveries cid.Cid
+}
+
+func (m minerMigrator) migratedCodeCID() cid.Cid {
+	return m.OutCodeCID
+}
+
+func (m minerMigrator) migrateState(ctx context.Context, store cbor.IpldStore, in actorMigrationInput) (*actorMigrationResult, error) {
+	var inState miner7.State
+	if err := store.Get(ctx, in.head, &inState); err != nil {
+		return nil, err
+	}
+	var inInfo miner7.MinerInfo
+	if err := store.Get(ctx, inState.Info, &inInfo); err != nil {
+		return nil, err
+	}
+
+	var pendingWorkerKey *miner.WorkerKeyChange
+	if inInfo.PendingWorkerKey != nil {
+		pendingWorkerKey = &miner.WorkerKeyChange{
+			NewWorker:   inInfo.PendingWorkerKey.NewWorker,
+			EffectiveAt: inInfo.PendingWorkerKey.EffectiveAt,
+		}
+	}
+	outInfo := miner.MinerInfo{
+		Owner:                      inInfo.Owner,
+		Worker:                     inInfo.Worker,
+		ControlAddresses:           inInfo.ControlAddresses,
+		PendingWorkerKey:           pendingWorkerKey,
+		PeerId:                     inInfo.PeerId,
+		Multiaddrs:                 inInfo.Multiaddrs,
+		WindowPoStProofType:        inInfo.WindowPoStProofType,
+		SectorSize:                 inInfo.SectorSize,
+		WindowPoStPartitionSectors: inInfo.WindowPoStPartitionSectors,
+		ConsensusFaultElapsed:      inInfo.ConsensusFaultElapsed,
+		PendingOwnerAddress:        inInfo.PendingOwnerAddress,
+		Beneficiary:                inInfo.Owner,
+		BeneficiaryTerm: miner.BeneficiaryTerm{
+			Quota:      big.Zero(),
+			UsedQuota:  big.Zero(),
+			Expiration: 0,
+		},
+		PendingBeneficiaryTerm: nil,
+		DebtRepaymentPolicy: miner.DebtRepaymentPolicy{
+			Priority:     miner.DebtRepaymentVestingFirst,
+			MaxRepayment: big.Zero(),
+		},
+	}
+	infoCidOut, err := store.Put(ctx, &outInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	adtStore := adt.WrapStore(ctx, store)
+	deadlinesOut, err := migrateDeadlines(ctx, adtStore, inState.Deadlines)
+	if err != nil {
+		return nil, err
+	}
+
+	precommitsOut, err := migratePreCommits(adtStore, inState.PreCommittedSectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to migrate pre-committed sectors: %w", err)
+	}
+
+	outState := miner.State{
+		Info:                       infoCidOut,
+		PreCommitDeposits:          inState.PreCommitDeposits,
+		LockedFunds:                inState.LockedFunds,
+		VestingFunds:               inState.VestingFunds,
+		FeeDebt:                    inState.FeeDebt,
+		InitialPledge:              inState.InitialPledge,
+		PreCommittedSectors:        precommitsOut,
+		PreCommittedSectorsCleanUp: inState.PreCommittedSectorsCleanUp,
+		AllocatedSectors:           inState.AllocatedSectors,
+		Sectors:                    inState.Sectors,
+		ProvingPeriodStart:         inState.ProvingPeriodStart,
+		CurrentDeadline:            inState.CurrentDeadline,
+		Deadlines:                  deadlinesOut,
+		EarlyTerminations:          inState.EarlyTerminations,
+		DeadlineCronActive:         inState.DeadlineCronActive,
+		ScheduledRecoveries:        m.EmptyScheduledRecoveries,
+	}
+
+	newHead, err := store.Put(ctx, &outState)
+	return &actorMigrationResult{
+		newCodeCID: m.migratedCodeCID(),
+		newHead:    newHead,
+	}, err
+}
+
+// Rewrites the Window PoSt submissions of each deadline in the new schema.
+// Deadlines without any submissions are unchanged.
+func migrateDeadlines(ctx context.Context, store adt.Store, deadlinesIn cid.Cid) (cid.Cid, error) {
+	var deadlines miner.Deadlines
+	if err := store.Get(ctx, deadlinesIn, &deadlines); err != nil {
+		return cid.Undef, err
+	}
+
+	changed := false
+	for i, dlCid := range deadlines.Due {
+		var deadline miner.Deadline
+		if err := store.Get(ctx, dlCid, &deadline); err != nil {
+			return cid.Undef, err
+		}
+
+		submissions, submissionsChanged, err := migratePoStSubmissions(store, deadline.OptimisticPoStSubmissions)
+		if err != nil {
+			return cid.Undef, xerrors.Errorf("failed to migrate submissions for deadline %d: %w", i, err)
+		}
+		snapshot, snapshotChanged, err := migratePoStSubmissions(store, deadline.OptimisticPoStSubmissionsSnapshot)
+		if err != nil {
+			return cid.Undef, xerrors.Errorf("failed to migrate submissions snapshot for deadline %d: %w", i, err)
+		}
+		if !submissionsChanged && !snapshotChanged {
+			continue
+		}
+
+		deadline.OptimisticPoStSubmissions = submissions
+		deadline.OptimisticPoStSubmissionsSnapshot = snapshot
+		if deadlines.Due[i], err = store.Put(ctx, &deadline); err != nil {
+			return cid.Undef, err
+		}
+		changed = true
+	}
+
+	if !changed {
+		return deadlinesIn, nil
+	}
+	return store.Put(ctx, &deadlines)
+}
+
+// Rewrites an array of Window PoSt submissions, preserving indices. Returns whether the array is changed,
+// which is the case unless it is empty.
+func migratePoStSubmissions(store adt.Store, root cid.Cid) (cid.Cid, bool, error) {
+	inArray, err := adt.AsArray(store, root, miner7.DeadlineOptimisticPoStSubmissionsAmtBitwidth)
+	if err != nil {
+		return cid.Undef, false, err
+	}
+	if inArray.Length() == 0 {
+		return root, false, nil
+	}
+
+	outArray, err := adt.MakeEmptyArray(store, miner.DeadlineOptimisticPoStSubmissionsAmtBitwidth)
+	if err != nil {
+		return cid.Undef, false, err
+	}
+	var inPoSt miner7.WindowedPoSt
+	err = inArray.ForEach(&inPoSt, func(i int64) error {
+		return outArray.Set(uint64(i), &miner.WindowedPoSt{
+			Partitions: inPoSt.Partitions,
+			Proofs:     inPoSt.Proofs,
+			Aggregated: false,
+		})
+	})
+	if err != nil {
+		return cid.Undef, false, err
+	}
+
+	outRoot, err := outArray.Root()
+	return outRoot, true, err
+}
+
+// Rewrites the pre-committed sectors in the new schema, with no prove-commit extension.
+func migratePreCommits(store adt.Store, root cid.Cid) (cid.Cid, error) {
+	inMap, err := adt.AsMap(store, root, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+	outMap, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	var inPreCommit miner7.SectorPreCommitOnChainInfo
+	err = inMap.ForEach(&inPreCommit, func(_ string) error {
+		return outMap.Put(miner.SectorKey(inPreCommit.Info.SectorNumber), &miner.SectorPreCommitOnChainInfo{
+			Info:                 miner.SectorPreCommitInfo(inPreCommit.Info),
+			PreCommitDeposit:     inPreCommit.PreCommitDeposit,
+			PreCommitEpoch:       inPreCommit.PreCommitEpoch,
+			DealWeight:           inPreCommit.DealWeight,
+			VerifiedDealWeight:   inPreCommit.VerifiedDealWeight,
+			ProveCommitExtension: 0,
+		})
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+	return outMap.Root()
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 	"unicode/utf8"
 
@@ -213,8 +214,53 @@ func TestDealLabelMigration(t *testing.T) {
 
 	var market8State market.State
 	require.NoError(t, v8.GetState(builtin.StorageMarketActorAddr, &market8State))
-	market.CheckStateInvariants(&market8State, v8.Store(), oldMarketActor.Balance, v.GetEpoch()+1)
+	_, msgs := market.CheckStateInvariants(&market8State, v8.Store(), oldMarketActor.Balance, v.GetEpoch()+1)
+	require.True(t, msgs.IsEmpty(), strings.Join(msgs.Messages(), "\n"))
 
+	// every deal is indexed by its provider and its client
+	clientID, found := v.NormalizeAddress(client)
+	require.True(t, found)
+	require.ElementsMatch(t, dealIDs, indexedDeals(t, adtStore, market8State.ProviderDeals, minerAddrs.IDAddress))
+	require.ElementsMatch(t, dealIDs, indexedDeals(t, adtStore, market8State.ClientDeals, clientID))
+	require.Empty(t, indexedDeals(t, adtStore, market8State.ProviderDeals, clientID))
+
+	// migrated deals are paid from escrow, so none are paid through a payment channel
+	dealPayments, err := adt.AsMap(adtStore, market8State.DealPayments, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	paymentKeys, err := dealPayments.CollectKeys()
+	require.NoError(t, err)
+	require.Empty(t, paymentKeys)
+
+	// cronned deals are rescheduled from their next update to their expiry, others remain scheduled after their start
+	dealEnd := dealStart + 365*builtin.EpochsInDay
+	for dealID, cronTime := range map[abi.DealID]abi.ChainEpoch{deal1ID: deal1CronTime, deal2ID: deal2CronTime} {
+		require.NotContains(t, scheduledDeals(t, adtStore, market8State.DealOpsByEpoch, cronTime+market.DealUpdatesInterval), dealID)
+		require.Contains(t, scheduledDeals(t, adtStore, market8State.DealOpsByEpoch, market.GenRandNextEpoch(dealEnd, dealID)), dealID)
+	}
+	require.Contains(t, scheduledDeals(t, adtStore, market8State.DealOpsByEpoch, deal3CronTime), deal3ID)
+	require.Contains(t, scheduledDeals(t, adtStore, market8State.DealOpsByEpoch, deal5CronTime), deal5ID)
+}
+
+func scheduledDeals(t *testing.T, store adt.Store, root cid.Cid, epoch abi.ChainEpoch) []abi.DealID {
+	dealOps, err := market.AsSetMultimap(store, root, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var dealIDs []abi.DealID
+	require.NoError(t, dealOps.ForEach(epoch, func(dealID abi.DealID) error {
+		dealIDs = append(dealIDs, dealID)
+		return nil
+	}))
+	return dealIDs
+}
+
+func indexedDeals(t *testing.T, store adt.Store, root cid.Cid, a addr.Address) []abi.DealID {
+	index, err := market.AsDealIndex(store, root, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var dealIDs []abi.DealID
+	require.NoError(t, index.ForEach(a, func(dealID abi.DealID) error {
+		dealIDs = append(dealIDs, dealID)
+		return nil
+	}))
+	return dealIDs
 }
 
 func publishDealv7(t *testing.T, v *vm7.VM, provider, dealClient, minerID addr.Address, dealLabel string,
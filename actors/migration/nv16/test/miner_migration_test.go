@@ -0,0 +1,190 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/rt"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+	miner7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
+	power7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/power"
+	vm7 "github.com/filecoin-project/specs-actors/v7/support/vm"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/exported"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/migration/nv16"
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+	"github.com/filecoin-project/specs-actors/v8/support/vm7Util"
+)
+
+func TestMinerBeneficiaryMigration(t *testing.T) {
+	ctx := context.Background()
+	log := nv16.TestLogger{TB: t}
+	bs := ipld2.NewSyncBlockStoreInMemory()
+	v := vm7.NewVMWithSingletons(ctx, t, bs)
+
+	addrs := vm7.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	owner, worker, newOwner := addrs[0], addrs[1], addrs[2]
+
+	params := power7.CreateMinerParams{
+		Owner:               owner,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	ret := vm7.ApplyOk(t, v, owner, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &params)
+	minerAddrs, ok := ret.(*power7.CreateMinerReturn)
+	require.True(t, ok)
+
+	// Leave an owner change pending across the migration.
+	newOwnerID, found := v.NormalizeAddress(newOwner)
+	require.True(t, found)
+	vm7.ApplyOk(t, v, owner, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ChangeOwnerAddress, &newOwnerID)
+	v = vm7Util.AdvanceToEpochWithCron(t, v, 200)
+
+	// Leave a sector pre-committed across the migration.
+	precommits := vm7Util.PreCommitSectors(t, v, 1, 1, worker, minerAddrs.IDAddress, abi.RegisteredSealProof_StackedDrg32GiBV1_1, 100, true, -1, nil)
+
+	var minerState7 miner7.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &minerState7))
+	adtStore := adt.WrapStore(ctx, v.Store())
+	info7, err := minerState7.GetInfo(adtStore)
+	require.NoError(t, err)
+
+	manifestCid := makeTestManifest(t, adtStore)
+	nextRoot, err := nv16.MigrateStateTree(ctx, adtStore, manifestCid, v.StateRoot(), v.GetEpoch(), nv16.Config{MaxWorkers: 1}, log, nv16.NewMemMigrationCache())
+	require.NoError(t, err)
+
+	lookup := map[cid.Cid]rt.VMActor{}
+	for _, ba := range exported.BuiltinActors() {
+		lookup[ba.Code()] = ba
+	}
+	v8, err := vm.NewVMAtEpoch(ctx, lookup, v.Store(), nextRoot, v.GetEpoch())
+	require.NoError(t, err)
+
+	var minerState miner.State
+	require.NoError(t, v8.GetState(minerAddrs.IDAddress, &minerState))
+	info, err := minerState.GetInfo(adtStore)
+	require.NoError(t, err)
+
+	assert.Equal(t, info7.Owner, info.Owner)
+	assert.Equal(t, info7.Worker, info.Worker)
+	assert.Equal(t, info7.PeerId, info.PeerId)
+	assert.Equal(t, info7.SectorSize, info.SectorSize)
+	assert.Equal(t, info7.ConsensusFaultElapsed, info.ConsensusFaultElapsed)
+	require.NotNil(t, info.PendingOwnerAddress)
+	assert.Equal(t, *info7.PendingOwnerAddress, *info.PendingOwnerAddress)
+
+	assert.Equal(t, info.Owner, info.Beneficiary)
+	assert.Equal(t, big.Zero(), info.BeneficiaryTerm.Quota)
+	assert.Equal(t, big.Zero(), info.BeneficiaryTerm.UsedQuota)
+	assert.Equal(t, abi.ChainEpoch(0), info.BeneficiaryTerm.Expiration)
+	assert.Nil(t, info.PendingBeneficiaryTerm)
+	assert.Equal(t, miner.DebtRepaymentVestingFirst, info.DebtRepaymentPolicy.Priority)
+	assert.Equal(t, big.Zero(), info.DebtRepaymentPolicy.MaxRepayment)
+
+	// The pre-commitment is carried over with no prove-commit extension.
+	precommit, found, err := minerState.GetPrecommittedSector(adtStore, precommits[0].Info.SectorNumber)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, precommits[0].PreCommitDeposit, precommit.PreCommitDeposit)
+	assert.Equal(t, precommits[0].PreCommitEpoch, precommit.PreCommitEpoch)
+	assert.Equal(t, precommits[0].Info.SealedCID, precommit.Info.SealedCID)
+	assert.Equal(t, abi.ChainEpoch(0), precommit.ProveCommitExtension)
+
+	// No recoveries are scheduled.
+	scheduledRecoveries, err := adt.AsArray(adtStore, minerState.ScheduledRecoveries, miner.ScheduledRecoveriesAmtBitwidth)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), scheduledRecoveries.Length())
+
+	// All state other than the info and pre-commitments is carried over unchanged.
+	assert.Equal(t, minerState7.Sectors, minerState.Sectors)
+	assert.Equal(t, minerState7.Deadlines, minerState.Deadlines)
+	assert.Equal(t, minerState7.ProvingPeriodStart, minerState.ProvingPeriodStart)
+
+	stateTree, err := v8.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v8.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v8.GetEpoch()-1)
+	require.NoError(t, err)
+	require.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
+
+func TestMinerPoStSubmissionsMigration(t *testing.T) {
+	ctx := context.Background()
+	log := nv16.TestLogger{TB: t}
+	bs := ipld2.NewSyncBlockStoreInMemory()
+	v := vm7.NewVMWithSingletons(ctx, t, bs)
+
+	addrs := vm7.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker := addrs[0]
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+
+	params := power7.CreateMinerParams{
+		Owner:               worker,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	ret := vm7.ApplyOk(t, v, worker, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &params)
+	minerAddrs, ok := ret.(*power7.CreateMinerReturn)
+	require.True(t, ok)
+
+	// Prove a sector and optimistically submit an invalid PoSt for it.
+	v = vm7Util.AdvanceToEpochWithCron(t, v, 200)
+	precommits := vm7Util.PreCommitSectors(t, v, 1, 1, worker, minerAddrs.IDAddress, sealProof, 100, true, -1, nil)
+	sectorNumber := precommits[0].Info.SectorNumber
+	proveTime := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	v = vm7Util.AdvanceToEpochWithCron(t, v, proveTime)
+	vm7.ApplyOk(t, v, worker, minerAddrs.IDAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSector, &miner7.ProveCommitSectorParams{SectorNumber: sectorNumber})
+	vm7.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	dlInfo, pIdx, v := vm7.AdvanceTillProvingDeadline(t, v, minerAddrs.IDAddress, sectorNumber)
+	vm7.SubmitInvalidPoSt(t, v, minerAddrs.IDAddress, worker, dlInfo, pIdx)
+
+	adtStore := adt.WrapStore(ctx, v.Store())
+	manifestCid := makeTestManifest(t, adtStore)
+	nextRoot, err := nv16.MigrateStateTree(ctx, adtStore, manifestCid, v.StateRoot(), v.GetEpoch(), nv16.Config{MaxWorkers: 1}, log, nv16.NewMemMigrationCache())
+	require.NoError(t, err)
+
+	lookup := map[cid.Cid]rt.VMActor{}
+	for _, ba := range exported.BuiltinActors() {
+		lookup[ba.Code()] = ba
+	}
+	v8, err := vm.NewVMAtEpoch(ctx, lookup, v.Store(), nextRoot, v.GetEpoch())
+	require.NoError(t, err)
+
+	// The submission is carried over, not aggregated.
+	deadline := vm.DeadlineState(t, v8, minerAddrs.IDAddress, dlInfo.Index)
+	submissions, err := adt.AsArray(adtStore, deadline.OptimisticPoStSubmissions, miner.DeadlineOptimisticPoStSubmissionsAmtBitwidth)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), submissions.Length())
+	var post miner.WindowedPoSt
+	found, err := submissions.Get(0, &post)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.False(t, post.Aggregated)
+	require.Len(t, post.Proofs, 1)
+	assert.Equal(t, []byte(vm7.InvalidProof), post.Proofs[0].ProofBytes)
+	partitions, err := post.Partitions.All(miner.AddressedPartitionsMax)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{pIdx}, partitions)
+
+	// The migrated submission can be disputed once its challenge window has closed.
+	v8, _ = vm.AdvanceByDeadlineTillEpoch(t, v8, minerAddrs.IDAddress, v8.GetEpoch()+miner.WPoStChallengeWindow)
+	v8 = vm.AdvanceOneEpochWithCron(t, v8)
+	vm.ApplyOk(t, v8, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.DisputeWindowedPoSt,
+		&miner.DisputeWindowedPoStParams{Deadline: dlInfo.Index, PoStIndex: 0})
+	assert.True(t, vm.CheckSectorFaulty(t, v8, minerAddrs.IDAddress, dlInfo.Index, pIdx, sectorNumber))
+}
@@ -15,6 +15,7 @@ import (
 	states7 "github.com/filecoin-project/specs-actors/v7/actors/states"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/exported"
 	manifest8 "github.com/filecoin-project/specs-actors/v8/actors/builtin/manifest"
+	miner8 "github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
 	states8 "github.com/filecoin-project/specs-actors/v8/actors/states"
 	adt8 "github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 
@@ -47,7 +48,13 @@ type Logger interface {
 
 // Migrates from v15 to v16
 //
-// This migration only updates the actor code CIDs in the state tree.
+// This migration updates the actor code CIDs in the state tree and migrates market and miner state.
+// In the market, it rewrites deal proposal labels, indexes the deals by provider and by client,
+// reschedules the next cron processing of active deals in DealOpsByEpoch to their expiry,
+// and adds an empty table of deal payments.
+// For each miner, it sets the owner as the beneficiary, sets the default debt repayment policy,
+// marks optimistically accepted Window PoSts as not aggregated, records pre-committed sectors
+// as having no prove-commit extension, and adds an empty queue of scheduled recoveries.
 // MigrationCache stores and loads cached data. Its implementation must be threadsafe
 type MigrationCache interface {
 	Write(key string, newCid cid.Cid) error
@@ -97,7 +104,6 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, actorsManifest
 		"cron":             builtin7.CronActorCodeID,
 		"account":          builtin7.AccountActorCodeID,
 		"storagepower":     builtin7.StoragePowerActorCodeID,
-		"storageminer":     builtin7.StorageMinerActorCodeID,
 		"paymentchannel":   builtin7.PaymentChannelActorCodeID,
 		"multisig":         builtin7.MultisigActorCodeID,
 		"reward":           builtin7.RewardActorCodeID,
@@ -124,6 +130,18 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, actorsManifest
 		return cid.Undef, xerrors.Errorf("code cid for market actor not found in manifest")
 	}
 	migrations[builtin7.StorageMarketActorCodeID] = marketMigrator{market8Cid}
+	miner8Cid, ok := manifest.Get("storageminer")
+	if !ok {
+		return cid.Undef, xerrors.Errorf("code cid for miner actor not found in manifest")
+	}
+	emptyScheduledRecoveries, err := adt8.StoreEmptyArray(adtStore, miner8.ScheduledRecoveriesAmtBitwidth)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to construct empty scheduled recoveries array: %w", err)
+	}
+	migrations[builtin7.StorageMinerActorCodeID] = minerMigrator{
+		OutCodeCID:               miner8Cid,
+		EmptyScheduledRecoveries: emptyScheduledRecoveries,
+	}
 
 	if len(migrations)+len(deferredCodeIDs) != len(exported.BuiltinActors()) {
 		return cid.Undef, xerrors.Errorf("incomplete migration specification with %d code CIDs", len(migrations))
@@ -1,6 +1,9 @@
 package proof
 
 import (
+	"github.com/filecoin-project/go-state-types/abi"
+	cid "github.com/ipfs/go-cid"
+
 	proof0 "github.com/filecoin-project/specs-actors/actors/runtime/proof"
 	proof5 "github.com/filecoin-project/specs-actors/v5/actors/runtime/proof"
 	proof7 "github.com/filecoin-project/specs-actors/v7/actors/runtime/proof"
@@ -29,6 +32,26 @@ type AggregateSealVerifyInfo = proof5.AggregateSealVerifyInfo
 
 type AggregateSealVerifyProofAndInfos = proof5.AggregateSealVerifyProofAndInfos
 
+// Information needed to verify one sector's non-interactive seal proof within an aggregate.
+// A non-interactive proof's challenges are derived from the sealed CID rather than from
+// interactive randomness drawn after a pre-commitment.
+type NonInteractiveSealVerifyInfo struct {
+	Number      abi.SectorNumber
+	Randomness  abi.SealRandomness
+	SealedCID   cid.Cid // CommR
+	UnsealedCID cid.Cid // CommD
+}
+
+// An aggregate of non-interactive seal proofs for sectors of a single miner and seal proof type.
+type AggregateNonInteractiveSealVerifyProofAndInfos struct {
+	Miner          abi.ActorID
+	SealProof      abi.RegisteredSealProof
+	AggregateProof abi.RegisteredAggregationProof
+
+	Proof []byte
+	Infos []NonInteractiveSealVerifyInfo
+}
+
 ///
 /// Replica
 ///
@@ -75,3 +98,14 @@ type WinningPoStVerifyInfo = proof0.WinningPoStVerifyInfo
 // Prover            abi.ActorID // used to derive 32-byte prover ID
 //}
 type WindowPoStVerifyInfo = proof0.WindowPoStVerifyInfo
+
+// Information needed to verify a single proof aggregating the Window PoSt proofs of many partitions.
+// The aggregate is checked against the same challenge randomness and sectors as the proofs it replaces.
+type AggregateWindowPoStVerifyInfo struct {
+	Randomness     abi.PoStRandomness
+	AggregateProof abi.RegisteredAggregationProof
+	// The aggregate, tagged with the Window PoSt proof type of the proofs it aggregates.
+	Proof             PoStProof
+	ChallengedSectors []SectorInfo
+	Prover            abi.ActorID // used to derive 32-byte prover ID
+}
@@ -142,10 +142,22 @@ type Runtime interface {
 	// Note events that may make debugging easier
 	Log(level rt.LogLevel, msg string, args ...interface{})
 
+	// Records a structured event describing an effect of the current invocation, for observation outside the
+	// state tree. Events do not affect execution. Events emitted by an invocation that aborts are discarded
+	// along with its state changes.
+	EmitEvent(event Event)
+
 	// BaseFee returns the basefee value in attoFIL per unit gas for the currently exectuting tipset.
 	BaseFee() abi.TokenAmount
 }
 
+// Event is a structured record of some effect of a method, emitted by an actor.
+type Event interface {
+	cbor.Marshaler
+	// Names the kind of event, which determines the schema of the serialized event.
+	EventType() string
+}
+
 // Store defines the storage module exposed to actors.
 type Store interface {
 	// Retrieves and deserializes an object from the store into `o`. Returns whether successful.
@@ -187,11 +199,15 @@ type Syscalls interface {
 
 	BatchVerifySeals(vis map[addr.Address][]proof.SealVerifyInfo) (map[addr.Address][]bool, error)
 	VerifyAggregateSeals(aggregate proof.AggregateSealVerifyProofAndInfos) error
+	// Verifies an aggregate of non-interactive seal proofs, which are not preceded by a pre-commitment.
+	VerifyAggregateNonInteractiveSeals(aggregate proof.AggregateNonInteractiveSealVerifyProofAndInfos) error
 
 	VerifyReplicaUpdate(replicaInfo proof.ReplicaUpdateInfo) error
 
 	// Verifies a proof of spacetime.
 	VerifyPoSt(vi proof5.WindowPoStVerifyInfo) error
+	// Verifies a single proof aggregating the proofs of spacetime for many partitions.
+	VerifyAggregatePoSt(vi proof.AggregateWindowPoStVerifyInfo) error
 	// Verifies that two block headers provide proof of a consensus fault:
 	// - both headers mined by the same actor
 	// - headers are different
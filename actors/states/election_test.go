@@ -150,6 +150,11 @@ func constructMinerState(ctx context.Context, t *testing.T, store adt.Store, own
 		SectorSize:                 ssize,
 		WindowPoStPartitionSectors: psize,
 		ConsensusFaultElapsed:      0,
+		Beneficiary:                owner,
+		BeneficiaryTerm: miner.BeneficiaryTerm{
+			Quota:     big.Zero(),
+			UsedQuota: big.Zero(),
+		},
 	}
 	infoCid, err := store.Put(ctx, &info)
 	require.NoError(t, err)
@@ -23,6 +23,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime/proof"
 	"github.com/filecoin-project/specs-actors/v8/actors/states"
 	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
 	"github.com/filecoin-project/specs-actors/v8/support/vm"
 )
 
@@ -121,7 +122,18 @@ func TestCommitPoStFlow(t *testing.T) {
 	}.Matches(t, v.LastInvocation())
 
 	// In the same epoch, trigger cron to validate prove commit
-	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+	cronResult := vm.RequireApplyMessage(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil, t.Name())
+	require.Equal(t, exitcode.Ok, cronResult.Code)
+
+	// the miner reports the sector's activation
+	require.Len(t, cronResult.Events, 1)
+	assert.Equal(t, minerAddrs.IDAddress, cronResult.Events[0].Emitter)
+	activated, ok := cronResult.Events[0].Event.(*miner.SectorEvent)
+	require.True(t, ok)
+	assert.Equal(t, miner.SectorEventActivated, activated.Type)
+	activatedSectors, err := activated.Sectors.All(miner.AddressedSectorsMax)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{uint64(sectorNumber)}, activatedSectors)
 
 	vm.ExpectInvocation{
 		To:     builtin.CronActorAddr,
@@ -216,6 +228,7 @@ func TestCommitPoStFlow(t *testing.T) {
 		// PoSt is rejected for skipping all sectors.
 		result := vm.RequireApplyMessage(t, tv, addrs[0], minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.SubmitWindowedPoSt, &submitParams, t.Name())
 		assert.Equal(t, exitcode.ErrIllegalArgument, result.Code)
+		assert.Empty(t, result.Events)
 
 		vm.ExpectInvocation{
 			To:       minerAddrs.IDAddress,
@@ -615,6 +628,92 @@ func TestAggregateOnePreCommitExpires(t *testing.T) {
 
 }
 
+func TestProveCommitSectorsNI(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+	wPoStProof, err := sealProof.RegisteredWindowPoStProof()
+	require.NoError(t, err)
+	sectorSize, err := sealProof.SectorSize()
+	require.NoError(t, err)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), builtin.TokenPrecision), 93837778)
+	owner, worker := addrs[0], addrs[0]
+	minerAddrs := createMiner(t, v, owner, worker, wPoStProof, big.Mul(big.NewInt(10_000), vm.FIL))
+
+	// advance vm so we can have seal randomness epoch in the past
+	v, err = v.WithEpoch(200)
+	require.NoError(t, err)
+
+	firstSectorNo := abi.SectorNumber(100)
+	params := miner.ProveCommitSectorsNIParams{SealProof: sealProof}
+	for i := 0; i < miner.MinAggregatedSectors; i++ {
+		sectorNumber := firstSectorNo + abi.SectorNumber(i)
+		params.Sectors = append(params.Sectors, miner.SectorNIActivationInfo{
+			SectorNumber:  sectorNumber,
+			SealedCID:     tutil.MakeCID(fmt.Sprintf("%d", sectorNumber), &miner.SealedCIDPrefix),
+			SealRandEpoch: v.GetEpoch() - 1,
+			Expiration:    v.GetEpoch() + miner.MinSectorExpiration + miner.WPoStProvingPeriod,
+		})
+	}
+
+	t.Run("invalid proof is rejected", func(t *testing.T) {
+		badParams := params
+		badParams.AggregateProof = []byte(vm.InvalidProof)
+		result := vm.RequireApplyMessage(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSectorsNI, &badParams, t.Name())
+		assert.Equal(t, exitcode.ErrIllegalArgument, result.Code)
+	})
+
+	// A single message activates the sectors, with no pre-commit deposit.
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSectorsNI, &params)
+	vm.ExpectInvocation{
+		To:     minerAddrs.IDAddress,
+		Method: builtin.MethodsMiner.ProveCommitSectorsNI,
+		Params: vm.ExpectObject(&params),
+		SubInvocations: []vm.ExpectInvocation{
+			{To: builtin.StorageMarketActorAddr, Method: builtin.MethodsMarket.ComputeDataCommitment},
+			{To: builtin.RewardActorAddr, Method: builtin.MethodsReward.ThisEpochReward},
+			{To: builtin.StoragePowerActorAddr, Method: builtin.MethodsPower.CurrentTotalPower},
+			{To: builtin.StoragePowerActorAddr, Method: builtin.MethodsPower.UpdatePledgeTotal},
+			{To: builtin.BurntFundsActorAddr, Method: builtin.MethodSend},
+			{To: builtin.StoragePowerActorAddr, Method: builtin.MethodsPower.EnrollCronEvent},
+		},
+	}.Matches(t, v.LastInvocation())
+
+	balances := vm.GetMinerBalances(t, v, minerAddrs.IDAddress)
+	assert.True(t, balances.InitialPledge.GreaterThan(big.Zero()))
+	assert.Equal(t, big.Zero(), balances.PreCommitDeposit)
+
+	// The sectors gain power when first proven.
+	dlInfo, pIdx, v := vm.AdvanceTillProvingDeadline(t, v, minerAddrs.IDAddress, firstSectorNo)
+	var minerState miner.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &minerState))
+	var infos []*miner.SectorOnChainInfo
+	for _, sector := range params.Sectors {
+		info, found, err := minerState.GetSector(v.Store(), sector.SectorNumber)
+		require.NoError(t, err)
+		require.True(t, found)
+		infos = append(infos, info)
+	}
+	submitWindowPoSt(t, v, worker, minerAddrs.IDAddress, dlInfo, []miner.PoStPartition{{
+		Index:   pIdx,
+		Skipped: bitfield.New(),
+	}}, miner.PowerForSectors(sectorSize, infos))
+
+	networkStats := vm.GetNetworkStats(t, v)
+	assert.Equal(t, big.NewInt(int64(sectorSize)*int64(len(infos))), networkStats.TotalBytesCommitted)
+
+	// Trigger cron to keep reward accounting correct
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	stateTree, err := v.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v.GetEpoch())
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
+
 func TestAggregateSizeLimits(t *testing.T) {
 	overSizedBatch := 820
 	ctx := context.Background()
@@ -6,6 +6,7 @@ import (
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
@@ -152,3 +153,76 @@ func TestCronTick(t *testing.T) {
 			}},
 	}.Matches(t, v.Invocations()[0])
 }
+
+func TestReportConsensusFaults(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	owner, reporter := addrs[0], addrs[1]
+
+	minerBalance := big.Mul(big.NewInt(1_000), vm.FIL)
+	var miners []*power.CreateMinerReturn
+	for i := 0; i < 2; i++ {
+		params := power.CreateMinerParams{
+			Owner:               owner,
+			Worker:              owner,
+			WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+			Peer:                abi.PeerID("not really a peer id"),
+		}
+		ret := vm.ApplyOk(t, v, owner, builtin.StoragePowerActorAddr, minerBalance, builtin.MethodsPower.CreateMiner, &params)
+		minerAddrs, ok := ret.(*power.CreateMinerReturn)
+		require.True(t, ok)
+		miners = append(miners, minerAddrs)
+	}
+	v, err := v.WithEpoch(200)
+	require.NoError(t, err)
+
+	reporterBefore, found, err := v.GetActor(reporter)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	fault := power.ReportConsensusFaultParams{
+		BlockHeader1: []byte("header1"),
+		BlockHeader2: []byte("header2"),
+	}
+	params := power.ReportConsensusFaultsParams{Reports: []power.ConsensusFaultReport{
+		{Miner: miners[0].RobustAddress, Fault: fault},
+		{Miner: miners[0].IDAddress, Fault: fault},
+		{Miner: owner, Fault: fault},
+		{Miner: miners[1].IDAddress, Fault: fault},
+	}}
+	ret := vm.ApplyOk(t, v, reporter, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.ReportConsensusFaults, &params)
+	faultsRet, ok := ret.(*power.ReportConsensusFaultsReturn)
+	require.True(t, ok)
+
+	assert.Equal(t, []power.ConsensusFaultReportOutcome{
+		power.ConsensusFaultAccepted,
+		power.ConsensusFaultDuplicate,
+		power.ConsensusFaultInvalid,
+		power.ConsensusFaultAccepted,
+	}, faultsRet.Outcomes)
+	assert.True(t, faultsRet.Reward.GreaterThan(big.Zero()))
+
+	// The reporter receives the rewards for both accepted reports.
+	reporterAfter, found, err := v.GetActor(reporter)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, big.Add(reporterBefore.Balance, faultsRet.Reward), reporterAfter.Balance)
+
+	// Both miners are excluded from consensus until the ineligibility period elapses.
+	for _, m := range miners {
+		var st miner.State
+		require.NoError(t, v.GetState(m.IDAddress, &st))
+		info, err := st.GetInfo(v.Store())
+		require.NoError(t, err)
+		assert.Equal(t, v.GetEpoch()+miner.ConsensusFaultIneligibilityDuration, info.ConsensusFaultElapsed)
+	}
+
+	// A later report of the same fault is too old.
+	ret = vm.ApplyOk(t, v, reporter, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.ReportConsensusFaults,
+		&power.ReportConsensusFaultsParams{Reports: []power.ConsensusFaultReport{{Miner: miners[0].IDAddress, Fault: fault}}})
+	faultsRet, ok = ret.(*power.ReportConsensusFaultsReturn)
+	require.True(t, ok)
+	assert.Equal(t, []power.ConsensusFaultReportOutcome{power.ConsensusFaultExpired}, faultsRet.Outcomes)
+	assert.Equal(t, big.Zero(), faultsRet.Reward)
+}
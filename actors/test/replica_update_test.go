@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v8/support/ipld"
 	"github.com/filecoin-project/specs-actors/v8/support/vm"
@@ -811,6 +812,88 @@ func TestDealIncludedInMultipleSectors(t *testing.T) {
 	require.NotEqual(t, replicaUpdate2.NewSealedSectorCID, newSectorInfo2.SealedCID)
 }
 
+// Tests that ProveReplicaUpdates2 reports the outcome of each update, and permits updating a sector
+// once the deals it carries have expired
+func TestProveReplicaUpdates2(t *testing.T) {
+	v, sectorInfo, worker, minerAddrs, deadlineIndex, partitionIndex, _ := createMinerAndUpgradeASector(t)
+	sectorNumber := sectorInfo.SectorNumber
+	require.Equal(t, 1, len(sectorInfo.DealIDs))
+	oldDealID := sectorInfo.DealIDs[0]
+
+	replicaUpdate := func(sectorNumber abi.SectorNumber, dealIDs []abi.DealID) miner.ReplicaUpdate {
+		return miner.ReplicaUpdate{
+			SectorID:           sectorNumber,
+			Deadline:           deadlineIndex,
+			Partition:          partitionIndex,
+			NewSealedSectorCID: tutil.MakeCID("replica2", &miner.SealedCIDPrefix),
+			Deals:              dealIDs,
+			UpdateProofType:    abi.RegisteredUpdateProof_StackedDrg32GiBV1,
+		}
+	}
+	proveReplicaUpdates2 := func(updates ...miner.ReplicaUpdate) []miner.ReplicaUpdateResult {
+		ret := vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(),
+			builtin.MethodsMiner.ProveReplicaUpdates2,
+			&miner.ProveReplicaUpdates2Params{Updates: updates})
+		results, ok := ret.(*miner.ProveReplicaUpdates2Return)
+		require.True(t, ok)
+		return results.Results
+	}
+
+	// Advance an epoch so that new deals are distinct from those already published.
+	v = vm.AdvanceOneEpochWithCron(t, v)
+	dealIDs := createDeals(t, 1, v, worker, worker, minerAddrs.IDAddress, sectorInfo.SealProof)
+
+	// The sector's deal has not yet expired, so it cannot be updated.
+	// Duplicate and malformed updates are skipped too.
+	oversizedProof := replicaUpdate(sectorNumber+1, dealIDs)
+	oversizedProof.ReplicaProof = make([]byte, 4097)
+	results := proveReplicaUpdates2(
+		replicaUpdate(sectorNumber, dealIDs),
+		replicaUpdate(sectorNumber, dealIDs),
+		oversizedProof,
+	)
+	assert.Equal(t, []miner.ReplicaUpdateResult{
+		{SectorNumber: sectorNumber, Outcome: miner.ReplicaUpdateActiveDeals},
+		{SectorNumber: sectorNumber, Outcome: miner.ReplicaUpdateDuplicate},
+		{SectorNumber: sectorNumber + 1, Outcome: miner.ReplicaUpdateInvalidParams},
+	}, results)
+	assert.Equal(t, sectorInfo.SealedCID, vm.SectorInfo(t, v, minerAddrs.IDAddress, sectorNumber).SealedCID)
+
+	// Prove the sector until its deal has expired.
+	var marketState market.State
+	require.NoError(t, v.GetState(builtin.StorageMarketActorAddr, &marketState))
+	proposals, err := market.AsDealProposalArray(v.Store(), marketState.Proposals)
+	require.NoError(t, err)
+	oldDeal, found, err := proposals.Get(oldDealID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	v, _ = vm.AdvanceByDeadlineTillIndex(t, v, minerAddrs.IDAddress, deadlineIndex+2%miner.WPoStPeriodDeadlines)
+	v = vm.AdvanceByDeadlineTillEpochWhileProving(t, v, minerAddrs.IDAddress, worker, sectorNumber, oldDeal.EndEpoch)
+	require.True(t, vm.CheckSectorActive(t, v, minerAddrs.IDAddress, deadlineIndex, partitionIndex, sectorNumber))
+
+	// The original method still refuses to update a sector with deals.
+	newDealIDs := createDeals(t, 1, v, worker, worker, minerAddrs.IDAddress, sectorInfo.SealProof)
+	vm.ApplyCode(t, v, worker, minerAddrs.RobustAddress, big.Zero(),
+		builtin.MethodsMiner.ProveReplicaUpdates,
+		&miner.ProveReplicaUpdatesParams{Updates: []miner.ReplicaUpdate{replicaUpdate(sectorNumber, newDealIDs)}},
+		exitcode.ErrIllegalArgument)
+
+	// The sector can now be updated with new deals, replacing the expired one.
+	results = proveReplicaUpdates2(replicaUpdate(sectorNumber, newDealIDs))
+	assert.Equal(t, []miner.ReplicaUpdateResult{{SectorNumber: sectorNumber, Outcome: miner.ReplicaUpdateSucceeded}}, results)
+
+	newSectorInfo := vm.SectorInfo(t, v, minerAddrs.IDAddress, sectorNumber)
+	assert.Equal(t, newDealIDs, newSectorInfo.DealIDs)
+	assert.Equal(t, tutil.MakeCID("replica2", &miner.SealedCIDPrefix), newSectorInfo.SealedCID)
+	assert.Equal(t, sectorInfo.SectorKeyCID, newSectorInfo.SectorKeyCID)
+
+	// The miner's pledge is reset to the new sector's requirement.
+	var minerState miner.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &minerState))
+	assert.Equal(t, newSectorInfo.InitialPledge, minerState.InitialPledge)
+}
+
 func createDeals(t *testing.T, numberOfDeals int, v *vm.VM, clientAddress address.Address, workerAddress address.Address, minerAddress address.Address, sealProof abi.RegisteredSealProof) []abi.DealID {
 	// add market collateral for client and miner
 	collateral := big.Mul(big.NewInt(int64(3*numberOfDeals)), vm.FIL)
@@ -3,6 +3,7 @@ package main
 import (
 	gen "github.com/whyrusleeping/cbor-gen"
 
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/account"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/cron"
 	init_ "github.com/filecoin-project/specs-actors/v8/actors/builtin/init"
@@ -30,14 +31,15 @@ func main() {
 	//	panic(err)
 	//}
 
-	//if err := gen.WriteTupleEncodersToFile("./actors/builtin/cbor_gen.go", "builtin",
-	//	//builtin.MinerAddrs{}, // Aliased from v0
-	//	//builtin.ConfirmSectorProofsParams{}, // Aliased from v6
-	//	//builtin.DeferredCronEventParams{}, // Aliased from v6
-	//	//builtin.ApplyRewardParams{}, // Aliased from v2
-	//); err != nil {
-	//	panic(err)
-	//}
+	if err := gen.WriteTupleEncodersToFile("./actors/builtin/cbor_gen.go", "builtin",
+		//builtin.MinerAddrs{}, // Aliased from v0
+		//builtin.ConfirmSectorProofsParams{}, // Aliased from v6
+		//builtin.DeferredCronEventParams{}, // Aliased from v6
+		//builtin.ApplyRewardParams{}, // Aliased from v2
+		builtin.MinerSectorActiveReturn{},
+	); err != nil {
+		panic(err)
+	}
 
 	// if err := gen.WriteTupleEncodersToFile("./actors/states/cbor_gen.go", "states",
 	// 	states.Actor{}, // Aliased from v0
@@ -129,6 +131,8 @@ func main() {
 		//paych.UpdateChannelStateParams{}, // Aliased from v7
 		//paych.SignedVoucher{},            // Aliased from v7
 		//paych.ModVerifyParams{}, // Aliased from v0
+		paych.GetLaneParams{},
+		paych.GetLaneReturn{},
 		// other types
 		//paych.Merge{}, // Aliased from v0
 	); err != nil {
@@ -141,6 +145,9 @@ func main() {
 		power.Claim{},
 		power.CronEvent{},
 		// method params and returns
+		power.ConsensusFaultReport{},
+		power.ReportConsensusFaultsParams{},
+		power.ReportConsensusFaultsReturn{},
 		//power.CreateMinerParams{}, // Aliased from v3
 		//power.CreateMinerReturn{}, // Aliased from v0
 		//power.EnrollCronEventParams{}, // Aliased from v0
@@ -166,9 +173,29 @@ func main() {
 		//market.ComputeDataCommitmentParams{}, // Aliased from v5
 		//market.ComputeDataCommitmentReturn{}, // Aliased from v5
 		//market.OnMinerSectorsTerminateParams{}, // Aliased from v0
+		market.GetActiveDealsParams{},
+		market.GetActiveDealsReturn{},
+		market.ActivateDealsBatchParams{},
+		market.DealActivationResult{},
+		market.SectorDealActivation{},
+		market.ActivateDealsBatchReturn{},
+		market.CancelPendingDealParams{},
+		market.ExtendDealsParams{},
+		market.GetBalanceReturn{},
+		market.DealQueryParams{},
+		market.GetDealActivationReturn{},
+		market.GetDealsByProviderParams{},
+		market.GetDealsByProviderReturn{},
+		market.SettleDealPaymentsParams{},
+		market.SettleDealPaymentsReturn{},
+		market.DealPaymentVoucher{},
+		market.VerifyDealPaymentParams{},
 		// other types
 		market.DealProposal{},       // Changed in v7
 		market.ClientDealProposal{}, // Changed in v7
+		market.DealExtension{},
+		market.ClientDealExtension{},
+		market.DealPayment{},
 		// market.SectorDeals{},     // Aliased from v3
 		// market.SectorWeights{},   // Aliased from v3
 	); err != nil {
@@ -188,6 +215,8 @@ func main() {
 		miner.SectorPreCommitInfo{},
 		miner.SectorOnChainInfo{},
 		miner.WorkerKeyChange{},
+		miner.BeneficiaryTerm{},
+		miner.PendingBeneficiaryChange{},
 		miner.VestingFunds{},
 		miner.VestingFund{},
 		miner.WindowedPoSt{},
@@ -214,6 +243,25 @@ func main() {
 		// miner.DisputeWindowedPoStParams{}, // Aliased from v3
 		//miner.PreCommitSectorBatchParams{}, // Aliased from v5
 		//miner.ProveReplicaUpdatesParams{}, // Aliased from v7
+		miner.GetSectorInfoParams{},
+		miner.GetDeadlineInfoReturn{},
+		miner.IsSectorActiveParams{},
+		miner.IsSectorActiveReturn{},
+		miner.ExtendSectorExpiration2Params{},
+		miner.MovePartitionsParams{},
+		miner.PreviewTerminationFeesParams{},
+		miner.TerminationFeePreview{},
+		miner.ChangeBeneficiaryParams{},
+		miner.GetBeneficiaryReturn{},
+		miner.CancelWorkerKeyChangeReturn{},
+		miner.SubmitWindowedPoStReturn{},
+		miner.ProveCommitSectorsNIParams{},
+		miner.ProveReplicaUpdates2Params{},
+		miner.ProveReplicaUpdates2Return{},
+		miner.SubmitWindowedPoStAggregateParams{},
+		miner.DebtRepaymentPolicy{},
+		miner.ExtendPreCommitsParams{},
+		miner.DeclareFaultsWithRecoveryEpochParams{},
 		// other types
 		//miner.FaultDeclaration{}, // Aliased from v0
 		//miner.RecoveryDeclaration{}, // Aliased from v0
@@ -221,6 +269,15 @@ func main() {
 		//miner.TerminationDeclaration{}, // Aliased from v0
 		//miner.PoStPartition{}, // Aliased from v0
 		//miner.ReplicaUpdate{}, // Aliased from v7
+		miner.ExpirationExtension2{},
+		miner.SectorExpiration{},
+		miner.SectorTerminationFee{},
+		miner.ActiveBeneficiary{},
+		miner.SectorNIActivationInfo{},
+		miner.ReplicaUpdateResult{},
+		// events
+		miner.SectorEvent{},
+		miner.WorkerKeyChangedEvent{},
 	); err != nil {
 		panic(err)
 	}
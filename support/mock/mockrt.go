@@ -75,15 +75,18 @@ type Runtime struct {
 	expectVerifySeal               *expectVerifySeal
 	expectComputeUnsealedSectorCID []*expectComputeUnsealedSectorCID
 	expectVerifyPoSt               *expectVerifyPoSt
+	expectVerifyAggregatePoSt      *expectVerifyAggregatePoSt
 	expectVerifyConsensusFault     *expectVerifyConsensusFault
 	expectDeleteActor              *addr.Address
 	expectBatchVerifySeals         *expectBatchVerifySeals
 	expectAggregateVerifySeals     *expectAggregateVerifySeals
+	expectAggregateVerifyNISeals   *expectAggregateVerifyNISeals
 	expectReplicaVerify            *expectReplicaVerify
 	// Gas charged explicitly through rt.ChargeGas. Note: most charges are implicit
 	expectGasCharged []int64
 
-	logs []string
+	logs   []string
+	events []runtime.Event
 }
 
 type expectBatchVerifySeals struct {
@@ -98,6 +101,11 @@ type expectAggregateVerifySeals struct {
 	err     error
 }
 
+type expectAggregateVerifyNISeals struct {
+	in  proof.AggregateNonInteractiveSealVerifyProofAndInfos
+	err error
+}
+
 type expectReplicaVerify struct {
 	inRUI proof.ReplicaUpdateInfo
 	err   error
@@ -150,6 +158,11 @@ type expectVerifyPoSt struct {
 	result error
 }
 
+type expectVerifyAggregatePoSt struct {
+	post   proof.AggregateWindowPoStVerifyInfo
+	result error
+}
+
 func (m *expectedMessage) Equal(to addr.Address, method abi.MethodNum, params cbor.Marshaler, value abi.TokenAmount) bool {
 	// avoid nil vs. zero/empty discrepancies that would disappear in serialization
 	paramBuf1 := new(bytes.Buffer)
@@ -696,6 +709,32 @@ func (rt *Runtime) VerifyAggregateSeals(agg proof.AggregateSealVerifyProofAndInf
 	return nil
 }
 
+func (rt *Runtime) VerifyAggregateNonInteractiveSeals(agg proof.AggregateNonInteractiveSealVerifyProofAndInfos) error {
+	exp := rt.expectAggregateVerifyNISeals
+	if exp != nil {
+		if agg.Miner != exp.in.Miner || agg.SealProof != exp.in.SealProof || agg.AggregateProof != exp.in.AggregateProof {
+			rt.failTest("aggregate %v does not match expected %v", agg, exp.in)
+		}
+		if !bytes.Equal(agg.Proof, exp.in.Proof) {
+			rt.failTest("proof %v does not match expected %v", agg.Proof, exp.in.Proof)
+		}
+		if len(agg.Infos) != len(exp.in.Infos) {
+			rt.failTest("length mismatch, expected: %v, actual: %v", exp.in.Infos, agg.Infos)
+		}
+		for i, expVI := range exp.in.Infos {
+			if !reflect.DeepEqual(agg.Infos[i], expVI) {
+				rt.failTest("verify info %v does not match expected %v", agg.Infos[i], expVI)
+			}
+		}
+		defer func() {
+			rt.expectAggregateVerifyNISeals = nil
+		}()
+		return exp.err
+	}
+	rt.failTestNow("unexpected syscall to verify aggregate non-interactive seals: %v", agg)
+	return nil
+}
+
 func (rt *Runtime) VerifyReplicaUpdate(replicaInfo proof.ReplicaUpdateInfo) error {
 	exp := rt.expectReplicaVerify
 	if exp != nil {
@@ -743,6 +782,24 @@ func (rt *Runtime) VerifyPoSt(vi proof.WindowPoStVerifyInfo) error {
 	return nil
 }
 
+func (rt *Runtime) VerifyAggregatePoSt(vi proof.AggregateWindowPoStVerifyInfo) error {
+	exp := rt.expectVerifyAggregatePoSt
+	if exp != nil {
+		if !reflect.DeepEqual(exp.post, vi) {
+			rt.failTest("unexpected aggregate PoSt verification\n"+
+				"        : %v\n"+
+				"expected: %v",
+				vi, exp.post)
+		}
+		defer func() {
+			rt.expectVerifyAggregatePoSt = nil
+		}()
+		return exp.result
+	}
+	rt.failTestNow("unexpected syscall to verify aggregate PoSt %v", vi)
+	return nil
+}
+
 func (rt *Runtime) VerifyConsensusFault(h1, h2, extra []byte) (*runtime.ConsensusFault, error) {
 	if rt.expectVerifyConsensusFault == nil {
 		rt.failTestNow("Unexpected syscall VerifyConsensusFault")
@@ -771,6 +828,10 @@ func (rt *Runtime) Log(level rt.LogLevel, msg string, args ...interface{}) {
 	rt.logs = append(rt.logs, fmt.Sprintf(msg, args...))
 }
 
+func (rt *Runtime) EmitEvent(event runtime.Event) {
+	rt.events = append(rt.events, event)
+}
+
 ///// Trace span implementation /////
 
 type TraceSpan struct {
@@ -964,6 +1025,12 @@ func (rt *Runtime) ExpectAggregateVerifySeals(agg proof.AggregateSealVerifyProof
 	}
 }
 
+func (rt *Runtime) ExpectAggregateVerifyNonInteractiveSeals(agg proof.AggregateNonInteractiveSealVerifyProofAndInfos, err error) {
+	rt.expectAggregateVerifyNISeals = &expectAggregateVerifyNISeals{
+		agg, err,
+	}
+}
+
 func (rt *Runtime) ExpectReplicaVerify(replica proof.ReplicaUpdateInfo, err error) {
 	rt.expectReplicaVerify = &expectReplicaVerify{
 		replica, err,
@@ -983,6 +1050,13 @@ func (rt *Runtime) ExpectVerifyPoSt(post proof.WindowPoStVerifyInfo, result erro
 	}
 }
 
+func (rt *Runtime) ExpectVerifyAggregatePoSt(post proof.AggregateWindowPoStVerifyInfo, result error) {
+	rt.expectVerifyAggregatePoSt = &expectVerifyAggregatePoSt{
+		post:   post,
+		result: result,
+	}
+}
+
 func (rt *Runtime) ExpectVerifyConsensusFault(h1, h2, extra []byte, result *runtime.ConsensusFault, resultErr error) {
 	rt.expectVerifyConsensusFault = &expectVerifyConsensusFault{
 		requireCorrectInput: true,
@@ -1042,10 +1116,18 @@ func (rt *Runtime) Verify() {
 		rt.failTest("missing expected aggregate verify seals with %v", rt.expectAggregateVerifySeals)
 	}
 
+	if rt.expectAggregateVerifyNISeals != nil {
+		rt.failTest("missing expected aggregate verify non-interactive seals with %v", rt.expectAggregateVerifyNISeals.in)
+	}
+
 	if rt.expectVerifyPoSt != nil {
 		rt.failTest("missing expected PoSt verification with %v", rt.expectVerifyPoSt)
 	}
 
+	if rt.expectVerifyAggregatePoSt != nil {
+		rt.failTest("missing expected aggregate PoSt verification with %v", rt.expectVerifyAggregatePoSt)
+	}
+
 	if rt.expectVerifyConsensusFault != nil {
 		rt.failTest("missing expected verify consensus fault")
 	}
@@ -1068,6 +1150,7 @@ func (rt *Runtime) Reset() {
 	rt.expectVerifySigs = nil
 	rt.expectVerifySeal = nil
 	rt.expectBatchVerifySeals = nil
+	rt.expectAggregateVerifyNISeals = nil
 	rt.expectComputeUnsealedSectorCID = nil
 }
 
@@ -1080,6 +1163,7 @@ func (rt *Runtime) ExpectAbort(expected exitcode.ExitCode, f func()) {
 func (rt *Runtime) ExpectAbortContainsMessage(expected exitcode.ExitCode, substr string, f func()) {
 	rt.t.Helper()
 	prevState := rt.state
+	prevEvents := len(rt.events)
 
 	defer func() {
 		rt.t.Helper()
@@ -1100,8 +1184,9 @@ func (rt *Runtime) ExpectAbortContainsMessage(expected exitcode.ExitCode, substr
 				rt.failTest("abort expected message\n'%s'\nto contain\n'%s'\n", a.msg, substr)
 			}
 		}
-		// Roll back state change.
+		// Roll back state change and any events emitted.
 		rt.state = prevState
+		rt.events = rt.events[:prevEvents]
 	}()
 	f()
 }
@@ -1119,6 +1204,15 @@ func (rt *Runtime) ClearLogs() {
 	rt.logs = []string{}
 }
 
+// Returns the events emitted since the last call to ClearEvents, in order.
+func (rt *Runtime) Events() []runtime.Event {
+	return rt.events
+}
+
+func (rt *Runtime) ClearEvents() {
+	rt.events = nil
+}
+
 func (rt *Runtime) ExpectGasCharged(gas int64) {
 	rt.expectGasCharged = append(rt.expectGasCharged, gas)
 }
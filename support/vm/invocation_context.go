@@ -74,6 +74,8 @@ type topLevelContext struct {
 	// Temporary field to workaround test-vector limitations
 	// https://github.com/filecoin-project/specs-actors/issues/1454
 	fakeSyscallsAccessed bool
+	// Events emitted by invocations that have not aborted (mutable).
+	events []EmittedEvent
 }
 
 func (tc *topLevelContext) chargeGas(gas GasCharge) {
@@ -261,6 +263,11 @@ func (ic *invocationContext) VerifyAggregateSeals(agg proof.AggregateSealVerifyP
 	return ic.Syscalls().VerifyAggregateSeals(agg)
 }
 
+func (ic *invocationContext) VerifyAggregateNonInteractiveSeals(agg proof.AggregateNonInteractiveSealVerifyProofAndInfos) error {
+	ic.topLevel.fakeSyscallsAccessed = true
+	return ic.Syscalls().VerifyAggregateNonInteractiveSeals(agg)
+}
+
 func (ic *invocationContext) VerifyReplicaUpdate(replicaInfo proof.ReplicaUpdateInfo) error {
 	ic.topLevel.fakeSyscallsAccessed = true
 	return ic.Syscalls().VerifyReplicaUpdate(replicaInfo)
@@ -272,6 +279,12 @@ func (ic *invocationContext) VerifyPoSt(vi proof.WindowPoStVerifyInfo) error {
 	return ic.Syscalls().VerifyPoSt(vi)
 }
 
+func (ic *invocationContext) VerifyAggregatePoSt(vi proof.AggregateWindowPoStVerifyInfo) error {
+	ic.topLevel.fakeSyscallsAccessed = true
+	ic.topLevel.chargeGas(ic.topLevel.gasPrices.OnVerifyAggregatePost(vi))
+	return ic.Syscalls().VerifyAggregatePoSt(vi)
+}
+
 func (ic *invocationContext) VerifyConsensusFault(h1, h2, extra []byte) (*runtime.ConsensusFault, error) {
 	ic.topLevel.fakeSyscallsAccessed = true
 	ic.topLevel.chargeGas(ic.topLevel.gasPrices.OnVerifyConsensusFault())
@@ -512,6 +525,10 @@ func (ic *invocationContext) Log(level rt.LogLevel, msg string, args ...interfac
 	ic.rt.Log(level, msg, args...)
 }
 
+func (ic *invocationContext) EmitEvent(event runtime.Event) {
+	ic.topLevel.events = append(ic.topLevel.events, EmittedEvent{Emitter: ic.msg.to, Event: event})
+}
+
 type returnWrapper struct {
 	inner cbor.Marshaler
 }
@@ -581,6 +598,13 @@ func (s fakeSyscalls) VerifyAggregateSeals(agg proof.AggregateSealVerifyProofAnd
 	return nil
 }
 
+func (s fakeSyscalls) VerifyAggregateNonInteractiveSeals(agg proof.AggregateNonInteractiveSealVerifyProofAndInfos) error {
+	if bytes.Equal(agg.Proof, []byte(InvalidProof)) {
+		return xerrors.New("invalid aggregate proof")
+	}
+	return nil
+}
+
 func (s fakeSyscalls) VerifyReplicaUpdate(replicaInfo proof.ReplicaUpdateInfo) error {
 	return nil
 }
@@ -594,6 +618,13 @@ func (s fakeSyscalls) VerifyPoSt(info proof.WindowPoStVerifyInfo) error {
 	return nil
 }
 
+func (s fakeSyscalls) VerifyAggregatePoSt(info proof.AggregateWindowPoStVerifyInfo) error {
+	if bytes.Equal(info.Proof.ProofBytes, []byte(InvalidProof)) {
+		return xerrors.New("invalid aggregate post")
+	}
+	return nil
+}
+
 func (s fakeSyscalls) VerifyConsensusFault(_, _, _ []byte) (*runtime.ConsensusFault, error) {
 	return &runtime.ConsensusFault{
 		Target: s.receiver,
@@ -645,9 +676,11 @@ func (ic *invocationContext) invoke() (ret returnWrapper, errcode exitcode.ExitC
 		panic(err)
 	}
 
+	priorEvents := len(ic.topLevel.events)
+
 	ic.rt.startInvocation(&ic.msg)
 
-	// Install handler for abort, which rolls back all state changes from this and any nested invocations.
+	// Install handler for abort, which rolls back all state changes and events from this and any nested invocations.
 	// This is the only path by which a non-OK exit code may be returned.
 	defer func() {
 		ic.stats.Capture()
@@ -656,6 +689,7 @@ func (ic *invocationContext) invoke() (ret returnWrapper, errcode exitcode.ExitC
 			if err := ic.rt.rollback(priorRoot); err != nil {
 				panic(err)
 			}
+			ic.topLevel.events = ic.topLevel.events[:priorEvents]
 			switch r := r.(type) {
 			case abort:
 				ic.rt.Log(rt.WARN, "Abort during actor execution. errMsg: %v exitCode: %d sender: %v receiver; %v method: %d value %v",
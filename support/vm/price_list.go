@@ -34,6 +34,7 @@ type Pricelist interface {
 	OnComputeUnsealedSectorCid(proofType abi.RegisteredSealProof, pieces []abi.PieceInfo) GasCharge
 	OnVerifySeal(info proof.SealVerifyInfo) GasCharge
 	OnVerifyPost(info proof.WindowPoStVerifyInfo) GasCharge
+	OnVerifyAggregatePost(info proof.AggregateWindowPoStVerifyInfo) GasCharge
 	OnVerifyConsensusFault() GasCharge
 }
 
@@ -77,6 +78,28 @@ type scalingCost struct {
 	scale int64
 }
 
+type step struct {
+	start int64
+	cost  int64
+}
+
+// A cost that is constant within each range of a quantity, beginning at each step's start.
+// Quantities below the first step are charged at the first step.
+type stepCost []step
+
+func (sc stepCost) Lookup(x int64) int64 {
+	i := 0
+	for ; i < len(sc); i++ {
+		if sc[i].start > x {
+			break
+		}
+	}
+	if i > 0 {
+		i--
+	}
+	return sc[i].cost
+}
+
 type pricelist struct {
 	computeGasMulti int64
 	storageGasMulti int64
@@ -153,6 +176,7 @@ type pricelist struct {
 	verifySealBase               int64
 	verifyPostLookup             map[abi.RegisteredPoStProof]scalingCost
 	verifyPostDiscount           bool
+	verifyAggregatePostSteps     stepCost
 	verifyConsensusFault         int64
 }
 
@@ -247,33 +271,62 @@ func (pl *pricelist) OnVerifySeal(info proof.SealVerifyInfo) GasCharge {
 
 // OnVerifyPost
 func (pl *pricelist) OnVerifyPost(info proof.WindowPoStVerifyInfo) GasCharge {
-	sectorSize := "unknown"
 	var proofType abi.RegisteredPoStProof
-
 	if len(info.Proofs) != 0 {
 		proofType = info.Proofs[0].PoStProof
-		ss, err := info.Proofs[0].PoStProof.SectorSize()
-		if err == nil {
-			sectorSize = ss.ShortString()
-		}
 	}
+	gasUsed := pl.verifyPostCost(proofType, len(info.ChallengedSectors))
+
+	return newGasCharge("OnVerifyPost", gasUsed, 0).
+		WithVirtual(117680921+43780*int64(len(info.ChallengedSectors)), 0).
+		WithExtra(verifyPostExtra(proofType, len(info.ChallengedSectors)))
+}
+
+// OnVerifyAggregatePost
+func (pl *pricelist) OnVerifyAggregatePost(info proof.AggregateWindowPoStVerifyInfo) GasCharge {
+	// The aggregate is charged for verifying the aggregation over the number of partition proofs it comprises,
+	// plus the per-sector cost of the public inputs of those proofs.
+	// Each partition's proof challenges a full partition of sectors, so the number of proofs is derived
+	// from the challenged sectors.
+	sectorCount := int64(len(info.ChallengedSectors))
+	proofCount := int64(1)
+	if partitionSectors, err := builtin.PoStProofWindowPoStPartitionSectors(info.Proof.PoStProof); err == nil && partitionSectors > 0 {
+		proofCount = (sectorCount + int64(partitionSectors) - 1) / int64(partitionSectors)
+	}
+	cost, ok := pl.verifyPostLookup[info.Proof.PoStProof]
+	if !ok {
+		cost = pl.verifyPostLookup[abi.RegisteredPoStProof_StackedDrgWindow512MiBV1]
+	}
+	gasUsed := pl.verifyAggregatePostSteps.Lookup(proofCount) + sectorCount*cost.scale
+
+	// The aggregate is verified once over all its challenged sectors, so is virtually charged as a single proof.
+	return newGasCharge("OnVerifyAggregatePost", gasUsed, 0).
+		WithVirtual(117680921+43780*sectorCount, 0).
+		WithExtra(verifyPostExtra(info.Proof.PoStProof, len(info.ChallengedSectors)))
+}
 
+func (pl *pricelist) verifyPostCost(proofType abi.RegisteredPoStProof, sectorCount int) int64 {
 	cost, ok := pl.verifyPostLookup[proofType]
 	if !ok {
 		cost = pl.verifyPostLookup[abi.RegisteredPoStProof_StackedDrgWindow512MiBV1]
 	}
 
-	gasUsed := cost.flat + int64(len(info.ChallengedSectors))*cost.scale
+	gasUsed := cost.flat + int64(sectorCount)*cost.scale
 	if pl.verifyPostDiscount {
 		gasUsed /= 2 // XXX: this is an artificial discount
 	}
+	return gasUsed
+}
 
-	return newGasCharge("OnVerifyPost", gasUsed, 0).
-		WithVirtual(117680921+43780*int64(len(info.ChallengedSectors)), 0).
-		WithExtra(map[string]interface{}{
-			"type": sectorSize,
-			"size": len(info.ChallengedSectors),
-		})
+func verifyPostExtra(proofType abi.RegisteredPoStProof, sectorCount int) map[string]interface{} {
+	sectorSize := "unknown"
+	if ss, err := proofType.SectorSize(); err == nil {
+		sectorSize = ss.ShortString()
+	}
+	return map[string]interface{}{
+		"type": sectorSize,
+		"size": sectorCount,
+	}
 }
 
 // OnVerifyConsensusFault
@@ -332,4 +385,17 @@ var v13PriceList = pricelist{
 	},
 	verifyPostDiscount:   false,
 	verifyConsensusFault: 495422,
+	// SnarkPack verification cost depends on the number of proofs aggregated rather than their circuit,
+	// so these are the aggregate seal verification costs from the FIP-0013 benchmarks
+	// (https://github.com/filecoin-project/FIPs/blob/master/FIPS/fip-0013.md), by number of proofs.
+	verifyAggregatePostSteps: stepCost{
+		{4, 103994170},
+		{7, 112356810},
+		{13, 122912610},
+		{26, 137559930},
+		{52, 162039100},
+		{103, 210960780},
+		{205, 318351180},
+		{410, 528274980},
+	},
 }
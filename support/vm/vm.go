@@ -338,6 +338,15 @@ type MessageResult struct {
 	Ret        cbor.Marshaler
 	Code       exitcode.ExitCode
 	GasCharged int64
+	// Events emitted during execution of the message, in order.
+	// Events from aborted invocations are excluded, and there are none if the message failed.
+	Events []EmittedEvent
+}
+
+// EmittedEvent is an event emitted by an actor during message execution.
+type EmittedEvent struct {
+	Emitter address.Address // ID address of the emitting actor
+	Event   runtime.Event
 }
 
 // ApplyMessage applies the message to the current state. It returns result of message application and any internal vm errors.
@@ -368,7 +377,7 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 	// load actor from global state
 	fromID, ok := vm.NormalizeAddress(from)
 	if !ok {
-		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged}, 0, false, nil
+		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged, nil}, 0, false, nil
 	}
 
 	fromActor, found, err := vm.GetActor(fromID)
@@ -377,7 +386,7 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 	}
 	if !found {
 		// Execution error; sender does not exist at time of message execution.
-		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged}, 0, false, nil
+		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged, nil}, 0, false, nil
 	}
 
 	// send
@@ -466,7 +475,12 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 	retGasCharge := vm.gasPrices.OnChainReturnValue(len(retBuf.Bytes()))
 	gasCharged = retGasCharge.Total() + ctx.topLevel.gasUsed
 
-	return MessageResult{ret.inner, exitCode, gasCharged}, callSeq, ctx.topLevel.fakeSyscallsAccessed, nil
+	var events []EmittedEvent
+	if exitCode == exitcode.Ok {
+		events = ctx.topLevel.events
+	}
+
+	return MessageResult{ret.inner, exitCode, gasCharged, events}, callSeq, ctx.topLevel.fakeSyscallsAccessed, nil
 }
 
 func (vm *VM) StateRoot() cid.Cid {